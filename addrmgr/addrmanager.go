@@ -45,6 +45,7 @@ type AddrManager struct {
 	nNew           int                                      // number of new addresses (i.e., not tried)
 	lamtx          sync.Mutex                               // local address mutex
 	localAddresses map[string]*localAddress                 // address key to la for all local addresses
+	asmap          *ASMap                                   // optional map of IPs to ASNs used for grouping
 }
 
 type serializedKnownAddress struct {
@@ -63,6 +64,11 @@ type serializedAddrManager struct {
 	Addresses    []*serializedKnownAddress
 	NewBuckets   [newBucketCount][]string // string is NetAddressKey
 	TriedBuckets [triedBucketCount][]string
+
+	// ASMapChecksum identifies the AS map the addresses were grouped with
+	// when they were placed into the buckets.  It is empty when no AS map
+	// was used.
+	ASMapChecksum string `json:",omitempty"`
 }
 
 type localAddress struct {
@@ -303,8 +309,8 @@ func (a *AddrManager) getNewBucket(netAddr, srcAddr *wire.NetAddress) int {
 
 	data1 := []byte{}
 	data1 = append(data1, a.key[:]...)
-	data1 = append(data1, []byte(GroupKey(netAddr))...)
-	data1 = append(data1, []byte(GroupKey(srcAddr))...)
+	data1 = append(data1, []byte(a.GroupKey(netAddr))...)
+	data1 = append(data1, []byte(a.GroupKey(srcAddr))...)
 	hash1 := chainhash.HashB(data1)
 	hash64 := binary.LittleEndian.Uint64(hash1)
 	hash64 %= newBucketsPerGroup
@@ -312,7 +318,7 @@ func (a *AddrManager) getNewBucket(netAddr, srcAddr *wire.NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, a.key[:]...)
-	data2 = append(data2, GroupKey(srcAddr)...)
+	data2 = append(data2, a.GroupKey(srcAddr)...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
@@ -333,7 +339,7 @@ func (a *AddrManager) getTriedBucket(netAddr *wire.NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, a.key[:]...)
-	data2 = append(data2, GroupKey(netAddr)...)
+	data2 = append(data2, a.GroupKey(netAddr)...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
@@ -375,6 +381,7 @@ func (a *AddrManager) savePeers() {
 	sam := new(serializedAddrManager)
 	sam.Version = serialisationVersion
 	copy(sam.Key[:], a.key[:])
+	sam.ASMapChecksum = a.asmapChecksum()
 
 	sam.Addresses = make([]*serializedKnownAddress, len(a.addrIndex))
 	i := 0
@@ -495,6 +502,15 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		a.addrIndex[NetAddressKey(ka.na)] = ka
 	}
 
+	// The addresses are grouped differently when the AS map changed since
+	// they were placed into the buckets, so place them into the buckets of
+	// their current groups instead.
+	if sam.ASMapChecksum != a.asmapChecksum() {
+		log.Infof("Rebucketing addresses since the AS map changed")
+		a.rebucket(sam.TriedBuckets)
+		return nil
+	}
+
 	for i := range sam.NewBuckets {
 		for _, val := range sam.NewBuckets[i] {
 			ka, ok := a.addrIndex[val]
@@ -540,6 +556,43 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 	return nil
 }
 
+// rebucket places the loaded addresses into the buckets for their groups.
+// The addresses in the passed serialized tried buckets are kept tried as long
+// as their tried bucket has room for them.  The remaining addresses are placed
+// into a single new bucket, and the ones that don't fit are dropped.
+//
+// This function MUST be called with the address manager lock held.
+func (a *AddrManager) rebucket(triedBuckets [triedBucketCount][]string) {
+	tried := make(map[string]struct{})
+	for i := range triedBuckets {
+		for _, key := range triedBuckets[i] {
+			tried[key] = struct{}{}
+		}
+	}
+
+	for key, ka := range a.addrIndex {
+		if _, ok := tried[key]; ok {
+			bucket := a.getTriedBucket(ka.na)
+			if a.addrTried[bucket].Len() < triedBucketSize {
+				ka.tried = true
+				a.nTried++
+				a.addrTried[bucket].PushBack(ka)
+				continue
+			}
+		}
+
+		bucket := a.getNewBucket(ka.na, ka.srcAddr)
+		if len(a.addrNew[bucket]) >= newBucketSize {
+			delete(a.addrIndex, key)
+			continue
+		}
+		ka.refs = 1
+		a.nNew++
+		a.addrNew[bucket][key] = ka
+	}
+	a.addrChanged = true
+}
+
 // DeserializeNetAddress converts a given address string to a *wire.NetAddress
 func (a *AddrManager) DeserializeNetAddress(addr string) (*wire.NetAddress, error) {
 	host, portStr, err := net.SplitHostPort(addr)
@@ -1131,6 +1184,44 @@ func (a *AddrManager) GetBestLocalAddress(remoteAddr *wire.NetAddress) *wire.Net
 	return bestAddress
 }
 
+// SetASMap sets the AS map used to group addresses by the autonomous system
+// that announces them instead of by their network prefix.  It must be called
+// before Start and the AS map is not changed afterwards, so it is safe to
+// access concurrently without a mutex.
+func (a *AddrManager) SetASMap(asmap *ASMap) {
+	a.asmap = asmap
+}
+
+// asmapChecksum returns the checksum of the AS map in use, or an empty string
+// when there is none.
+func (a *AddrManager) asmapChecksum() string {
+	if a.asmap == nil {
+		return ""
+	}
+	return a.asmap.Checksum()
+}
+
+// ASN returns the number of the autonomous system the passed address is mapped
+// to by the AS map in use, or 0 when there is no AS map or the address is not
+// mapped.
+func (a *AddrManager) ASN(na *wire.NetAddress) uint32 {
+	if a.asmap == nil || !IsRoutable(na) || isOnionCatTor(na) {
+		return 0
+	}
+	return a.asmap.ASN(na.IP)
+}
+
+// GroupKey returns a string representing the network group an address is part
+// of.  It is the autonomous system that announces the address when an AS map
+// is in use and the address is mapped, and the same as the package-level
+// GroupKey function otherwise.
+func (a *AddrManager) GroupKey(na *wire.NetAddress) string {
+	if asn := a.ASN(na); asn != 0 {
+		return fmt.Sprintf("as%d", asn)
+	}
+	return GroupKey(na)
+}
+
 // New returns a new Decred address manager.
 // Use Start to begin processing asynchronous address updates.
 // The address manager uses lookupFunc for necessary DNS lookups.
@@ -0,0 +1,350 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net"
+)
+
+// -----------------------------------------------------------------------------
+// An AS map maps IP addresses to the number of the autonomous system (ASN)
+// that announces them.  It uses the compact format that is also used by other
+// node implementations, which encodes a binary decision tree over the bits of
+// the address as a sequence of instructions.
+//
+// The instructions are read from the bits of the file, starting at the least
+// significant bit of the first byte.  The address is always interpreted as a
+// 128-bit IPv6 address with IPv4 addresses mapped into ::ffff:0:0/96, starting
+// at its most significant bit.  Each instruction is one of:
+//
+//   RETURN <asn>        finish with the given ASN
+//   JUMP <offset>       consume one bit of the address and skip offset bits of
+//                       the map when it is set
+//   MATCH <bits>        consume bits of the address and finish with the
+//                       default ASN when they differ from the given bits
+//   DEFAULT <asn>       set the ASN to finish with when a match fails
+//
+// Both the instructions and their arguments use a variable-length encoding.
+// See decodeBits for the details.
+// -----------------------------------------------------------------------------
+
+// asmapInstruction identifies an instruction in an AS map.
+type asmapInstruction uint32
+
+const (
+	asmapReturn asmapInstruction = iota
+	asmapJump
+	asmapMatch
+	asmapDefault
+)
+
+// asmapInvalid is returned by decodeBits when the value straddles the end of
+// the AS map.
+const asmapInvalid = 0xffffffff
+
+// These are the bit sizes of the classes of the variable-length encodings of
+// the instruction types and their arguments.
+var (
+	asmapTypeBitSizes  = []uint8{0, 0, 1}
+	asmapASNBitSizes   = []uint8{15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
+	asmapMatchBitSizes = []uint8{1, 2, 3, 4, 5, 6, 7, 8}
+	asmapJumpBitSizes  = []uint8{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30}
+)
+
+// asmapReader reads the bits of an AS map in order.
+type asmapReader struct {
+	data []byte
+	pos  int
+	end  int
+}
+
+// atEnd returns whether or not all bits of the AS map have been read.
+func (r *asmapReader) atEnd() bool {
+	return r.pos >= r.end
+}
+
+// remaining returns the number of bits of the AS map that have not been read.
+func (r *asmapReader) remaining() int {
+	return r.end - r.pos
+}
+
+// readBit returns the next bit of the AS map.  It must not be called when all
+// bits have been read.
+func (r *asmapReader) readBit() uint32 {
+	bit := uint32(r.data[r.pos/8]>>uint(r.pos%8)) & 1
+	r.pos++
+	return bit
+}
+
+// decodeBits decodes a value with a variable-length encoding in which the
+// passed bit sizes describe consecutive classes of values, starting at minVal.
+// Each class but the last is introduced by a one bit when the value is in a
+// later class or a zero bit followed by the offset of the value within the
+// class in the given number of bits.  It returns asmapInvalid when the value
+// straddles the end of the AS map.
+func (r *asmapReader) decodeBits(minVal uint32, bitSizes []uint8) uint32 {
+	val := minVal
+	for i, bitSize := range bitSizes {
+		var bit uint32
+		if i != len(bitSizes)-1 {
+			if r.atEnd() {
+				break
+			}
+			bit = r.readBit()
+		}
+		if bit == 1 {
+			val += 1 << bitSize
+			continue
+		}
+		for b := uint8(0); b < bitSize; b++ {
+			if r.atEnd() {
+				return asmapInvalid
+			}
+			val += r.readBit() << (bitSize - 1 - b)
+		}
+		return val
+	}
+	return asmapInvalid
+}
+
+func (r *asmapReader) decodeType() asmapInstruction {
+	return asmapInstruction(r.decodeBits(0, asmapTypeBitSizes))
+}
+
+func (r *asmapReader) decodeASN() uint32 {
+	return r.decodeBits(1, asmapASNBitSizes)
+}
+
+func (r *asmapReader) decodeMatch() uint32 {
+	return r.decodeBits(2, asmapMatchBitSizes)
+}
+
+func (r *asmapReader) decodeJump() uint32 {
+	return r.decodeBits(17, asmapJumpBitSizes)
+}
+
+// bitLen returns the number of bits needed to represent the passed value.
+func bitLen(v uint32) int {
+	var n int
+	for ; v != 0; v >>= 1 {
+		n++
+	}
+	return n
+}
+
+// ASMap maps IP addresses to the number of the autonomous system that
+// announces them.  It is safe for concurrent access.
+type ASMap struct {
+	data     []byte
+	checksum string
+}
+
+// NewASMap returns an AS map for the passed serialized map after ensuring it
+// is well formed.
+func NewASMap(data []byte) (*ASMap, error) {
+	if err := checkASMap(data, 128); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return &ASMap{data: data, checksum: hex.EncodeToString(sum[:])}, nil
+}
+
+// LoadASMap returns the AS map in the file at the passed path.
+func LoadASMap(path string) (*ASMap, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewASMap(data)
+}
+
+// checkASMap returns an error when the passed serialized AS map is not well
+// formed for addresses with the given number of bits, which ensures that
+// interpreting it always finishes with a RETURN instruction.
+func checkASMap(data []byte, bits int) error {
+	type jumpTarget struct {
+		offset int
+		bits   int
+	}
+
+	r := &asmapReader{data: data, end: len(data) * 8}
+	var jumps []jumpTarget
+	prevOpcode := asmapJump
+	hadIncompleteMatch := false
+	for !r.atEnd() {
+		if len(jumps) > 0 && r.pos >= jumps[len(jumps)-1].offset {
+			return errors.New("malformed AS map: jump into the middle " +
+				"of an instruction")
+		}
+		switch opcode := r.decodeType(); opcode {
+		case asmapReturn:
+			if prevOpcode == asmapDefault {
+				return errors.New("malformed AS map: return after " +
+					"default")
+			}
+			if r.decodeASN() == asmapInvalid {
+				return errors.New("malformed AS map: truncated ASN")
+			}
+			if len(jumps) == 0 {
+				// Nothing remains to be executed, so only zero
+				// padding to the end of the final byte may follow.
+				if r.remaining() > 7 {
+					return errors.New("malformed AS map: " +
+						"excessive padding")
+				}
+				for !r.atEnd() {
+					if r.readBit() != 0 {
+						return errors.New("malformed AS map: " +
+							"nonzero padding")
+					}
+				}
+				return nil
+			}
+
+			// Continue with the target of the most recent jump.
+			target := jumps[len(jumps)-1]
+			if r.pos != target.offset {
+				return errors.New("malformed AS map: unreachable " +
+					"instructions")
+			}
+			bits = target.bits
+			jumps = jumps[:len(jumps)-1]
+			prevOpcode = asmapJump
+
+		case asmapJump:
+			jump := r.decodeJump()
+			if jump == asmapInvalid {
+				return errors.New("malformed AS map: truncated jump")
+			}
+			if int64(jump) > int64(r.remaining()) {
+				return errors.New("malformed AS map: jump out of " +
+					"range")
+			}
+			if bits == 0 {
+				return errors.New("malformed AS map: consumes more " +
+					"bits than the address has")
+			}
+			bits--
+			offset := r.pos + int(jump)
+			if len(jumps) > 0 && offset >= jumps[len(jumps)-1].offset {
+				return errors.New("malformed AS map: intersecting " +
+					"jumps")
+			}
+			jumps = append(jumps, jumpTarget{offset: offset, bits: bits})
+			prevOpcode = asmapJump
+
+		case asmapMatch:
+			match := r.decodeMatch()
+			if match == asmapInvalid {
+				return errors.New("malformed AS map: truncated match")
+			}
+			matchLen := bitLen(match) - 1
+			if prevOpcode != asmapMatch {
+				hadIncompleteMatch = false
+			}
+			if matchLen < 8 && hadIncompleteMatch {
+				return errors.New("malformed AS map: multiple " +
+					"incomplete matches in a sequence")
+			}
+			hadIncompleteMatch = matchLen < 8
+			if bits < matchLen {
+				return errors.New("malformed AS map: consumes more " +
+					"bits than the address has")
+			}
+			bits -= matchLen
+			prevOpcode = asmapMatch
+
+		case asmapDefault:
+			if prevOpcode == asmapDefault {
+				return errors.New("malformed AS map: successive " +
+					"defaults")
+			}
+			if r.decodeASN() == asmapInvalid {
+				return errors.New("malformed AS map: truncated ASN")
+			}
+			prevOpcode = asmapDefault
+
+		default:
+			return errors.New("malformed AS map: truncated instruction")
+		}
+	}
+	return errors.New("malformed AS map: missing return")
+}
+
+// ASN returns the number of the autonomous system the passed IP address is
+// mapped to, or 0 when it is not mapped to any.
+func (m *ASMap) ASN(ip net.IP) uint32 {
+	ip16 := ip.To16()
+	if ip16 == nil {
+		return 0
+	}
+	ipBit := func(i int) uint32 {
+		return uint32(ip16[i/8]>>uint(7-i%8)) & 1
+	}
+
+	r := &asmapReader{data: m.data, end: len(m.data) * 8}
+	const ipBits = 128
+	bits := ipBits
+	var defaultASN uint32
+	for !r.atEnd() {
+		switch r.decodeType() {
+		case asmapReturn:
+			asn := r.decodeASN()
+			if asn == asmapInvalid {
+				return 0
+			}
+			return asn
+
+		case asmapJump:
+			jump := r.decodeJump()
+			if jump == asmapInvalid || bits == 0 ||
+				int64(jump) >= int64(r.remaining()) {
+				return 0
+			}
+			if ipBit(ipBits-bits) == 1 {
+				r.pos += int(jump)
+			}
+			bits--
+
+		case asmapMatch:
+			match := r.decodeMatch()
+			if match == asmapInvalid {
+				return 0
+			}
+			matchLen := bitLen(match) - 1
+			if bits < matchLen {
+				return 0
+			}
+			for i := 0; i < matchLen; i++ {
+				if ipBit(ipBits-bits) != (match>>uint(matchLen-1-i))&1 {
+					return defaultASN
+				}
+				bits--
+			}
+
+		case asmapDefault:
+			defaultASN = r.decodeASN()
+			if defaultASN == asmapInvalid {
+				return 0
+			}
+
+		default:
+			return 0
+		}
+	}
+
+	// Not reached for AS maps that passed the checks in NewASMap.
+	return 0
+}
+
+// Checksum returns a hash of the serialized AS map that identifies it.
+func (m *ASMap) Checksum() string {
+	return m.checksum
+}
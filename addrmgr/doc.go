@@ -24,6 +24,13 @@ generally helps provide greater peer diversity, and perhaps more importantly,
 drastically reduces the chances an attacker is able to coerce your peer into
 only connecting to nodes they control.
 
+The address manager groups addresses by their network prefix by default.  An
+AS map, which maps IP addresses to the autonomous systems that announce them,
+may be set via SetASMap to group the addresses by autonomous system instead.
+This makes it considerably harder for an attacker that controls many network
+prefixes within a single autonomous system to fill the buckets with their
+addresses.
+
 The address manager also understands routability and Tor addresses and tries
 hard to only return routable addresses.  In addition, it uses the information
 provided by the caller about connected, known good, and attempted addresses to
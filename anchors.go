@@ -0,0 +1,80 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// anchorsFilename is the name of the file in the data directory the addresses
+// of the block relay only peers connected at shutdown are saved to so they can
+// be reconnected first on the next startup.
+const anchorsFilename = "anchors.json"
+
+// anchorsPath returns the path of the file the anchor peers are saved to.
+func anchorsPath() string {
+	return filepath.Join(cfg.DataDir, anchorsFilename)
+}
+
+// saveAnchors writes the addresses of the block relay only outbound peers that
+// completed the handshake to the anchors file, replacing any existing one.
+//
+// This function MUST be called from the peer handler goroutine.
+func (s *server) saveAnchors(state *peerState) {
+	anchors := make([]string, 0, cfg.BlockRelayOnlyPeers)
+	state.forAllOutboundPeers(func(sp *serverPeer) {
+		if !sp.blockRelayOnly || !sp.VerAckReceived() ||
+			len(anchors) >= cfg.BlockRelayOnlyPeers {
+
+			return
+		}
+		anchors = append(anchors, sp.Addr())
+	})
+
+	serialized, err := json.Marshal(anchors)
+	if err != nil {
+		srvrLog.Errorf("Unable to save anchor peers: %v", err)
+		return
+	}
+	path := anchorsPath()
+	tmpPath := path + ".new"
+	err = ioutil.WriteFile(tmpPath, serialized, 0600)
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		srvrLog.Errorf("Unable to save anchor peers: %v", err)
+		return
+	}
+	srvrLog.Debugf("Saved %d anchor peers to %s", len(anchors), path)
+}
+
+// loadAnchors returns the addresses in the anchors file and removes the file so
+// that the same peers are not tried again after an unclean shutdown.  No
+// addresses are returned when the file does not exist.
+func loadAnchors() []string {
+	path := anchorsPath()
+	serialized, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		srvrLog.Errorf("Unable to load anchor peers: %v", err)
+		return nil
+	}
+	if err := os.Remove(path); err != nil {
+		srvrLog.Warnf("Unable to remove anchors file %s: %v", path, err)
+	}
+
+	var anchors []string
+	if err := json.Unmarshal(serialized, &anchors); err != nil {
+		srvrLog.Errorf("Malformed anchors file %s: %v", path, err)
+		return nil
+	}
+	return anchors
+}
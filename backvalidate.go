@@ -0,0 +1,448 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// A node that is bootstrapped from a utxo snapshot starts out without the
+// blocks prior to the snapshot block and relies on the snapshot hash committed
+// to by the network parameters for the state of the chain as of that block.
+// The history validator removes the need for that trust after the fact when
+// the backvalidate option is set.  It downloads the blocks up to the snapshot
+// block from full nodes in the background, validates them with a separate
+// chain instance that is backed by its own database, and ensures the utxo
+// snapshot of the resulting chain is the one the node was bootstrapped from.
+// Any difference means the node is following a chain state that is not the
+// result of the validated history, so it is shut down.
+//
+// The separate database is removed once the history has been validated, which
+// is recorded in the block database so it is only done once.  Interrupted
+// validation resumes from the blocks already in the separate database.
+// -----------------------------------------------------------------------------
+
+const (
+	// historyDbSuffix is the suffix of the name of the database the history
+	// prior to the utxo snapshot is validated with.
+	historyDbSuffix = "_history"
+
+	// historyWindow is the maximum number of blocks past the most recently
+	// validated one that are requested or held in memory at once.
+	historyWindow = 256
+
+	// historyBlocksPerPeer is the maximum number of blocks requested from a
+	// single peer at once.
+	historyBlocksPerPeer = 16
+
+	// historyRequestTimeout is how long a requested block may remain
+	// outstanding before it is requested from another peer.
+	historyRequestTimeout = time.Minute
+
+	// historyCheckInterval is the interval at which timed out requests are
+	// requested again and new peers are put to use.
+	historyCheckInterval = 5 * time.Second
+
+	// maxHistoryBlockFailures is the number of times in a row a block of the
+	// main chain may fail validation, each time after being received from a
+	// different peer, before the history is considered invalid.
+	maxHistoryBlockFailures = 3
+)
+
+// errInvalidHistory is returned by the history validator when the history
+// prior to the utxo snapshot is invalid.
+var errInvalidHistory = errors.New("the history prior to the UTXO snapshot " +
+	"is invalid")
+
+// historyRequest describes a block requested by the history validator.  The
+// peer is nil while the request is not outstanding with any peer.
+type historyRequest struct {
+	height int64
+	peer   *serverPeer
+	time   time.Time
+}
+
+// historyBlock houses a block received by the history validator along with the
+// peer it was received from.
+type historyBlock struct {
+	block *dcrutil.Block
+	peer  *serverPeer
+}
+
+// historyValidator validates the history prior to the utxo snapshot the block
+// database was initialized from in the background.
+type historyValidator struct {
+	server   *server
+	base     *blockchain.UtxoSnapshotBase
+	dbPath   string
+	db       database.DB
+	chain    *blockchain.BlockChain
+	progress *blockProgressLogger
+	failures int
+
+	// The following fields are protected by the mutex since they are
+	// accessed from the peer goroutines.
+	mtx        sync.Mutex
+	peers      map[*serverPeer]struct{}
+	requested  map[chainhash.Hash]*historyRequest
+	received   map[int64]*historyBlock
+	nextHeight int64
+
+	newBlock chan struct{}
+	quit     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// newHistoryValidator returns a new history validator for the passed utxo
+// snapshot base.  The database it validates the history with is created when
+// it does not exist yet.
+func newHistoryValidator(s *server, base *blockchain.UtxoSnapshotBase, interrupt <-chan struct{}) (*historyValidator, error) {
+	dbPath := blockDbPath(cfg.DbType) + historyDbSuffix
+	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net)
+	if err != nil {
+		if dbErr, ok := err.(database.Error); !ok || dbErr.ErrorCode !=
+			database.ErrDbDoesNotExist {
+			return nil, err
+		}
+		db, err = database.Create(cfg.DbType, dbPath, activeNetParams.Net)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		Interrupt:   interrupt,
+		ChainParams: s.chainParams,
+		TimeSource:  s.timeSource,
+		SigCache:    s.sigCache,
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	chain.DisableCheckpoints(cfg.DisableCheckpoints)
+
+	return &historyValidator{
+		server:     s,
+		base:       base,
+		dbPath:     dbPath,
+		db:         db,
+		chain:      chain,
+		progress:   newBlockProgressLogger("Validated history of", srvrLog),
+		peers:      make(map[*serverPeer]struct{}),
+		requested:  make(map[chainhash.Hash]*historyRequest),
+		received:   make(map[int64]*historyBlock),
+		nextHeight: chain.BestSnapshot().Height + 1,
+		newBlock:   make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// signal wakes up the handler without blocking when it is already due to run.
+func (v *historyValidator) signal() {
+	select {
+	case v.newBlock <- struct{}{}:
+	default:
+	}
+}
+
+// NewPeer informs the history validator of a newly connected peer.  Only peers
+// that serve the full block history are used.
+func (v *historyValidator) NewPeer(sp *serverPeer) {
+	if sp.Services()&wire.SFNodeNetwork != wire.SFNodeNetwork {
+		return
+	}
+	v.mtx.Lock()
+	v.peers[sp] = struct{}{}
+	v.mtx.Unlock()
+	v.signal()
+}
+
+// DonePeer informs the history validator that a peer has disconnected.  The
+// blocks that were outstanding with it are requested from other peers.
+func (v *historyValidator) DonePeer(sp *serverPeer) {
+	v.mtx.Lock()
+	delete(v.peers, sp)
+	for _, req := range v.requested {
+		if req.peer == sp {
+			req.peer = nil
+		}
+	}
+	v.mtx.Unlock()
+	v.signal()
+}
+
+// HandleBlock hands a block received from the passed peer to the history
+// validator and returns whether or not it was consumed.  Main chain blocks
+// prior to the snapshot block are consumed even when they were not requested,
+// such as when they arrive after their request timed out, since the block
+// manager has no use for them either.
+func (v *historyValidator) HandleBlock(sp *serverPeer, block *dcrutil.Block) bool {
+	if int64(block.MsgBlock().Header.Height) > v.base.Height {
+		return false
+	}
+
+	v.mtx.Lock()
+	req, ok := v.requested[*block.Hash()]
+	if ok {
+		delete(v.requested, *block.Hash())
+		v.received[req.height] = &historyBlock{block: block, peer: sp}
+	}
+	v.mtx.Unlock()
+	if ok {
+		v.signal()
+		return true
+	}
+	return v.server.blockManager.chain.MainChainHasBlock(block.Hash())
+}
+
+// requestBlocks adds requests for the blocks within the window past the tip of
+// the history chain and sends the requests that are not outstanding to the
+// peers with the fewest outstanding requests, lowest heights first.
+func (v *historyValidator) requestBlocks() {
+	tip := v.chain.BestSnapshot().Height
+	mainChain := v.server.blockManager.chain
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for ; v.nextHeight <= v.base.Height && v.nextHeight <= tip+historyWindow; v.nextHeight++ {
+		hash, err := mainChain.BlockHashByHeight(v.nextHeight)
+		if err != nil {
+			srvrLog.Errorf("Unable to look up the main chain block at "+
+				"height %d: %v", v.nextHeight, err)
+			return
+		}
+		v.requested[*hash] = &historyRequest{height: v.nextHeight}
+	}
+
+	// Count the outstanding requests of each peer and make the requests
+	// that timed out available again.
+	now := time.Now()
+	inFlight := make(map[*serverPeer]int, len(v.peers))
+	for sp := range v.peers {
+		inFlight[sp] = 0
+	}
+	var unassigned []chainhash.Hash
+	for hash, req := range v.requested {
+		if req.peer != nil && now.Sub(req.time) > historyRequestTimeout {
+			srvrLog.Debugf("Request for block %v (height %d) to %s "+
+				"timed out", hash, req.height, req.peer)
+			req.peer = nil
+		}
+		if req.peer == nil {
+			unassigned = append(unassigned, hash)
+			continue
+		}
+		inFlight[req.peer]++
+	}
+	sort.Slice(unassigned, func(i, j int) bool {
+		return v.requested[unassigned[i]].height <
+			v.requested[unassigned[j]].height
+	})
+
+	gdmsgs := make(map[*serverPeer]*wire.MsgGetData)
+	for i := range unassigned {
+		req := v.requested[unassigned[i]]
+		var best *serverPeer
+		for sp, n := range inFlight {
+			if n >= historyBlocksPerPeer || !sp.Connected() ||
+				sp.LastBlock() < req.height {
+				continue
+			}
+			if best == nil || n < inFlight[best] {
+				best = sp
+			}
+		}
+		if best == nil {
+			break
+		}
+		inFlight[best]++
+		req.peer = best
+		req.time = now
+
+		gdmsg, ok := gdmsgs[best]
+		if !ok {
+			gdmsg = wire.NewMsgGetData()
+			gdmsgs[best] = gdmsg
+		}
+		gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &unassigned[i]))
+	}
+	for sp, gdmsg := range gdmsgs {
+		sp.QueueMessage(gdmsg, nil)
+	}
+}
+
+// processBlocks validates the received blocks that directly follow the tip of
+// the history chain in order and returns whether or not the tip has reached the
+// snapshot block.  A block that fails validation is requested again from
+// another peer until it failed maxHistoryBlockFailures times in a row, in which
+// case errInvalidHistory is returned.
+func (v *historyValidator) processBlocks() (bool, error) {
+	for {
+		height := v.chain.BestSnapshot().Height
+		if height >= v.base.Height {
+			return true, nil
+		}
+		select {
+		case <-v.quit:
+			return false, nil
+		default:
+		}
+
+		v.mtx.Lock()
+		hb := v.received[height+1]
+		delete(v.received, height+1)
+		v.mtx.Unlock()
+		if hb == nil {
+			return false, nil
+		}
+
+		_, _, err := v.chain.ProcessBlock(hb.block, blockchain.BFNone)
+		if err != nil {
+			if _, ok := err.(blockchain.RuleError); !ok {
+				return false, err
+			}
+
+			v.failures++
+			srvrLog.Warnf("Block %v (height %d) prior to the UTXO "+
+				"snapshot received from %s failed validation: %v "+
+				"-- disconnecting", hb.block.Hash(), height+1,
+				hb.peer, err)
+			hb.peer.Disconnect()
+			if v.failures >= maxHistoryBlockFailures {
+				srvrLog.Criticalf("Block %v (height %d) prior to "+
+					"the UTXO snapshot failed validation %d "+
+					"times: %v", hb.block.Hash(), height+1,
+					v.failures, err)
+				return false, errInvalidHistory
+			}
+
+			v.mtx.Lock()
+			v.requested[*hb.block.Hash()] = &historyRequest{
+				height: height + 1,
+			}
+			v.mtx.Unlock()
+			return false, nil
+		}
+		v.failures = 0
+		v.progress.logBlockHeight(hb.block)
+	}
+}
+
+// verifySnapshot ensures the utxo snapshot of the validated history matches
+// the one the block database was initialized from and records that the history
+// has been validated.  errInvalidHistory is returned when it does not match.
+func (v *historyValidator) verifySnapshot() error {
+	best := v.chain.BestSnapshot()
+	if best.Hash != v.base.Hash {
+		srvrLog.Criticalf("The validated history ends with block %v "+
+			"instead of the UTXO snapshot block %v", best.Hash,
+			v.base.Hash)
+		return errInvalidHistory
+	}
+	info, err := v.chain.WriteUtxoSnapshot(ioutil.Discard, best.Height)
+	if err != nil {
+		return err
+	}
+	if info.SnapshotHash != v.base.SnapshotHash {
+		srvrLog.Criticalf("The UTXO snapshot %v of the validated history "+
+			"does not match the UTXO snapshot %v the block database "+
+			"was initialized from", info.SnapshotHash,
+			v.base.SnapshotHash)
+		return errInvalidHistory
+	}
+	return blockchain.MarkUtxoSnapshotValidated(v.server.db)
+}
+
+// handler downloads and validates the history prior to the utxo snapshot until
+// the snapshot block is reached and verifies the result.  It must be run as a
+// goroutine.
+func (v *historyValidator) handler() {
+	defer v.wg.Done()
+
+	srvrLog.Infof("Validating the history prior to the UTXO snapshot for "+
+		"block %v (height %d) in the background", v.base.Hash,
+		v.base.Height)
+
+	ticker := time.NewTicker(historyCheckInterval)
+	defer ticker.Stop()
+
+	var err error
+out:
+	for {
+		var done bool
+		done, err = v.processBlocks()
+		if err != nil {
+			break out
+		}
+		if done {
+			err = v.verifySnapshot()
+			if err != nil {
+				break out
+			}
+
+			v.db.Close()
+			if err := os.RemoveAll(v.dbPath); err != nil {
+				srvrLog.Errorf("Unable to remove the history "+
+					"database: %v", err)
+			}
+			srvrLog.Infof("Validated the history prior to the UTXO "+
+				"snapshot for block %v (height %d)", v.base.Hash,
+				v.base.Height)
+			return
+		}
+		v.requestBlocks()
+
+		select {
+		case <-v.newBlock:
+		case <-ticker.C:
+		case <-v.quit:
+			break out
+		}
+	}
+
+	v.db.Close()
+	switch {
+	case err == errInvalidHistory:
+		srvrLog.Criticalf("The history prior to the UTXO snapshot the " +
+			"block database was initialized from is invalid -- " +
+			"delete the block database and sync without the " +
+			"loadsnapshot option -- shutting down")
+		select {
+		case shutdownRequestChannel <- struct{}{}:
+		case <-v.quit:
+		}
+
+	case err != nil:
+		srvrLog.Errorf("Unable to validate the history prior to the UTXO "+
+			"snapshot: %v", err)
+	}
+}
+
+// Start begins validating the history in the background.
+func (v *historyValidator) Start() {
+	v.wg.Add(1)
+	go v.handler()
+}
+
+// Stop stops validating the history and waits for the handler to exit.
+// Validation resumes from the blocks validated so far when it is started again.
+func (v *historyValidator) Stop() {
+	close(v.quit)
+	v.wg.Wait()
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// banListFilename is the name of the file in the data directory the banned
+// subnets are persisted to across restarts.
+const banListFilename = "banlist.json"
+
+// banEntry describes a banned subnet along with when the ban was created and
+// when it expires.
+type banEntry struct {
+	subnet  *net.IPNet
+	created time.Time
+	until   time.Time
+}
+
+// banEntryJSON is the representation of a ban entry in the ban list file.
+type banEntryJSON struct {
+	Subnet  string `json:"subnet"`
+	Created int64  `json:"created"`
+	Until   int64  `json:"until"`
+}
+
+// banList houses the banned subnets and persists them to a file whenever they
+// change so that bans survive restarts.  Single addresses are stored as
+// subnets that only contain the address.
+//
+// It is safe for concurrent access.
+type banList struct {
+	mtx     sync.Mutex
+	path    string
+	entries map[string]*banEntry
+}
+
+// parseBanSubnet parses the passed address, which may either be a single IP
+// address or a subnet in CIDR notation, into the subnet to ban.
+func parseBanSubnet(addr string) (*net.IPNet, error) {
+	if strings.Contains(addr, "/") {
+		_, subnet, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet '%s'", addr)
+		}
+		return subnet, nil
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address '%s'", addr)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// newBanList returns a ban list persisted to the passed path with the bans
+// that were previously saved to it.  The returned ban list is empty when the
+// file does not exist yet.
+func newBanList(path string) (*banList, error) {
+	b := &banList{
+		path:    path,
+		entries: make(map[string]*banEntry),
+	}
+
+	serialized, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return b, err
+	}
+	var entries []banEntryJSON
+	if err := json.Unmarshal(serialized, &entries); err != nil {
+		return b, corruptBanListError(path, serialized, err)
+	}
+	for _, e := range entries {
+		_, subnet, err := net.ParseCIDR(e.Subnet)
+		if err != nil {
+			err := fmt.Errorf("invalid subnet '%s'", e.Subnet)
+			return b, corruptBanListError(path, serialized, err)
+		}
+		b.entries[subnet.String()] = &banEntry{
+			subnet:  subnet,
+			created: time.Unix(e.Created, 0),
+			until:   time.Unix(e.Until, 0),
+		}
+	}
+	b.removeExpired()
+
+	return b, nil
+}
+
+// corruptBanListError saves a copy of the passed contents of the corrupt ban
+// list at the given path next to it and returns an error that describes why it
+// is corrupt.  The copy is kept since the ban list file itself is overwritten
+// the next time the bans change.
+func corruptBanListError(path string, serialized []byte, reason error) error {
+	backupPath := path + ".bak"
+	if err := ioutil.WriteFile(backupPath, serialized, 0600); err != nil {
+		return fmt.Errorf("malformed ban list %s: %v (unable to save a "+
+			"copy: %v)", path, reason, err)
+	}
+	return fmt.Errorf("malformed ban list %s: %v (a copy was saved to %s)",
+		path, reason, backupPath)
+}
+
+// save writes the ban list to its file, replacing the previous contents.
+//
+// This function MUST be called with the ban list lock held.
+func (b *banList) save() error {
+	entries := make([]banEntryJSON, 0, len(b.entries))
+	for _, e := range b.sortedEntries() {
+		entries = append(entries, banEntryJSON{
+			Subnet:  e.subnet.String(),
+			Created: e.created.Unix(),
+			Until:   e.until.Unix(),
+		})
+	}
+	serialized, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write the list to a temporary file which is then moved over the
+	// existing file so an interrupted write does not lose the bans.
+	tmpPath := b.path + ".new"
+	if err := ioutil.WriteFile(tmpPath, serialized, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, b.path)
+}
+
+// sortedEntries returns the entries of the ban list sorted by subnet.
+//
+// This function MUST be called with the ban list lock held.
+func (b *banList) sortedEntries() []banEntry {
+	entries := make([]banEntry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].subnet.String() < entries[j].subnet.String()
+	})
+	return entries
+}
+
+// removeExpired removes the bans that have expired and returns whether or not
+// any were removed.
+//
+// This function MUST be called with the ban list lock held.
+func (b *banList) removeExpired() bool {
+	now := time.Now()
+	var removed bool
+	for key, e := range b.entries {
+		if now.Before(e.until) {
+			continue
+		}
+		srvrLog.Infof("Subnet %s is no longer banned", key)
+		delete(b.entries, key)
+		removed = true
+	}
+	return removed
+}
+
+// Ban bans the passed subnet until the provided time, replacing any existing
+// ban of the same subnet, and saves the ban list.
+func (b *banList) Ban(subnet *net.IPNet, until time.Time) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.entries[subnet.String()] = &banEntry{
+		subnet:  subnet,
+		created: time.Now(),
+		until:   until,
+	}
+	b.removeExpired()
+	return b.save()
+}
+
+// Unban removes the ban of the passed subnet and saves the ban list.  It
+// returns false when the subnet is not banned.
+func (b *banList) Unban(subnet *net.IPNet) (bool, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	key := subnet.String()
+	if _, ok := b.entries[key]; !ok {
+		return false, nil
+	}
+	delete(b.entries, key)
+	return true, b.save()
+}
+
+// Clear removes all bans and saves the ban list.
+func (b *banList) Clear() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.entries = make(map[string]*banEntry)
+	return b.save()
+}
+
+// Entries returns the current bans sorted by subnet.
+func (b *banList) Entries() []banEntry {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.removeExpired() {
+		if err := b.save(); err != nil {
+			srvrLog.Errorf("Unable to save ban list: %v", err)
+		}
+	}
+	return b.sortedEntries()
+}
+
+// BannedUntil returns when the ban of the passed IP address expires and
+// whether or not it is banned, either directly or as part of a banned subnet.
+// The latest expiration is returned when multiple bans apply.
+func (b *banList) BannedUntil(ip net.IP) (time.Time, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.removeExpired() {
+		if err := b.save(); err != nil {
+			srvrLog.Errorf("Unable to save ban list: %v", err)
+		}
+	}
+
+	var until time.Time
+	var banned bool
+	for _, e := range b.entries {
+		if e.subnet.Contains(ip) && e.until.After(until) {
+			until = e.until
+			banned = true
+		}
+	}
+	return until, banned
+}
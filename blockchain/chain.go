@@ -144,8 +144,10 @@ type BlockChain struct {
 	chainParams         *chaincfg.Params
 	timeSource          MedianTimeSource
 	notifications       NotificationCallback
+	connectTimings      func(validate, connect time.Duration)
 	sigCache            *txscript.SigCache
 	indexManager        IndexManager
+	pruneTarget         uint64
 	interrupt           <-chan struct{}
 
 	// subsidyCache is the cache that provides quick lookup of subsidy
@@ -787,16 +789,6 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *dcrutil.Block,
 			return err
 		}
 
-		// Allow the index manager to call each of the currently active
-		// optional indexes with the block being connected so they can
-		// update themselves accordingly.
-		if b.indexManager != nil {
-			err := b.indexManager.ConnectBlock(dbTx, block, parent, view)
-			if err != nil {
-				return err
-			}
-		}
-
 		return nil
 	})
 	if err != nil {
@@ -958,16 +950,6 @@ func (b *BlockChain) disconnectBlock(node *blockNode, block, parent *dcrutil.Blo
 			return err
 		}
 
-		// Allow the index manager to call each of the currently active
-		// optional indexes with the block being disconnected so they
-		// can update themselves accordingly.
-		if b.indexManager != nil {
-			err := b.indexManager.DisconnectBlock(dbTx, block, parent, view)
-			if err != nil {
-				return err
-			}
-		}
-
 		return nil
 	})
 	if err != nil {
@@ -1193,6 +1175,7 @@ func (b *BlockChain) reorganizeChainInternal(targetTip *blockNode) error {
 		// the utxo view still needs to be updated and the stxos are still
 		// needed.
 		stxos := make([]spentTxOut, 0, countSpentOutputs(block))
+		var validateTime time.Duration
 		if b.index.NodeStatus(n).KnownValid() {
 			// Update the view to mark all utxos referenced by the block as
 			// spent and add all transactions being created by this block to it.
@@ -1207,7 +1190,9 @@ func (b *BlockChain) reorganizeChainInternal(targetTip *blockNode) error {
 			// In the case the block is determined to be invalid due to a rule
 			// violation, mark it as invalid and mark all of its descendants as
 			// having an invalid ancestor.
+			start := time.Now()
 			err = b.checkConnectBlock(n, block, parent, view, &stxos)
+			validateTime = time.Since(start)
 			if err != nil {
 				if _, ok := err.(RuleError); ok {
 					b.index.SetStatusFlags(n, statusValidateFailed)
@@ -1221,10 +1206,14 @@ func (b *BlockChain) reorganizeChainInternal(targetTip *blockNode) error {
 		}
 
 		// Update the database and chain state.
+		start := time.Now()
 		err = b.connectBlock(n, block, parent, view, stxos)
 		if err != nil {
 			return err
 		}
+		if b.connectTimings != nil {
+			b.connectTimings(validateTime, time.Since(start))
+		}
 
 		tip = n
 	}
@@ -1450,9 +1439,12 @@ func (b *BlockChain) connectBestChain(node *blockNode, block, parent *dcrutil.Bl
 		view := NewUtxoViewpoint()
 		view.SetBestHash(parentHash)
 		var stxos []spentTxOut
+		var validateTime time.Duration
 		if !fastAdd {
+			start := time.Now()
 			err := b.checkConnectBlock(node, block, parent, view,
 				&stxos)
+			validateTime = time.Since(start)
 			if err != nil {
 				if _, ok := err.(RuleError); ok {
 					b.index.SetStatusFlags(node, statusValidateFailed)
@@ -1480,10 +1472,14 @@ func (b *BlockChain) connectBestChain(node *blockNode, block, parent *dcrutil.Bl
 		}
 
 		// Connect the block to the main chain.
+		start := time.Now()
 		err := b.connectBlock(node, block, parent, view, stxos)
 		if err != nil {
 			return 0, err
 		}
+		if b.connectTimings != nil {
+			b.connectTimings(validateTime, time.Since(start))
+		}
 
 		validateStr := "validating"
 		if !voteBitsApproveParent(node.voteBits) {
@@ -1987,9 +1983,13 @@ func extractDeploymentIDVersions(params *chaincfg.Params) (map[string]uint32, er
 	return deploymentVers, nil
 }
 
-// IndexManager provides a generic interface that the is called when blocks are
-// connected and disconnected to and from the tip of the main chain for the
-// purpose of supporting optional indexes.
+// IndexManager provides a generic interface that is called during chain
+// initialization for the purpose of supporting optional indexes.
+//
+// Index managers are NOT invoked as a part of connecting and disconnecting
+// blocks.  They are expected to track their own tips and update the indexes
+// asynchronously in response to the NTBlockConnected and NTBlockDisconnected
+// notifications so that slow indexes do not stall block processing.
 type IndexManager interface {
 	// Init is invoked during chain initialize in order to allow the index
 	// manager to initialize itself and any indexes it is managing.  The
@@ -1998,13 +1998,11 @@ type IndexManager interface {
 	// behavior is not desired.
 	Init(*BlockChain, <-chan struct{}) error
 
-	// ConnectBlock is invoked when a new block has been connected to the
-	// main chain.
-	ConnectBlock(database.Tx, *dcrutil.Block, *dcrutil.Block, *UtxoViewpoint) error
-
-	// DisconnectBlock is invoked when a block has been disconnected from
-	// the main chain.
-	DisconnectBlock(database.Tx, *dcrutil.Block, *dcrutil.Block, *UtxoViewpoint) error
+	// LowestTipHeight returns the lowest height through which all of the
+	// managed indexes have been updated.  The chain retains the data for
+	// all blocks after this height when pruning since the indexes still
+	// need to process them.
+	LowestTipHeight() int64
 }
 
 // Config is a descriptor which specifies the blockchain instance configuration.
@@ -2055,11 +2053,30 @@ type Config struct {
 	SigCache *txscript.SigCache
 
 	// IndexManager defines an index manager to use when initializing the
-	// chain and connecting and disconnecting blocks.
+	// chain.
 	//
 	// This field can be nil if the caller does not wish to make use of an
 	// index manager.
 	IndexManager IndexManager
+
+	// PruneTarget is the target size in bytes for the data of the stored
+	// blocks.  The data for the oldest blocks is deleted as needed to stay
+	// under the target, however the most recent blocks required for
+	// reorganizations and to regenerate stake nodes are always retained.
+	//
+	// This field can be zero to disable pruning.  A database that
+	// implements the database.Pruner interface is required otherwise.
+	PruneTarget uint64
+
+	// ConnectTimings defines a callback that is invoked with the amount of
+	// time spent validating a block and connecting it to the main chain
+	// each time a block is connected.  The validation time is zero when
+	// the checks were skipped because the block is already known to be
+	// valid or is being added in fast add mode.
+	//
+	// This field can be nil if the caller is not interested in the
+	// timings.
+	ConnectTimings func(validate, connect time.Duration)
 }
 
 // New returns a BlockChain instance using the provided configuration details.
@@ -2071,6 +2088,12 @@ func New(config *Config) (*BlockChain, error) {
 	if config.ChainParams == nil {
 		return nil, AssertError("blockchain.New chain parameters nil")
 	}
+	if config.PruneTarget != 0 {
+		if _, ok := config.DB.(database.Pruner); !ok {
+			return nil, AssertError("blockchain.New pruning requested " +
+				"with a database that does not support it")
+		}
+	}
 
 	// Generate a checkpoint by height map from the provided checkpoints.
 	params := config.ChainParams
@@ -2096,8 +2119,10 @@ func New(config *Config) (*BlockChain, error) {
 		chainParams:                   params,
 		timeSource:                    config.TimeSource,
 		notifications:                 config.Notifications,
+		connectTimings:                config.ConnectTimings,
 		sigCache:                      config.SigCache,
 		indexManager:                  config.IndexManager,
+		pruneTarget:                   config.PruneTarget,
 		interrupt:                     config.Interrupt,
 		index:                         newBlockIndex(config.DB),
 		bestChain:                     newChainView(nil),
@@ -2120,10 +2145,11 @@ func New(config *Config) (*BlockChain, error) {
 		return nil, err
 	}
 
-	// Initialize and catch up all of the currently active optional indexes
-	// as needed.
-	if config.IndexManager != nil {
-		err := config.IndexManager.Init(&b, config.Interrupt)
+	// Initialize all of the currently active optional indexes as needed.
+	// Catching them up to the main chain is handled asynchronously by the
+	// index manager.
+	if b.indexManager != nil {
+		err := b.indexManager.Init(&b, config.Interrupt)
 		if err != nil {
 			return nil, err
 		}
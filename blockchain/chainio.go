@@ -1164,8 +1164,14 @@ func dbFetchUtxoEntry(dbTx database.Tx, hash *chainhash.Hash) (*UtxoEntry, error
 // dbPutUtxoView uses an existing database transaction to update the utxo set
 // in the database based on the provided utxo view contents and state.  In
 // particular, only the entries that have been marked as modified are written
-// to the database.
+// to the database.  The utxo set state is updated to match when it is tracked.
 func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
+	// Load the utxo set state so it can be updated along with the utxo set.
+	state, err := dbFetchUtxoSetState(dbTx)
+	if err != nil {
+		return err
+	}
+
 	utxoBucket := dbTx.Metadata().Bucket(dbnamespace.UtxoSetBucketName)
 	for txHashIter, entry := range view.entries {
 		// No need to update the database if the entry was not modified.
@@ -1185,6 +1191,16 @@ func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
 		// data to change out from under the put/delete funcs below.
 		txHash := txHashIter
 
+		// Update the utxo set state for the outputs that were spent or
+		// created since the existing utxo entry was stored.
+		if state != nil {
+			existing := utxoBucket.Get(txHash[:])
+			err := state.update(&txHash, existing, serialized)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Remove the utxo entry if it is now fully spent.
 		if serialized == nil {
 			if err := utxoBucket.Delete(txHash[:]); err != nil {
@@ -1202,7 +1218,10 @@ func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
 		}
 	}
 
-	return nil
+	if state == nil {
+		return nil
+	}
+	return dbPutUtxoSetState(dbTx, state)
 }
 
 // -----------------------------------------------------------------------------
@@ -1569,6 +1588,12 @@ func (b *BlockChain) initChainState() error {
 	// Determine the state of the database.
 	var isStateInitialized bool
 	err = b.db.View(func(dbTx database.Tx) error {
+		// Refuse to use a database that a utxo snapshot was only
+		// partially loaded into.
+		if dbTx.Metadata().Get(dbnamespace.UtxoSnapshotLoadKeyName) != nil {
+			return errPartialUtxoSnapshot
+		}
+
 		// Fetch the database versioning information.
 		dbInfo, err := dbFetchDatabaseInfo(dbTx)
 		if err != nil {
@@ -1625,6 +1650,11 @@ func (b *BlockChain) initChainState() error {
 		return err
 	}
 
+	// Start tracking the utxo set state if needed.
+	if err := b.initUtxoSetState(); err != nil {
+		return err
+	}
+
 	// Attempt to load the chain state from the database.
 	err = b.db.View(func(dbTx database.Tx) error {
 		// Fetch the stored chain state from the database metadata.
@@ -29,6 +29,67 @@ var (
 	oneLsh256 = new(big.Int).Lsh(bigOne, 256)
 )
 
+// HeaderCtx is an interface to describe the header data of a block and its
+// ancestors that the difficulty retarget rules depend on.  It allows the rules
+// to be applied to header chains that are maintained outside of this package,
+// such as the header chains of SPV clients.
+type HeaderCtx interface {
+	// Height returns the height of the block.
+	Height() int64
+
+	// Bits returns the proof of work difficulty of the block.
+	Bits() uint32
+
+	// Timestamp returns the timestamp of the block as unix time.
+	Timestamp() int64
+
+	// SBits returns the stake difficulty of the block.
+	SBits() int64
+
+	// PoolSize returns the number of live tickets as of the block.
+	PoolSize() uint32
+
+	// FreshStake returns the number of tickets purchased in the block.
+	FreshStake() uint8
+
+	// Parent returns the header data of the parent block or nil when the
+	// block is the genesis block.
+	Parent() HeaderCtx
+}
+
+// blockNodeHeaderCtx implements the HeaderCtx interface for block nodes.
+type blockNodeHeaderCtx blockNode
+
+func (n *blockNodeHeaderCtx) Height() int64     { return n.height }
+func (n *blockNodeHeaderCtx) Bits() uint32      { return n.bits }
+func (n *blockNodeHeaderCtx) Timestamp() int64  { return n.timestamp }
+func (n *blockNodeHeaderCtx) SBits() int64      { return n.sbits }
+func (n *blockNodeHeaderCtx) PoolSize() uint32  { return n.poolSize }
+func (n *blockNodeHeaderCtx) FreshStake() uint8 { return n.freshStake }
+func (n *blockNodeHeaderCtx) Parent() HeaderCtx { return headerCtx(n.parent) }
+
+// headerCtx returns the header data of the passed block node.  It returns nil
+// rather than an interface holding a nil pointer when the node is nil.
+func headerCtx(node *blockNode) HeaderCtx {
+	if node == nil {
+		return nil
+	}
+	return (*blockNodeHeaderCtx)(node)
+}
+
+// headerCtxAncestor returns the ancestor of the passed header at the provided
+// height or nil when the height is after the height of the passed header or is
+// less than zero.
+func headerCtxAncestor(node HeaderCtx, height int64) HeaderCtx {
+	if height < 0 || height > node.Height() {
+		return nil
+	}
+	for node != nil && node.Height() != height {
+		node = node.Parent()
+	}
+	return node
+}
+
 // HashToBig converts a chainhash.Hash into a big.Int that can be used to
 // perform math comparisons.
 func HashToBig(hash *chainhash.Hash) *big.Int {
@@ -199,104 +260,103 @@ func (b *BlockChain) calcEasiestDifficulty(bits uint32, duration time.Duration)
 
 // findPrevTestNetDifficulty returns the difficulty of the previous block which
 // did not have the special testnet minimum difficulty rule applied.
-//
-// This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) findPrevTestNetDifficulty(startNode *blockNode) uint32 {
+func findPrevTestNetDifficulty(params *chaincfg.Params, startNode HeaderCtx) uint32 {
 	// Search backwards through the chain for the last block without
 	// the special rule applied.
-	blocksPerRetarget := b.chainParams.WorkDiffWindowSize *
-		b.chainParams.WorkDiffWindows
+	blocksPerRetarget := params.WorkDiffWindowSize *
+		params.WorkDiffWindows
 	iterNode := startNode
-	for iterNode != nil && iterNode.height%blocksPerRetarget != 0 &&
-		iterNode.bits == b.chainParams.PowLimitBits {
+	for iterNode != nil && iterNode.Height()%blocksPerRetarget != 0 &&
+		iterNode.Bits() == params.PowLimitBits {
 
-		iterNode = iterNode.parent
+		iterNode = iterNode.Parent()
 	}
 
 	// Return the found difficulty or the minimum difficulty if no
 	// appropriate block was found.
-	lastBits := b.chainParams.PowLimitBits
+	lastBits := params.PowLimitBits
 	if iterNode != nil {
-		lastBits = iterNode.bits
+		lastBits = iterNode.Bits()
 	}
 	return lastBits
 }
 
-// calcNextRequiredDifficulty calculates the required difficulty for the block
-// after the passed previous block node based on the difficulty retarget rules.
-// This function differs from the exported CalcNextRequiredDifficulty in that
-// the exported version uses the current best chain as the previous block node
-// while this function accepts any block node.
-func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime time.Time) (uint32, error) {
+// CalcNextRequiredDiffFromHeader calculates the required difficulty for the
+// block after the passed previous header based on the difficulty retarget
+// rules of the passed network.  It allows header chains that are maintained
+// outside of this package to apply the same rules as the chain.
+//
+// This function is safe for concurrent access.
+func CalcNextRequiredDiffFromHeader(params *chaincfg.Params, curNode HeaderCtx, newBlockTime time.Time) uint32 {
 	// Get the old difficulty; if we aren't at a block height where it changes,
 	// just return this.
-	oldDiff := curNode.bits
-	oldDiffBig := CompactToBig(curNode.bits)
+	oldDiff := curNode.Bits()
+	oldDiffBig := CompactToBig(curNode.Bits())
 
 	// We're not at a retarget point, return the oldDiff.
-	if (curNode.height+1)%b.chainParams.WorkDiffWindowSize != 0 {
+	if (curNode.Height()+1)%params.WorkDiffWindowSize != 0 {
 		// For networks that support it, allow special reduction of the
 		// required difficulty once too much time has elapsed without
 		// mining a block.
-		if b.chainParams.ReduceMinDifficulty {
+		if params.ReduceMinDifficulty {
 			// Return minimum difficulty when more than the desired
 			// amount of time has elapsed without mining a block.
-			reductionTime := int64(b.chainParams.MinDiffReductionTime /
+			reductionTime := int64(params.MinDiffReductionTime /
 				time.Second)
-			allowMinTime := curNode.timestamp + reductionTime
+			allowMinTime := curNode.Timestamp() + reductionTime
 			if newBlockTime.Unix() > allowMinTime {
-				return b.chainParams.PowLimitBits, nil
+				return params.PowLimitBits
 			}
 
 			// The block was mined within the desired timeframe, so
 			// return the difficulty for the last block which did
 			// not have the special minimum difficulty rule applied.
-			return b.findPrevTestNetDifficulty(curNode), nil
+			return findPrevTestNetDifficulty(params, curNode)
 		}
 
-		return oldDiff, nil
+		return oldDiff
 	}
 
 	// Declare some useful variables.
-	RAFBig := big.NewInt(b.chainParams.RetargetAdjustmentFactor)
-	nextDiffBigMin := CompactToBig(curNode.bits)
+	RAFBig := big.NewInt(params.RetargetAdjustmentFactor)
+	nextDiffBigMin := CompactToBig(curNode.Bits())
 	nextDiffBigMin.Div(nextDiffBigMin, RAFBig)
-	nextDiffBigMax := CompactToBig(curNode.bits)
+	nextDiffBigMax := CompactToBig(curNode.Bits())
 	nextDiffBigMax.Mul(nextDiffBigMax, RAFBig)
 
-	alpha := b.chainParams.WorkDiffAlpha
+	alpha := params.WorkDiffAlpha
 
 	// Number of nodes to traverse while calculating difficulty.
-	nodesToTraverse := (b.chainParams.WorkDiffWindowSize *
-		b.chainParams.WorkDiffWindows)
+	nodesToTraverse := (params.WorkDiffWindowSize *
+		params.WorkDiffWindows)
 
 	// Initialize bigInt slice for the percentage changes for each window period
 	// above or below the target.
-	windowChanges := make([]*big.Int, b.chainParams.WorkDiffWindows)
+	windowChanges := make([]*big.Int, params.WorkDiffWindows)
 
 	// Regress through all of the previous blocks and store the percent changes
 	// per window period; use bigInts to emulate 64.32 bit fixed point.
 	var olderTime, windowPeriod int64
 	var weights uint64
 	oldNode := curNode
-	recentTime := curNode.timestamp
+	recentTime := curNode.Timestamp()
 
 	for i := int64(0); ; i++ {
 		// Store and reset after reaching the end of every window period.
-		if i%b.chainParams.WorkDiffWindowSize == 0 && i != 0 {
-			olderTime = oldNode.timestamp
+		if i%params.WorkDiffWindowSize == 0 && i != 0 {
+			olderTime = oldNode.Timestamp()
 			timeDifference := recentTime - olderTime
 
 			// Just assume we're at the target (no change) if we've
 			// gone all the way back to the genesis block.
-			if oldNode.height == 0 {
-				timeDifference = int64(b.chainParams.TargetTimespan /
+			if oldNode.Height() == 0 {
+				timeDifference = int64(params.TargetTimespan /
 					time.Second)
 			}
 
 			timeDifBig := big.NewInt(timeDifference)
 			timeDifBig.Lsh(timeDifBig, 32) // Add padding
-			targetTemp := big.NewInt(int64(b.chainParams.TargetTimespan /
+			targetTemp := big.NewInt(int64(params.TargetTimespan /
 				time.Second))
 
 			windowAdjusted := targetTemp.Div(timeDifBig, targetTemp)
@@ -304,10 +364,10 @@ func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime
 			// Weight it exponentially. Be aware that this could at some point
 			// overflow if alpha or the number of blocks used is really large.
 			windowAdjusted = windowAdjusted.Lsh(windowAdjusted,
-				uint((b.chainParams.WorkDiffWindows-windowPeriod)*alpha))
+				uint((params.WorkDiffWindows-windowPeriod)*alpha))
 
 			// Sum up all the different weights incrementally.
-			weights += 1 << uint64((b.chainParams.WorkDiffWindows-windowPeriod)*
+			weights += 1 << uint64((params.WorkDiffWindows-windowPeriod)*
 				alpha)
 
 			// Store it in the slice.
@@ -324,14 +384,14 @@ func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime
 
 		// Get the previous node while staying at the genesis block as
 		// needed.
-		if oldNode.parent != nil {
-			oldNode = oldNode.parent
+		if parent := oldNode.Parent(); parent != nil {
+			oldNode = parent
 		}
 	}
 
 	// Sum up the weighted window periods.
 	weightedSum := big.NewInt(0)
-	for i := int64(0); i < b.chainParams.WorkDiffWindows; i++ {
+	for i := int64(0); i < params.WorkDiffWindows; i++ {
 		weightedSum.Add(weightedSum, windowChanges[i])
 	}
 
@@ -351,7 +411,7 @@ func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime
 	if oldDiffBig.Cmp(bigZero) == 0 { // This should never really happen,
 		nextDiffBig.Set(nextDiffBig) // but in case it does...
 	} else if nextDiffBig.Cmp(bigZero) == 0 {
-		nextDiffBig.Set(b.chainParams.PowLimit)
+		nextDiffBig.Set(params.PowLimit)
 	} else if nextDiffBig.Cmp(nextDiffBigMax) == 1 {
 		nextDiffBig.Set(nextDiffBigMax)
 	} else if nextDiffBig.Cmp(nextDiffBigMin) == -1 {
@@ -359,8 +419,8 @@ func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime
 	}
 
 	// Limit new value to the proof of work limit.
-	if nextDiffBig.Cmp(b.chainParams.PowLimit) > 0 {
-		nextDiffBig.Set(b.chainParams.PowLimit)
+	if nextDiffBig.Cmp(params.PowLimit) > 0 {
+		nextDiffBig.Set(params.PowLimit)
 	}
 
 	// Log new target difficulty and return it.  The new target logging is
@@ -368,11 +428,21 @@ func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime
 	// newTarget since conversion to the compact representation loses
 	// precision.
 	nextDiffBits := BigToCompact(nextDiffBig)
-	log.Debugf("Difficulty retarget at block height %d", curNode.height+1)
-	log.Debugf("Old target %08x (%064x)", curNode.bits, oldDiffBig)
+	log.Debugf("Difficulty retarget at block height %d", curNode.Height()+1)
+	log.Debugf("Old target %08x (%064x)", curNode.Bits(), oldDiffBig)
 	log.Debugf("New target %08x (%064x)", nextDiffBits, CompactToBig(nextDiffBits))
 
-	return nextDiffBits, nil
+	return nextDiffBits
+}
+
+// calcNextRequiredDifficulty calculates the required difficulty for the block
+// after the passed previous block node based on the difficulty retarget rules.
+// This function differs from the exported CalcNextRequiredDifficulty in that
+// the exported version uses the current best chain as the previous block node
+// while this function accepts any block node.
+func (b *BlockChain) calcNextRequiredDifficulty(curNode *blockNode, newBlockTime time.Time) (uint32, error) {
+	return CalcNextRequiredDiffFromHeader(b.chainParams, headerCtx(curNode),
+		newBlockTime), nil
 }
 
 // CalcNextRequiredDiffFromNode calculates the required difficulty for the block
@@ -426,24 +496,24 @@ func mergeDifficulty(oldDiff int64, newDiff1 int64, newDiff2 int64) int64 {
 	return summedChange.Int64()
 }
 
-// calcNextRequiredStakeDifficultyV1 calculates the required stake difficulty
-// for the block after the passed previous block node based on exponentially
-// weighted averages.
+// CalcNextRequiredStakeDiffFromHeaderV1 calculates the required stake
+// difficulty for the block after the passed previous header based on
+// exponentially weighted averages.
 //
 // NOTE: This is the original stake difficulty algorithm that was used at Decred
 // launch.
 //
-// This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int64, error) {
-	alpha := b.chainParams.StakeDiffAlpha
-	stakeDiffStartHeight := int64(b.chainParams.CoinbaseMaturity) +
+// This function is safe for concurrent access.
+func CalcNextRequiredStakeDiffFromHeaderV1(params *chaincfg.Params, curNode HeaderCtx) int64 {
+	alpha := params.StakeDiffAlpha
+	stakeDiffStartHeight := int64(params.CoinbaseMaturity) +
 		1
-	maxRetarget := b.chainParams.RetargetAdjustmentFactor
-	TicketPoolWeight := int64(b.chainParams.TicketPoolSizeWeight)
+	maxRetarget := params.RetargetAdjustmentFactor
+	TicketPoolWeight := int64(params.TicketPoolSizeWeight)
 
 	// Number of nodes to traverse while calculating difficulty.
-	nodesToTraverse := (b.chainParams.StakeDiffWindowSize *
-		b.chainParams.StakeDiffWindows)
+	nodesToTraverse := (params.StakeDiffWindowSize *
+		params.StakeDiffWindows)
 
 	// Genesis block. Block at height 1 has these parameters.
 	// Additionally, if we're before the time when people generally begin
@@ -454,26 +524,26 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 	// Decred parameters, but might do weird things if you use custom
 	// parameters.
 	if curNode == nil ||
-		curNode.height < stakeDiffStartHeight {
-		return b.chainParams.MinimumStakeDiff, nil
+		curNode.Height() < stakeDiffStartHeight {
+		return params.MinimumStakeDiff
 	}
 
 	// Get the old difficulty; if we aren't at a block height where it changes,
 	// just return this.
-	oldDiff := curNode.sbits
-	if (curNode.height+1)%b.chainParams.StakeDiffWindowSize != 0 {
-		return oldDiff, nil
+	oldDiff := curNode.SBits()
+	if (curNode.Height()+1)%params.StakeDiffWindowSize != 0 {
+		return oldDiff
 	}
 
 	// The target size of the ticketPool in live tickets. Recast these as int64
 	// to avoid possible overflows for large sizes of either variable in
 	// params.
-	targetForTicketPool := int64(b.chainParams.TicketsPerBlock) *
-		int64(b.chainParams.TicketPoolSize)
+	targetForTicketPool := int64(params.TicketsPerBlock) *
+		int64(params.TicketPoolSize)
 
 	// Initialize bigInt slice for the percentage changes for each window period
 	// above or below the target.
-	windowChanges := make([]*big.Int, b.chainParams.StakeDiffWindows)
+	windowChanges := make([]*big.Int, params.StakeDiffWindows)
 
 	// Regress through all of the previous blocks and store the percent changes
 	// per window period; use bigInts to emulate 64.32 bit fixed point.
@@ -483,11 +553,11 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 
 	for i := int64(0); ; i++ {
 		// Store and reset after reaching the end of every window period.
-		if (i+1)%b.chainParams.StakeDiffWindowSize == 0 {
+		if (i+1)%params.StakeDiffWindowSize == 0 {
 			// First adjust based on ticketPoolSize. Skew the difference
 			// in ticketPoolSize by max adjustment factor to help
 			// weight ticket pool size versus tickets per block.
-			poolSizeSkew := (int64(oldNode.poolSize)-
+			poolSizeSkew := (int64(oldNode.PoolSize())-
 				targetForTicketPool)*TicketPoolWeight + targetForTicketPool
 
 			// Don't let this be negative or zero.
@@ -504,10 +574,10 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 			// Weight it exponentially. Be aware that this could at some point
 			// overflow if alpha or the number of blocks used is really large.
 			windowAdjusted = windowAdjusted.Lsh(windowAdjusted,
-				uint((b.chainParams.StakeDiffWindows-windowPeriod)*alpha))
+				uint((params.StakeDiffWindows-windowPeriod)*alpha))
 
 			// Sum up all the different weights incrementally.
-			weights += 1 << uint64((b.chainParams.StakeDiffWindows-windowPeriod)*
+			weights += 1 << uint64((params.StakeDiffWindows-windowPeriod)*
 				alpha)
 
 			// Store it in the slice.
@@ -523,14 +593,14 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 
 		// Get the previous node while staying at the genesis block as
 		// needed.
-		if oldNode.parent != nil {
-			oldNode = oldNode.parent
+		if parent := oldNode.Parent(); parent != nil {
+			oldNode = parent
 		}
 	}
 
 	// Sum up the weighted window periods.
 	weightedSum := big.NewInt(0)
-	for i := int64(0); i < b.chainParams.StakeDiffWindows; i++ {
+	for i := int64(0); i < params.StakeDiffWindows; i++ {
 		weightedSum.Add(weightedSum, windowChanges[i])
 	}
 
@@ -550,7 +620,7 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 	// if we are, return the maximum or minimum except in the case that oldDiff
 	// is zero.
 	if oldDiff == 0 { // This should never really happen, but in case it does...
-		return nextDiffTicketPool, nil
+		return nextDiffTicketPool
 	} else if nextDiffTicketPool == 0 {
 		nextDiffTicketPool = oldDiff / maxRetarget
 	} else if (nextDiffTicketPool / oldDiff) > (maxRetarget - 1) {
@@ -560,8 +630,8 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 	}
 
 	// The target number of new SStx per block for any given window period.
-	targetForWindow := b.chainParams.StakeDiffWindowSize *
-		int64(b.chainParams.TicketsPerBlock)
+	targetForWindow := params.StakeDiffWindowSize *
+		int64(params.TicketsPerBlock)
 
 	// Regress through all of the previous blocks and store the percent changes
 	// per window period; use bigInts to emulate 64.32 bit fixed point.
@@ -572,10 +642,10 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 
 	for i := int64(0); ; i++ {
 		// Add the fresh stake into the store for this window period.
-		windowFreshStake += int64(oldNode.freshStake)
+		windowFreshStake += int64(oldNode.FreshStake())
 
 		// Store and reset after reaching the end of every window period.
-		if (i+1)%b.chainParams.StakeDiffWindowSize == 0 {
+		if (i+1)%params.StakeDiffWindowSize == 0 {
 			// Don't let fresh stake be zero.
 			if windowFreshStake <= 0 {
 				windowFreshStake = 1
@@ -591,11 +661,11 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 			// Weight it exponentially. Be aware that this could at some point
 			// overflow if alpha or the number of blocks used is really large.
 			windowAdjusted = windowAdjusted.Lsh(windowAdjusted,
-				uint((b.chainParams.StakeDiffWindows-windowPeriod)*alpha))
+				uint((params.StakeDiffWindows-windowPeriod)*alpha))
 
 			// Sum up all the different weights incrementally.
 			weights += 1 <<
-				uint64((b.chainParams.StakeDiffWindows-windowPeriod)*alpha)
+				uint64((params.StakeDiffWindows-windowPeriod)*alpha)
 
 			// Store it in the slice.
 			windowChanges[windowPeriod] = windowAdjusted
@@ -610,14 +680,14 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 
 		// Get the previous node while staying at the genesis block as
 		// needed.
-		if oldNode.parent != nil {
-			oldNode = oldNode.parent
+		if parent := oldNode.Parent(); parent != nil {
+			oldNode = parent
 		}
 	}
 
 	// Sum up the weighted window periods.
 	weightedSum = big.NewInt(0)
-	for i := int64(0); i < b.chainParams.StakeDiffWindows; i++ {
+	for i := int64(0); i < params.StakeDiffWindows; i++ {
 		weightedSum.Add(weightedSum, windowChanges[i])
 	}
 
@@ -637,7 +707,7 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 	// if we are, return the maximum or minimum except in the case that oldDiff
 	// is zero.
 	if oldDiff == 0 { // This should never really happen, but in case it does...
-		return nextDiffFreshStake, nil
+		return nextDiffFreshStake
 	} else if nextDiffFreshStake == 0 {
 		nextDiffFreshStake = oldDiff / maxRetarget
 	} else if (nextDiffFreshStake / oldDiff) > (maxRetarget - 1) {
@@ -653,7 +723,7 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 	// if we are, return the maximum or minimum except in the case that oldDiff
 	// is zero.
 	if oldDiff == 0 { // This should never really happen, but in case it does...
-		return oldDiff, nil
+		return oldDiff
 	} else if nextDiff == 0 {
 		nextDiff = oldDiff / maxRetarget
 	} else if (nextDiff / oldDiff) > (maxRetarget - 1) {
@@ -664,11 +734,24 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int6
 
 	// If the next diff is below the network minimum, set the required stake
 	// difficulty to the minimum.
-	if nextDiff < b.chainParams.MinimumStakeDiff {
-		return b.chainParams.MinimumStakeDiff, nil
+	if nextDiff < params.MinimumStakeDiff {
+		return params.MinimumStakeDiff
 	}
 
-	return nextDiff, nil
+	return nextDiff
+}
+
+// calcNextRequiredStakeDifficultyV1 calculates the required stake difficulty
+// for the block after the passed previous block node based on exponentially
+// weighted averages.
+//
+// NOTE: This is the original stake difficulty algorithm that was used at Decred
+// launch.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) calcNextRequiredStakeDifficultyV1(curNode *blockNode) (int64, error) {
+	return CalcNextRequiredStakeDiffFromHeaderV1(b.chainParams,
+		headerCtx(curNode)), nil
 }
 
 // estimateSupply returns an estimate of the coin supply for the provided block
@@ -711,13 +794,13 @@ func estimateSupply(params *chaincfg.Params, height int64) int64 {
 // sumPurchasedTickets returns the sum of the number of tickets purchased in the
 // most recent specified number of blocks from the point of view of the passed
 // node.
-func (b *BlockChain) sumPurchasedTickets(startNode *blockNode, numToSum int64) int64 {
+func sumPurchasedTickets(startNode HeaderCtx, numToSum int64) int64 {
 	var numPurchased int64
 	for node, numTraversed := startNode, int64(0); node != nil &&
 		numTraversed < numToSum; numTraversed++ {
 
-		numPurchased += int64(node.freshStake)
-		node = node.parent
+		numPurchased += int64(node.FreshStake())
+		node = node.Parent()
 	}
 
 	return numPurchased
@@ -798,29 +881,29 @@ func calcNextStakeDiffV2(params *chaincfg.Params, nextHeight, curDiff, prevPoolS
 	return nextDiff
 }
 
-// calcNextRequiredStakeDifficultyV2 calculates the required stake difficulty
-// for the block after the passed previous block node based on the algorithm
-// defined in DCP0001.
+// CalcNextRequiredStakeDiffFromHeaderV2 calculates the required stake
+// difficulty for the block after the passed previous header based on the
+// algorithm defined in DCP0001.
 //
-// This function MUST be called with the chain state lock held (for writes).
-func (b *BlockChain) calcNextRequiredStakeDifficultyV2(curNode *blockNode) (int64, error) {
+// This function is safe for concurrent access.
+func CalcNextRequiredStakeDiffFromHeaderV2(params *chaincfg.Params, curNode HeaderCtx) int64 {
 	// Stake difficulty before any tickets could possibly be purchased is
 	// the minimum value.
 	nextHeight := int64(0)
 	if curNode != nil {
-		nextHeight = curNode.height + 1
+		nextHeight = curNode.Height() + 1
 	}
-	stakeDiffStartHeight := int64(b.chainParams.CoinbaseMaturity) + 1
+	stakeDiffStartHeight := int64(params.CoinbaseMaturity) + 1
 	if nextHeight < stakeDiffStartHeight {
-		return b.chainParams.MinimumStakeDiff, nil
+		return params.MinimumStakeDiff
 	}
 
 	// Return the previous block's difficulty requirements if the next block
 	// is not at a difficulty retarget interval.
-	intervalSize := b.chainParams.StakeDiffWindowSize
-	curDiff := curNode.sbits
+	intervalSize := params.StakeDiffWindowSize
+	curDiff := curNode.SBits()
 	if nextHeight%intervalSize != 0 {
-		return curDiff, nil
+		return curDiff
 	}
 
 	// Get the pool size and number of tickets that were immature at the
@@ -833,28 +916,38 @@ func (b *BlockChain) calcNextRequiredStakeDifficultyV2(curNode *blockNode) (int6
 	// originally calculated.
 	var prevPoolSize int64
 	prevRetargetHeight := nextHeight - intervalSize - 1
-	prevRetargetNode := curNode.Ancestor(prevRetargetHeight)
+	prevRetargetNode := headerCtxAncestor(curNode, prevRetargetHeight)
 	if prevRetargetNode != nil {
-		prevPoolSize = int64(prevRetargetNode.poolSize)
+		prevPoolSize = int64(prevRetargetNode.PoolSize())
 	}
-	ticketMaturity := int64(b.chainParams.TicketMaturity)
-	prevImmatureTickets := b.sumPurchasedTickets(prevRetargetNode,
+	ticketMaturity := int64(params.TicketMaturity)
+	prevImmatureTickets := sumPurchasedTickets(prevRetargetNode,
 		ticketMaturity)
 
 	// Return the existing ticket price for the first few intervals to avoid
 	// division by zero and encourage initial pool population.
 	prevPoolSizeAll := prevPoolSize + prevImmatureTickets
 	if prevPoolSizeAll == 0 {
-		return curDiff, nil
+		return curDiff
 	}
 
 	// Count the number of currently immature tickets.
-	immatureTickets := b.sumPurchasedTickets(curNode, ticketMaturity)
+	immatureTickets := sumPurchasedTickets(curNode, ticketMaturity)
 
 	// Calculate and return the final next required difficulty.
-	curPoolSizeAll := int64(curNode.poolSize) + immatureTickets
-	return calcNextStakeDiffV2(b.chainParams, nextHeight, curDiff,
-		prevPoolSizeAll, curPoolSizeAll), nil
+	curPoolSizeAll := int64(curNode.PoolSize()) + immatureTickets
+	return calcNextStakeDiffV2(params, nextHeight, curDiff,
+		prevPoolSizeAll, curPoolSizeAll)
+}
+
+// calcNextRequiredStakeDifficultyV2 calculates the required stake difficulty
+// for the block after the passed previous block node based on the algorithm
+// defined in DCP0001.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) calcNextRequiredStakeDifficultyV2(curNode *blockNode) (int64, error) {
+	return CalcNextRequiredStakeDiffFromHeaderV2(b.chainParams,
+		headerCtx(curNode)), nil
 }
 
 // calcNextRequiredStakeDifficulty calculates the required stake difficulty for
@@ -1254,7 +1347,7 @@ func (b *BlockChain) estimateNextStakeDifficultyV2(curNode *blockNode, newTicket
 	if prevRetargetNode != nil {
 		prevPoolSize = int64(prevRetargetNode.poolSize)
 	}
-	prevImmatureTickets := b.sumPurchasedTickets(prevRetargetNode,
+	prevImmatureTickets := sumPurchasedTickets(headerCtx(prevRetargetNode),
 		ticketMaturity)
 
 	// Return the existing ticket price for the first few intervals to avoid
@@ -1276,7 +1369,7 @@ func (b *BlockChain) estimateNextStakeDifficultyV2(curNode *blockNode, newTicket
 	var remainingImmatureTickets int64
 	nextMaturityFloor := nextRetargetHeight - ticketMaturity - 1
 	if curHeight > nextMaturityFloor {
-		remainingImmatureTickets = b.sumPurchasedTickets(curNode,
+		remainingImmatureTickets = sumPurchasedTickets(headerCtx(curNode),
 			curHeight-nextMaturityFloor)
 	}
 
@@ -1302,7 +1395,7 @@ func (b *BlockChain) estimateNextStakeDifficultyV2(curNode *blockNode, newTicket
 	}
 	finalMaturingNode := curNode.Ancestor(finalMaturingHeight)
 	firstMaturingHeight := curHeight - ticketMaturity
-	maturingTickets := b.sumPurchasedTickets(finalMaturingNode,
+	maturingTickets := sumPurchasedTickets(headerCtx(finalMaturingNode),
 		finalMaturingHeight-firstMaturingHeight+1)
 
 	// Add the number of tickets that will mature based on the estimated data.
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/wire"
+)
+
+// headerOnlyCtx implements the HeaderCtx interface on top of nothing but block
+// headers in the same way as the header chain of SPV clients.
+type headerOnlyCtx struct {
+	parent *headerOnlyCtx
+	header wire.BlockHeader
+}
+
+func (n *headerOnlyCtx) Height() int64     { return int64(n.header.Height) }
+func (n *headerOnlyCtx) Bits() uint32      { return n.header.Bits }
+func (n *headerOnlyCtx) Timestamp() int64  { return n.header.Timestamp.Unix() }
+func (n *headerOnlyCtx) SBits() int64      { return n.header.SBits }
+func (n *headerOnlyCtx) PoolSize() uint32  { return n.header.PoolSize }
+func (n *headerOnlyCtx) FreshStake() uint8 { return n.header.FreshStake }
+
+func (n *headerOnlyCtx) Parent() HeaderCtx {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// TestHeaderCtxRetargets ensures the proof of work and stake difficulties the
+// chain requires for its block nodes match the ones required for a chain of
+// plain headers, such as the header chain of SPV clients, over several retarget
+// windows of each network.
+func TestHeaderCtxRetargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+	}{
+		{name: "mainnet", params: &chaincfg.MainNetParams},
+		{name: "testnet3", params: &chaincfg.TestNet3Params},
+		{name: "simnet", params: &chaincfg.SimNetParams},
+	}
+
+	for _, test := range tests {
+		params := test.params
+		b := &BlockChain{chainParams: params}
+		rng := rand.New(rand.NewSource(1))
+
+		// Cover a few full windows of both the proof of work and the
+		// stake difficulty retargets.
+		numBlocks := params.WorkDiffWindowSize * params.WorkDiffWindows
+		stakeBlocks := params.StakeDiffWindowSize * params.StakeDiffWindows
+		if stakeBlocks > numBlocks {
+			numBlocks = stakeBlocks
+		}
+		numBlocks = numBlocks*2 + params.StakeValidationHeight
+
+		genesis := params.GenesisBlock.Header
+		node := newBlockNode(&genesis, nil)
+		hdr := &headerOnlyCtx{header: genesis}
+		var bitsChanges, sdiffChanges int
+		for height := int64(1); height <= numBlocks; height++ {
+			// Vary the block times to mostly be faster than the
+			// target so the difficulty rises above the minimum, with
+			// the occasional long gap to trigger the minimum
+			// difficulty reduction on the test network.
+			delta := params.TargetTimePerBlock/4 + time.Duration(
+				rng.Int63n(int64(params.TargetTimePerBlock)))
+			if rng.Intn(50) == 0 {
+				delta += params.MinDiffReductionTime
+			}
+			timestamp := time.Unix(node.timestamp, 0).Add(delta)
+
+			bits, err := b.calcNextRequiredDifficulty(node, timestamp)
+			if err != nil {
+				t.Fatalf("%s: unexpected error at height %d: %v",
+					test.name, height, err)
+			}
+			hdrBits := CalcNextRequiredDiffFromHeader(params, hdr,
+				timestamp)
+			if bits != hdrBits {
+				t.Fatalf("%s: mismatched difficulty at height %d: "+
+					"chain %08x, headers %08x", test.name,
+					height, bits, hdrBits)
+			}
+
+			sdiffV1, err := b.calcNextRequiredStakeDifficultyV1(node)
+			if err != nil {
+				t.Fatalf("%s: unexpected error at height %d: %v",
+					test.name, height, err)
+			}
+			hdrSDiffV1 := CalcNextRequiredStakeDiffFromHeaderV1(params,
+				hdr)
+			if sdiffV1 != hdrSDiffV1 {
+				t.Fatalf("%s: mismatched v1 stake difficulty at "+
+					"height %d: chain %d, headers %d", test.name,
+					height, sdiffV1, hdrSDiffV1)
+			}
+			sdiffV2, err := b.calcNextRequiredStakeDifficultyV2(node)
+			if err != nil {
+				t.Fatalf("%s: unexpected error at height %d: %v",
+					test.name, height, err)
+			}
+			hdrSDiffV2 := CalcNextRequiredStakeDiffFromHeaderV2(params,
+				hdr)
+			if sdiffV2 != hdrSDiffV2 {
+				t.Fatalf("%s: mismatched v2 stake difficulty at "+
+					"height %d: chain %d, headers %d", test.name,
+					height, sdiffV2, hdrSDiffV2)
+			}
+
+			// Purchase a random number of tickets, which mature into
+			// the pool after the ticket maturity, and call votes
+			// once the stake validation height is reached.
+			var freshStake uint8
+			if height < params.StakeValidationHeight ||
+				rng.Intn(4) != 0 {
+
+				freshStake = uint8(rng.Intn(
+					int(params.MaxFreshStakePerBlock) + 1))
+			}
+			poolSize := node.poolSize
+			if height > int64(params.TicketMaturity) {
+				matured := hdr
+				for i := uint16(1); i < params.TicketMaturity &&
+					matured.parent != nil; i++ {
+
+					matured = matured.parent
+				}
+				poolSize += uint32(matured.header.FreshStake)
+			}
+			if height >= params.StakeValidationHeight {
+				voted := uint32(params.TicketsPerBlock)
+				if voted > poolSize {
+					voted = poolSize
+				}
+				poolSize -= voted
+			}
+
+			header := wire.BlockHeader{
+				PrevBlock:  node.hash,
+				Height:     uint32(height),
+				Bits:       bits,
+				SBits:      sdiffV2,
+				Timestamp:  timestamp,
+				PoolSize:   poolSize,
+				FreshStake: freshStake,
+			}
+			if bits != node.bits {
+				bitsChanges++
+			}
+			if sdiffV2 != node.sbits {
+				sdiffChanges++
+			}
+			node = newBlockNode(&header, node)
+			hdr = &headerOnlyCtx{parent: hdr, header: header}
+		}
+
+		// Ensure the difficulties were actually retargeted.
+		if bitsChanges == 0 || sdiffChanges == 0 {
+			t.Fatalf("%s: %d difficulty and %d stake difficulty "+
+				"changes, want both to change", test.name,
+				bitsChanges, sdiffChanges)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2016 The btcsuite developers
-// Copyright (c) 2016-2017 The Decred developers
+// Copyright (c) 2016-2019 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,8 @@ package indexers
 import (
 	"bytes"
 	"fmt"
+	"sync"
+	"sync/atomic"
 
 	"github.com/decred/dcrd/blockchain"
 	"github.com/decred/dcrd/blockchain/internal/progresslog"
@@ -175,13 +177,45 @@ func dbIndexDisconnectBlock(dbTx database.Tx, indexer Indexer, block, parent *dc
 	return dbPutIndexerTip(dbTx, idxKey, prevHash, int32(block.Height()-1))
 }
 
+// indexTip describes the block an index has been updated through.
+type indexTip struct {
+	hash   chainhash.Hash
+	height int64
+}
+
 // Manager defines an index manager that manages multiple optional indexes and
 // implements the blockchain.IndexManager interface so it can be seamlessly
-// plugged into normal chain processing.
+// plugged into chain initialization.
+//
+// Unlike the chain itself, the indexes are not updated as a part of connecting
+// and disconnecting blocks.  Instead, each index tracks its own tip and the
+// manager brings them up to date with the main chain in a separate goroutine
+// whenever it is notified that the main chain has changed.  This prevents slow
+// indexes from stalling block processing and allows indexes to catch up in the
+// background after startup.
 type Manager struct {
+	started  int32
+	shutdown int32
+
 	params         *chaincfg.Params
 	db             database.DB
 	enabledIndexes []Indexer
+	chain          *blockchain.BlockChain
+
+	// tips houses the current tip of each of the enabled indexes in the
+	// same order as enabledIndexes.  It is protected by tipsMtx since it is
+	// updated by the index handler and read by callers interested in the
+	// sync status of the indexes.
+	tipsMtx sync.RWMutex
+	tips    []indexTip
+
+	// syncRequested is signalled when the main chain changes in order to
+	// wake up the index handler.  It is buffered so that multiple requests
+	// which arrive while the indexes are being updated collapse into a
+	// single follow up pass.
+	syncRequested chan struct{}
+	quit          chan struct{}
+	wg            sync.WaitGroup
 }
 
 // Ensure the Manager type implements the blockchain.IndexManager interface.
@@ -371,14 +405,17 @@ func dbFetchBlockByHash(dbTx database.Tx, hash *chainhash.Hash) (*dcrutil.Block,
 }
 
 // Init initializes the enabled indexes.  This is called during chain
-// initialization and primarily consists of catching up all indexes to the
-// current best chain tip.  This is necessary since each index can be disabled
-// and re-enabled at any time and attempting to catch-up indexes at the same
-// time new blocks are being downloaded would lead to an overall longer time to
-// catch up due to the I/O contention.
+// initialization and consists of finishing any interrupted drops, creating and
+// upgrading the indexes as needed, and loading their current tips.
+//
+// Notice that the indexes are NOT caught up to the current best chain tip here.
+// That is done asynchronously by the index handler once the manager is started
+// so that the node can begin processing blocks without waiting for the indexes.
 //
 // This is part of the blockchain.IndexManager interface.
 func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{}) error {
+	m.chain = chain
+
 	// Nothing to do when no indexes are enabled.
 	if len(m.enabledIndexes) == 0 {
 		return nil
@@ -410,206 +447,250 @@ func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{})
 		}
 	}
 
-	// Rollback indexes to the main chain if their tip is an orphaned fork.
-	// This is fairly unlikely, but it can happen if the chain is
-	// reorganized while the index is disabled.  This has to be done in
-	// reverse order because later indexes can depend on earlier ones.
-	var err error
-	var cachedBlock *dcrutil.Block
-	for i := len(m.enabledIndexes); i > 0; i-- {
-		indexer := m.enabledIndexes[i-1]
+	// Load the current tip of each index.
+	err := m.db.View(func(dbTx database.Tx) error {
+		for i, indexer := range m.enabledIndexes {
+			hash, height, err := dbFetchIndexerTip(dbTx, indexer.Key())
+			if err != nil {
+				return err
+			}
 
-		// Fetch the current tip for the index.
-		var height int32
-		var hash *chainhash.Hash
-		err := m.db.View(func(dbTx database.Tx) error {
-			idxKey := indexer.Key()
-			hash, height, err = dbFetchIndexerTip(dbTx, idxKey)
-			return err
-		})
-		if err != nil {
-			return err
+			log.Debugf("Current %s tip (height %d, hash %v)",
+				indexer.Name(), height, hash)
+			m.tips[i] = indexTip{hash: *hash, height: int64(height)}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The indexes can't be caught up when the data for the blocks they
+	// still need to process has been pruned from the database or was never
+	// available because the chain was bootstrapped from a utxo snapshot.
+	best := chain.BestSnapshot()
+	err = m.db.View(func(dbTx database.Tx) error {
+		for i, indexer := range m.enabledIndexes {
+			if m.tips[i].height >= best.Height {
+				continue
+			}
+			hash, err := chain.BlockHashByHeight(m.tips[i].height + 1)
+			if err != nil {
+				return err
+			}
+			_, err = dbTx.FetchBlock(hash)
+			if database.IsError(err, database.ErrBlockPruned) {
+				return fmt.Errorf("the %s can not be caught up from "+
+					"height %d since the data for the blocks it "+
+					"requires has been pruned", indexer.Name(),
+					m.tips[i].height)
+			}
+			if database.IsError(err, database.ErrBlockNotFound) {
+				return fmt.Errorf("the %s can not be caught up from "+
+					"height %d since the data for the blocks it "+
+					"requires is not available", indexer.Name(),
+					m.tips[i].height)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		// Nothing to do if the index does not have any entries yet.
-		if height == 0 {
+	lowestHeight := best.Height
+	for _, tip := range m.tips {
+		if tip.height < lowestHeight {
+			lowestHeight = tip.height
+		}
+	}
+	if lowestHeight < best.Height {
+		log.Infof("Indexes will be caught up from height %d to %d in the "+
+			"background", lowestHeight, best.Height)
+	}
+
+	return nil
+}
+
+// setTip updates the cached tip of the index at the provided position in the
+// enabled indexes.
+//
+// This function is safe for concurrent access.
+func (m *Manager) setTip(i int, hash *chainhash.Hash, height int64) {
+	m.tipsMtx.Lock()
+	m.tips[i] = indexTip{hash: *hash, height: height}
+	m.tipsMtx.Unlock()
+}
+
+// fetchTips returns a copy of the cached tips of all enabled indexes.
+//
+// This function is safe for concurrent access.
+func (m *Manager) fetchTips() []indexTip {
+	m.tipsMtx.RLock()
+	tips := make([]indexTip, len(m.tips))
+	copy(tips, m.tips)
+	m.tipsMtx.RUnlock()
+	return tips
+}
+
+// rollbackOrphans removes blocks from the tip of each index until the tip is a
+// block that is part of the main chain.  This is necessary when the chain was
+// reorganized while the index was disabled or had not yet caught up.  This has
+// to be done in reverse order because later indexes can depend on earlier ones.
+func (m *Manager) rollbackOrphans(interrupt <-chan struct{}) error {
+	tips := m.fetchTips()
+	for i := len(m.enabledIndexes) - 1; i >= 0; i-- {
+		indexer := m.enabledIndexes[i]
+
+		// Nothing to do if the index does not have any entries yet or
+		// is already on the main chain.
+		hash, height := tips[i].hash, tips[i].height
+		if height == 0 || m.chain.MainChainHasBlock(&hash) {
 			continue
 		}
 
 		// Loop until the tip is a block that exists in the main chain.
-		var interrupted bool
 		initialHeight := height
-		err = m.db.Update(func(dbTx database.Tx) error {
-			for !chain.MainChainHasBlock(hash) {
-				// Get the block, unless it's already cached.
-				var block *dcrutil.Block
-				if cachedBlock == nil && height > 0 {
-					block, err = dbFetchBlockByHash(dbTx, hash)
-					if err != nil {
-						return err
-					}
-				} else {
-					block = cachedBlock
+		for !m.chain.MainChainHasBlock(&hash) {
+			err := m.db.Update(func(dbTx database.Tx) error {
+				block, err := dbFetchBlockByHash(dbTx, &hash)
+				if err != nil {
+					return err
 				}
 
-				// Load the parent block for the height since it is
-				// required to remove it.
+				// Load the parent block since it is required to
+				// remove the block.
 				parentHash := &block.MsgBlock().Header.PrevBlock
 				parent, err := dbFetchBlockByHash(dbTx, parentHash)
 				if err != nil {
 					return err
 				}
-				cachedBlock = parent
 
 				// When the index requires all of the referenced
 				// txouts they need to be retrieved from the
 				// transaction index.
 				var view *blockchain.UtxoViewpoint
 				if indexNeedsInputs(indexer) {
-					var err error
-					view, err = makeUtxoView(dbTx, block,
-						interrupt)
+					view, err = makeUtxoView(dbTx, block, interrupt)
 					if err != nil {
 						return err
 					}
 				}
 
-				// Remove all of the index entries associated
-				// with the block and update the indexer tip.
-				err = dbIndexDisconnectBlock(dbTx, indexer,
-					block, parent, view)
+				// Remove all of the index entries associated with
+				// the block and update the indexer tip.
+				err = dbIndexDisconnectBlock(dbTx, indexer, block,
+					parent, view)
 				if err != nil {
 					return err
 				}
 
-				// Update the tip to the previous block.
-				hash = &block.MsgBlock().Header.PrevBlock
+				hash = *parentHash
 				height--
-
-				// NOTE: This does not return as it does
-				// elsewhere since it would cause the
-				// database transaction to rollback and
-				// undo all work that has been done.
-				if interruptRequested(interrupt) {
-					interrupted = true
-					break
-				}
+				return nil
+			})
+			if err != nil {
+				return err
 			}
+			m.setTip(i, &hash, height)
 
-			return nil
-		})
-		if err != nil {
-			return err
-		}
-		if interrupted {
-			return errInterruptRequested
+			if interruptRequested(interrupt) {
+				return errInterruptRequested
+			}
 		}
 
-		if initialHeight != height {
-			log.Infof("Removed %d orphaned blocks from %s "+
-				"(heights %d to %d)", initialHeight-height,
-				indexer.Name(), height+1, initialHeight)
-		}
+		log.Infof("Removed %d orphaned blocks from %s (heights %d to %d)",
+			initialHeight-height, indexer.Name(), height+1, initialHeight)
 	}
 
-	// Fetch the current tip heights for each index along with tracking the
-	// lowest one so the catchup code only needs to start at the earliest
-	// block and is able to skip connecting the block for the indexes that
-	// don't need it.
-	bestHeight := int32(chain.BestSnapshot().Height)
-	lowestHeight := bestHeight
-	indexerHeights := make([]int32, len(m.enabledIndexes))
-	err = m.db.View(func(dbTx database.Tx) error {
-		for i, indexer := range m.enabledIndexes {
-			idxKey := indexer.Key()
-			hash, height, err := dbFetchIndexerTip(dbTx, idxKey)
-			if err != nil {
-				return err
-			}
+	return nil
+}
 
-			log.Debugf("Current %s tip (height %d, hash %v)",
-				indexer.Name(), height, hash)
-			indexerHeights[i] = height
-			if height < lowestHeight {
-				lowestHeight = height
-			}
+// catchUp connects all main chain blocks after the tip of each index through
+// the current best chain tip.  It returns false without an error when the main
+// chain was reorganized out from under the indexes while doing so, in which
+// case the caller is expected to roll back the orphaned blocks and try again.
+func (m *Manager) catchUp(interrupt <-chan struct{}) (bool, error) {
+	// Determine the lowest tip height so the catchup code only needs to start
+	// at the earliest block and is able to skip connecting the block for the
+	// indexes that don't need it.
+	tips := m.fetchTips()
+	bestHeight := m.chain.BestSnapshot().Height
+	lowestHeight := bestHeight
+	for _, tip := range tips {
+		if tip.height < lowestHeight {
+			lowestHeight = tip.height
 		}
-		return nil
-	})
-	if err != nil {
-		return err
 	}
 
 	// Nothing to index if all of the indexes are caught up.
-	if lowestHeight == bestHeight {
-		return nil
+	if lowestHeight >= bestHeight {
+		return true, nil
 	}
 
 	// Create a progress logger for the indexing process below.
 	progressLogger := progresslog.NewBlockProgressLogger("Indexed", log)
 
-	// At this point, one or more indexes are behind the current best chain
-	// tip and need to be caught up, so log the details and loop through
-	// each block that needs to be indexed.
-	log.Infof("Catching up indexes from height %d to %d", lowestHeight,
+	log.Debugf("Catching up indexes from height %d to %d", lowestHeight,
 		bestHeight)
 
 	var cachedParent *dcrutil.Block
 	for height := lowestHeight + 1; height <= bestHeight; height++ {
 		if interruptRequested(interrupt) {
-			return errInterruptRequested
+			return false, errInterruptRequested
+		}
+
+		// Load the main chain block for the height since it is required
+		// to index it.  The main chain might have been reorganized to a
+		// lower height since the best height was determined, so treat a
+		// failed lookup the same as a reorganization.
+		hash, err := m.chain.BlockHashByHeight(height)
+		if err != nil {
+			return false, nil
 		}
 
 		var block, parent *dcrutil.Block
+		reorged := false
 		err = m.db.Update(func(dbTx database.Tx) error {
-			// Get the parent of the block, unless it's already cached.
-			if cachedParent == nil && height > 0 {
-				parentHash, err := chain.BlockHashByHeight(int64(height - 1))
-				if err != nil {
-					return err
-				}
-				parent, err = dbFetchBlockByHash(dbTx, parentHash)
-				if err != nil {
-					return err
-				}
-			} else {
-				parent = cachedParent
-			}
-
-			// Load the block for the height since it is required to index
-			// it.
-			hash, err := chain.BlockHashByHeight(int64(height))
-			if err != nil {
-				return err
-			}
 			block, err = dbFetchBlockByHash(dbTx, hash)
 			if err != nil {
 				return err
 			}
-			cachedParent = block
 
-			if interruptRequested(interrupt) {
-				return errInterruptRequested
+			// Get the parent of the block, unless it's already cached.
+			prevHash := &block.MsgBlock().Header.PrevBlock
+			if cachedParent != nil && *cachedParent.Hash() == *prevHash {
+				parent = cachedParent
+			} else {
+				parent, err = dbFetchBlockByHash(dbTx, prevHash)
+				if err != nil {
+					return err
+				}
 			}
 
 			// Connect the block for all indexes that need it.
 			var view *blockchain.UtxoViewpoint
 			for i, indexer := range m.enabledIndexes {
-				// Skip indexes that don't need to be updated with this
-				// block.
-				if indexerHeights[i] >= height {
+				// Skip indexes that don't need to be updated with
+				// this block.
+				if tips[i].height >= height {
 					continue
 				}
 
+				// The main chain was reorganized if the block no
+				// longer extends the tip of the index.
+				if tips[i].hash != *prevHash {
+					reorged = true
+					return nil
+				}
+
 				// When the index requires all of the referenced
 				// txouts and they haven't been loaded yet, they
-				// need to be retrieved from the transaction
-				// index.
+				// need to be retrieved from the transaction index.
 				if view == nil && indexNeedsInputs(indexer) {
-					var errMakeView error
-					view, errMakeView = makeUtxoView(dbTx, block, interrupt)
-					if errMakeView != nil {
-						return errMakeView
+					view, err = makeUtxoView(dbTx, block, interrupt)
+					if err != nil {
+						return err
 					}
 				}
 				err = dbIndexConnectBlock(dbTx, indexer, block,
@@ -617,22 +698,223 @@ func (m *Manager) Init(chain *blockchain.BlockChain, interrupt <-chan struct{})
 				if err != nil {
 					return err
 				}
-
-				indexerHeights[i] = height
 			}
 
 			return nil
 		})
 		if err != nil {
-			return err
+			return false, err
 		}
+		if reorged {
+			return false, nil
+		}
+
+		// Update the tips now that the database transaction which
+		// connected the block to the lagging indexes was committed.
+		for i := range m.enabledIndexes {
+			if tips[i].height < height {
+				tips[i] = indexTip{hash: *hash, height: height}
+				m.setTip(i, hash, height)
+			}
+		}
+		cachedParent = block
 		progressLogger.LogBlockHeight(block.MsgBlock(), parent.MsgBlock())
 	}
 
-	log.Infof("Indexes caught up to height %d", bestHeight)
+	return true, nil
+}
+
+// Sync brings all of the enabled indexes up to date with the current main chain
+// by removing any blocks from their tips that are no longer part of the main
+// chain and then connecting all main chain blocks they do not have yet.  It
+// repeats the process until the indexes are caught up or the provided interrupt
+// channel is closed.
+//
+// This is normally run by the index handler, but it may also be called
+// directly by callers that do not start the manager, such as utilities which
+// only need the indexes to be current once they are done processing blocks.
+func (m *Manager) Sync(interrupt <-chan struct{}) error {
+	if len(m.enabledIndexes) == 0 {
+		return nil
+	}
+
+	for {
+		if err := m.rollbackOrphans(interrupt); err != nil {
+			return err
+		}
+		caughtUp, err := m.catchUp(interrupt)
+		if err != nil {
+			return err
+		}
+		if caughtUp {
+			return nil
+		}
+	}
+}
+
+// indexHandler is the main handler for the index manager.  It catches the
+// indexes up to the main chain when it is first started and then keeps them up
+// to date as the main chain changes.  It must be run as a goroutine.
+func (m *Manager) indexHandler() {
+	defer m.wg.Done()
+
+	for {
+		err := m.Sync(m.quit)
+		if err != nil && err != errInterruptRequested {
+			log.Errorf("Unable to update indexes: %v", err)
+		}
+
+		select {
+		case <-m.syncRequested:
+		case <-m.quit:
+			log.Trace("Index handler done")
+			return
+		}
+	}
+}
+
+// requestSync signals the index handler that the main chain has changed without
+// blocking.
+func (m *Manager) requestSync() {
+	select {
+	case m.syncRequested <- struct{}{}:
+	default:
+	}
+}
+
+// Notify must be invoked with the notifications produced by the chain so the
+// manager is able to update the indexes in response to blocks being connected
+// to and disconnected from the main chain.  It does not block.
+func (m *Manager) Notify(notification *blockchain.Notification) {
+	switch notification.Type {
+	case blockchain.NTBlockConnected, blockchain.NTBlockDisconnected:
+		m.requestSync()
+	}
+}
+
+// Start begins the asynchronous index handler which catches the enabled
+// indexes up to the main chain and keeps them up to date with it.
+func (m *Manager) Start() {
+	// Already started?
+	if atomic.AddInt32(&m.started, 1) != 1 {
+		return
+	}
+
+	if len(m.enabledIndexes) == 0 {
+		return
+	}
+
+	log.Trace("Starting index manager")
+	m.wg.Add(1)
+	go m.indexHandler()
+}
+
+// Stop gracefully shuts down the index manager by stopping the index handler
+// and waiting for it to finish.  Any index updates which are in progress are
+// interrupted and resume from the index tips on the next start.
+func (m *Manager) Stop() error {
+	if atomic.AddInt32(&m.shutdown, 1) != 1 {
+		log.Warnf("Index manager is already in the process of " +
+			"shutting down")
+		return nil
+	}
+
+	log.Infof("Index manager shutting down")
+	close(m.quit)
+	m.wg.Wait()
 	return nil
 }
 
+// IndexSyncStatus describes how far an enabled index has been updated with
+// respect to the current best chain tip.
+type IndexSyncStatus struct {
+	// Name is the human-readable name of the index.
+	Name string
+
+	// Hash and Height identify the most recent block the index has been
+	// updated through.
+	Hash   chainhash.Hash
+	Height int64
+
+	// BestHeight is the height of the current best chain tip.
+	BestHeight int64
+
+	// Synced is whether or not the index has been updated through the
+	// current best chain tip.
+	Synced bool
+}
+
+// SyncStatus returns the sync status of each of the enabled indexes in the
+// order they are updated.
+//
+// This function is safe for concurrent access.
+func (m *Manager) SyncStatus() []IndexSyncStatus {
+	best := m.chain.BestSnapshot()
+	tips := m.fetchTips()
+	statuses := make([]IndexSyncStatus, 0, len(m.enabledIndexes))
+	for i, indexer := range m.enabledIndexes {
+		statuses = append(statuses, IndexSyncStatus{
+			Name:       indexer.Name(),
+			Hash:       tips[i].hash,
+			Height:     tips[i].height,
+			BestHeight: best.Height,
+			Synced:     tips[i].hash == best.Hash,
+		})
+	}
+	return statuses
+}
+
+// LowestTipHeight returns the lowest height through which all of the enabled
+// indexes have been updated.  The height of the current best chain tip is
+// returned when no indexes are enabled.
+//
+// This is part of the blockchain.IndexManager interface.
+//
+// This function is safe for concurrent access.
+func (m *Manager) LowestTipHeight() int64 {
+	lowestHeight := m.chain.BestSnapshot().Height
+	for _, tip := range m.fetchTips() {
+		if tip.height < lowestHeight {
+			lowestHeight = tip.height
+		}
+	}
+	return lowestHeight
+}
+
+// IsSynced returns whether or not the provided index has been updated through
+// the current best chain tip.  Queries against an index that is not synced may
+// return incomplete results, so callers should check this first and report the
+// condition instead.  False is returned for indexes that are not managed by the
+// manager.
+//
+// This function is safe for concurrent access.
+func (m *Manager) IsSynced(indexer Indexer) bool {
+	best := m.chain.BestSnapshot()
+	m.tipsMtx.RLock()
+	defer m.tipsMtx.RUnlock()
+	for i, idx := range m.enabledIndexes {
+		if idx == indexer {
+			return m.tips[i].hash == best.Hash
+		}
+	}
+	return false
+}
+
+// TipHeight returns the height through which the provided index has been
+// updated along with whether or not the index is managed by the manager.
+//
+// This function is safe for concurrent access.
+func (m *Manager) TipHeight(indexer Indexer) (int64, bool) {
+	m.tipsMtx.RLock()
+	defer m.tipsMtx.RUnlock()
+	for i, idx := range m.enabledIndexes {
+		if idx == indexer {
+			return m.tips[i].height, true
+		}
+	}
+	return 0, false
+}
+
 // indexNeedsInputs returns whether or not the index needs access to the txouts
 // referenced by the transaction inputs being indexed.
 func indexNeedsInputs(index Indexer) bool {
@@ -731,50 +1013,20 @@ func makeUtxoView(dbTx database.Tx, block *dcrutil.Block, interrupt <-chan struc
 	return view, nil
 }
 
-// ConnectBlock must be invoked when a block is extending the main chain.  It
-// keeps track of the state of each index it is managing, performs some sanity
-// checks, and invokes each indexer.
-//
-// This is part of the blockchain.IndexManager interface.
-func (m *Manager) ConnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, view *blockchain.UtxoViewpoint) error {
-	// Call each of the currently active optional indexes with the block
-	// being connected so they can update accordingly.
-	for _, index := range m.enabledIndexes {
-		err := dbIndexConnectBlock(dbTx, index, block, parent, view)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// DisconnectBlock must be invoked when a block is being disconnected from the
-// end of the main chain.  It keeps track of the state of each index it is
-// managing, performs some sanity checks, and invokes each indexer to remove
-// the index entries associated with the block.
-//
-// This is part of the blockchain.IndexManager interface.
-func (m *Manager) DisconnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, view *blockchain.UtxoViewpoint) error {
-	// Call each of the currently active optional indexes with the block
-	// being disconnected so they can update accordingly.
-	for _, index := range m.enabledIndexes {
-		err := dbIndexDisconnectBlock(dbTx, index, block, parent, view)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // NewManager returns a new index manager with the provided indexes enabled.
 //
 // The manager returned satisfies the blockchain.IndexManager interface and thus
-// cleanly plugs into the normal blockchain processing path.
+// cleanly plugs into chain initialization.  The caller must start the manager
+// and forward chain notifications to it via Notify in order for the indexes to
+// be kept up to date.
 func NewManager(db database.DB, enabledIndexes []Indexer, params *chaincfg.Params) *Manager {
 	return &Manager{
 		db:             db,
 		enabledIndexes: enabledIndexes,
 		params:         params,
+		tips:           make([]indexTip, len(enabledIndexes)),
+		syncRequested:  make(chan struct{}, 1),
+		quit:           make(chan struct{}),
 	}
 }
 
@@ -48,6 +48,19 @@ var (
 	// unspent transaction output set.
 	UtxoSetBucketName = []byte("utxoset")
 
+	// UtxoSetStateKeyName is the name of the db key used to store the
+	// statistics and rolling hash of the unspent transaction output set.
+	UtxoSetStateKeyName = []byte("utxosetstate")
+
+	// UtxoSnapshotLoadKeyName is the name of the db key used to mark a
+	// database that is in the process of being initialized from a utxo
+	// snapshot.
+	UtxoSnapshotLoadKeyName = []byte("utxosnapshotload")
+
+	// UtxoSnapshotBaseKeyName is the name of the db key used to store the
+	// utxo snapshot a database was initialized from.
+	UtxoSnapshotBaseKeyName = []byte("utxosnapshotbase")
+
 	// BlockIndexBucketName is the name of the db bucket used to house the
 	// block index which consists of metadata for all known blocks both in
 	// the main chain and on side chains.
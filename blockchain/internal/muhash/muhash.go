@@ -0,0 +1,123 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package muhash implements a rolling hash of a set of byte strings that can
+// be updated incrementally as elements are added to and removed from the set.
+//
+// Each element is hashed to a number modulo the 3072-bit prime 2^3072 - 1103717
+// and the set is represented by the product of the numbers for all of its
+// elements.  Adding an element multiplies the state by its number and removing
+// an element divides the state by it, so the final hash only depends on the
+// contents of the set and not the order of the operations.
+package muhash
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// ElementSize is the size in bytes of the serialized numbers elements
+	// are hashed to and of the serialized state.
+	ElementSize = 384
+
+	// primeOffset is the offset of the prime used as the modulus from
+	// 2^3072.
+	primeOffset = 1103717
+)
+
+// prime is the prime modulus 2^3072 - 1103717.
+var prime = func() *big.Int {
+	p := new(big.Int).Lsh(big.NewInt(1), ElementSize*8)
+	return p.Sub(p, big.NewInt(primeOffset))
+}()
+
+// MuHash is the rolling hash of a set.  The zero value is not usable; use New
+// to create instances.
+//
+// Division is deferred by tracking the elements that are removed separately
+// so that each update only requires a modular multiplication.
+type MuHash struct {
+	numerator   *big.Int
+	denominator *big.Int
+}
+
+// New returns a MuHash for the empty set.
+func New() *MuHash {
+	return &MuHash{
+		numerator:   big.NewInt(1),
+		denominator: big.NewInt(1),
+	}
+}
+
+// elementNumber deterministically maps the passed data to a number modulo the
+// prime by expanding its hash to ElementSize bytes.
+func elementNumber(data []byte) *big.Int {
+	seed := chainhash.HashB(data)
+	var expanded [ElementSize]byte
+	var buf [chainhash.HashSize + 1]byte
+	copy(buf[:], seed)
+	for i := 0; i*chainhash.HashSize < ElementSize; i++ {
+		buf[chainhash.HashSize] = byte(i)
+		copy(expanded[i*chainhash.HashSize:], chainhash.HashB(buf[:]))
+	}
+	n := new(big.Int).SetBytes(expanded[:])
+	return n.Mod(n, prime)
+}
+
+// Add adds the passed data to the set.
+func (h *MuHash) Add(data []byte) {
+	h.numerator.Mul(h.numerator, elementNumber(data))
+	h.numerator.Mod(h.numerator, prime)
+}
+
+// Remove removes the passed data from the set.  The data must have previously
+// been added.
+func (h *MuHash) Remove(data []byte) {
+	h.denominator.Mul(h.denominator, elementNumber(data))
+	h.denominator.Mod(h.denominator, prime)
+}
+
+// normalize performs the deferred division so the denominator is one.
+func (h *MuHash) normalize() {
+	if h.denominator.Cmp(big.NewInt(1)) == 0 {
+		return
+	}
+	inverse := new(big.Int).ModInverse(h.denominator, prime)
+	h.numerator.Mul(h.numerator, inverse)
+	h.numerator.Mod(h.numerator, prime)
+	h.denominator.SetInt64(1)
+}
+
+// Bytes returns the serialized state of the set.  It can be restored with
+// SetBytes.
+func (h *MuHash) Bytes() []byte {
+	h.normalize()
+	var serialized [ElementSize]byte
+	b := h.numerator.Bytes()
+	copy(serialized[ElementSize-len(b):], b)
+	return serialized[:]
+}
+
+// SetBytes restores the state of the set from the serialization returned by
+// Bytes.
+func (h *MuHash) SetBytes(serialized []byte) error {
+	if len(serialized) != ElementSize {
+		return errors.New("invalid serialized muhash size")
+	}
+	n := new(big.Int).SetBytes(serialized)
+	if n.Sign() == 0 || n.Cmp(prime) >= 0 {
+		return errors.New("serialized muhash is out of range")
+	}
+	h.numerator = n
+	h.denominator = big.NewInt(1)
+	return nil
+}
+
+// Hash returns the hash of the set.
+func (h *MuHash) Hash() chainhash.Hash {
+	return chainhash.HashH(h.Bytes())
+}
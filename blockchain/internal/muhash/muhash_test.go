@@ -0,0 +1,165 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package muhash
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// TestKnownAnswers ensures the hashes of various sets match the expected
+// values.  The serialized state of the empty set is the number one and the
+// hashes of the other sets guard against unintended changes to the way the
+// elements are mapped to numbers.
+func TestKnownAnswers(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []string
+		want     string
+	}{{
+		name:     "empty set",
+		elements: nil,
+		want:     "6519f8176fb48e76c253244bb4f71f3343e4d62f7bca571f055efe57f42a598b",
+	}, {
+		name:     "empty element",
+		elements: []string{""},
+		want:     "3babed2ee688b1a5d69c6f844eb3b7097c033a536e446692a6a34afb8ba451ae",
+	}, {
+		name:     "single element",
+		elements: []string{"abc"},
+		want:     "2185e0ca04fd5d354ac55140a5fc2d6a19a7a540ecaa66f55896ced6e130835d",
+	}, {
+		name:     "two elements",
+		elements: []string{"abc", "def"},
+		want:     "71807c65b1e7373ebfa8a07bd62f58b810f057dbacb719d31a2b17b2acb6d691",
+	}, {
+		name:     "duplicate element",
+		elements: []string{"a", "b", "c", "a"},
+		want:     "89a11a278cadce386097c46496634a8f10dde48a07d7b29f71918b7de1584682",
+	}}
+
+	for _, test := range tests {
+		h := New()
+		for _, element := range test.elements {
+			h.Add([]byte(element))
+		}
+		if got := h.Hash().String(); got != test.want {
+			t.Errorf("%s: unexpected hash - got %s, want %s", test.name,
+				got, test.want)
+		}
+	}
+
+	// The serialized state of the empty set is the number one.
+	want := make([]byte, ElementSize)
+	want[ElementSize-1] = 1
+	if got := New().Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("unexpected serialized empty set - got %x, want %x", got,
+			want)
+	}
+}
+
+// TestAddRemove ensures removing elements undoes adding them regardless of
+// whether the deferred division has been performed in between.
+func TestAddRemove(t *testing.T) {
+	empty := New().Hash()
+
+	h := New()
+	h.Add([]byte("abc"))
+	h.Remove([]byte("abc"))
+	if got := h.Hash(); got != empty {
+		t.Fatalf("add and remove: got %v, want empty set hash %v", got,
+			empty)
+	}
+
+	// Remove an element after the state has been normalized.
+	h.Add([]byte("abc"))
+	h.Add([]byte("def"))
+	h.Bytes()
+	h.Remove([]byte("abc"))
+	single := New()
+	single.Add([]byte("def"))
+	if got, want := h.Hash(), single.Hash(); got != want {
+		t.Fatalf("remove after normalize: got %v, want %v", got, want)
+	}
+
+	// Removing an element before it is added results in the same set once
+	// it is added.
+	h = New()
+	h.Remove([]byte("abc"))
+	h.Add([]byte("def"))
+	h.Add([]byte("abc"))
+	if got, want := h.Hash(), single.Hash(); got != want {
+		t.Fatalf("remove before add: got %v, want %v", got, want)
+	}
+}
+
+// TestOrderIndependence ensures the hash of a set does not depend on the order
+// its elements are added and removed in.
+func TestOrderIndependence(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	elements := make([][]byte, 50)
+	for i := range elements {
+		elements[i] = make([]byte, rng.Intn(100))
+		rng.Read(elements[i])
+	}
+
+	// Calculate the hash of the set with the elements added in order.
+	want := New()
+	for _, element := range elements {
+		want.Add(element)
+	}
+
+	for i := 0; i < 10; i++ {
+		// Add the elements along with some additional elements that are
+		// later removed, all in a random order.
+		extra := [][]byte{[]byte("x"), []byte("y"), []byte("z")}
+		h := New()
+		for _, j := range rng.Perm(len(elements)) {
+			h.Add(elements[j])
+			if j < len(extra) {
+				h.Add(extra[j])
+			}
+		}
+		for _, j := range rng.Perm(len(extra)) {
+			h.Remove(extra[j])
+		}
+		if got := h.Hash(); got != want.Hash() {
+			t.Fatalf("permutation %d: got %v, want %v", i, got,
+				want.Hash())
+		}
+	}
+}
+
+// TestSetBytes ensures the serialized state round trips and invalid states are
+// rejected.
+func TestSetBytes(t *testing.T) {
+	h := New()
+	h.Add([]byte("abc"))
+	h.Remove([]byte("def"))
+
+	var restored MuHash
+	if err := restored.SetBytes(h.Bytes()); err != nil {
+		t.Fatalf("SetBytes: unexpected error %v", err)
+	}
+	restored.Add([]byte("def"))
+	want := New()
+	want.Add([]byte("abc"))
+	if got := restored.Hash(); got != want.Hash() {
+		t.Fatalf("restored hash: got %v, want %v", got, want.Hash())
+	}
+
+	invalid := [][]byte{
+		nil,
+		make([]byte, ElementSize-1),
+		make([]byte, ElementSize),
+		prime.Bytes(),
+	}
+	for i, serialized := range invalid {
+		if err := restored.SetBytes(serialized); err == nil {
+			t.Errorf("#%d: SetBytes did not reject invalid state", i)
+		}
+	}
+}
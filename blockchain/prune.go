@@ -2,14 +2,26 @@ package blockchain
 
 import (
 	"time"
+
+	"github.com/decred/dcrd/database"
 )
 
-// pruningIntervalInMinutes is the interval in which to prune the blockchain's
-// nodes and restore memory to the garbage collector.
-const pruningIntervalInMinutes = 5
+const (
+	// pruningIntervalInMinutes is the interval in which to prune the
+	// blockchain's nodes and restore memory to the garbage collector.
+	pruningIntervalInMinutes = 5
+
+	// minBlocksToKeep is the minimum number of the most recent main chain
+	// blocks for which the block data is retained when pruning block data
+	// is enabled.  Disconnecting blocks during a reorganization and
+	// regenerating stake nodes both require the block data, so this is the
+	// same as the minimum number of nodes kept in memory for validation.
+	minBlocksToKeep = minMemoryNodes
+)
 
 // chainPruner is used to occasionally prune the blockchain of old nodes that
-// can be freed to the garbage collector.
+// can be freed to the garbage collector.  It is also responsible for deleting
+// the data of old blocks from the database when pruning is enabled.
 type chainPruner struct {
 	chain              *BlockChain
 	lastNodeInsertTime time.Time
@@ -25,9 +37,15 @@ func newChainPruner(chain *BlockChain) *chainPruner {
 
 // pruneChainIfNeeded checks the current time versus the time of the last pruning.
 // If the blockchain hasn't been pruned in this time, it initiates a new pruning.
+// It also deletes the data of old blocks from the database as needed when
+// pruning is enabled.
 //
 // pruneChainIfNeeded must be called with the chainLock held for writes.
 func (c *chainPruner) pruneChainIfNeeded() {
+	if err := c.chain.pruneBlockData(); err != nil {
+		log.Warnf("Unable to prune block data: %v", err)
+	}
+
 	now := time.Now()
 	duration := now.Sub(c.lastNodeInsertTime)
 	if duration < time.Minute*pruningIntervalInMinutes {
@@ -37,3 +55,43 @@ func (c *chainPruner) pruneChainIfNeeded() {
 	c.lastNodeInsertTime = now
 	c.chain.pruneStakeNodes()
 }
+
+// pruneBlockData deletes the data of the oldest blocks from the database until
+// the stored block data is under the configured prune target.  The data for
+// the most recent minBlocksToKeep main chain blocks, along with any blocks the
+// optional indexes have not processed yet, is always retained.  It has no
+// effect when pruning is not enabled.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) pruneBlockData() error {
+	if b.pruneTarget == 0 {
+		return nil
+	}
+
+	// Determine the oldest main chain block that must be retained.
+	keepHeight := b.bestChain.Tip().height - minBlocksToKeep + 1
+	if b.indexManager != nil {
+		indexHeight := b.indexManager.LowestTipHeight() + 1
+		if indexHeight < keepHeight {
+			keepHeight = indexHeight
+		}
+	}
+	if keepHeight <= 0 {
+		return nil
+	}
+	keepNode := b.bestChain.NodeByHeight(keepHeight)
+	if keepNode == nil {
+		return nil
+	}
+
+	pruner := b.db.(database.Pruner)
+	freed, err := pruner.PruneBlocks(b.pruneTarget, &keepNode.hash)
+	if err != nil {
+		return err
+	}
+	if freed > 0 {
+		log.Infof("Pruned %d MiB of old block data (retaining blocks "+
+			"from height %d)", freed/(1024*1024), keepHeight)
+	}
+	return nil
+}
@@ -338,6 +338,12 @@ func DbFetchBestState(dbTx database.Tx) (BestChainState, error) {
 	return deserializeBestChainState(v)
 }
 
+// SerializeBestChainState returns the serialization of the passed best chain
+// state as it is stored in the database.
+func SerializeBestChainState(state BestChainState) []byte {
+	return serializeBestChainState(state)
+}
+
 // DbPutBestState uses an existing database transaction to update the best chain
 // state with the given parameters.
 func DbPutBestState(dbTx database.Tx, bcs BestChainState) error {
@@ -618,17 +624,22 @@ func DbDeleteTicket(dbTx database.Tx, ticketBucket []byte, hash *chainhash.Hash)
 	return bucket.Delete(hash[:])
 }
 
+// SerializeTicket returns the serialization of a ticket with the passed height
+// and flags as it is stored in the ticket database buckets.
+func SerializeTicket(height uint32, missed, revoked, spent, expired bool) []byte {
+	v := make([]byte, 5)
+	dbnamespace.ByteOrder.PutUint32(v, height)
+	v[4] = undoBitFlagsToByte(missed, revoked, spent, expired)
+	return v
+}
+
 // DbPutTicket inserts a ticket into one of the ticket database buckets.
 func DbPutTicket(dbTx database.Tx, ticketBucket []byte, hash *chainhash.Hash,
 	height uint32, missed, revoked, spent, expired bool) error {
 	meta := dbTx.Metadata()
 	bucket := meta.Bucket(ticketBucket)
-	k := hash[:]
-	v := make([]byte, 5)
-	dbnamespace.ByteOrder.PutUint32(v, height)
-	v[4] = undoBitFlagsToByte(missed, revoked, spent, expired)
-
-	return bucket.Put(k, v)
+	v := SerializeTicket(height, missed, revoked, spent, expired)
+	return bucket.Put(hash[:], v)
 }
 
 // DbLoadAllTickets loads all the live tickets from the database into a treap.
@@ -0,0 +1,177 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stake
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/blockchain/stake/internal/dbnamespace"
+	"github.com/decred/dcrd/blockchain/stake/internal/ticketdb"
+	"github.com/decred/dcrd/blockchain/stake/internal/tickettreap"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database"
+	"github.com/decred/dcrd/wire"
+)
+
+// maxSnapshotRecordSize is the maximum size of any single key or value that
+// is read from a ticket database snapshot.
+const maxSnapshotRecordSize = wire.MaxBlockPayload
+
+// snapshotBuckets are the names of the ticket database buckets the live,
+// missed, and revoked tickets of snapshots are loaded into.
+var snapshotBuckets = [][]byte{
+	dbnamespace.LiveTicketsBucketName,
+	dbnamespace.MissedTicketsBucketName,
+	dbnamespace.RevokedTicketsBucketName,
+}
+
+// snapshotHeightBuckets are the names of the ticket database buckets keyed by
+// block height that are written to and loaded from snapshots for the heights
+// covered by the snapshot.
+var snapshotHeightBuckets = [][]byte{
+	dbnamespace.StakeBlockUndoDataBucketName,
+	dbnamespace.TicketsInBlockBucketName,
+}
+
+// writeSnapshotRecords writes the provided key/value pairs to the writer
+// prefixed by the number of pairs.
+func writeSnapshotRecords(w io.Writer, records [][2][]byte) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(records)))
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := wire.WriteVarBytes(w, 0, record[0]); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, record[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadSnapshotRecords reads key/value pairs written by writeSnapshotRecords
+// from the reader and stores them in the provided bucket.
+func loadSnapshotRecords(r io.Reader, bucket database.Bucket) error {
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < count; i++ {
+		key, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"ticket db key")
+		if err != nil {
+			return err
+		}
+		value, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"ticket db value")
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteSnapshot serializes the state of the ticket database as of the passed
+// node, which is for the main chain block with the passed hash, to the provided
+// writer so it can later be restored with LoadSnapshot.  This includes the best
+// state, all live, missed, and revoked tickets, and the undo data and new
+// tickets for every block from oldestHeight through the node.  The latter are
+// required to disconnect blocks and to regenerate stake nodes for the blocks in
+// that range.
+//
+// The tickets are written from the node rather than the database, so the node
+// does not have to be the best node.  This allows snapshots of blocks prior to
+// the current best chain tip.
+func WriteSnapshot(dbTx database.Tx, w io.Writer, node *Node, hash chainhash.Hash, oldestHeight uint32) error {
+	if oldestHeight > node.height {
+		return fmt.Errorf("snapshot height %d is after the stake node "+
+			"height %d", oldestHeight, node.height)
+	}
+	state := ticketdb.SerializeBestChainState(bestChainState(node, hash))
+	if err := wire.WriteVarBytes(w, 0, state); err != nil {
+		return err
+	}
+
+	// Write all of the tickets in the same form as they are stored in the
+	// ticket database buckets.
+	treaps := []*tickettreap.Immutable{node.liveTickets, node.missedTickets,
+		node.revokedTickets}
+	for _, treap := range treaps {
+		records := make([][2][]byte, 0, treap.Len())
+		treap.ForEach(func(k tickettreap.Key, v *tickettreap.Value) bool {
+			key := make([]byte, chainhash.HashSize)
+			copy(key, k[:])
+			value := ticketdb.SerializeTicket(v.Height, v.Missed,
+				v.Revoked, v.Spent, v.Expired)
+			records = append(records, [2][]byte{key, value})
+			return true
+		})
+		if err := writeSnapshotRecords(w, records); err != nil {
+			return err
+		}
+	}
+
+	// Write the undo data and new tickets for the blocks covered by the
+	// snapshot.
+	meta := dbTx.Metadata()
+	for _, bucketName := range snapshotHeightBuckets {
+		var records [][2][]byte
+		bucket := meta.Bucket(bucketName)
+		for height := oldestHeight; height <= node.height; height++ {
+			k := make([]byte, 4)
+			dbnamespace.ByteOrder.PutUint32(k, height)
+			v := bucket.Get(k)
+			if v == nil {
+				return stakeRuleError(ErrDatabaseCorrupt,
+					fmt.Sprintf("missing %s data for height %d",
+						bucketName, height))
+			}
+			records = append(records, [2][]byte{k, v})
+		}
+		if err := writeSnapshotRecords(w, records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadSnapshot creates the ticket database and populates it with the state
+// read from the provided reader, which must have been written by WriteSnapshot.
+// The ticket database must not already exist.
+func LoadSnapshot(dbTx database.Tx, r io.Reader) error {
+	if err := ticketdb.DbCreate(dbTx); err != nil {
+		return err
+	}
+
+	meta := dbTx.Metadata()
+	state, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+		"stake best state")
+	if err != nil {
+		return err
+	}
+	if err := meta.Put(dbnamespace.StakeChainStateKeyName, state); err != nil {
+		return err
+	}
+
+	for _, bucketName := range snapshotBuckets {
+		if err := loadSnapshotRecords(r, meta.Bucket(bucketName)); err != nil {
+			return err
+		}
+	}
+	for _, bucketName := range snapshotHeightBuckets {
+		if err := loadSnapshotRecords(r, meta.Bucket(bucketName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
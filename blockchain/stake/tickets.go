@@ -915,6 +915,12 @@ func WriteConnectedBestNode(dbTx database.Tx, node *Node, hash chainhash.Hash) e
 	}
 
 	// Write the new best state to the database.
+	return ticketdb.DbPutBestState(dbTx, bestChainState(node, hash))
+}
+
+// bestChainState returns the best chain state of the ticket database when the
+// passed node, which is for the block with the passed hash, is the best node.
+func bestChainState(node *Node, hash chainhash.Hash) ticketdb.BestChainState {
 	nextWinners := make([]chainhash.Hash, int(node.params.TicketsPerBlock))
 	if node.height >= uint32(node.params.StakeValidationHeight-1) {
 		for i := range nextWinners {
@@ -922,7 +928,7 @@ func WriteConnectedBestNode(dbTx database.Tx, node *Node, hash chainhash.Hash) e
 		}
 	}
 
-	return ticketdb.DbPutBestState(dbTx, ticketdb.BestChainState{
+	return ticketdb.BestChainState{
 		Hash:        hash,
 		Height:      node.height,
 		Live:        uint32(node.liveTickets.Len()),
@@ -930,7 +936,7 @@ func WriteConnectedBestNode(dbTx database.Tx, node *Node, hash chainhash.Hash) e
 		Revoked:     uint64(node.revokedTickets.Len()),
 		PerBlock:    node.params.TicketsPerBlock,
 		NextWinners: nextWinners,
-	})
+	}
 }
 
 // WriteDisconnectedBestNode writes the newly connected best node to the database
@@ -1046,20 +1052,5 @@ func WriteDisconnectedBestNode(dbTx database.Tx, node *Node, hash chainhash.Hash
 	}
 
 	// Write the new best state to the database.
-	nextWinners := make([]chainhash.Hash, int(node.params.TicketsPerBlock))
-	if node.height >= uint32(node.params.StakeValidationHeight-1) {
-		for i := range nextWinners {
-			nextWinners[i] = node.nextWinners[i]
-		}
-	}
-
-	return ticketdb.DbPutBestState(dbTx, ticketdb.BestChainState{
-		Hash:        hash,
-		Height:      node.height,
-		Live:        uint32(node.liveTickets.Len()),
-		Missed:      uint64(node.missedTickets.Len()),
-		Revoked:     uint64(node.revokedTickets.Len()),
-		PerBlock:    node.params.TicketsPerBlock,
-		NextWinners: nextWinners,
-	})
+	return ticketdb.DbPutBestState(dbTx, bestChainState(node, hash))
 }
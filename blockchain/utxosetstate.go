@@ -0,0 +1,314 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/internal/dbnamespace"
+	"github.com/decred/dcrd/blockchain/internal/muhash"
+	"github.com/decred/dcrd/blockchain/stake"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database"
+)
+
+// -----------------------------------------------------------------------------
+// The utxo set state consists of statistics about the utxo set along with a
+// rolling hash of all of the unspent outputs it contains.  It is updated
+// whenever the utxo set is updated, in the same database transaction, so that
+// it always describes the utxo set as of the current best chain tip.
+//
+// The serialized format is:
+//
+//   <num entries><num outputs><total amount><serialized size><muhash state>
+//
+//   Field             Type     Size
+//   num entries       uint64   8
+//   num outputs       uint64   8
+//   total amount      uint64   8
+//   serialized size   uint64   8
+//   muhash state      []byte   muhash.ElementSize
+//
+// The rolling hash commits to every unspent output individually, so it does
+// not depend on how the outputs are grouped into utxo entries.  Each output is
+// hashed as:
+//
+//   <tx hash><output index><tx version><block height><block index><flags>
+//   <compressed txout><stake extra>
+//
+// where all integers are VLQ encoded, the output is compressed the same way as
+// in the utxo set, and the stake extra data is only present for ticket
+// purchases.
+// -----------------------------------------------------------------------------
+
+// utxoSetStateSize is the size of a serialized utxo set state.
+const utxoSetStateSize = 32 + muhash.ElementSize
+
+// utxoSetState houses statistics about the utxo set along with a rolling hash
+// of its contents.
+type utxoSetState struct {
+	numEntries     uint64
+	numOutputs     uint64
+	totalAmount    int64
+	serializedSize uint64
+	hash           *muhash.MuHash
+}
+
+// newUtxoSetState returns the state of an empty utxo set.
+func newUtxoSetState() *utxoSetState {
+	return &utxoSetState{hash: muhash.New()}
+}
+
+// utxoOutputElement returns the data committed to by the rolling hash for the
+// given output of the passed utxo entry.
+func utxoOutputElement(txHash *chainhash.Hash, outputIndex uint32, entry *UtxoEntry, out *utxoOutput) []byte {
+	flags := encodeFlags(entry.isCoinBase, entry.hasExpiry, entry.txType,
+		false)
+	size := chainhash.HashSize + serializeSizeVLQ(uint64(outputIndex)) +
+		serializeSizeVLQ(uint64(entry.txVersion)) +
+		serializeSizeVLQ(uint64(entry.height)) +
+		serializeSizeVLQ(uint64(entry.index)) +
+		serializeSizeVLQ(uint64(flags)) +
+		compressedTxOutSize(uint64(out.amount), out.scriptVersion,
+			out.pkScript, currentCompressionVersion, out.compressed, true)
+	if entry.txType == stake.TxTypeSStx {
+		size += len(entry.stakeExtra)
+	}
+
+	element := make([]byte, size)
+	offset := copy(element, txHash[:])
+	offset += putVLQ(element[offset:], uint64(outputIndex))
+	offset += putVLQ(element[offset:], uint64(entry.txVersion))
+	offset += putVLQ(element[offset:], uint64(entry.height))
+	offset += putVLQ(element[offset:], uint64(entry.index))
+	offset += putVLQ(element[offset:], uint64(flags))
+	offset += putCompressedTxOut(element[offset:], uint64(out.amount),
+		out.scriptVersion, out.pkScript, currentCompressionVersion,
+		out.compressed, true)
+	if entry.txType == stake.TxTypeSStx {
+		copy(element[offset:], entry.stakeExtra)
+	}
+	return element
+}
+
+// unspentOutputElements returns the data committed to by the rolling hash for
+// each unspent output of the passed serialized utxo entry keyed by output index
+// along with the total amount of those outputs.
+func unspentOutputElements(txHash *chainhash.Hash, serialized []byte) (map[uint32][]byte, int64, error) {
+	entry, err := deserializeUtxoEntry(serialized)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	elements := make(map[uint32][]byte, len(entry.sparseOutputs))
+	var amount int64
+	for outputIndex, out := range entry.sparseOutputs {
+		if out.spent {
+			continue
+		}
+		elements[outputIndex] = utxoOutputElement(txHash, outputIndex,
+			entry, out)
+		amount += out.amount
+	}
+	return elements, amount, nil
+}
+
+// update updates the state for the replacement of the existing serialized utxo
+// entry for the passed transaction hash with the passed serialized entry.  The
+// existing entry is nil when the entry is created and the new one is nil when
+// it is removed.
+//
+// Only the outputs that differ between the two entries are removed from and
+// added to the rolling hash, so spending a single output of an entry with many
+// outputs only requires a single update of the hash.
+func (s *utxoSetState) update(txHash *chainhash.Hash, existing, serialized []byte) error {
+	var spent, created map[uint32][]byte
+	var spentAmount, createdAmount int64
+	if existing != nil {
+		var err error
+		spent, spentAmount, err = unspentOutputElements(txHash, existing)
+		if err != nil {
+			return err
+		}
+		s.numEntries--
+		s.serializedSize -= uint64(chainhash.HashSize + len(existing))
+	}
+	if serialized != nil {
+		var err error
+		created, createdAmount, err = unspentOutputElements(txHash,
+			serialized)
+		if err != nil {
+			return err
+		}
+		s.numEntries++
+		s.serializedSize += uint64(chainhash.HashSize + len(serialized))
+	}
+
+	// Outputs that are unchanged in both entries are neither spent nor
+	// created.
+	for outputIndex, element := range spent {
+		if bytes.Equal(element, created[outputIndex]) {
+			delete(spent, outputIndex)
+			delete(created, outputIndex)
+		}
+	}
+
+	s.numOutputs += uint64(len(created)) - uint64(len(spent))
+	s.totalAmount += createdAmount - spentAmount
+	for _, element := range spent {
+		s.hash.Remove(element)
+	}
+	for _, element := range created {
+		s.hash.Add(element)
+	}
+	return nil
+}
+
+// serializeUtxoSetState returns the serialization of the passed utxo set
+// state according to the format described above.
+func serializeUtxoSetState(s *utxoSetState) []byte {
+	serialized := make([]byte, utxoSetStateSize)
+	dbnamespace.ByteOrder.PutUint64(serialized[0:8], s.numEntries)
+	dbnamespace.ByteOrder.PutUint64(serialized[8:16], s.numOutputs)
+	dbnamespace.ByteOrder.PutUint64(serialized[16:24], uint64(s.totalAmount))
+	dbnamespace.ByteOrder.PutUint64(serialized[24:32], s.serializedSize)
+	copy(serialized[32:], s.hash.Bytes())
+	return serialized
+}
+
+// deserializeUtxoSetState deserializes the passed serialized utxo set state
+// according to the format described above.
+func deserializeUtxoSetState(serialized []byte) (*utxoSetState, error) {
+	if len(serialized) != utxoSetStateSize {
+		return nil, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: "corrupt utxo set state: unexpected " +
+				"size",
+		}
+	}
+
+	s := newUtxoSetState()
+	s.numEntries = dbnamespace.ByteOrder.Uint64(serialized[0:8])
+	s.numOutputs = dbnamespace.ByteOrder.Uint64(serialized[8:16])
+	s.totalAmount = int64(dbnamespace.ByteOrder.Uint64(serialized[16:24]))
+	s.serializedSize = dbnamespace.ByteOrder.Uint64(serialized[24:32])
+	if err := s.hash.SetBytes(serialized[32:]); err != nil {
+		return nil, database.Error{
+			ErrorCode:   database.ErrCorruption,
+			Description: "corrupt utxo set state: " + err.Error(),
+		}
+	}
+	return s, nil
+}
+
+// dbFetchUtxoSetState uses an existing database transaction to load the utxo
+// set state.  It returns nil when the state is not tracked in the database.
+func dbFetchUtxoSetState(dbTx database.Tx) (*utxoSetState, error) {
+	serialized := dbTx.Metadata().Get(dbnamespace.UtxoSetStateKeyName)
+	if serialized == nil {
+		return nil, nil
+	}
+	return deserializeUtxoSetState(serialized)
+}
+
+// dbPutUtxoSetState uses an existing database transaction to store the passed
+// utxo set state.
+func dbPutUtxoSetState(dbTx database.Tx, s *utxoSetState) error {
+	return dbTx.Metadata().Put(dbnamespace.UtxoSetStateKeyName,
+		serializeUtxoSetState(s))
+}
+
+// dbCalcUtxoSetState uses an existing database transaction to calculate the
+// utxo set state from scratch by iterating the entire utxo set.
+func dbCalcUtxoSetState(dbTx database.Tx) (*utxoSetState, error) {
+	s := newUtxoSetState()
+	cursor := dbTx.Metadata().Bucket(dbnamespace.UtxoSetBucketName).Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		var txHash chainhash.Hash
+		copy(txHash[:], cursor.Key())
+		if err := s.update(&txHash, nil, cursor.Value()); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// initUtxoSetState calculates and stores the utxo set state when the database
+// does not track it yet, such as when upgrading from a version that did not
+// or after loading a utxo snapshot.
+func (b *BlockChain) initUtxoSetState() error {
+	return b.db.Update(func(dbTx database.Tx) error {
+		if dbTx.Metadata().Get(dbnamespace.UtxoSetStateKeyName) != nil {
+			return nil
+		}
+
+		log.Infof("Calculating the utxo set state.  This might take a " +
+			"while...")
+		start := time.Now()
+		s, err := dbCalcUtxoSetState(dbTx)
+		if err != nil {
+			return err
+		}
+		log.Infof("Calculated the state of %d utxos in %v", s.numOutputs,
+			time.Since(start).Round(time.Millisecond))
+		return dbPutUtxoSetState(dbTx, s)
+	})
+}
+
+// UtxoStats houses statistics about the unspent transaction output set as of
+// the block it describes.
+type UtxoStats struct {
+	BestHash       chainhash.Hash
+	BestHeight     int64
+	Utxos          int64
+	Transactions   int64
+	Size           int64
+	Total          int64
+	SerializedHash chainhash.Hash
+}
+
+// FetchUtxoStats returns statistics about the unspent transaction output set
+// as of the current best chain tip, including a hash of its contents that is
+// independent of how it is stored.  The statistics are maintained as blocks are
+// connected and disconnected, so this is cheap at every height.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) FetchUtxoStats() (*UtxoStats, error) {
+	var stats UtxoStats
+	err := b.db.View(func(dbTx database.Tx) error {
+		serializedState := dbTx.Metadata().Get(dbnamespace.ChainStateKeyName)
+		if serializedState == nil {
+			return AssertError("FetchUtxoStats: missing chain state")
+		}
+		best, err := deserializeBestChainState(serializedState)
+		if err != nil {
+			return err
+		}
+		s, err := dbFetchUtxoSetState(dbTx)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return AssertError("FetchUtxoStats: missing utxo set state")
+		}
+
+		stats = UtxoStats{
+			BestHash:       best.hash,
+			BestHeight:     int64(best.height),
+			Utxos:          int64(s.numOutputs),
+			Transactions:   int64(s.numEntries),
+			Size:           int64(s.serializedSize),
+			Total:          s.totalAmount,
+			SerializedHash: s.hash.Hash(),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,905 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dchest/blake256"
+	"github.com/decred/dcrd/blockchain/internal/dbnamespace"
+	"github.com/decred/dcrd/blockchain/stake"
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// A utxo snapshot contains everything needed to initialize a new database to
+// the state of the main chain as of a given block without downloading and
+// validating the blocks before it.
+//
+// The serialized format is:
+//
+//   <magic><version><network><height><hash><best state><block index>
+//   <blocks><ticket db><utxo set><snapshot hash>
+//
+//   Field           Type                 Size
+//   magic           uint32               4
+//   version         uint32               4
+//   network         uint32               4
+//   height          uint32               4
+//   hash            chainhash.Hash       chainhash.HashSize
+//   best state      []byte               variable
+//   block index     varint + [][]byte    variable
+//   blocks          varint + [][]byte    variable
+//   ticket db       stake snapshot       variable
+//   utxo set        varint + [][]byte    variable
+//   snapshot hash   chainhash.Hash       chainhash.HashSize
+//
+// All integers in the header are encoded in little endian and all byte slices
+// are prefixed with their length as a varint.
+//
+// The block index contains the block index entry of every main chain block
+// starting with the snapshot block and walking backwards to the genesis block.
+//
+// The blocks section contains the full block followed by the spend journal
+// entry for each of the most recent minBlocksToKeep main chain blocks, again
+// starting with the snapshot block.  These are required to disconnect blocks
+// during a reorganization and to regenerate stake nodes, so only the data for
+// these blocks is available on nodes that are bootstrapped from a snapshot.
+//
+// The ticket db section is written by stake.WriteSnapshot.
+//
+// The utxo set contains each key and value of the utxo set bucket.
+//
+// The snapshot hash is the BLAKE-256 hash of all of the preceding data.  It is
+// what the network parameters commit to.
+//
+// Every section describes the state of the main chain as of the snapshot
+// block and is written in a canonical order, so all nodes produce the same
+// snapshot for a given block regardless of their current best chain tip.
+//
+// The history prior to the snapshot block is not validated by nodes that are
+// bootstrapped from a snapshot when it is loaded.  Such nodes rely on the
+// snapshot hash committed to by the network parameters, which is reviewed along
+// with the rest of the source code, in the same way they rely on checkpoints.
+// The snapshot a database was initialized from is recorded along with whether
+// or not the history prior to it has since been validated, which is done by
+// syncing that history separately and comparing the snapshot of the result.
+// -----------------------------------------------------------------------------
+
+const (
+	// utxoSnapshotMagic identifies utxo snapshot files.  It is "dutx" when
+	// serialized in little endian.
+	utxoSnapshotMagic uint32 = 0x78747564
+
+	// utxoSnapshotVersion is the current version of the utxo snapshot
+	// format.
+	utxoSnapshotVersion uint32 = 1
+
+	// utxoSnapshotHeaderSize is the size of the serialized header of a utxo
+	// snapshot.
+	utxoSnapshotHeaderSize = 16 + chainhash.HashSize
+
+	// maxSnapshotRecordSize is the maximum size of any single record that
+	// is read from a utxo snapshot.
+	maxSnapshotRecordSize = wire.MaxBlockPayload * 4
+
+	// utxoSnapshotBatchSize is the number of bytes of keys, values, and
+	// blocks loaded from a utxo snapshot after which the database
+	// transaction they are written in is committed.  This keeps the
+	// amount of pending data within the limits of the database cache.
+	utxoSnapshotBatchSize = 16 * 1024 * 1024
+
+	// utxoSnapshotBaseSize is the size of the serialized utxo snapshot base
+	// that is stored in the database.
+	utxoSnapshotBaseSize = 4 + chainhash.HashSize*2 + 8 + 1
+)
+
+// ErrChainStateExists is returned by LoadUtxoSnapshot when the database it is
+// asked to load a snapshot into has already been initialized.
+var ErrChainStateExists = errors.New("the database already contains a " +
+	"chain state")
+
+// errPartialUtxoSnapshot is returned when a database contains the partial
+// state of a utxo snapshot load that did not complete.
+var errPartialUtxoSnapshot = errors.New("the database contains a partially " +
+	"loaded utxo snapshot -- delete the database and try again")
+
+// UtxoSnapshotInfo describes a utxo snapshot.
+type UtxoSnapshotInfo struct {
+	Height       int64
+	Hash         chainhash.Hash
+	SnapshotHash chainhash.Hash
+	NumUtxos     uint64
+}
+
+// UtxoSnapshotBase describes the utxo snapshot a database was initialized from
+// along with whether or not the history prior to it has been validated.
+type UtxoSnapshotBase struct {
+	UtxoSnapshotInfo
+	Validated bool
+}
+
+// serializeUtxoSnapshotBase returns the serialization of the passed utxo
+// snapshot base.  The format is:
+//
+//	<height><hash><snapshot hash><num utxos><validated>
+//
+//	Field           Type              Size
+//	height          uint32            4
+//	hash            chainhash.Hash    chainhash.HashSize
+//	snapshot hash   chainhash.Hash    chainhash.HashSize
+//	num utxos       uint64            8
+//	validated       bool              1
+func serializeUtxoSnapshotBase(base *UtxoSnapshotBase) []byte {
+	serialized := make([]byte, utxoSnapshotBaseSize)
+	binary.LittleEndian.PutUint32(serialized[0:4], uint32(base.Height))
+	offset := 4
+	copy(serialized[offset:], base.Hash[:])
+	offset += chainhash.HashSize
+	copy(serialized[offset:], base.SnapshotHash[:])
+	offset += chainhash.HashSize
+	binary.LittleEndian.PutUint64(serialized[offset:], base.NumUtxos)
+	offset += 8
+	if base.Validated {
+		serialized[offset] = 1
+	}
+	return serialized
+}
+
+// deserializeUtxoSnapshotBase deserializes the passed serialized utxo snapshot
+// base.
+func deserializeUtxoSnapshotBase(serialized []byte) (*UtxoSnapshotBase, error) {
+	if len(serialized) != utxoSnapshotBaseSize {
+		return nil, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: fmt.Sprintf("corrupt utxo snapshot base: "+
+				"unexpected size %d", len(serialized)),
+		}
+	}
+	var base UtxoSnapshotBase
+	base.Height = int64(binary.LittleEndian.Uint32(serialized[0:4]))
+	offset := 4
+	copy(base.Hash[:], serialized[offset:])
+	offset += chainhash.HashSize
+	copy(base.SnapshotHash[:], serialized[offset:])
+	offset += chainhash.HashSize
+	base.NumUtxos = binary.LittleEndian.Uint64(serialized[offset:])
+	offset += 8
+	base.Validated = serialized[offset] != 0
+	return &base, nil
+}
+
+// FetchUtxoSnapshotBase returns the utxo snapshot the passed database was
+// initialized from, or nil when it was not initialized from a snapshot.
+func FetchUtxoSnapshotBase(db database.DB) (*UtxoSnapshotBase, error) {
+	var base *UtxoSnapshotBase
+	err := db.View(func(dbTx database.Tx) error {
+		serialized := dbTx.Metadata().Get(dbnamespace.UtxoSnapshotBaseKeyName)
+		if serialized == nil {
+			return nil
+		}
+		var err error
+		base, err = deserializeUtxoSnapshotBase(serialized)
+		return err
+	})
+	return base, err
+}
+
+// MarkUtxoSnapshotValidated records that the history prior to the utxo snapshot
+// the passed database was initialized from has been validated.
+func MarkUtxoSnapshotValidated(db database.DB) error {
+	return db.Update(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		serialized := meta.Get(dbnamespace.UtxoSnapshotBaseKeyName)
+		if serialized == nil {
+			return AssertError("MarkUtxoSnapshotValidated: the " +
+				"database was not initialized from a utxo snapshot")
+		}
+		base, err := deserializeUtxoSnapshotBase(serialized)
+		if err != nil {
+			return err
+		}
+		base.Validated = true
+		return meta.Put(dbnamespace.UtxoSnapshotBaseKeyName,
+			serializeUtxoSnapshotBase(base))
+	})
+}
+
+// blockDataHeights returns the range of heights of the main chain blocks that
+// have their data included in a utxo snapshot for a block at the given height.
+func blockDataHeights(height int64) (int64, int64) {
+	oldest := height - minBlocksToKeep + 1
+	if oldest < 0 {
+		oldest = 0
+	}
+	return oldest, height
+}
+
+// utxoSnapshotState houses the state of the main chain as of the block a utxo
+// snapshot is written for, which is determined while the chain lock is held.
+type utxoSnapshotState struct {
+	node            *blockNode
+	serializedState []byte
+	stakeNode       *stake.Node
+
+	// utxoOverrides contains the utxo entries that differ from the utxo set
+	// in the database as of the snapshot block and overrideHashes contains
+	// their transaction hashes in the order the utxo set is stored in.
+	utxoOverrides  map[chainhash.Hash][]byte
+	overrideHashes []chainhash.Hash
+
+	// dbTx is a read-only database transaction started while the chain lock
+	// was held, so it provides a consistent view of the database as of the
+	// chain tip the state was determined from.
+	dbTx database.Tx
+}
+
+// utxoSnapshotState determines the state of the main chain as of the block at
+// the passed height and starts the database transaction the rest of a utxo
+// snapshot for it is written from.  The caller is responsible for rolling
+// back the returned transaction.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) utxoSnapshotState(height int64) (*utxoSnapshotState, error) {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	tip := b.bestChain.Tip()
+	node := b.bestChain.NodeByHeight(height)
+	if node == nil {
+		return nil, fmt.Errorf("no main chain block at height %d (best "+
+			"height %d)", height, tip.height)
+	}
+
+	// Roll the utxo set and the chain totals back to the snapshot block by
+	// disconnecting the blocks after it in a view.
+	b.stateLock.RLock()
+	totalTxns := b.stateSnapshot.TotalTxns
+	totalSubsidy := b.stateSnapshot.TotalSubsidy
+	b.stateLock.RUnlock()
+	view := NewUtxoViewpoint()
+	view.SetBestHash(&tip.hash)
+	for n := tip; n != node; n = n.parent {
+		block, err := b.fetchMainChainBlockByNode(n)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := b.fetchMainChainBlockByNode(n.parent)
+		if err != nil {
+			return nil, err
+		}
+		var stxos []spentTxOut
+		err = b.db.View(func(dbTx database.Tx) error {
+			stxos, err = dbFetchSpendJournalEntry(dbTx, block)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = view.disconnectBlock(b.db, block, parent, stxos)
+		if err != nil {
+			return nil, err
+		}
+		totalTxns -= uint64(len(block.Transactions()) +
+			len(block.STransactions()))
+		totalSubsidy -= CalculateAddedSubsidy(block, parent)
+	}
+
+	// Determine the utxo entries that differ from the utxo set in the
+	// database as of the snapshot block.  Entries that are fully spent as of
+	// the snapshot block are nil.  They are sorted the same way as the utxo
+	// set in the database so they can be merged into it while it is written.
+	utxoOverrides := make(map[chainhash.Hash][]byte)
+	for txHash, entry := range view.entries {
+		if entry == nil || !entry.modified {
+			continue
+		}
+		serialized, err := serializeUtxoEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		utxoOverrides[txHash] = serialized
+	}
+	overrideHashes := make([]chainhash.Hash, 0, len(utxoOverrides))
+	for txHash := range utxoOverrides {
+		overrideHashes = append(overrideHashes, txHash)
+	}
+	sort.Slice(overrideHashes, func(i, j int) bool {
+		return bytes.Compare(overrideHashes[i][:], overrideHashes[j][:]) < 0
+	})
+
+	stakeNode, err := b.fetchStakeNode(node)
+	if err != nil {
+		return nil, err
+	}
+	serializedState := serializeBestChainState(bestChainState{
+		hash:         node.hash,
+		height:       uint32(node.height),
+		totalTxns:    totalTxns,
+		totalSubsidy: totalSubsidy,
+		workSum:      node.workSum,
+	})
+
+	dbTx, err := b.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &utxoSnapshotState{
+		node:            node,
+		serializedState: serializedState,
+		stakeNode:       stakeNode,
+		utxoOverrides:   utxoOverrides,
+		overrideHashes:  overrideHashes,
+		dbTx:            dbTx,
+	}, nil
+}
+
+// WriteUtxoSnapshot writes a snapshot of the main chain as of the block at the
+// passed height to the provided writer.  The snapshot may be used to initialize
+// a new database via LoadUtxoSnapshot.
+//
+// Snapshots of blocks prior to the current best chain tip are created by
+// disconnecting the blocks after them from the utxo set in memory with the help
+// of the spend journal, so the data of those blocks must be available.  The
+// database itself is not modified.
+//
+// The chain lock is only held while the blocks after the snapshot block are
+// disconnected.  The snapshot is then written from a database transaction that
+// was started at the same time, which continues to reflect that state while
+// new blocks are connected, so the chain is not stalled while the potentially
+// large utxo set is written.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) WriteUtxoSnapshot(w io.Writer, height int64) (*UtxoSnapshotInfo, error) {
+	state, err := b.utxoSnapshotState(height)
+	if err != nil {
+		return nil, err
+	}
+	defer state.dbTx.Rollback()
+
+	info := UtxoSnapshotInfo{
+		Height: state.node.height,
+		Hash:   state.node.hash,
+	}
+	if err := b.writeUtxoSnapshot(w, state, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// writeUtxoSnapshot writes a utxo snapshot with the passed state to the
+// provided writer and updates the passed info with the number of utxos and the
+// snapshot hash.
+func (b *BlockChain) writeUtxoSnapshot(w io.Writer, state *utxoSnapshotState, info *UtxoSnapshotInfo) error {
+	dbTx := state.dbTx
+	meta := dbTx.Metadata()
+	utxoOverrides, overrideHashes := state.utxoOverrides, state.overrideHashes
+
+	// All data aside from the snapshot hash itself is committed to
+	// by the snapshot hash.
+	bw := bufio.NewWriter(w)
+	hasher := blake256.New()
+	hw := io.MultiWriter(bw, hasher)
+
+	var hdr [utxoSnapshotHeaderSize]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], utxoSnapshotMagic)
+	binary.LittleEndian.PutUint32(hdr[4:8], utxoSnapshotVersion)
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(b.chainParams.Net))
+	binary.LittleEndian.PutUint32(hdr[12:16], uint32(info.Height))
+	copy(hdr[16:], info.Hash[:])
+	if _, err := hw.Write(hdr[:]); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(hw, 0, state.serializedState); err != nil {
+		return err
+	}
+
+	// Write the block index entries of all main chain blocks up to
+	// the snapshot block.  The status of each entry is normalized so
+	// the snapshot does not depend on the local state of the node
+	// that created it.
+	oldestDataHeight, _ := blockDataHeights(info.Height)
+	dataHashes := make([]chainhash.Hash, 0,
+		info.Height-oldestDataHeight+1)
+	err := wire.WriteVarInt(hw, 0, uint64(info.Height+1))
+	if err != nil {
+		return err
+	}
+	indexBucket := meta.Bucket(dbnamespace.BlockIndexBucketName)
+	hash := info.Hash
+	for h := info.Height; h >= 0; h-- {
+		key := blockIndexKey(&hash, uint32(h))
+		entry, err := deserializeBlockIndexEntry(indexBucket.Get(key))
+		if err != nil {
+			return err
+		}
+		entry.status = statusValid
+		if h >= oldestDataHeight {
+			entry.status |= statusDataStored
+			dataHashes = append(dataHashes, hash)
+		}
+		serialized, err := serializeBlockIndexEntry(entry)
+		if err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(hw, 0, serialized); err != nil {
+			return err
+		}
+		hash = entry.header.PrevBlock
+	}
+
+	// Write the block data and spend journal entries of the most
+	// recent blocks.
+	err = wire.WriteVarInt(hw, 0, uint64(len(dataHashes)))
+	if err != nil {
+		return err
+	}
+	spendBucket := meta.Bucket(dbnamespace.SpendJournalBucketName)
+	for i := range dataHashes {
+		blockBytes, err := dbTx.FetchBlock(&dataHashes[i])
+		if err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(hw, 0, blockBytes); err != nil {
+			return err
+		}
+		stxos := spendBucket.Get(dataHashes[i][:])
+		if err := wire.WriteVarBytes(hw, 0, stxos); err != nil {
+			return err
+		}
+	}
+
+	// Write the ticket database.
+	err = stake.WriteSnapshot(dbTx, hw, state.stakeNode, info.Hash,
+		uint32(oldestDataHeight))
+	if err != nil {
+		return err
+	}
+
+	// Write the utxo set as of the snapshot block by merging the
+	// rolled back entries into the utxo set in the database.
+	utxoBucket := meta.Bucket(dbnamespace.UtxoSetBucketName)
+	cursor := utxoBucket.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		info.NumUtxos++
+	}
+	for i := range overrideHashes {
+		if utxoBucket.Get(overrideHashes[i][:]) != nil {
+			info.NumUtxos--
+		}
+		if utxoOverrides[overrideHashes[i]] != nil {
+			info.NumUtxos++
+		}
+	}
+	if err := wire.WriteVarInt(hw, 0, info.NumUtxos); err != nil {
+		return err
+	}
+	var numWritten uint64
+	writeUtxo := func(key, value []byte) error {
+		numWritten++
+		if err := wire.WriteVarBytes(hw, 0, key); err != nil {
+			return err
+		}
+		return wire.WriteVarBytes(hw, 0, value)
+	}
+	cursor = utxoBucket.Cursor()
+	ok := cursor.First()
+	for i := 0; ok || i < len(overrideHashes); {
+		// Write the next rolled back entry when it sorts before or
+		// replaces the next entry in the database.
+		if i < len(overrideHashes) && (!ok ||
+			bytes.Compare(overrideHashes[i][:], cursor.Key()) <= 0) {
+
+			txHash := overrideHashes[i][:]
+			if ok && bytes.Equal(txHash, cursor.Key()) {
+				ok = cursor.Next()
+			}
+			serialized := utxoOverrides[overrideHashes[i]]
+			i++
+			if serialized == nil {
+				continue
+			}
+			if err := writeUtxo(txHash, serialized); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := writeUtxo(cursor.Key(), cursor.Value()); err != nil {
+			return err
+		}
+		ok = cursor.Next()
+	}
+	if numWritten != info.NumUtxos {
+		return AssertError(fmt.Sprintf("WriteUtxoSnapshot: wrote "+
+			"%d utxo entries instead of %d", numWritten,
+			info.NumUtxos))
+	}
+
+	copy(info.SnapshotHash[:], hasher.Sum(nil))
+	if _, err := bw.Write(info.SnapshotHash[:]); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// utxoSnapshotLoader writes the contents of a utxo snapshot to a database.
+// Since a single database transaction for the utxo set of a large chain would
+// exceed the limits of the database cache, the contents are written in a
+// series of transactions, each of which is committed once it contains
+// utxoSnapshotBatchSize bytes.
+type utxoSnapshotLoader struct {
+	db      database.DB
+	dbTx    database.Tx
+	pending int
+}
+
+// tx returns the current database transaction of the loader.  A new one is
+// started when there is none.
+func (l *utxoSnapshotLoader) tx() (database.Tx, error) {
+	if l.dbTx == nil {
+		dbTx, err := l.db.Begin(true)
+		if err != nil {
+			return nil, err
+		}
+		l.dbTx = dbTx
+	}
+	return l.dbTx, nil
+}
+
+// added accounts for the passed number of bytes that were written in the
+// current database transaction and commits it once the batch is full.
+func (l *utxoSnapshotLoader) added(n int) error {
+	l.pending += n
+	if l.pending < utxoSnapshotBatchSize {
+		return nil
+	}
+	return l.commit()
+}
+
+// put stores the passed key and value in the bucket with the passed name.
+func (l *utxoSnapshotLoader) put(bucketName, key, value []byte) error {
+	dbTx, err := l.tx()
+	if err != nil {
+		return err
+	}
+	if err := dbTx.Metadata().Bucket(bucketName).Put(key, value); err != nil {
+		return err
+	}
+	return l.added(len(key) + len(value))
+}
+
+// commit commits the current database transaction, if any.
+func (l *utxoSnapshotLoader) commit() error {
+	if l.dbTx == nil {
+		return nil
+	}
+	err := l.dbTx.Commit()
+	l.dbTx = nil
+	l.pending = 0
+	return err
+}
+
+// rollback rolls back the current database transaction, if any.
+func (l *utxoSnapshotLoader) rollback() {
+	if l.dbTx == nil {
+		return
+	}
+	l.dbTx.Rollback()
+	l.dbTx = nil
+	l.pending = 0
+}
+
+// load writes the contents of the utxo snapshot read from the reader, which
+// follow the header described by info, to the database and returns the
+// serialized best chain state.  The best chain state is not written since it
+// must only be stored once the snapshot has been verified.
+func (l *utxoSnapshotLoader) load(r io.Reader, params *chaincfg.Params, info *UtxoSnapshotInfo) ([]byte, error) {
+	// Read the best chain state.
+	serializedState, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+		"best chain state")
+	if err != nil {
+		return nil, err
+	}
+	state, err := deserializeBestChainState(serializedState)
+	if err != nil {
+		return nil, err
+	}
+	if int64(state.height) != info.Height || state.hash != info.Hash {
+		return nil, fmt.Errorf("utxo snapshot best chain state %v "+
+			"(height %d) does not match its header", state.hash,
+			state.height)
+	}
+
+	// Load the block index and ensure it links the snapshot block back to
+	// the genesis block.
+	numEntries, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if numEntries != uint64(info.Height+1) {
+		return nil, fmt.Errorf("utxo snapshot has %d block index "+
+			"entries instead of %d", numEntries, info.Height+1)
+	}
+	oldestDataHeight, _ := blockDataHeights(info.Height)
+	var dataHashes []chainhash.Hash
+	hash := info.Hash
+	for height := info.Height; height >= 0; height-- {
+		serialized, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"block index entry")
+		if err != nil {
+			return nil, err
+		}
+		entry, err := deserializeBlockIndexEntry(serialized)
+		if err != nil {
+			return nil, err
+		}
+		header := &entry.header
+		if height == 0 && hash != *params.GenesisHash {
+			return nil, fmt.Errorf("utxo snapshot block index does " +
+				"not start with the genesis block")
+		}
+		if header.BlockHash() != hash || int64(header.Height) != height {
+			return nil, fmt.Errorf("utxo snapshot block index entry "+
+				"for %v (height %d) does not link to the snapshot "+
+				"block", header.BlockHash(), header.Height)
+		}
+		if height >= oldestDataHeight {
+			dataHashes = append(dataHashes, hash)
+		}
+		err = l.put(dbnamespace.BlockIndexBucketName,
+			blockIndexKey(&hash, uint32(height)), serialized)
+		if err != nil {
+			return nil, err
+		}
+		hash = header.PrevBlock
+	}
+	if hash != (chainhash.Hash{}) {
+		return nil, fmt.Errorf("utxo snapshot block index does not " +
+			"start with the genesis block")
+	}
+
+	// Load the most recent blocks along with their spend journal entries.
+	numBlocks, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if numBlocks != uint64(len(dataHashes)) {
+		return nil, fmt.Errorf("utxo snapshot has %d blocks instead of "+
+			"%d", numBlocks, len(dataHashes))
+	}
+	for i := range dataHashes {
+		blockBytes, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"block")
+		if err != nil {
+			return nil, err
+		}
+		block, err := dcrutil.NewBlockFromBytes(blockBytes)
+		if err != nil {
+			return nil, err
+		}
+		if *block.Hash() != dataHashes[i] {
+			return nil, fmt.Errorf("utxo snapshot contains block %v "+
+				"instead of %v", block.Hash(), dataHashes[i])
+		}
+		dbTx, err := l.tx()
+		if err != nil {
+			return nil, err
+		}
+		if err := dbTx.StoreBlock(block); err != nil {
+			return nil, err
+		}
+		if err := l.added(len(blockBytes)); err != nil {
+			return nil, err
+		}
+		stxos, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"spend journal entry")
+		if err != nil {
+			return nil, err
+		}
+		err = l.put(dbnamespace.SpendJournalBucketName, dataHashes[i][:],
+			stxos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Load the ticket database.  It is small compared to the utxo set, so
+	// it is loaded in a single transaction.
+	dbTx, err := l.tx()
+	if err != nil {
+		return nil, err
+	}
+	if err := stake.LoadSnapshot(dbTx, r); err != nil {
+		return nil, err
+	}
+	if err := l.commit(); err != nil {
+		return nil, err
+	}
+
+	// Load the utxo set.
+	info.NumUtxos, err = wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint64(0); i < info.NumUtxos; i++ {
+		key, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"utxo key")
+		if err != nil {
+			return nil, err
+		}
+		value, err := wire.ReadVarBytes(r, 0, maxSnapshotRecordSize,
+			"utxo value")
+		if err != nil {
+			return nil, err
+		}
+		err = l.put(dbnamespace.UtxoSetBucketName, key, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := l.commit(); err != nil {
+		return nil, err
+	}
+
+	return serializedState, nil
+}
+
+// LoadUtxoSnapshot initializes the provided database with the utxo snapshot
+// read from the reader.  The snapshot must be for the network described by the
+// passed parameters and its hash must match one of the snapshots they commit
+// to.
+//
+// The snapshot is written in a series of database transactions and the best
+// chain state is only stored once the entire snapshot has been verified, so
+// the database is never used with the contents of an invalid snapshot.  When
+// loading fails after the first transaction, the database is left marked as
+// partially loaded and must be deleted before trying again.
+//
+// ErrChainStateExists is returned when the database has already been
+// initialized.
+func LoadUtxoSnapshot(db database.DB, params *chaincfg.Params, r io.Reader) (*UtxoSnapshotInfo, error) {
+	br := bufio.NewReader(r)
+	hasher := blake256.New()
+	hr := io.TeeReader(br, hasher)
+
+	// Read the header and ensure the snapshot is for a block the network
+	// parameters have a commitment for.
+	var hdr [utxoSnapshotHeaderSize]byte
+	if _, err := io.ReadFull(hr, hdr[:]); err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(hdr[0:4]) != utxoSnapshotMagic {
+		return nil, fmt.Errorf("not a utxo snapshot")
+	}
+	version := binary.LittleEndian.Uint32(hdr[4:8])
+	if version != utxoSnapshotVersion {
+		return nil, fmt.Errorf("unsupported utxo snapshot version %d",
+			version)
+	}
+	net := wire.CurrencyNet(binary.LittleEndian.Uint32(hdr[8:12]))
+	if net != params.Net {
+		return nil, fmt.Errorf("the utxo snapshot is for network %v "+
+			"instead of %v", net, params.Net)
+	}
+	info := UtxoSnapshotInfo{
+		Height: int64(binary.LittleEndian.Uint32(hdr[12:16])),
+	}
+	copy(info.Hash[:], hdr[16:])
+	var commitment *chaincfg.UtxoSnapshot
+	for i := range params.UtxoSnapshots {
+		snapshot := &params.UtxoSnapshots[i]
+		if snapshot.Height == info.Height && *snapshot.Hash == info.Hash {
+			commitment = snapshot
+			break
+		}
+	}
+	if commitment == nil {
+		return nil, fmt.Errorf("no utxo snapshot for block %v (height %d) "+
+			"is committed to by the %s network parameters", info.Hash,
+			info.Height, params.Name)
+	}
+
+	// Mark the database as being loaded from a snapshot and create the
+	// buckets the snapshot is loaded into.
+	err := db.Update(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		if meta.Get(dbnamespace.UtxoSnapshotLoadKeyName) != nil {
+			return errPartialUtxoSnapshot
+		}
+		if meta.Bucket(dbnamespace.BCDBInfoBucketName) != nil {
+			return ErrChainStateExists
+		}
+		bucketNames := [][]byte{
+			dbnamespace.BlockIndexBucketName,
+			dbnamespace.SpendJournalBucketName,
+			dbnamespace.UtxoSetBucketName,
+		}
+		for _, bucketName := range bucketNames {
+			if _, err := meta.CreateBucket(bucketName); err != nil {
+				return err
+			}
+		}
+		return meta.Put(dbnamespace.UtxoSnapshotLoadKeyName, []byte{1})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the snapshot and ensure it matches both the hash it was written
+	// with and the hash committed to by the network parameters.  Note that
+	// the trailing hash is read directly from the underlying reader since
+	// it is not part of the hashed data.
+	loader := utxoSnapshotLoader{db: db}
+	serializedState, err := loader.load(hr, params, &info)
+	if err != nil {
+		loader.rollback()
+		return nil, fmt.Errorf("%v; %v", err, errPartialUtxoSnapshot)
+	}
+	copy(info.SnapshotHash[:], hasher.Sum(nil))
+	var fileHash chainhash.Hash
+	if _, err := io.ReadFull(br, fileHash[:]); err != nil {
+		return nil, fmt.Errorf("%v; %v", err, errPartialUtxoSnapshot)
+	}
+	if fileHash != info.SnapshotHash {
+		return nil, fmt.Errorf("utxo snapshot is corrupt (hash %v, "+
+			"expected %v); %v", info.SnapshotHash, fileHash,
+			errPartialUtxoSnapshot)
+	}
+	if info.SnapshotHash != *commitment.SnapshotHash {
+		return nil, fmt.Errorf("utxo snapshot hash %v does not match "+
+			"the committed hash %v; %v", info.SnapshotHash,
+			commitment.SnapshotHash, errPartialUtxoSnapshot)
+	}
+
+	// Store the database versioning information the same way as for a new
+	// chain along with the best chain state, which makes the database
+	// usable, record the snapshot it was initialized from, and remove the
+	// mark.
+	err = db.Update(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		_, err := meta.CreateBucket(dbnamespace.BCDBInfoBucketName)
+		if err != nil {
+			return err
+		}
+		err = dbPutDatabaseInfo(dbTx, &databaseInfo{
+			version: currentDatabaseVersion,
+			compVer: currentCompressionVersion,
+			bidxVer: currentBlockIndexVersion,
+			created: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		err = meta.Put(dbnamespace.ChainStateKeyName, serializedState)
+		if err != nil {
+			return err
+		}
+		err = meta.Put(dbnamespace.UtxoSnapshotBaseKeyName,
+			serializeUtxoSnapshotBase(&UtxoSnapshotBase{
+				UtxoSnapshotInfo: info,
+			}))
+		if err != nil {
+			return err
+		}
+		return meta.Delete(dbnamespace.UtxoSnapshotLoadKeyName)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%v; %v", err, errPartialUtxoSnapshot)
+	}
+
+	return &info, nil
+}
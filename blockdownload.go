@@ -0,0 +1,232 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// blockDownloadWindow is the maximum number of blocks past the next
+	// block to process that are requested in headers-first mode.  Blocks
+	// that arrive out of order are held in memory until all of their
+	// ancestors have been processed, so the window bounds the number of
+	// such blocks.
+	blockDownloadWindow = 1024
+
+	// maxInFlightBlocksPerPeer is the maximum number of blocks that are
+	// requested from a single peer at the same time in headers-first mode.
+	maxInFlightBlocksPerPeer = 16
+
+	// blockStallTimeout is the amount of time after which a block that was
+	// requested in headers-first mode and not received yet is considered
+	// stalled and requested from another peer.
+	blockStallTimeout = 15 * time.Second
+
+	// blockStallCheckInterval is the interval at which requested blocks
+	// are checked for stalls.
+	blockStallCheckInterval = 3 * time.Second
+)
+
+// blockRequest houses information about a block that was requested in
+// headers-first mode and is not received yet.
+type blockRequest struct {
+	height    int64
+	requested time.Time
+	peers     []*serverPeer
+}
+
+// fetchingHeaderBlocks returns whether or not the current round of headers,
+// which ends at the next checkpoint when there is one, has been downloaded and
+// their blocks are being fetched.
+func (b *blockManager) fetchingHeaderBlocks() bool {
+	return b.headersFirstMode && b.headersReceived &&
+		b.headerList.Len() > 0
+}
+
+// blockDownloadPeer returns the candidate peer that is least busy downloading
+// blocks among those which are able to provide the block at the passed height
+// and are below the limit of in-flight blocks.  Peers the passed request was
+// already sent to are skipped.  It returns nil when there is no such peer.
+func (b *blockManager) blockDownloadPeer(peers *list.List, height int64, req *blockRequest) *serverPeer {
+	var bestPeer *serverPeer
+	for e := peers.Front(); e != nil; e = e.Next() {
+		sp := e.Value.(*serverPeer)
+		if !sp.Connected() || sp.LastBlock() < height ||
+			len(sp.requestedBlocks) >= maxInFlightBlocksPerPeer {
+			continue
+		}
+		if req != nil && req.sentTo(sp) {
+			continue
+		}
+		if bestPeer == nil ||
+			len(sp.requestedBlocks) < len(bestPeer.requestedBlocks) {
+			bestPeer = sp
+		}
+	}
+	return bestPeer
+}
+
+// sentTo returns whether or not the block request was sent to the passed peer.
+func (r *blockRequest) sentTo(sp *serverPeer) bool {
+	for _, p := range r.peers {
+		if p == sp {
+			return true
+		}
+	}
+	return false
+}
+
+// requestHeaderBlock records that the block with the passed hash is requested
+// from the passed peer and adds it to the getdata message for the peer in the
+// passed map.
+func (b *blockManager) requestHeaderBlock(sp *serverPeer, hash *chainhash.Hash, req *blockRequest, getData map[*serverPeer]*wire.MsgGetData) {
+	b.requestedBlocks[*hash] = struct{}{}
+	sp.requestedBlocks[*hash] = struct{}{}
+	req.peers = append(req.peers, sp)
+	req.requested = time.Now()
+
+	gdmsg, ok := getData[sp]
+	if !ok {
+		gdmsg = wire.NewMsgGetData()
+		getData[sp] = gdmsg
+	}
+	iv := wire.NewInvVect(wire.InvTypeBlock, hash)
+	if err := gdmsg.AddInvVect(iv); err != nil {
+		bmgrLog.Warnf("Failed to add invvect while fetching block "+
+			"headers: %v", err)
+	}
+}
+
+// fetchHeaderBlocks requests the blocks within the download window that are
+// neither requested nor received yet based on the current list of headers.
+// The requests are spread over all candidate peers that are able to provide
+// the blocks.
+func (b *blockManager) fetchHeaderBlocks(peers *list.List) {
+	// Nothing to do until the current round of headers has been
+	// downloaded.
+	if !b.fetchingHeaderBlocks() {
+		return
+	}
+
+	getData := make(map[*serverPeer]*wire.MsgGetData)
+	e := b.headerList.Front()
+	for i := 0; e != nil && i < blockDownloadWindow; i++ {
+		node := e.Value.(*headerNode)
+		e = e.Next()
+
+		if _, exists := b.blockRequests[*node.hash]; exists {
+			continue
+		}
+		if _, exists := b.pendingBlocks[*node.hash]; exists {
+			continue
+		}
+		iv := wire.NewInvVect(wire.InvTypeBlock, node.hash)
+		haveInv, err := b.haveInventory(iv)
+		if err != nil {
+			bmgrLog.Warnf("Unexpected failure when checking for "+
+				"existing inventory during header block "+
+				"fetch: %v", err)
+			continue
+		}
+		if haveInv {
+			continue
+		}
+
+		// Stop once every peer is at its limit of in-flight blocks.
+		sp := b.blockDownloadPeer(peers, node.height, nil)
+		if sp == nil {
+			break
+		}
+		req := &blockRequest{height: node.height}
+		b.blockRequests[*node.hash] = req
+		b.requestHeaderBlock(sp, node.hash, req, getData)
+	}
+	for sp, gdmsg := range getData {
+		sp.QueueMessage(gdmsg, nil)
+	}
+}
+
+// handleStalledBlocks requests the blocks that were requested in headers-first
+// mode and have not been received within the stall timeout from other peers.
+// The original requests stay outstanding so the blocks are accepted from
+// whichever peer delivers them first.
+func (b *blockManager) handleStalledBlocks(peers *list.List) {
+	if !b.fetchingHeaderBlocks() {
+		return
+	}
+
+	now := time.Now()
+	getData := make(map[*serverPeer]*wire.MsgGetData)
+	for hash, req := range b.blockRequests {
+		if now.Sub(req.requested) < blockStallTimeout {
+			continue
+		}
+		sp := b.blockDownloadPeer(peers, req.height, req)
+		if sp == nil {
+			continue
+		}
+		hash := hash
+		bmgrLog.Debugf("Block %v (height %d) requested from %s stalled "+
+			"-- requesting it from %s", &hash, req.height,
+			req.peers[len(req.peers)-1], sp)
+		b.requestHeaderBlock(sp, &hash, req, getData)
+	}
+	for sp, gdmsg := range getData {
+		sp.QueueMessage(gdmsg, nil)
+	}
+	b.fetchHeaderBlocks(peers)
+}
+
+// removeBlockRequest removes the headers-first request for the block with the
+// passed hash from all peers it was sent to.
+func (b *blockManager) removeBlockRequest(hash *chainhash.Hash) {
+	req, exists := b.blockRequests[*hash]
+	if !exists {
+		return
+	}
+	for _, sp := range req.peers {
+		delete(sp.requestedBlocks, *hash)
+	}
+	delete(b.blockRequests, *hash)
+}
+
+// removePeerBlockRequests removes the passed peer from the headers-first block
+// requests that were sent to it so the blocks that were only requested from
+// the peer are requested from other peers.
+func (b *blockManager) removePeerBlockRequests(sp *serverPeer) {
+	for hash := range sp.requestedBlocks {
+		req, exists := b.blockRequests[hash]
+		if !exists {
+			continue
+		}
+		for i, p := range req.peers {
+			if p == sp {
+				req.peers = append(req.peers[:i], req.peers[i+1:]...)
+				break
+			}
+		}
+		if len(req.peers) == 0 {
+			delete(b.blockRequests, hash)
+		}
+	}
+}
+
+// isLateHeaderBlock returns whether or not the block with the passed hash is a
+// block that was requested from more than one peer in headers-first mode and
+// was already received from another peer.
+func (b *blockManager) isLateHeaderBlock(hash *chainhash.Hash) bool {
+	if !b.headersFirstMode {
+		return false
+	}
+	if _, exists := b.pendingBlocks[*hash]; exists {
+		return true
+	}
+	return b.chain.MainChainHasBlock(hash)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"container/list"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -22,11 +23,6 @@ import (
 )
 
 const (
-	// minInFlightBlocks is the minimum number of blocks that should be
-	// in the request queue for headers-first mode before requesting
-	// more.
-	minInFlightBlocks = 10
-
 	// blockDbNamePrefix is the prefix for the block database name.  The
 	// database type is appended to this value to form the full block
 	// database name.
@@ -63,16 +59,23 @@ const (
 // zeroHash is the zero value hash (all zeros).  It is defined as a convenience.
 var zeroHash chainhash.Hash
 
+// errBlockManagerShutdown is returned by requests to the block manager that
+// can't be handled because it is shutting down.
+var errBlockManagerShutdown = errors.New("the block manager is shutting down")
+
 // newPeerMsg signifies a newly connected peer to the block handler.
 type newPeerMsg struct {
 	peer *serverPeer
 }
 
 // blockMsg packages a Decred block message and the peer it came from together
-// so the block handler has access to that information.
+// so the block handler has access to that information.  The compact flag is
+// set for blocks reconstructed from a compact block announcement, which are
+// not requested before they are received.
 type blockMsg struct {
-	block *dcrutil.Block
-	peer  *serverPeer
+	block   *dcrutil.Block
+	peer    *serverPeer
+	compact bool
 }
 
 // invMsg packages a Decred inv message and the peer it came from together
@@ -305,9 +308,11 @@ type blockManager struct {
 
 	// The following fields are used for headers-first mode.
 	headersFirstMode bool
+	headersReceived  bool
 	headerList       *list.List
-	startHeader      *list.Element
 	nextCheckpoint   *chaincfg.Checkpoint
+	blockRequests    map[chainhash.Hash]*blockRequest
+	pendingBlocks    map[chainhash.Hash]*blockMsg
 
 	// lotteryDataBroadcastMutex is a mutex protecting the map
 	// that checks if block lottery data has been broadcasted
@@ -334,16 +339,17 @@ type blockManager struct {
 // syncing from a new peer.
 func (b *blockManager) resetHeaderState(newestHash *chainhash.Hash, newestHeight int64) {
 	b.headersFirstMode = false
+	b.headersReceived = false
 	b.headerList.Init()
-	b.startHeader = nil
+	b.blockRequests = make(map[chainhash.Hash]*blockRequest)
+	b.pendingBlocks = make(map[chainhash.Hash]*blockMsg)
+	b.nextCheckpoint = b.findNextHeaderCheckpoint(newestHeight)
 
-	// When there is a next checkpoint, add an entry for the latest known
-	// block into the header pool.  This allows the next downloaded header
-	// to prove it links to the chain properly.
-	if b.nextCheckpoint != nil {
-		node := headerNode{height: newestHeight, hash: newestHash}
-		b.headerList.PushBack(&node)
-	}
+	// Add an entry for the latest known block into the header pool.  This
+	// allows the next downloaded header to prove it links to the chain
+	// properly.
+	node := headerNode{height: newestHeight, hash: newestHash}
+	b.headerList.PushBack(&node)
 }
 
 // SyncHeight returns latest known block being synced to.
@@ -440,37 +446,47 @@ func (b *blockManager) startSync(peers *list.List) {
 		bmgrLog.Infof("Syncing to block height %d from peer %v",
 			bestPeer.LastBlock(), bestPeer.Addr())
 
-		// When the current height is less than a known checkpoint we
-		// can use block headers to learn about which blocks comprise
-		// the chain up to the checkpoint and perform less validation
-		// for them.  This is possible since each header contains the
-		// hash of the previous header and a merkle root.  Therefore if
-		// we validate all of the received headers link together
-		// properly and the checkpoint hashes match, we can be sure the
+		// When the peer has blocks we don't, use block headers to learn
+		// about which blocks comprise its chain so the blocks can be
+		// downloaded from multiple peers at once.  This is possible
+		// since each header contains the hash of the previous header
+		// and a merkle root.  Therefore if we validate all of the
+		// received headers link together properly, we can be sure the
 		// hashes for the blocks in between are accurate.  Further, once
 		// the full blocks are downloaded, the merkle root is computed
 		// and compared against the value in the header which proves the
 		// full block hasn't been tampered with.
 		//
-		// Once we have passed the final checkpoint, or checkpoints are
-		// disabled, use standard inv messages learn about the blocks
-		// and fully validate them.  Finally, regression test mode does
-		// not support the headers-first approach so do normal block
-		// downloads when in regression test mode.
-		if b.nextCheckpoint != nil &&
-			best.Height < b.nextCheckpoint.Height &&
-			!cfg.DisableCheckpoints {
-
-			err := bestPeer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
+		// When the current height is less than a known checkpoint, the
+		// headers are downloaded up to the checkpoint and less
+		// validation is performed for the blocks since the checkpoint
+		// hash must match.  Once we have passed the final checkpoint,
+		// or checkpoints are disabled, the headers are downloaded up
+		// to the tip of the peer and the blocks are fully validated.
+		// Standard inv messages are used to learn about new blocks
+		// once all of the headers have been downloaded.
+		if bestPeer.LastBlock() > best.Height {
+			b.resetHeaderState(&best.Hash, best.Height)
+			stopHash := &zeroHash
+			if b.nextCheckpoint != nil {
+				stopHash = b.nextCheckpoint.Hash
+			}
+			err := bestPeer.PushGetHeadersMsg(locator, stopHash)
 			if err != nil {
 				bmgrLog.Errorf("Failed to push getheadermsg for the "+
 					"latest blocks: %v", err)
 				return
 			}
 			b.headersFirstMode = true
-			bmgrLog.Infof("Downloading headers for blocks %d to "+
-				"%d from peer %s", best.Height+1,
-				b.nextCheckpoint.Height, bestPeer.Addr())
+			if b.nextCheckpoint != nil {
+				bmgrLog.Infof("Downloading headers for blocks %d "+
+					"to %d from peer %s", best.Height+1,
+					b.nextCheckpoint.Height, bestPeer.Addr())
+			} else {
+				bmgrLog.Infof("Downloading headers for blocks "+
+					"after %d from peer %s", best.Height,
+					bestPeer.Addr())
+			}
 		} else {
 			err := bestPeer.PushGetBlocksMsg(locator, &zeroHash)
 			if err != nil {
@@ -536,8 +552,9 @@ func (b *blockManager) handleNewPeerMsg(peers *list.List, sp *serverPeer) {
 	// Start syncing by choosing the best candidate if needed.
 	b.startSync(peers)
 
-	// Grab the mining state from this peer after we're synced.
-	if !cfg.NoMiningStateSync {
+	// Grab the mining state from this peer after we're synced.  It is not
+	// requested from block relay only peers since it includes votes.
+	if !cfg.NoMiningStateSync && !sp.blockRelayOnly {
 		b.syncMiningStateAfterSync(sp)
 	}
 }
@@ -564,10 +581,15 @@ func (b *blockManager) handleDonePeerMsg(peers *list.List, sp *serverPeer) {
 	}
 
 	// Remove requested blocks from the global map so that they will be
-	// fetched from elsewhere next time we get an inv.
-	// TODO(oga) we could possibly here check which peers have these blocks
-	// and request them now to speed things up a little.
+	// fetched from elsewhere next time we get an inv.  Blocks requested in
+	// headers-first mode are requested from other peers right away, unless
+	// they are still outstanding with other peers they were requested from
+	// because of a stall.
+	b.removePeerBlockRequests(sp)
 	for k := range sp.requestedBlocks {
+		if _, exists := b.blockRequests[k]; exists {
+			continue
+		}
 		delete(b.requestedBlocks, k)
 	}
 
@@ -581,7 +603,9 @@ func (b *blockManager) handleDonePeerMsg(peers *list.List, sp *serverPeer) {
 			b.resetHeaderState(&best.Hash, best.Height)
 		}
 		b.startSync(peers)
+		return
 	}
+	b.fetchHeaderBlocks(peers)
 }
 
 // handleTxMsg handles transaction messages from all peers.
@@ -847,38 +871,38 @@ func (b *blockManager) checkBlockForHiddenVotes(block *dcrutil.Block) {
 }
 
 // handleBlockMsg handles block messages from all peers.
-func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
-	// If we didn't ask for this block then the peer is misbehaving.
+func (b *blockManager) handleBlockMsg(peers *list.List, bmsg *blockMsg) {
+	// If we didn't ask for this block then the peer is misbehaving.  Blocks
+	// reconstructed from compact block announcements are the exception.
+	// Also, blocks that were requested from multiple peers in headers-first
+	// mode because the first request stalled are ignored when they were
+	// already received from another peer.
 	blockHash := bmsg.block.Hash()
-	if _, exists := bmsg.peer.requestedBlocks[*blockHash]; !exists {
+	_, exists := bmsg.peer.requestedBlocks[*blockHash]
+	if !exists && !bmsg.compact {
+		if b.isLateHeaderBlock(blockHash) {
+			bmgrLog.Debugf("Ignoring block %v from %s that was "+
+				"already received from another peer", blockHash,
+				bmsg.peer)
+			return
+		}
 		bmgrLog.Warnf("Got unrequested block %v from %s -- "+
 			"disconnecting", blockHash, bmsg.peer.Addr())
 		bmsg.peer.Disconnect()
 		return
 	}
 
-	// When in headers-first mode, if the block matches the hash of the
-	// first header in the list of headers that are being fetched, it's
-	// eligible for less validation since the headers have already been
-	// verified to link together and are valid up to the next checkpoint.
-	// Also, remove the list entry for all blocks except the checkpoint
-	// since it is needed to verify the next round of headers links
-	// properly.
-	isCheckpointBlock := false
-	behaviorFlags := blockchain.BFNone
-	if b.headersFirstMode {
-		firstNodeEl := b.headerList.Front()
-		if firstNodeEl != nil {
-			firstNode := firstNodeEl.Value.(*headerNode)
-			if blockHash.IsEqual(firstNode.hash) {
-				behaviorFlags |= blockchain.BFFastAdd
-				if firstNode.hash.IsEqual(b.nextCheckpoint.Hash) {
-					isCheckpointBlock = true
-				} else {
-					b.headerList.Remove(firstNodeEl)
-				}
-			}
-		}
+	// When in headers-first mode, blocks for the list of headers that are
+	// being fetched are downloaded from multiple peers and therefore may
+	// arrive out of order.  They are held until all of the blocks before
+	// them have been processed so they are processed in the order of the
+	// headers.
+	if _, exists := b.blockRequests[*blockHash]; exists {
+		b.removeBlockRequest(blockHash)
+		delete(b.requestedBlocks, *blockHash)
+		b.pendingBlocks[*blockHash] = bmsg
+		b.processPendingBlocks(peers)
+		return
 	}
 
 	// Remove block from request maps. Either chain will know about it and
@@ -887,8 +911,100 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	delete(bmsg.peer.requestedBlocks, *blockHash)
 	delete(b.requestedBlocks, *blockHash)
 
-	// Process the block to include validation, best chain selection, orphan
-	// handling, etc.
+	b.processBlockMsg(bmsg, blockchain.BFNone)
+}
+
+// processPendingBlocks processes the blocks that were received in headers-first
+// mode in the order of the list of headers that are being fetched until a block
+// that has not been received yet is encountered, and then requests more blocks.
+//
+// When the headers have been verified to link together and are valid up to the
+// next checkpoint, the blocks are eligible for less validation.  Otherwise they
+// are fully validated.  The list entry of every processed block except the
+// final one of the round is removed, since the final one is needed to verify
+// the next round of headers links properly.
+func (b *blockManager) processPendingBlocks(peers *list.List) {
+	behaviorFlags := blockchain.BFNone
+	if b.nextCheckpoint != nil {
+		behaviorFlags = blockchain.BFFastAdd
+	}
+	for b.fetchingHeaderBlocks() {
+		firstNodeEl := b.headerList.Front()
+		firstNode := firstNodeEl.Value.(*headerNode)
+		bmsg, exists := b.pendingBlocks[*firstNode.hash]
+		if exists {
+			delete(b.pendingBlocks, *firstNode.hash)
+			if !b.processBlockMsg(bmsg, behaviorFlags) {
+				// Headers past the checkpoints are not known to
+				// be valid, so the sync peer provided the
+				// headers of an invalid chain in that case.
+				// Otherwise the block is requested again from
+				// the list of headers.
+				if b.nextCheckpoint == nil && b.syncPeer != nil {
+					bmgrLog.Infof("Sync peer %s provided the "+
+						"headers of invalid block %v -- "+
+						"disconnecting", b.syncPeer,
+						firstNode.hash)
+					b.syncPeer.Disconnect()
+				}
+				break
+			}
+		} else if !b.chain.MainChainHasBlock(firstNode.hash) {
+			// Blocks that were already added to the main chain by
+			// other means are skipped.
+			break
+		}
+
+		if firstNodeEl.Next() == nil {
+			b.handleHeaderRoundEnd(firstNode)
+			return
+		}
+		b.headerList.Remove(firstNodeEl)
+	}
+
+	b.fetchHeaderBlocks(peers)
+}
+
+// handleHeaderRoundEnd requests the next round of headers from the sync peer
+// once the block of the final header of the current round, which is the next
+// checkpoint when there is one, was processed in headers-first mode.
+func (b *blockManager) handleHeaderRoundEnd(node *headerNode) {
+	b.headersReceived = false
+	if b.syncPeer == nil {
+		return
+	}
+
+	// Get the next round of headers by asking for headers starting from
+	// the block after this one up to the next checkpoint, or up to the end
+	// of the chain (zero hash) when there are no more checkpoints.
+	b.nextCheckpoint = b.findNextHeaderCheckpoint(node.height)
+	stopHash := &zeroHash
+	if b.nextCheckpoint != nil {
+		stopHash = b.nextCheckpoint.Hash
+	}
+	locator := blockchain.BlockLocator([]*chainhash.Hash{node.hash})
+	err := b.syncPeer.PushGetHeadersMsg(locator, stopHash)
+	if err != nil {
+		bmgrLog.Warnf("Failed to send getheaders message to peer %s: %v",
+			b.syncPeer.Addr(), err)
+		return
+	}
+	if b.nextCheckpoint != nil {
+		bmgrLog.Infof("Downloading headers for blocks %d to %d from "+
+			"peer %s", node.height+1, b.nextCheckpoint.Height,
+			b.syncPeer.Addr())
+		return
+	}
+	bmgrLog.Debugf("Downloading headers for blocks after %d from peer %s",
+		node.height, b.syncPeer.Addr())
+}
+
+// processBlockMsg processes the block in the passed block message with the
+// passed behavior flags to include validation, best chain selection, orphan
+// handling, etc.  It returns whether or not the block was processed without
+// error.
+func (b *blockManager) processBlockMsg(bmsg *blockMsg, behaviorFlags blockchain.BehaviorFlags) bool {
+	blockHash := bmsg.block.Hash()
 	forkLen, isOrphan, err := b.chain.ProcessBlock(bmsg.block,
 		behaviorFlags)
 	if err != nil {
@@ -913,7 +1029,7 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 		code, reason := mempool.ErrToRejectErr(err)
 		bmsg.peer.PushRejectMsg(wire.CmdBlock, code, reason,
 			blockHash, false)
-		return
+		return false
 	}
 
 	// Meta-data about the new block this peer is reporting. We use this
@@ -1016,109 +1132,11 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 		}
 	}
 
-	// Nothing more to do if we aren't in headers-first mode.
-	if !b.headersFirstMode {
-		return
-	}
-
-	// This is headers-first mode, so if the block is not a checkpoint
-	// request more blocks using the header list when the request queue is
-	// getting short.
-	if !isCheckpointBlock {
-		if b.startHeader != nil &&
-			len(bmsg.peer.requestedBlocks) < minInFlightBlocks {
-			b.fetchHeaderBlocks()
-		}
-		return
-	}
-
-	// This is headers-first mode and the block is a checkpoint.  When
-	// there is a next checkpoint, get the next round of headers by asking
-	// for headers starting from the block after this one up to the next
-	// checkpoint.
-	prevHeight := b.nextCheckpoint.Height
-	prevHash := b.nextCheckpoint.Hash
-	b.nextCheckpoint = b.findNextHeaderCheckpoint(prevHeight)
-	if b.nextCheckpoint != nil {
-		locator := blockchain.BlockLocator([]*chainhash.Hash{prevHash})
-		err := bmsg.peer.PushGetHeadersMsg(locator, b.nextCheckpoint.Hash)
-		if err != nil {
-			bmgrLog.Warnf("Failed to send getheaders message to "+
-				"peer %s: %v", bmsg.peer.Addr(), err)
-			return
-		}
-		bmgrLog.Infof("Downloading headers for blocks %d to %d from "+
-			"peer %s", prevHeight+1, b.nextCheckpoint.Height,
-			b.syncPeer.Addr())
-		return
-	}
-
-	// This is headers-first mode, the block is a checkpoint, and there are
-	// no more checkpoints, so switch to normal mode by requesting blocks
-	// from the block after this one up to the end of the chain (zero hash).
-	b.headersFirstMode = false
-	b.headerList.Init()
-	bmgrLog.Infof("Reached the final checkpoint -- switching to normal mode")
-	locator := blockchain.BlockLocator([]*chainhash.Hash{blockHash})
-	err = bmsg.peer.PushGetBlocksMsg(locator, &zeroHash)
-	if err != nil {
-		bmgrLog.Warnf("Failed to send getblocks message to peer %s: %v",
-			bmsg.peer.Addr(), err)
-		return
-	}
-}
-
-// fetchHeaderBlocks creates and sends a request to the syncPeer for the next
-// list of blocks to be downloaded based on the current list of headers.
-func (b *blockManager) fetchHeaderBlocks() {
-	// Nothing to do if there is no start header.
-	if b.startHeader == nil {
-		bmgrLog.Warnf("fetchHeaderBlocks called with no start header")
-		return
-	}
-
-	// Build up a getdata request for the list of blocks the headers
-	// describe.  The size hint will be limited to wire.MaxInvPerMsg by
-	// the function, so no need to double check it here.
-	gdmsg := wire.NewMsgGetDataSizeHint(uint(b.headerList.Len()))
-	numRequested := 0
-	for e := b.startHeader; e != nil; e = e.Next() {
-		node, ok := e.Value.(*headerNode)
-		if !ok {
-			bmgrLog.Warn("Header list node type is not a headerNode")
-			continue
-		}
-
-		iv := wire.NewInvVect(wire.InvTypeBlock, node.hash)
-		haveInv, err := b.haveInventory(iv)
-		if err != nil {
-			bmgrLog.Warnf("Unexpected failure when checking for "+
-				"existing inventory during header block "+
-				"fetch: %v", err)
-			continue
-		}
-		if !haveInv {
-			b.requestedBlocks[*node.hash] = struct{}{}
-			b.syncPeer.requestedBlocks[*node.hash] = struct{}{}
-			err = gdmsg.AddInvVect(iv)
-			if err != nil {
-				bmgrLog.Warnf("Failed to add invvect while fetching "+
-					"block headers: %v", err)
-			}
-			numRequested++
-		}
-		b.startHeader = e.Next()
-		if numRequested >= wire.MaxInvPerMsg {
-			break
-		}
-	}
-	if len(gdmsg.InvList) > 0 {
-		b.syncPeer.QueueMessage(gdmsg, nil)
-	}
+	return true
 }
 
 // handleHeadersMsg handles headers messages from all peers.
-func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
+func (b *blockManager) handleHeadersMsg(peers *list.List, hmsg *headersMsg) {
 	// The remote peer is misbehaving if we didn't request headers.
 	msg := hmsg.headers
 	numHeaders := len(msg.Headers)
@@ -1129,11 +1147,53 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 		return
 	}
 
-	// Nothing to do for an empty headers message.
+	// Ignore headers that arrive while the blocks of the current round of
+	// headers are being fetched since they were not requested.
+	if b.fetchingHeaderBlocks() {
+		bmgrLog.Debugf("Ignoring %d unrequested headers from %s",
+			numHeaders, hmsg.peer)
+		return
+	}
+
+	// An empty headers message from the sync peer in response to a request
+	// for the headers past the final checkpoint means all of its headers
+	// and their blocks were received, so switch to normal mode by
+	// requesting any blocks that were announced in the meantime from the
+	// block after the final header up to the end of the chain (zero hash).
 	if numHeaders == 0 {
+		if b.nextCheckpoint != nil || hmsg.peer != b.syncPeer {
+			return
+		}
+		finalNode := b.headerList.Back().Value.(*headerNode)
+		b.headersFirstMode = false
+		b.headerList.Init()
+		bmgrLog.Infof("Received the blocks of all headers from peer %s "+
+			"-- switching to normal mode", hmsg.peer)
+		locator := blockchain.BlockLocator([]*chainhash.Hash{finalNode.hash})
+		err := hmsg.peer.PushGetBlocksMsg(locator, &zeroHash)
+		if err != nil {
+			bmgrLog.Warnf("Failed to send getblocks message to "+
+				"peer %s: %v", hmsg.peer.Addr(), err)
+		}
 		return
 	}
 
+	// Past the checkpoints, the headers may fork from the best chain before
+	// its tip, in which case they are linked to the main chain block they
+	// build on instead.
+	if b.nextCheckpoint == nil && b.headerList.Len() == 1 {
+		baseNode := b.headerList.Front().Value.(*headerNode)
+		prevHash := msg.Headers[0].PrevBlock
+		if !baseNode.hash.IsEqual(&prevHash) {
+			height, err := b.chain.BlockHeightByHash(&prevHash)
+			if err == nil {
+				b.headerList.Init()
+				b.headerList.PushBack(&headerNode{height: height,
+					hash: &prevHash})
+			}
+		}
+	}
+
 	// Process all of the received headers ensuring each one connects to the
 	// previous and that checkpoints match.
 	receivedCheckpoint := false
@@ -1157,10 +1217,7 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 		prevNode := prevNodeEl.Value.(*headerNode)
 		if prevNode.hash.IsEqual(&blockHeader.PrevBlock) {
 			node.height = prevNode.height + 1
-			e := b.headerList.PushBack(&node)
-			if b.startHeader == nil {
-				b.startHeader = e
-			}
+			b.headerList.PushBack(&node)
 		} else {
 			bmgrLog.Warnf("Received block header that does not "+
 				"properly connect to the chain from peer %s "+
@@ -1170,7 +1227,8 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 		}
 
 		// Verify the header at the next checkpoint height matches.
-		if node.height == b.nextCheckpoint.Height {
+		if b.nextCheckpoint != nil &&
+			node.height == b.nextCheckpoint.Height {
 			if node.hash.IsEqual(b.nextCheckpoint.Hash) {
 				receivedCheckpoint = true
 				bmgrLog.Infof("Verified downloaded block "+
@@ -1191,17 +1249,20 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 	}
 
 	// When this header is a checkpoint, switch to fetching the blocks for
-	// all of the headers since the last checkpoint.
-	if receivedCheckpoint {
+	// all of the headers since the last checkpoint.  Past the checkpoints,
+	// the blocks for every batch of headers are fetched before requesting
+	// the next one.
+	if receivedCheckpoint || b.nextCheckpoint == nil {
 		// Since the first entry of the list is always the final block
 		// that is already in the database and is only used to ensure
 		// the next header links properly, it must be removed before
 		// fetching the blocks.
 		b.headerList.Remove(b.headerList.Front())
+		b.headersReceived = true
 		bmgrLog.Infof("Received %v block headers: Fetching blocks",
 			b.headerList.Len())
 		b.progressLogger.SetLastLogTime(time.Now())
-		b.fetchHeaderBlocks()
+		b.fetchHeaderBlocks(peers)
 		return
 	}
 
@@ -1454,9 +1515,14 @@ func (b *blockManager) limitMap(m map[chainhash.Hash]struct{}, limit int) {
 // the fetching should proceed.
 func (b *blockManager) blockHandler() {
 	candidatePeers := list.New()
+	stallTicker := time.NewTicker(blockStallCheckInterval)
+	defer stallTicker.Stop()
 out:
 	for {
 		select {
+		case <-stallTicker.C:
+			b.handleStalledBlocks(candidatePeers)
+
 		case m := <-b.msgChan:
 			switch msg := m.(type) {
 			case *newPeerMsg:
@@ -1467,14 +1533,14 @@ out:
 				msg.peer.txProcessed <- struct{}{}
 
 			case *blockMsg:
-				b.handleBlockMsg(msg)
+				b.handleBlockMsg(candidatePeers, msg)
 				msg.peer.blockProcessed <- struct{}{}
 
 			case *invMsg:
 				b.handleInvMsg(msg)
 
 			case *headersMsg:
-				b.handleHeadersMsg(msg)
+				b.handleHeadersMsg(candidatePeers, msg)
 
 			case *donePeerMsg:
 				b.handleDonePeerMsg(candidatePeers, msg.peer)
@@ -1667,6 +1733,12 @@ func isDoubleSpendOrDuplicateError(err error) bool {
 // handleNotifyMsg handles notifications from blockchain.  It does things such
 // as request orphan block parents and relay accepted blocks to connected peers.
 func (b *blockManager) handleNotifyMsg(notification *blockchain.Notification) {
+	// Let the index manager know about changes to the main chain so it can
+	// update the optional indexes in the background.
+	if b.server.indexManager != nil {
+		b.server.indexManager.Notify(notification)
+	}
+
 	switch notification.Type {
 	// A block that intends to extend the main chain has passed all sanity and
 	// contextual checks and the chain is believed to be current.  Relay it to
@@ -1683,7 +1755,7 @@ func (b *blockManager) handleNotifyMsg(notification *blockchain.Notification) {
 
 		// Generate the inventory vector and relay it immediately.
 		iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
-		b.server.RelayInventory(iv, block.MsgBlock().Header, true)
+		b.server.RelayInventory(iv, block, true)
 		b.announcedBlockMtx.Lock()
 		b.announcedBlock = block.Hash()
 		b.announcedBlockMtx.Unlock()
@@ -1772,7 +1844,7 @@ func (b *blockManager) handleNotifyMsg(notification *blockchain.Notification) {
 		b.announcedBlockMtx.Unlock()
 		if !sent {
 			iv := wire.NewInvVect(wire.InvTypeBlock, blockHash)
-			b.server.RelayInventory(iv, block.MsgBlock().Header, true)
+			b.server.RelayInventory(iv, block, true)
 		}
 
 		if !b.server.feeEstimator.IsEnabled() {
@@ -2022,6 +2094,11 @@ func (b *blockManager) handleNotifyMsg(notification *blockchain.Notification) {
 			r.ntfnMgr.NotifyReorganization(rd)
 		}
 
+		// Count the reorganization when metrics are enabled.
+		if m := b.server.metricsServer; m != nil {
+			m.reorganized()
+		}
+
 		// Drop the associated mining template from the old chain, since it
 		// will be no longer valid.
 		b.cachedCurrentTemplate = nil
@@ -2060,6 +2137,18 @@ func (b *blockManager) QueueBlock(block *dcrutil.Block, sp *serverPeer) {
 	b.msgChan <- &blockMsg{block: block, peer: sp}
 }
 
+// QueueCmpctBlock adds the passed block reconstructed from a compact block
+// announcement and the peer that announced it to the block handling queue.
+func (b *blockManager) QueueCmpctBlock(block *dcrutil.Block, sp *serverPeer) {
+	// Don't accept more blocks if we're shutting down.
+	if atomic.LoadInt32(&b.shutdown) != 0 {
+		sp.blockProcessed <- struct{}{}
+		return
+	}
+
+	b.msgChan <- &blockMsg{block: block, peer: sp, compact: true}
+}
+
 // QueueInv adds the passed inv message and peer to the block handling queue.
 func (b *blockManager) QueueInv(inv *wire.MsgInv, sp *serverPeer) {
 	// No channel handling here because peers do not need to block on inv
@@ -2293,13 +2382,25 @@ func (b *blockManager) ProcessBlock(block *dcrutil.Block, flags blockchain.Behav
 // ProcessTransaction makes use of ProcessTransaction on an internal instance of
 // a block chain.  It is funneled through the block manager since blockchain is
 // not safe for concurrent access.
+//
+// Since it is also called from server goroutines that may outlive the block
+// handler during shutdown, errBlockManagerShutdown is returned instead of
+// waiting for a reply once the block manager is shutting down.
 func (b *blockManager) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
 	rateLimit bool, allowHighFees bool) ([]*dcrutil.Tx, error) {
 	reply := make(chan processTransactionResponse, 1)
-	b.msgChan <- processTransactionMsg{tx, allowOrphans, rateLimit,
-		allowHighFees, reply}
-	response := <-reply
-	return response.acceptedTxs, response.err
+	select {
+	case b.msgChan <- processTransactionMsg{tx, allowOrphans, rateLimit,
+		allowHighFees, reply}:
+	case <-b.quit:
+		return nil, errBlockManagerShutdown
+	}
+	select {
+	case response := <-reply:
+		return response.acceptedTxs, response.err
+	case <-b.quit:
+		return nil, errBlockManagerShutdown
+	}
 }
 
 // IsCurrent returns whether or not the block manager believes it is synced with
@@ -2357,36 +2458,42 @@ func newBlockManager(s *server, indexManager blockchain.IndexManager, interrupt
 		progressLogger:   newBlockProgressLogger("Processed", bmgrLog),
 		msgChan:          make(chan interface{}, cfg.MaxPeers*3),
 		headerList:       list.New(),
+		blockRequests:    make(map[chainhash.Hash]*blockRequest),
+		pendingBlocks:    make(map[chainhash.Hash]*blockMsg),
 		AggressiveMining: !cfg.NonAggressive,
 		quit:             make(chan struct{}),
 	}
 
 	// Create a new block chain instance with the appropriate configuration.
+	var connectTimings func(validate, connect time.Duration)
+	if s.metricsServer != nil {
+		connectTimings = s.metricsServer.observeConnectTimings
+	}
 	var err error
 	bm.chain, err = blockchain.New(&blockchain.Config{
-		DB:            s.db,
-		Interrupt:     interrupt,
-		ChainParams:   s.chainParams,
-		TimeSource:    s.timeSource,
-		Notifications: bm.handleNotifyMsg,
-		SigCache:      s.sigCache,
-		IndexManager:  indexManager,
+		DB:             s.db,
+		Interrupt:      interrupt,
+		ChainParams:    s.chainParams,
+		TimeSource:     s.timeSource,
+		Notifications:  bm.handleNotifyMsg,
+		SigCache:       s.sigCache,
+		IndexManager:   indexManager,
+		PruneTarget:    cfg.Prune * 1024 * 1024,
+		ConnectTimings: connectTimings,
 	})
 	if err != nil {
 		return nil, err
 	}
 	best := bm.chain.BestSnapshot()
 	bm.chain.DisableCheckpoints(cfg.DisableCheckpoints)
-	if !cfg.DisableCheckpoints {
-		// Initialize the next checkpoint based on the current height.
-		bm.nextCheckpoint = bm.findNextHeaderCheckpoint(best.Height)
-		if bm.nextCheckpoint != nil {
-			bm.resetHeaderState(&best.Hash, best.Height)
-		}
-	} else {
+	if cfg.DisableCheckpoints {
 		bmgrLog.Info("Checkpoints are disabled")
 	}
 
+	// Initialize the headers-first state, including the next checkpoint,
+	// based on the current height.
+	bm.resetHeaderState(&best.Hash, best.Height)
+
 	// Dump the blockchain here if asked for it, and quit.
 	if cfg.DumpBlockchain != "" {
 		err = dumpBlockChain(bm.chain, best.Height)
@@ -2450,6 +2557,35 @@ func loadBlockDB() (database.DB, error) {
 	return db, nil
 }
 
+// loadUtxoSnapshot initializes the provided block database from the utxo
+// snapshot specified by the loadsnapshot option.  Nothing is done when the
+// database has already been initialized, which allows the option to remain
+// set after the initial bootstrap.
+func loadUtxoSnapshot(db database.DB) error {
+	file, err := os.Open(cfg.LoadSnapshot)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	dcrdLog.Infof("Loading UTXO snapshot from '%s', please wait...",
+		cfg.LoadSnapshot)
+	info, err := blockchain.LoadUtxoSnapshot(db, activeNetParams.Params, file)
+	if err == blockchain.ErrChainStateExists {
+		dcrdLog.Infof("Not loading UTXO snapshot since the block " +
+			"database is already initialized")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to load UTXO snapshot: %v", err)
+	}
+
+	dcrdLog.Infof("Loaded UTXO snapshot %v with %d UTXOs for block %v "+
+		"(height %d)", info.SnapshotHash, info.NumUtxos, info.Hash,
+		info.Height)
+	return nil
+}
+
 // dumpBlockChain dumps a map of the blockchain blocks as serialized bytes.
 func dumpBlockChain(b *blockchain.BlockChain, height int64) error {
 	bmgrLog.Infof("Writing the blockchain to disk as a flat file, " +
@@ -71,6 +71,20 @@ var MainNetParams = Params{
 		{295940, newHashFromStr("0000000000000000148852c8a919addf4043f9f267b13c08df051d359f1622ca")},
 	},
 
+	// Known good UTXO set snapshots.  The snapshot of the genesis block is
+	// created by the dumputxosnapshot RPC or utility with height 0.
+	//
+	// NOTE: Only the genesis block is committed to so far, so bootstrapping
+	// from a snapshot does not skip any blocks and is not yet usable on this
+	// network until snapshots of recent blocks are added.
+	UtxoSnapshots: []UtxoSnapshot{
+		{
+			Height:       0,
+			Hash:         newHashFromStr("298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980"),
+			SnapshotHash: newHashFromStr("3866573c9e7b26940e9061a2b3c4ec594fb78aa274e753d82c70fef203f633b4"),
+		},
+	},
+
 	// The miner confirmation window is defined as:
 	//   target proof of work timespan / target proof of work spacing
 	RuleChangeActivationQuorum:     4032, // 10 % of RuleChangeActivationInterval * TicketsPerBlock
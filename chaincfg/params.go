@@ -68,6 +68,16 @@ type Checkpoint struct {
 	Hash   *chainhash.Hash
 }
 
+// UtxoSnapshot identifies a known good snapshot of the unspent transaction
+// output set and stake ticket state at a given block.  Nodes may bootstrap
+// from a snapshot file whose contents hash to SnapshotHash instead of
+// validating every block prior to it.
+type UtxoSnapshot struct {
+	Height       int64
+	Hash         *chainhash.Hash
+	SnapshotHash *chainhash.Hash
+}
+
 // Vote describes a voting instance.  It is self-describing so that the UI can
 // be directly implemented using the fields.  Mask determines which bits can be
 // used.  Bits are enumerated and must be consecutive.  Each vote requires one
@@ -333,6 +343,10 @@ type Params struct {
 	// Checkpoints ordered from oldest to newest.
 	Checkpoints []Checkpoint
 
+	// UtxoSnapshots defines the snapshots of the unspent transaction output
+	// set that are considered valid when bootstrapping a new node.
+	UtxoSnapshots []UtxoSnapshot
+
 	// These fields are related to voting on consensus rule changes as
 	// defined by BIP0009.
 	//
@@ -690,4 +704,7 @@ func (p *Params) LatestCheckpointHeight() int64 {
 func init() {
 	// Register all default networks when the package is initialized.
 	mustRegister(&MainNetParams) // 注册main网络
+	mustRegister(&TestNet3Params)
+	mustRegister(&SimNetParams)
+	mustRegister(&RegNetParams)
 }
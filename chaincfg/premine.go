@@ -38,4 +38,27 @@ var BlockOneLedgerMainNet = []*TokenPayout{
 	{"Dse4dbCL8rRtAFQV46TAkqNXeGX3bh8RNrv", 5000 * 1e8},
 	{"Dse78aC4tE4eUhuyhYXN4nKDxb3v3rVo5Ys", 5000 * 1e8},
 	{"DseAaGdqwhTRKJMwrLXXVdy12uMyjU9hL8U", 5000 * 1e8},
-}
\ No newline at end of file
+}
+
+// BlockOneLedgerTestNet3 is the block one output ledger for testnet version 3.
+var BlockOneLedgerTestNet3 = []*TokenPayout{
+	{"TsT5rhHYqJF7sXouh9jHtwQEn5YJ9KKc5L9", 1000000 * 1e8},
+}
+
+// BlockOneLedgerSimNet is the block one output ledger for the simulation
+// network.  See "Decred organization related parameters" in simnetparams.go
+// for information on how to spend these outputs.
+var BlockOneLedgerSimNet = []*TokenPayout{
+	{"Sshw6S86G2bV6W32cbc7EhtFy8f93rU6pae", 100000 * 1e8},
+	{"SsjXRK6Xz6CFuBt6PugBvrkdAa4xGbcZ18w", 100000 * 1e8},
+	{"SsfXiYkYkCoo31CuVQw428N6wWKus2ZEw5X", 100000 * 1e8},
+}
+
+// BlockOneLedgerRegNet is the block one output ledger for the regression test
+// network.  See "Decred organization related parameters" in regnetparams.go
+// for information on how to spend these outputs.
+var BlockOneLedgerRegNet = []*TokenPayout{
+	{"RsKrWb7Vny1jnzL1sDLgKTAteh9RZcRr5g6", 100000 * 1e8},
+	{"Rs8ca5cDALtsMVD4PV3xvFTC7dmuU1juvLv", 100000 * 1e8},
+	{"RsHzbGt6YajuHpurtpqXXHz57LmYZK8w9tX", 100000 * 1e8},
+}
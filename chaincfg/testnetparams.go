@@ -0,0 +1,166 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Copyright (c) 2015-2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestNet3Params defines the network parameters for the test currency network.
+// This network is sometimes simply called "testnet".
+// This is the third public iteration of testnet.
+var TestNet3Params = Params{
+	Name:        "testnet3",
+	Net:         wire.TestNet3,
+	DefaultPort: "19108",
+	DNSSeeds: []DNSSeed{
+		{"testnet-seed.decred.mindcry.org", true},
+		{"testnet-seed.decred.netpurgatory.com", true},
+		{"testnet-seed.decred.org", true},
+	},
+
+	// Chain parameters
+	GenesisBlock:             &testNet3GenesisBlock,
+	GenesisHash:              &testNet3GenesisHash,
+	PowLimit:                 testNetPowLimit,
+	PowLimitBits:             0x1e00ffff,
+	ReduceMinDifficulty:      true,
+	MinDiffReductionTime:     time.Minute * 10, // ~99.3% chance to be mined before reduction
+	GenerateSupported:        true,
+	MaximumBlockSizes:        []int{1310720},
+	MaxTxSize:                1000000,
+	TargetTimePerBlock:       time.Minute * 2,
+	WorkDiffAlpha:            1,
+	WorkDiffWindowSize:       144,
+	WorkDiffWindows:          20,
+	TargetTimespan:           time.Minute * 2 * 144, // TimePerBlock * WindowSize
+	RetargetAdjustmentFactor: 4,
+
+	// Subsidy parameters.
+	BaseSubsidy:              2500000000, // 25 Coin
+	MulSubsidy:               100,
+	DivSubsidy:               101,
+	SubsidyReductionInterval: 2048,
+	WorkRewardProportion:     6,
+	StakeRewardProportion:    3,
+	BlockTaxProportion:       1,
+
+	// Checkpoints ordered from oldest to newest.
+	Checkpoints: nil,
+
+	// Known good UTXO set snapshots.  The snapshot of the genesis block is
+	// created by the dumputxosnapshot RPC or utility with height 0.
+	//
+	// NOTE: Only the genesis block is committed to so far, so bootstrapping
+	// from a snapshot does not skip any blocks and is not yet usable on this
+	// network until snapshots of recent blocks are added.
+	UtxoSnapshots: []UtxoSnapshot{
+		{
+			Height:       0,
+			Hash:         newHashFromStr("a649dce53918caf422e9c711c858837e08d626ecfcd198969b24f7b634a49bac"),
+			SnapshotHash: newHashFromStr("c6614480f357ba5b88d014d199793239abeb4bd247c66d5434951d287ed43c5e"),
+		},
+	},
+
+	// Consensus rule change deployments.
+	//
+	// The miner confirmation window is defined as:
+	//   target proof of work timespan / target proof of work spacing
+	RuleChangeActivationQuorum:     2520, // 10 % of RuleChangeActivationInterval * TicketsPerBlock
+	RuleChangeActivationMultiplier: 3,    // 75%
+	RuleChangeActivationDivisor:    4,
+	RuleChangeActivationInterval:   5040, // 1 week
+	Deployments: map[uint32][]ConsensusDeployment{
+		7: {{
+			Vote: Vote{
+				Id:          VoteIDFixLNSeqLocks,
+				Description: "Modify sequence lock handling as defined in DCP0004",
+				Mask:        0x0006, // Bits 1 and 2
+				Choices: []Choice{{
+					Id:          "abstain",
+					Description: "abstain voting for change",
+					Bits:        0x0000,
+					IsAbstain:   true,
+					IsNo:        false,
+				}, {
+					Id:          "no",
+					Description: "keep the existing consensus rules",
+					Bits:        0x0002, // Bit 1
+					IsAbstain:   false,
+					IsNo:        true,
+				}, {
+					Id:          "yes",
+					Description: "change to the new consensus rules",
+					Bits:        0x0004, // Bit 2
+					IsAbstain:   false,
+					IsNo:        false,
+				}},
+			},
+			StartTime:  1548633600, // Jan 28th, 2019
+			ExpireTime: 1580169600, // Jan 28th, 2020
+		}},
+	},
+
+	// Enforce current block version once majority of the network has
+	// upgraded.
+	// 51% (51 / 100)
+	// Reject previous block versions once a majority of the network has
+	// upgraded.
+	// 75% (75 / 100)
+	BlockEnforceNumRequired: 51,
+	BlockRejectNumRequired:  75,
+	BlockUpgradeNumToCheck:  100,
+
+	// AcceptNonStdTxs is a mempool param to either accept and relay
+	// non standard txs to the network or reject them
+	AcceptNonStdTxs: true,
+
+	// Address encoding magics
+	NetworkAddressPrefix: "T",
+	PubKeyAddrID:         [2]byte{0x28, 0xf7}, // starts with Tk
+	PubKeyHashAddrID:     [2]byte{0x0f, 0x21}, // starts with Ts
+	PKHEdwardsAddrID:     [2]byte{0x0f, 0x01}, // starts with Te
+	PKHSchnorrAddrID:     [2]byte{0x0e, 0xe3}, // starts with TS
+	ScriptHashAddrID:     [2]byte{0x0e, 0xfc}, // starts with Tc
+	PrivateKeyID:         [2]byte{0x23, 0x0e}, // starts with Pt
+
+	// BIP32 hierarchical deterministic extended key magics
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x97}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xd1}, // starts with tpub
+
+	// BIP44 coin type used in the hierarchical deterministic path for
+	// address generation.
+	SLIP0044CoinType: 1,  // SLIP0044, Testnet (all coins)
+	LegacyCoinType:   11, // for backwards compatibility
+
+	// Decred PoS parameters
+	MinimumStakeDiff:        20000000, // 0.2 Coin
+	TicketPoolSize:          1024,
+	TicketsPerBlock:         5,
+	TicketMaturity:          16,
+	TicketExpiry:            6144, // 6*TicketPoolSize
+	CoinbaseMaturity:        16,
+	SStxChangeMaturity:      1,
+	TicketPoolSizeWeight:    4,
+	StakeDiffAlpha:          1,
+	StakeDiffWindowSize:     144,
+	StakeDiffWindows:        20,
+	StakeVersionInterval:    144 * 2 * 7, // ~1 week
+	MaxFreshStakePerBlock:   20,          // 4*TicketsPerBlock
+	StakeEnabledHeight:      16 + 16,     // CoinbaseMaturity + TicketMaturity
+	StakeValidationHeight:   768,         // Arbitrary
+	StakeBaseSigScript:      []byte{0x00, 0x00},
+	StakeMajorityMultiplier: 3,
+	StakeMajorityDivisor:    4,
+
+	// Decred organization related parameters.
+	// Organization address is TcrypGAcGCRVXrES7hWqVZb5oLJKCZEtoL1
+	OrganizationPkScript:        hexDecode("a914d585cd7426d25b4ea5faf1e6987aacfeda3db94287"),
+	OrganizationPkScriptVersion: 0,
+	BlockOneLedger:              BlockOneLedgerTestNet3,
+}
@@ -35,6 +35,7 @@ type importResults struct {
 type blockImporter struct {
 	db                database.DB
 	chain             *blockchain.BlockChain
+	indexManager      *indexers.Manager
 	r                 io.ReadSeeker
 	processQueue      chan []byte
 	doneChan          chan bool
@@ -293,6 +294,24 @@ func (bi *blockImporter) Import() chan *importResults {
 	// the status handler when done.
 	go func() {
 		bi.wg.Wait()
+
+		// The optional indexes are updated independently of block
+		// connection, so bring them up to date with the imported blocks
+		// before reporting the import as done.
+		if bi.indexManager != nil {
+			select {
+			case <-bi.quit:
+				return
+			default:
+			}
+
+			log.Info("Catching up optional indexes")
+			if err := bi.indexManager.Sync(bi.quit); err != nil {
+				bi.errChan <- err
+				return
+			}
+		}
+
 		bi.doneChan <- true
 	}()
 
@@ -336,16 +355,18 @@ func newBlockImporter(db database.DB, r io.ReadSeeker) (*blockImporter, error) {
 	}
 
 	// Create an index manager if any of the optional indexes are enabled.
-	var indexManager blockchain.IndexManager
+	var indexManager *indexers.Manager
+	var chainIndexManager blockchain.IndexManager
 	if len(indexes) > 0 {
 		indexManager = indexers.NewManager(db, indexes, activeNetParams)
+		chainIndexManager = indexManager
 	}
 
 	chain, err := blockchain.New(&blockchain.Config{
 		DB:           db,
 		ChainParams:  activeNetParams,
 		TimeSource:   blockchain.NewMedianTime(),
-		IndexManager: indexManager,
+		IndexManager: chainIndexManager,
 	})
 	if err != nil {
 		return nil, err
@@ -359,6 +380,7 @@ func newBlockImporter(db database.DB, r io.ReadSeeker) (*blockImporter, error) {
 		errChan:      make(chan error),
 		quit:         make(chan struct{}),
 		chain:        chain,
+		indexManager: indexManager,
 		lastLogTime:  time.Now(),
 		startTime:    time.Now(),
 	}, nil
@@ -41,6 +41,7 @@ var (
 	defaultRPCServer       = "localhost"
 	defaultWalletRPCServer = "localhost"
 	defaultRPCCertFile     = filepath.Join(dcrdHomeDir, "rpc.cert")
+	defaultDcrdDataDir     = filepath.Join(dcrdHomeDir, "data")
 	defaultWalletCertFile  = filepath.Join(dcrwalletHomeDir, "rpc.cert")
 )
 
@@ -106,6 +107,7 @@ type config struct {
 	ConfigFile      string `short:"C" long:"configfile" description:"Path to configuration file"`
 	RPCUser         string `short:"u" long:"rpcuser" description:"RPC username"`
 	RPCPassword     string `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
+	RPCCookieFile   string `long:"rpccookiefile" description:"File to read the RPC username and password from when neither is specified (default: .cookie in the dcrd data directory of the network)"`
 	RPCServer       string `short:"s" long:"rpcserver" description:"RPC server to connect to"`
 	WalletRPCServer string `short:"w" long:"walletrpcserver" description:"Wallet RPC server to connect to"`
 	RPCCert         string `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
@@ -216,6 +218,20 @@ func fileExists(name string) bool {
 	return true
 }
 
+// readCookieFile returns the RPC username and password in the cookie file at
+// the passed path.
+func readCookieFile(path string) (string, string, error) {
+	cookie, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(cookie)), ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("malformed cookie file %s", path)
+	}
+	return parts[0], parts[1], nil
+}
+
 // loadConfig initializes and parses the config using a config file and command
 // line options.
 //
@@ -337,6 +353,33 @@ func loadConfig() (*config, []string, error) {
 	// Handle environment variable expansion in the RPC certificate path.
 	cfg.RPCCert = cleanAndExpandPath(cfg.RPCCert)
 
+	// Read the RPC username and password from the cookie file dcrd writes
+	// random credentials to when it is not configured with any.  A missing
+	// cookie file is only an error when it was specified explicitly.
+	if cfg.RPCUser == "" && cfg.RPCPassword == "" && !cfg.Wallet {
+		cookieFile := cfg.RPCCookieFile
+		if cookieFile == "" {
+			netName := "mainnet"
+			switch {
+			case cfg.TestNet:
+				netName = "testnet3"
+			case cfg.SimNet:
+				netName = "simnet"
+			}
+			cookieFile = filepath.Join(defaultDcrdDataDir, netName,
+				".cookie")
+		}
+		cookieFile = cleanAndExpandPath(cookieFile)
+		if cfg.RPCCookieFile != "" || fileExists(cookieFile) {
+			user, pass, err := readCookieFile(cookieFile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return nil, nil, err
+			}
+			cfg.RPCUser, cfg.RPCPassword = user, pass
+		}
+	}
+
 	// Add default port to RPC server based on --testnet and --wallet flags
 	// if needed.
 	cfg.RPCServer = normalizeAddress(cfg.RPCServer, cfg.TestNet,
@@ -0,0 +1,121 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// dumputxosnapshot writes a utxo snapshot of the main chain in an existing
+// dcrd block database.  It performs the same function as the dumputxosnapshot
+// RPC, but does not require a running dcrd, which must be stopped while the
+// tool runs since the database can only be opened by one process at a time.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/database"
+)
+
+const blockDbNamePrefix = "blocks"
+
+var (
+	cfg *config
+)
+
+// loadBlockDB opens the block database and returns a handle to it.
+func loadBlockDB() (database.DB, error) {
+	// The database name is based on the database type.
+	dbName := blockDbNamePrefix + "_" + cfg.DbType
+	dbPath := filepath.Join(cfg.DataDir, dbName)
+	fmt.Printf("Loading block database from '%s'\n", dbPath)
+	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net)
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// writeSnapshot writes the utxo snapshot of the main chain block at the passed
+// height to the output file.  The snapshot is written to a temporary file which
+// is only moved to the output path once it is complete.
+func writeSnapshot(chain *blockchain.BlockChain, height int64) (*blockchain.UtxoSnapshotInfo, error) {
+	tmpPath := cfg.OutputFile + ".incomplete"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+		0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := chain.WriteUtxoSnapshot(file, height)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, cfg.OutputFile)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+	return info, nil
+}
+
+func main() {
+	// Load configuration and parse command line.
+	tcfg, _, err := loadConfig()
+	if err != nil {
+		os.Exit(1)
+	}
+	cfg = tcfg
+
+	// Load the block database.
+	db, err := loadBlockDB()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load database:", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// Setup chain.  Ignore notifications since they aren't needed for this
+	// util.
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: activeNetParams,
+		TimeSource:  blockchain.NewMedianTime(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize chain: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+
+	best := chain.BestSnapshot()
+	fmt.Printf("Block database loaded with block height %d\n", best.Height)
+	height := cfg.Height
+	if height < 0 {
+		height = best.Height
+	}
+	if height > best.Height {
+		fmt.Fprintf(os.Stderr, "height %d is not in the main chain (best "+
+			"height %d)\n", height, best.Height)
+		db.Close()
+		os.Exit(1)
+	}
+
+	fmt.Printf("Writing the UTXO snapshot of block height %d to '%s', "+
+		"please wait...\n", height, cfg.OutputFile)
+	info, err := writeSnapshot(chain, height)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write snapshot: %v\n", err)
+		db.Close()
+		os.Exit(1)
+	}
+
+	fmt.Printf("Block hash:    %v\n", info.Hash)
+	fmt.Printf("Block height:  %d\n", info.Height)
+	fmt.Printf("Snapshot hash: %v\n", info.SnapshotHash)
+	fmt.Printf("UTXOs:         %d\n", info.NumUtxos)
+}
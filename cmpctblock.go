@@ -0,0 +1,253 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dchest/siphash"
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/mempool/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+// shortTxIDMask is the mask applied to the hashes of transaction ids to obtain
+// the short transaction ids of compact blocks.
+const shortTxIDMask = 1<<(wire.ShortTxIDSize*8) - 1
+
+// maxBlockTxnDepth is the maximum number of blocks a block may be below the tip
+// of the main chain for the transactions requested from it with a getblocktxn
+// message to be served.  Requests for older blocks are answered with the full
+// block.
+const maxBlockTxnDepth = 10
+
+// shortTxIDKeys returns the siphash keys used to derive the short transaction
+// ids of a compact block with the passed header and nonce.  The keys are the
+// first 16 bytes of the BLAKE256 hash of the serialized header followed by the
+// nonce, which prevents the ids from being predicted by anyone that does not
+// know the block yet.
+func shortTxIDKeys(header *wire.BlockHeader, nonce uint64) (uint64, uint64, error) {
+	var buf bytes.Buffer
+	buf.Grow(wire.MaxBlockHeaderPayload + 8)
+	if err := header.Serialize(&buf); err != nil {
+		return 0, 0, err
+	}
+	var nonceBytes [8]byte
+	binary.LittleEndian.PutUint64(nonceBytes[:], nonce)
+	buf.Write(nonceBytes[:])
+
+	hash := chainhash.HashB(buf.Bytes())
+	k0 := binary.LittleEndian.Uint64(hash[0:8])
+	k1 := binary.LittleEndian.Uint64(hash[8:16])
+	return k0, k1, nil
+}
+
+// shortTxID returns the short id of the transaction with the passed hash for
+// the given siphash keys.
+//
+// The short ids are derived from the transaction hashes which exclude the
+// witness data.  A block reconstructed from transactions with different
+// witness data fails the merkle root checks and is then requested in full.
+func shortTxID(k0, k1 uint64, txHash *chainhash.Hash) uint64 {
+	return siphash.Hash(k0, k1, txHash[:]) & shortTxIDMask
+}
+
+// newCmpctBlock returns a compact block message for the passed block with a
+// random nonce.  The coinbase is prefilled since the receiving peer can't
+// possibly have it while all other transactions are sent as short ids.
+func newCmpctBlock(block *dcrutil.Block) (*wire.MsgCmpctBlock, error) {
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return nil, err
+	}
+	msgBlock := block.MsgBlock()
+	msg := wire.NewMsgCmpctBlock(&msgBlock.Header, nonce)
+	k0, k1, err := shortTxIDKeys(&msg.Header, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := block.Transactions()
+	msg.ShortIDs = make([]uint64, 0, len(txns))
+	for i, tx := range txns {
+		if i == 0 {
+			msg.PrefilledTxs = []wire.PrefilledTx{{Tx: tx.MsgTx()}}
+			continue
+		}
+		msg.ShortIDs = append(msg.ShortIDs, shortTxID(k0, k1, tx.Hash()))
+	}
+	stxns := block.STransactions()
+	msg.StakeShortIDs = make([]uint64, 0, len(stxns))
+	for _, stx := range stxns {
+		msg.StakeShortIDs = append(msg.StakeShortIDs,
+			shortTxID(k0, k1, stx.Hash()))
+	}
+	return msg, nil
+}
+
+// partialBlock houses a block announced with a compact block message while its
+// transactions are being collected.  The transactions that are still missing
+// are nil.
+type partialBlock struct {
+	header wire.BlockHeader
+	hash   chainhash.Hash
+	txns   []*wire.MsgTx
+	stxns  []*wire.MsgTx
+}
+
+// fillCmpctTxTree places the prefilled transactions of one transaction tree of
+// a compact block at their positions in txns and the transactions matching the
+// short ids in the remaining positions.  Short ids that match more than one
+// transaction are left for the peer to provide.
+func fillCmpctTxTree(txns []*wire.MsgTx, shortIDs []uint64, prefilled []wire.PrefilledTx, pool map[uint64]*wire.MsgTx) error {
+	for _, ptx := range prefilled {
+		txns[ptx.Index] = ptx.Tx
+	}
+
+	seen := make(map[uint64]struct{}, len(shortIDs))
+	var next int
+	for _, id := range shortIDs {
+		for txns[next] != nil {
+			next++
+		}
+		if _, ok := seen[id]; ok {
+			return fmt.Errorf("duplicate short transaction id %x", id)
+		}
+		seen[id] = struct{}{}
+		txns[next] = pool[id]
+		next++
+	}
+	return nil
+}
+
+// newPartialBlock returns a partial block for the passed compact block with
+// all transactions that are either prefilled or found among the passed memory
+// pool transactions.  An error is returned when the compact block can't be
+// used to reconstruct the block, in which case the full block needs to be
+// requested.
+func newPartialBlock(msg *wire.MsgCmpctBlock, txDescs []*mempool.TxDesc) (*partialBlock, error) {
+	k0, k1, err := shortTxIDKeys(&msg.Header, msg.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the memory pool transactions by their short ids.  Colliding
+	// ids are mapped to nil so the transactions are requested instead.
+	pool := make(map[uint64]*wire.MsgTx, len(txDescs))
+	for _, txDesc := range txDescs {
+		id := shortTxID(k0, k1, txDesc.Tx.Hash())
+		if _, ok := pool[id]; ok {
+			pool[id] = nil
+			continue
+		}
+		pool[id] = txDesc.Tx.MsgTx()
+	}
+
+	pb := &partialBlock{
+		header: msg.Header,
+		hash:   msg.Header.BlockHash(),
+		txns:   make([]*wire.MsgTx, msg.TxCount()),
+		stxns:  make([]*wire.MsgTx, msg.STxCount()),
+	}
+	err = fillCmpctTxTree(pb.txns, msg.ShortIDs, msg.PrefilledTxs, pool)
+	if err != nil {
+		return nil, err
+	}
+	err = fillCmpctTxTree(pb.stxns, msg.StakeShortIDs,
+		msg.StakePrefilledTxs, pool)
+	if err != nil {
+		return nil, err
+	}
+	return pb, nil
+}
+
+// missingIndexes returns the positions of the transactions in the passed list
+// that are still missing.
+func missingIndexes(txns []*wire.MsgTx) []uint32 {
+	var indexes []uint32
+	for i, tx := range txns {
+		if tx == nil {
+			indexes = append(indexes, uint32(i))
+		}
+	}
+	return indexes
+}
+
+// missing returns the positions of the transactions of the regular and stake
+// transaction trees that are still missing.
+func (pb *partialBlock) missing() ([]uint32, []uint32) {
+	return missingIndexes(pb.txns), missingIndexes(pb.stxns)
+}
+
+// fillMissing places the passed transactions at the positions of the missing
+// transactions in txns in order.
+func fillMissing(txns []*wire.MsgTx, missing []*wire.MsgTx) error {
+	var next int
+	for i, tx := range txns {
+		if tx != nil {
+			continue
+		}
+		if next == len(missing) {
+			return fmt.Errorf("%d transactions provided while more "+
+				"are missing", len(missing))
+		}
+		txns[i] = missing[next]
+		next++
+	}
+	if next != len(missing) {
+		return fmt.Errorf("%d transactions provided while %d are "+
+			"missing", len(missing), next)
+	}
+	return nil
+}
+
+// fill places the transactions of the passed blocktxn message, which must
+// provide exactly the missing transactions, into the partial block.
+func (pb *partialBlock) fill(msg *wire.MsgBlockTxn) error {
+	if err := fillMissing(pb.txns, msg.Transactions); err != nil {
+		return err
+	}
+	return fillMissing(pb.stxns, msg.STransactions)
+}
+
+// block returns the reconstructed block once no transactions are missing.  An
+// error is returned when the transactions don't commit to the merkle roots in
+// the header, which happens when a short id matched the wrong transaction.
+func (pb *partialBlock) block() (*dcrutil.Block, error) {
+	msgBlock := &wire.MsgBlock{
+		Header:        pb.header,
+		Transactions:  pb.txns,
+		STransactions: pb.stxns,
+	}
+	for _, tx := range msgBlock.Transactions {
+		if tx == nil {
+			return nil, fmt.Errorf("block %v is missing transactions",
+				pb.hash)
+		}
+	}
+	for _, stx := range msgBlock.STransactions {
+		if stx == nil {
+			return nil, fmt.Errorf("block %v is missing stake "+
+				"transactions", pb.hash)
+		}
+	}
+
+	block := dcrutil.NewBlock(msgBlock)
+	merkles := blockchain.BuildMerkleTreeStore(block.Transactions())
+	if !pb.header.MerkleRoot.IsEqual(merkles[len(merkles)-1]) {
+		return nil, fmt.Errorf("reconstructed block %v does not match "+
+			"the merkle root", pb.hash)
+	}
+	merkles = blockchain.BuildMerkleTreeStore(block.STransactions())
+	if !pb.header.StakeRoot.IsEqual(merkles[len(merkles)-1]) {
+		return nil, fmt.Errorf("reconstructed block %v does not match "+
+			"the stake root", pb.hash)
+	}
+	return block, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/decred/dcrd/database"
 	_ "github.com/decred/dcrd/database/ffldb"
+	"github.com/decred/dcrd/dcrec/secp256k1"
 	"github.com/decred/dcrd/dcrjson"
 	"github.com/decred/dcrd/dcrutil"
 	"github.com/decred/dcrd/mempool"
@@ -30,11 +32,13 @@ const (
 	defaultLogFilename           = "dcrd.log"
 	defaultMaxSameIP             = 5
 	defaultMaxPeers              = 125
+	defaultBlockRelayOnlyPeers   = 2
 	defaultBanDuration           = time.Hour * 24
 	defaultBanThreshold          = 100
 	defaultMaxRPCClients         = 10
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
+	defaultRESTRateLimit         = 10.0
 	defaultDbType                = "ffldb"
 	defaultFreeTxRelayLimit      = 15.0
 	defaultBlockMinSize          = 0
@@ -46,10 +50,16 @@ const (
 	defaultAllowOldVotes         = false
 	defaultMaxOrphanTransactions = 1000
 	defaultMaxOrphanTxSize       = 5000
+	defaultMaxMempool            = 300
 	defaultSigCacheMaxSize       = 100000
 	defaultTxIndex               = false
 	defaultNoExistsAddrIndex     = false
 	defaultNoCFilters            = false
+	defaultPrune                 = 0
+	defaultStratumPort           = "3333"
+	defaultStratumShareDiff      = 1.0
+	defaultMetricsPort           = "9108"
+	minPruneTargetMB             = 1024
 )
 
 var (
@@ -90,22 +100,36 @@ type config struct {
 	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
 	MaxSameIP            int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
 	MaxPeers             int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
+	BlockRelayOnlyPeers  int           `long:"blockrelayonlypeers" description:"Number of additional outbound peers that are only used to relay blocks and headers -- The last known good ones are reconnected first on startup"`
 	DisableBanning       bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
 	BanDuration          time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	BanThreshold         uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
+	ASMap                string        `long:"asmap" description:"Path to a file that maps IP addresses to the autonomous systems (ASNs) announcing them, which is used to diversify the peers by autonomous system rather than by network prefix"`
+	MaxUploadTarget      uint64        `long:"maxuploadtarget" description:"Maximum number of bytes to upload to peers per 24 hours -- Historical blocks are no longer served to peers that are not whitelisted once the remainder is needed for new blocks (0 for unlimited)"`
+	MaxUploadRate        uint64        `long:"maxuploadrate" description:"Maximum combined rate in KiB/s at which data is uploaded to peers that are not whitelisted (0 for unlimited)"`
+	MaxPeerUploadRate    uint64        `long:"maxpeeruploadrate" description:"Maximum rate in KiB/s at which data is uploaded to each peer that is not whitelisted (0 for unlimited)"`
+	P2PEncryption        bool          `long:"p2pencryption" description:"Encrypt the communication with peers that also support the encrypted transport"`
+	P2PPeerKeys          []string      `long:"p2ppeerkey" description:"Require the peer at the given IP address to authenticate with the given identity key over the encrypted transport -- Requires --p2pencryption (format: <pubkey>@<ip>)"`
 	RPCUser              string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
 	RPCPass              string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
+	RPCCookieFile        string        `long:"rpccookiefile" description:"File to write the random credentials for RPC connections to when no rpcuser/rpcpass is specified (default: .cookie in the data directory)"`
 	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
 	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
 	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
 	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
 	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
-	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server"`
 	DisableTLS           bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
+	REST                 bool          `long:"rest" description:"Serve the unauthenticated and read-only REST interface under /rest/ on the RPC listeners"`
+	RESTRateLimit        float64       `long:"restratelimit" description:"Max number of REST requests per second from each client IP"`
+	GRPCListeners        []string      `long:"grpclisten" description:"Add an interface/port to serve the gRPC API with the TLS and authentication settings of the RPC server (default port: 9113, testnet: 19113)"`
+	MetricsListeners     []string      `long:"metricslisten" description:"Add an interface/port to serve Prometheus metrics over HTTP (default port: 9108)"`
 	DisableDNSSeed       bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
 	ExternalIPs          []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
 	TestNet              bool          `long:"testnet" description:"Use the test network"`
+	SimNet               bool          `long:"simnet" description:"Use the simulation test network"`
+	RegNet               bool          `long:"regnet" description:"Use the regression test network"`
 	DisableCheckpoints   bool          `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing."`
 	DbType               string        `long:"dbtype" description:"Database backend to use for the Block Chain"`
 	DumpBlockchain       string        `long:"dumpblockchain" description:"Write blockchain as a flat file of blocks for use with addblock, to the specified filename"`
@@ -116,8 +140,12 @@ type config struct {
 	FreeTxRelayLimit     float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
 	NoRelayPriority      bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
 	MaxOrphanTxs         int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	MaxMempool           int64         `long:"maxmempool" description:"Max total size of the transactions in the memory pool in MiB -- The lowest fee rate transactions are evicted when it is exceeded (0 = unlimited)"`
 	Generate             bool          `long:"generate" description:"Generate (mine) coins using the CPU"`
 	MiningAddrs          []string      `long:"miningaddr" description:"Add the specified payment address to the list of addresses to use for generated blocks -- At least one address is required if the generate option is set"`
+	StratumListeners     []string      `long:"stratumlisten" description:"Add an interface/port to listen for stratum mining connections -- Requires at least one mining address (default port: 3333)"`
+	StratumWorkers       []string      `long:"stratumworker" description:"Add a worker name and password, separated by a colon, that stratum miners may authorize as -- Stratum connections are only accepted on localhost addresses when no workers are specified"`
+	StratumShareDiff     float64       `long:"stratumsharediff" description:"The minimum difficulty, as a multiple of the minimum network difficulty, of shares accepted from stratum miners"`
 	BlockMinSize         uint32        `long:"blockminsize" description:"Mininum block size in bytes to be used when creating a block"`
 	BlockMaxSize         uint32        `long:"blockmaxsize" description:"Maximum block size in bytes to be used when creating a block"`
 	BlockPrioritySize    uint32        `long:"blockprioritysize" description:"Size in bytes for high-priority/low-fee transactions when creating a block"`
@@ -126,6 +154,8 @@ type config struct {
 	NoMiningStateSync    bool          `long:"nominingstatesync" description:"Disable synchronizing the mining state with other nodes"`
 	AllowOldVotes        bool          `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
 	BlocksOnly           bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	Dandelion            bool          `long:"dandelion" description:"Relay new transactions through a path of single peers before announcing them to the network (dandelion) to hide the node they originated from"`
+	TxReconciliation     bool          `long:"txreconciliation" description:"Periodically reconcile the transactions announced to peers that support it instead of announcing every transaction individually"`
 	AcceptNonStd         bool          `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network."`
 	RejectNonStd         bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	TxIndex              bool          `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
@@ -135,11 +165,16 @@ type config struct {
 	NoExistsAddrIndex    bool          `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used."`
 	DropExistsAddrIndex  bool          `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits."`
 	NoCFilters           bool          `long:"nocfilters" description:"Disable compact filtering (CF) support"`
+	SPV                  bool          `long:"spv" description:"Run as a light client that only syncs block headers and uses the committed filters served by peers to find the blocks relevant to the addresses and outpoints loaded by websocket clients -- Implies --blocksonly and is incompatible with --generate, --txindex, --addrindex and --backvalidate"`
 	DropCFIndex          bool          `long:"dropcfindex" description:"Deletes the index used for compact filtering (CF) support from the database on start up and then exits."`
+	Prune                uint64        `long:"prune" description:"Reduce storage requirements by deleting the data of old blocks so the stored blocks stay under the given target size in MiB (0 = disabled) -- The most recent blocks needed for reorganizations are always kept -- Incompatible with --txindex and --addrindex"`
+	LoadSnapshot         string        `long:"loadsnapshot" description:"Initialize a new blockchain database from the UTXO snapshot at the given path instead of syncing the blocks prior to it -- The snapshot must be committed to by the active network parameters, which currently only commit to the genesis block, so the option is not yet usable for bootstrapping -- The blocks prior to the snapshot are only validated when --backvalidate is set -- Requires --nocfilters and --noexistsaddrindex and is incompatible with --txindex and --addrindex"`
+	BackValidate         bool          `long:"backvalidate" description:"Download and validate the blocks prior to the UTXO snapshot the blockchain database was initialized from in the background and shut down when they do not result in the same UTXO set -- Uses a separate database that is removed once the history has been validated"`
 	PipeRx               uint          `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
 	PipeTx               uint          `long:"pipetx" description:"File descriptor of write end pipe to enable parent <- child process communication"`
 	LifetimeEvents       bool          `long:"lifetimeevents" description:"Send lifetime notifications over the TX pipe"`
 	AltDNSNames          []string      `long:"altdnsnames" description:"Specify additional dns names to use when generating the rpc server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
+	RPCUsers             rpcUsersOptions `group:"RPC Users"`
 	onionlookup          func(string) ([]net.IP, error)
 	lookup               func(string) ([]net.IP, error)
 	oniondial            func(string, string) (net.Conn, error)
@@ -147,10 +182,26 @@ type config struct {
 	miningAddrs          []dcrutil.Address
 	minRelayTxFee        dcrutil.Amount
 	whitelists           []*net.IPNet
+	p2pPeerKeys          map[string]*secp256k1.PublicKey
+	rpcACLUsers          *rpcACLUsers
+	rpcCookieAuth        bool
 	ipv4NetInfo          dcrjson.NetworksResult
 	ipv6NetInfo          dcrjson.NetworksResult
 }
 
+// rpcUsersOptions defines the configuration options for the RPC users that are
+// only authorized for the RPC methods and websocket notifications granted to
+// them.  They are housed in the RPC Users section of the configuration file.
+type rpcUsersOptions struct {
+	Users        []string `long:"rpcacluser" description:"Add an RPC user that authenticates with the password whose hex-encoded HMAC-SHA256 keyed by the given salt is given (format: <name>:<salt>$<passwordhmac>)"`
+	CertUsers    []string `long:"rpcaclcertuser" description:"Add an RPC user that authenticates with a TLS client certificate with the given common name -- Requires --rpcclientcafile (format: <name>:<commonname>)"`
+	Allow        []string `long:"rpcaclallow" description:"Authorize an RPC user for the given comma-separated RPC methods, or * for all methods (format: <name>:<methods>)"`
+	Deny         []string `long:"rpcacldeny" description:"Deny an RPC user the given comma-separated RPC methods, or * for all methods, even when they are allowed (format: <name>:<methods>)"`
+	AllowNtfns   []string `long:"rpcaclallowntfn" description:"Authorize an RPC user for the given comma-separated websocket notifications, or * for all notifications (format: <name>:<notifications>)"`
+	DenyNtfns    []string `long:"rpcacldenyntfn" description:"Deny an RPC user the given comma-separated websocket notifications, or * for all notifications, even when they are allowed (format: <name>:<notifications>)"`
+	ClientCAFile string   `long:"rpcclientcafile" description:"File containing the certificate authorities that issue the TLS client certificates of RPC users"`
+}
+
 // serviceOptions defines the configuration options for the daemon as a service on
 // Windows.
 type serviceOptions struct {
@@ -373,11 +424,13 @@ func loadConfig() (*config, []string, error) {
 		DebugLevel:           defaultLogLevel,
 		MaxSameIP:            defaultMaxSameIP,
 		MaxPeers:             defaultMaxPeers, // 125
+		BlockRelayOnlyPeers:  defaultBlockRelayOnlyPeers,
 		BanDuration:          defaultBanDuration,
 		BanThreshold:         defaultBanThreshold,
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs, // 20
+		RESTRateLimit:        defaultRESTRateLimit,
 		DataDir:              defaultDataDir,              // ~/.dcrd/data
 		LogDir:               defaultLogDir,
 		DbType:               defaultDbType, // "ffldb"
@@ -385,10 +438,12 @@ func loadConfig() (*config, []string, error) {
 		RPCCert:              defaultRPCCertFile,
 		MinRelayTxFee:        mempool.DefaultMinRelayTxFee.ToCoin(), // 0.0001
 		FreeTxRelayLimit:     defaultFreeTxRelayLimit,
+		StratumShareDiff:     defaultStratumShareDiff,
 		BlockMinSize:         defaultBlockMinSize,              // 0
 		BlockMaxSize:         defaultBlockMaxSize,              // 375000
 		BlockPrioritySize:    mempool.DefaultBlockPrioritySize, // 20000
 		MaxOrphanTxs:         defaultMaxOrphanTransactions,     // 1000
+		MaxMempool:           defaultMaxMempool,
 		SigCacheMaxSize:      defaultSigCacheMaxSize,           // 100000
 		Generate:             defaultGenerate,
 		NoMiningStateSync:    defaultNoMiningStateSync,
@@ -397,6 +452,7 @@ func loadConfig() (*config, []string, error) {
 		AllowOldVotes:        defaultAllowOldVotes,
 		NoExistsAddrIndex:    defaultNoExistsAddrIndex,
 		NoCFilters:           defaultNoCFilters, // false
+		Prune:                defaultPrune,
 		AltDNSNames:          defaultAltDNSNames,
 		ipv4NetInfo:          dcrjson.NetworksResult{Name: "IPV4"},
 		ipv6NetInfo:          dcrjson.NetworksResult{Name: "IPV6"},
@@ -472,6 +528,31 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Multiple networks can't be selected simultaneously.  Count number of
+	// network flags passed and assign active network params while we're at
+	// it.
+	numNets := 0
+	if cfg.TestNet {
+		numNets++
+		activeNetParams = &testNet3Params
+	}
+	if cfg.SimNet {
+		numNets++
+		// Also disable dns seeding on the simulation test network.
+		activeNetParams = &simNetParams
+		cfg.DisableDNSSeed = true
+	}
+	if cfg.RegNet {
+		numNets++
+		activeNetParams = &regNetParams
+	}
+	if numNets > 1 {
+		str := "%s: the testnet, regnet, and simnet params can't be " +
+			"used together -- choose one of the three"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
 	// Set the default policy for relaying non-standard transactions
 	// according to the default of the active network. The set
 	// configuration value takes precedence over the default value for the
@@ -508,6 +589,67 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Don't allow a negative number of block relay only peers.
+	if cfg.BlockRelayOnlyPeers < 0 {
+		str := "%s: the blockrelayonlypeers option may not be less " +
+			"than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.BlockRelayOnlyPeers)
+		return nil, nil, err
+	}
+
+	// Transactions are not relayed in blocks only mode.
+	if cfg.Dandelion && cfg.BlocksOnly {
+		str := "%s: the dandelion and blocksonly options may not be " +
+			"used together"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// Light clients do not keep the data of the blocks, which the indexes
+	// and mining require, and do not relay transactions.
+	if cfg.SPV && (cfg.Generate || cfg.TxIndex || cfg.AddrIndex ||
+		cfg.BackValidate) {
+
+		str := "%s: the spv option can't be used with the generate, " +
+			"txindex, addrindex or backvalidate options"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	if cfg.SPV {
+		cfg.BlocksOnly = true
+	}
+
+	// Parse the identity keys pinned to peers, which are only verified over
+	// the encrypted transport.
+	if len(cfg.P2PPeerKeys) > 0 && !cfg.P2PEncryption {
+		str := "%s: the p2ppeerkey option requires the p2pencryption " +
+			"option"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	cfg.p2pPeerKeys = make(map[string]*secp256k1.PublicKey,
+		len(cfg.P2PPeerKeys))
+	for _, peerKey := range cfg.P2PPeerKeys {
+		parts := strings.Split(peerKey, "@")
+		var ip net.IP
+		var pubKey *secp256k1.PublicKey
+		if len(parts) == 2 {
+			ip = net.ParseIP(parts[1])
+			pubKeyBytes, err := hex.DecodeString(parts[0])
+			if err == nil {
+				pubKey, _ = secp256k1.ParsePubKey(pubKeyBytes)
+			}
+		}
+		if ip == nil || pubKey == nil {
+			str := "%s: the p2ppeerkey option must be an identity key " +
+				"and an IP address in the form <pubkey>@<ip> -- " +
+				"parsed [%s]"
+			err := fmt.Errorf(str, funcName, peerKey)
+			return nil, nil, err
+		}
+		cfg.p2pPeerKeys[ip.String()] = pubKey
+	}
+
 	// Add the default listener if none were specified. The default
 	// listener is all addresses on the listen port for the network
 	// we are to connect to.
@@ -518,10 +660,38 @@ func loadConfig() (*config, []string, error) {
 		}
 	}
 
-	// The RPC server is disabled if no username or password is provided.
-	// 如果RPC没有用户或者密码被指定，则RPC服务器被禁止
-	if cfg.RPCUser == "" || cfg.RPCPass == "" {
-		cfg.DisableRPC = true
+	// Parse the RPC users that are only authorized for the RPC methods and
+	// websocket notifications granted to them.
+	cfg.rpcACLUsers, err = parseRPCACLUsers(&cfg.RPCUsers)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		return nil, nil, err
+	}
+	if len(cfg.rpcACLUsers.byCommonName) > 0 &&
+		(cfg.RPCUsers.ClientCAFile == "" || cfg.DisableTLS) {
+
+		str := "%s: the rpcaclcertuser option requires the " +
+			"rpcclientcafile option and can't be used with the notls " +
+			"option"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	if cfg.RPCUsers.ClientCAFile != "" {
+		cfg.RPCUsers.ClientCAFile = cleanAndExpandPath(
+			cfg.RPCUsers.ClientCAFile)
+	}
+
+	// Authenticate the admin user of the RPC server with random
+	// credentials written to a cookie file in the data directory when no
+	// username or password is provided.  Local clients such as dcrctl read
+	// the cookie file instead of requiring credentials in their config.
+	if !cfg.DisableRPC && (cfg.RPCUser == "" || cfg.RPCPass == "") {
+		if cfg.RPCCookieFile == "" {
+			cfg.RPCCookieFile = filepath.Join(cfg.DataDir,
+				defaultRPCCookieFilename)
+		}
+		cfg.RPCCookieFile = cleanAndExpandPath(cfg.RPCCookieFile)
+		cfg.rpcCookieAuth = true
 	}
 
 	// Default RPC to listen on localhost only.
@@ -568,6 +738,53 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Limit the max mempool size to a sane value.
+	if cfg.MaxMempool < 0 {
+		str := "%s: the maxmempool option may not be less than 0 " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.MaxMempool)
+		return nil, nil, err
+	}
+
+	// Ensure the prune target is large enough to retain the blocks needed
+	// for reorganizations and the transaction and address indexes, which
+	// require every block, are not enabled along with pruning.
+	if cfg.Prune != 0 && cfg.Prune < minPruneTargetMB {
+		str := "%s: the prune option may not be less than %d MiB " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, minPruneTargetMB, cfg.Prune)
+		return nil, nil, err
+	}
+	if cfg.Prune != 0 && (cfg.TxIndex || cfg.AddrIndex) {
+		str := "%s: the prune option can't be used with the txindex " +
+			"or addrindex options since they require the data of " +
+			"every block"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// Ensure none of the indexes, which require the data of every block,
+	// are enabled when bootstrapping from a utxo snapshot.
+	if cfg.LoadSnapshot != "" {
+		if cfg.TxIndex || cfg.AddrIndex || !cfg.NoExistsAddrIndex ||
+			!cfg.NoCFilters {
+
+			str := "%s: the loadsnapshot option requires the " +
+				"nocfilters and noexistsaddrindex options and " +
+				"can't be used with the txindex or addrindex " +
+				"options since the indexes require the data of " +
+				"every block"
+			err := fmt.Errorf(str, funcName)
+			return nil, nil, err
+		}
+		cfg.LoadSnapshot = cleanAndExpandPath(cfg.LoadSnapshot)
+	}
+
+	// Expand the path to the AS map.
+	if cfg.ASMap != "" {
+		cfg.ASMap = cleanAndExpandPath(cfg.ASMap)
+	}
+
 	// Limit the block priority and minimum block sizes to max block size.
 	// 限制块的优先级大小为20000, 块最小的大小为0
 	cfg.BlockPrioritySize = minUint32(cfg.BlockPrioritySize, cfg.BlockMaxSize) // 20000
@@ -601,6 +818,69 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Ensure there is at least one mining address when the stratum server is
+	// enabled since it serves the templates of the background block template
+	// generator.
+	if len(cfg.StratumListeners) > 0 && len(cfg.MiningAddrs) == 0 {
+		str := "%s: the stratumlisten option is set, but there are no " +
+			"mining addresses specified"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// Ensure the stratum share difficulty is positive.
+	if cfg.StratumShareDiff <= 0 {
+		str := "%s: the stratumsharediff option must be positive -- " +
+			"parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.StratumShareDiff)
+		return nil, nil, err
+	}
+
+	// Add default port to all stratum listener addresses if needed and
+	// remove duplicate addresses.
+	cfg.StratumListeners = normalizeAddresses(cfg.StratumListeners,
+		defaultStratumPort)
+
+	// Ensure the stratum workers are valid.
+	for _, worker := range cfg.StratumWorkers {
+		if i := strings.Index(worker, ":"); i < 1 {
+			str := "%s: the stratumworker option must be a worker " +
+				"name and password separated by a colon -- " +
+				"parsed [%v]"
+			err := fmt.Errorf(str, funcName, worker)
+			return nil, nil, err
+		}
+	}
+
+	// Only allow stratum miners to connect without authorizing as one of
+	// the configured workers when the stratum server is bound to localhost
+	// addresses.
+	if len(cfg.StratumWorkers) == 0 {
+		for _, addr := range cfg.StratumListeners {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				str := "%s: stratum listen interface '%s' is " +
+					"invalid: %v"
+				err := fmt.Errorf(str, funcName, addr, err)
+				return nil, nil, err
+			}
+			switch host {
+			case "localhost", "127.0.0.1", "::1":
+			default:
+				str := "%s: the stratumworker option must be " +
+					"set when binding stratum to non " +
+					"localhost addresses: %s"
+				err := fmt.Errorf(str, funcName, addr)
+				return nil, nil, err
+			}
+		}
+	}
+
+	// Add default port to all metrics listener addresses if needed and
+	// remove duplicate addresses.
+	cfg.MetricsListeners = normalizeAddresses(cfg.MetricsListeners,
+		defaultMetricsPort)
+
 	// Add default port to all listener addresses if needed and remove
 	// duplicate addresses.
 	// 添加默认的端口到每一个非重复的地址中
@@ -611,8 +891,37 @@ func loadConfig() (*config, []string, error) {
 	// 添加默认的端口到每一个非重复的地址中
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners, activeNetParams.rpcPort) // 默认端口9109
 
-	// Only allow TLS to be disabled if the RPC is bound to localhost
-	// addresses.
+	// The REST interface is served on the RPC listeners and must be rate
+	// limited.
+	if cfg.REST && cfg.DisableRPC {
+		str := "%s: the --rest option requires the RPC server and " +
+			"can't be used with the --norpc option"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+	if cfg.REST && cfg.RESTRateLimit <= 0 {
+		str := "%s: the restratelimit option must be greater than " +
+			"zero -- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RESTRateLimit)
+		return nil, nil, err
+	}
+
+	// The gRPC API is served alongside the RPC server and shares its TLS
+	// and authentication settings.
+	if len(cfg.GRPCListeners) > 0 && cfg.DisableRPC {
+		str := "%s: the grpclisten option requires the RPC server and " +
+			"can't be used with the --norpc option"
+		err := fmt.Errorf(str, funcName)
+		return nil, nil, err
+	}
+
+	// Add default port to all gRPC listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.GRPCListeners = normalizeAddresses(cfg.GRPCListeners,
+		activeNetParams.grpcPort)
+
+	// Only allow TLS to be disabled if the RPC and gRPC servers are bound to
+	// localhost addresses.
 	// 如果没有禁止RPC, 但是禁止了TLS时，则只允许本地监听者
 	if !cfg.DisableRPC && cfg.DisableTLS {
 		allowedTLSListeners := map[string]struct{}{
@@ -620,7 +929,11 @@ func loadConfig() (*config, []string, error) {
 			"127.0.0.1": {},
 			"::1":       {},
 		}
-		for _, addr := range cfg.RPCListeners {
+		listeners := make([]string, 0, len(cfg.RPCListeners)+
+			len(cfg.GRPCListeners))
+		listeners = append(listeners, cfg.RPCListeners...)
+		listeners = append(listeners, cfg.GRPCListeners...)
+		for _, addr := range listeners {
 			host, _, err := net.SplitHostPort(addr)
 			if err != nil {
 				str := "%s: RPC listen interface '%s' is " +
@@ -57,16 +57,18 @@ const (
 )
 
 // ConnReq is the connection request to a network address. If permanent, the
-// connection will be retried on disconnection.
+// connection will be retried on disconnection.  Block relay only connection
+// requests are maintained separately from the other outbound connections.
 type ConnReq struct {
 	// The following variables must only be used atomically.
 	id    uint64
 	state uint32
 
-	retryCount uint32
-	conn       net.Conn
-	Addr       net.Addr
-	Permanent  bool
+	retryCount     uint32
+	conn           net.Conn
+	Addr           net.Addr
+	Permanent      bool
+	BlockRelayOnly bool
 }
 
 // updateState updates the state of the connection request.
@@ -121,6 +123,12 @@ type Config struct {
 	// maintain. Defaults to 8.
 	TargetOutbound uint32
 
+	// TargetBlockRelayOnly is the number of block relay only outbound
+	// network connections to maintain in addition to TargetOutbound.  New
+	// connection requests made to maintain them have the BlockRelayOnly
+	// flag set.  Defaults to 0.
+	TargetBlockRelayOnly uint32
+
 	// RetryDuration is the duration to wait before retrying connection
 	// requests. Defaults to 5s.
 	RetryDuration time.Duration
@@ -168,9 +176,10 @@ type handleFailed struct {
 // ConnManager provides a manager to handle network connections.
 type ConnManager struct {
 	// The following variables must only be used atomically.
-	connReqCount uint64
-	start        int32
-	stop         int32
+	connReqCount           uint64
+	blockRelayOnlyReqCount uint64
+	start                  int32
+	stop                   int32
 
 	cfg            Config
 	wg             sync.WaitGroup
@@ -205,10 +214,10 @@ func (cm *ConnManager) handleFailedConn(c *ConnReq) {
 				"-- retrying connection in: %v", maxFailedAttempts,
 				cm.cfg.RetryDuration)
 			time.AfterFunc(cm.cfg.RetryDuration, func() {
-				cm.NewConnReq()
+				cm.newConnReq(c.BlockRelayOnly)
 			})
 		} else {
-			go cm.NewConnReq()
+			go cm.newConnReq(c.BlockRelayOnly)
 		}
 	}
 }
@@ -253,7 +262,22 @@ out:
 						go cm.cfg.OnDisconnection(connReq)
 					}
 
-					if uint32(len(conns)) < cm.cfg.TargetOutbound && msg.retry {
+					// Count the remaining connections of the
+					// same kind to determine if a new one is
+					// needed.
+					var numBlockRelayOnly uint32
+					for _, c := range conns {
+						if c.BlockRelayOnly {
+							numBlockRelayOnly++
+						}
+					}
+					numConns := uint32(len(conns)) - numBlockRelayOnly
+					target := cm.cfg.TargetOutbound
+					if connReq.BlockRelayOnly {
+						numConns = numBlockRelayOnly
+						target = cm.cfg.TargetBlockRelayOnly
+					}
+					if numConns < target && msg.retry {
 						cm.handleFailedConn(connReq)
 					}
 				} else {
@@ -279,6 +303,12 @@ out:
 // NewConnReq creates a new connection request and connects to the
 // corresponding address.
 func (cm *ConnManager) NewConnReq() {
+	cm.newConnReq(false)
+}
+
+// newConnReq creates a new connection request, which is block relay only as
+// specified, and connects to the corresponding address.
+func (cm *ConnManager) newConnReq(blockRelayOnly bool) {
 	if atomic.LoadInt32(&cm.stop) != 0 {
 		return
 	}
@@ -286,8 +316,8 @@ func (cm *ConnManager) NewConnReq() {
 		return
 	}
 
-	c := &ConnReq{}
-	atomic.StoreUint64(&c.id, atomic.AddUint64(&cm.connReqCount, 1))
+	c := &ConnReq{BlockRelayOnly: blockRelayOnly}
+	cm.assignID(c)
 
 	addr, err := cm.cfg.GetNewAddress()
 	if err != nil {
@@ -300,6 +330,15 @@ func (cm *ConnManager) NewConnReq() {
 	cm.Connect(c)
 }
 
+// assignID assigns a new unique id to the passed connection request and keeps
+// track of the number of block relay only requests.
+func (cm *ConnManager) assignID(c *ConnReq) {
+	atomic.StoreUint64(&c.id, atomic.AddUint64(&cm.connReqCount, 1))
+	if c.BlockRelayOnly {
+		atomic.AddUint64(&cm.blockRelayOnlyReqCount, 1)
+	}
+}
+
 // Connect assigns an id and dials a connection to the address of the
 // connection request.
 func (cm *ConnManager) Connect(c *ConnReq) {
@@ -307,7 +346,7 @@ func (cm *ConnManager) Connect(c *ConnReq) {
 		return
 	}
 	if atomic.LoadUint64(&c.id) == 0 {
-		atomic.StoreUint64(&c.id, atomic.AddUint64(&cm.connReqCount, 1))
+		cm.assignID(c)
 	}
 	log.Debugf("Attempting to connect to %v", c)
 
@@ -384,9 +423,17 @@ func (cm *ConnManager) Start() {
 		}
 	}
 
-	for i := atomic.LoadUint64(&cm.connReqCount); i < uint64(cm.cfg.TargetOutbound); i++ {
+	// Block relay only connection requests made before starting, such as
+	// those to previously known good peers, count toward the target of
+	// block relay only connections instead of the regular target.
+	numBlockRelayOnly := atomic.LoadUint64(&cm.blockRelayOnlyReqCount)
+	numRegular := atomic.LoadUint64(&cm.connReqCount) - numBlockRelayOnly
+	for i := numRegular; i < uint64(cm.cfg.TargetOutbound); i++ {
 		go cm.NewConnReq()
 	}
+	for i := numBlockRelayOnly; i < uint64(cm.cfg.TargetBlockRelayOnly); i++ {
+		go cm.newConnReq(true)
+	}
 }
 
 // Wait blocks until the connection manager halts gracefully.
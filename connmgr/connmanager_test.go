@@ -240,6 +240,89 @@ func TestTargetOutbound(t *testing.T) {
 	cmgr.Stop()
 }
 
+// TestTargetBlockRelayOnly tests the target number of block relay only
+// outbound connections is maintained in addition to the target number of other
+// outbound connections and that block relay only connection requests made
+// before starting count toward it.
+func TestTargetBlockRelayOnly(t *testing.T) {
+	targetOutbound := uint32(3)
+	targetBlockRelayOnly := uint32(2)
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		TargetOutbound:       targetOutbound,
+		TargetBlockRelayOnly: targetBlockRelayOnly,
+		Dial:                 mockDialer,
+		GetNewAddress: func() (net.Addr, error) {
+			return &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555,
+			}, nil
+		},
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	anchor := &ConnReq{
+		Addr: &net.TCPAddr{
+			IP:   net.ParseIP("127.0.0.2"),
+			Port: 18555,
+		},
+		BlockRelayOnly: true,
+	}
+	go cmgr.Connect(anchor)
+	for anchor.ID() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cmgr.Start()
+
+	var numRegular, numBlockRelayOnly uint32
+	var blockRelayOnlyReq *ConnReq
+	for i := uint32(0); i < targetOutbound+targetBlockRelayOnly; i++ {
+		c := <-connected
+		if c.BlockRelayOnly {
+			numBlockRelayOnly++
+			if c != anchor {
+				blockRelayOnlyReq = c
+			}
+		} else {
+			numRegular++
+		}
+	}
+	if numRegular != targetOutbound {
+		t.Fatalf("target outbound: got %d connections, want %d",
+			numRegular, targetOutbound)
+	}
+	if numBlockRelayOnly != targetBlockRelayOnly {
+		t.Fatalf("target block relay only: got %d connections, want %d",
+			numBlockRelayOnly, targetBlockRelayOnly)
+	}
+	select {
+	case c := <-connected:
+		t.Fatalf("target block relay only: got unexpected connection - "+
+			"%v", c.Addr)
+	case <-time.After(time.Millisecond):
+		break
+	}
+
+	// Ensure a disconnected block relay only connection is replaced by
+	// another block relay only connection.
+	cmgr.Disconnect(blockRelayOnlyReq.ID())
+	select {
+	case c := <-connected:
+		if !c.BlockRelayOnly {
+			t.Fatalf("target block relay only: replacement " +
+				"connection is not block relay only")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("target block relay only: disconnected connection " +
+			"was not replaced")
+	}
+	cmgr.Stop()
+}
+
 // TestPassAddrAlongDialAddr tests if when using the DialAddr config option,
 // any address object returned by GetNewAddress will be correctly passed along
 // to DialAddr to be used for connecting to a host.
@@ -0,0 +1,195 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// The dandelion transaction relay hides the node a transaction originated from
+// by relaying new transactions in two phases.  In the stem phase, which starts
+// at the node the transaction originated from, every node passes it along to a
+// single outbound peer.  Each node ends the stem phase with a small
+// probability, in which case it starts the fluff phase by adding the
+// transaction to its memory pool and announcing it to all of its peers as
+// usual.  Transactions in the stem phase are kept in a separate stem pool so
+// they are not revealed through inventory or mempool replies.  Every node in
+// the stem ensures the transaction is fluffed eventually by fluffing it itself
+// when it has not seen the transaction in the fluff phase before a random
+// embargo timeout.
+// -----------------------------------------------------------------------------
+
+const (
+	// stemEpoch is how long the same outbound peer is used to relay the
+	// transactions in the stem phase to before another one is chosen.
+	stemEpoch = 10 * time.Minute
+
+	// fluffPercent is the probability in percent with which the stem phase
+	// of a transaction received from a peer is ended.
+	fluffPercent = 10
+
+	// minStemEmbargo and stemEmbargoJitter define the range of the random
+	// embargo timeout after which a transaction in the stem phase is fluffed
+	// by the node itself.
+	minStemEmbargo    = 10 * time.Second
+	stemEmbargoJitter = 20 * time.Second
+
+	// stemEmbargoCheckInterval is the interval at which the stem pool is
+	// checked for transactions whose embargo timeout has passed.
+	stemEmbargoCheckInterval = 2 * time.Second
+)
+
+// relayStemMsg packages a transaction in the stem phase along with the peer it
+// was received from, if any, so the peer handler can relay it to the stem
+// peer.
+type relayStemMsg struct {
+	tx     *dcrutil.Tx
+	origin *serverPeer
+}
+
+// shouldFluff returns whether or not the stem phase of a transaction received
+// from a peer should be ended.
+func shouldFluff() bool {
+	return rand.Intn(100) < fluffPercent
+}
+
+// isStemPeerCandidate returns whether or not transactions in the stem phase may
+// be relayed to the passed peer.
+func isStemPeerCandidate(sp *serverPeer) bool {
+	return sp.Connected() && sp.VerAckReceived() && !sp.blockRelayOnly &&
+		!sp.relayTxDisabled() &&
+		sp.ProtocolVersion() >= wire.DandelionVersion
+}
+
+// stemPeer returns the outbound peer to relay a transaction in the stem phase
+// received from the passed peer to, or nil when there is none.  The same peer
+// is used for the duration of an epoch unless it sent the transaction itself.
+//
+// This function MUST be called from the peer handler goroutine.
+func (s *server) stemPeer(state *peerState, origin *serverPeer) *serverPeer {
+	now := time.Now()
+	if state.stemPeer != nil && state.stemPeer != origin &&
+		now.Before(state.stemPeerExpiry) && isStemPeerCandidate(state.stemPeer) {
+
+		return state.stemPeer
+	}
+
+	var candidates []*serverPeer
+	state.forAllOutboundPeers(func(sp *serverPeer) {
+		if sp != origin && isStemPeerCandidate(sp) {
+			candidates = append(candidates, sp)
+		}
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+	sp := candidates[rand.Intn(len(candidates))]
+
+	// Only start a new epoch when the current stem peer is no longer
+	// usable rather than just being the origin of this transaction.
+	if state.stemPeer == nil || !now.Before(state.stemPeerExpiry) ||
+		!isStemPeerCandidate(state.stemPeer) {
+
+		state.stemPeer = sp
+		state.stemPeerExpiry = now.Add(stemEpoch)
+		srvrLog.Debugf("Relaying transactions in the stem phase to %v",
+			sp)
+	}
+	return sp
+}
+
+// handleRelayStemMsg relays a transaction in the stem phase to the stem peer or
+// fluffs it when there is none.  It is invoked from the peerHandler goroutine.
+func (s *server) handleRelayStemMsg(state *peerState, msg relayStemMsg) {
+	sp := s.stemPeer(state, msg.origin)
+	if sp == nil {
+		// The transaction is fluffed in a separate goroutine since
+		// adding it to the memory pool involves the block manager, which
+		// in turn relies on the peer handler to relay it.
+		go s.fluffStemTransaction(msg.tx)
+		return
+	}
+	sp.QueueMessage(wire.NewMsgStemTx(msg.tx.MsgTx()), nil)
+}
+
+// StemTransaction adds the passed transaction to the stem pool and relays it
+// to the stem peer.  The origin is the peer the transaction was received from
+// and is nil for transactions that originated from this node.  An error is
+// returned when the transaction can't be relayed in the stem phase, in which
+// case it should be processed as a regular transaction instead.
+func (s *server) StemTransaction(tx *dcrutil.Tx, origin *serverPeer, allowHighFees bool) error {
+	embargo := time.Now().Add(minStemEmbargo +
+		time.Duration(rand.Int63n(int64(stemEmbargoJitter))))
+	err := s.txMemPool.ProcessStemTransaction(tx, embargo, allowHighFees)
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		s.localStemTxnsMtx.Lock()
+		s.localStemTxns[*tx.Hash()] = struct{}{}
+		s.localStemTxnsMtx.Unlock()
+	}
+	select {
+	case s.relayStem <- relayStemMsg{tx: tx, origin: origin}:
+	case <-s.quit:
+	}
+	return nil
+}
+
+// takeLocalStemTx returns whether or not the passed transaction originated from
+// this node and was relayed in the stem phase, and stops tracking it.
+//
+// This function is safe for concurrent access.
+func (s *server) takeLocalStemTx(hash *chainhash.Hash) bool {
+	s.localStemTxnsMtx.Lock()
+	_, exists := s.localStemTxns[*hash]
+	delete(s.localStemTxns, *hash)
+	s.localStemTxnsMtx.Unlock()
+	return exists
+}
+
+// fluffStemTransaction ends the stem phase of the passed transaction by adding
+// it to the memory pool and announcing it to all peers.
+func (s *server) fluffStemTransaction(tx *dcrutil.Tx) {
+	acceptedTxs, err := s.blockManager.ProcessTransaction(tx, false, false,
+		true)
+	if err != nil {
+		srvrLog.Debugf("Unable to fluff stem transaction %v: %v",
+			tx.Hash(), err)
+		s.takeLocalStemTx(tx.Hash())
+		return
+	}
+	srvrLog.Debugf("Fluffed stem transaction %v", tx.Hash())
+	s.AnnounceNewTransactions(acceptedTxs)
+}
+
+// stemHandler fluffs the transactions in the stem pool whose embargo timeout
+// has passed without them being seen in the fluff phase.  It must be run as a
+// goroutine.
+func (s *server) stemHandler() {
+	ticker := time.NewTicker(stemEmbargoCheckInterval)
+	defer ticker.Stop()
+
+out:
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, tx := range s.txMemPool.ExpiredStemTransactions(now) {
+				s.fluffStemTransaction(tx)
+			}
+
+		case <-s.quit:
+			break out
+		}
+	}
+
+	s.wg.Done()
+}
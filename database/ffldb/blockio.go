@@ -11,6 +11,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -163,6 +165,11 @@ type blockStore struct {
 	fileNumToLRUElem map[uint32]*list.Element
 	openBlockFiles   map[uint32]*lockableFile
 
+	// oldestFileNum is the number of the oldest block file that still
+	// exists on disk.  All files before it have been pruned.  It is
+	// protected by obfMutex.
+	oldestFileNum uint32
+
 	// writeCursor houses the state for the current file and location that
 	// new blocks are written to.
 	writeCursor *writeCursor
@@ -307,6 +314,14 @@ func (s *blockStore) deleteFile(fileNum uint32) error {
 	return nil
 }
 
+// prunedFileErr returns an ErrBlockPruned database error for the passed flat
+// file number.
+func prunedFileErr(fileNum uint32) database.Error {
+	str := fmt.Sprintf("block file %d containing the requested block data "+
+		"has been pruned", fileNum)
+	return makeDbErr(database.ErrBlockPruned, str, nil)
+}
+
 // blockFile attempts to return an existing file handle for the passed flat file
 // number if it is already open as well as marking it as most recently used.  It
 // will also open the file when it's not already open subject to the rules
@@ -331,6 +346,10 @@ func (s *blockStore) blockFile(fileNum uint32) (*lockableFile, error) {
 
 	// Try to return an open file under the overall files read lock.
 	s.obfMutex.RLock()
+	if fileNum < s.oldestFileNum {
+		s.obfMutex.RUnlock()
+		return nil, prunedFileErr(fileNum)
+	}
 	if obf, ok := s.openBlockFiles[fileNum]; ok {
 		s.lruMutex.Lock()
 		s.openBlocksLRU.MoveToFront(s.fileNumToLRUElem[fileNum])
@@ -346,6 +365,10 @@ func (s *blockStore) blockFile(fileNum uint32) (*lockableFile, error) {
 	// map again under write lock in case multiple readers got here and a
 	// separate one is already opening the file.
 	s.obfMutex.Lock()
+	if fileNum < s.oldestFileNum {
+		s.obfMutex.Unlock()
+		return nil, prunedFileErr(fileNum)
+	}
 	if obf, ok := s.openBlockFiles[fileNum]; ok {
 		obf.RLock()
 		s.obfMutex.Unlock()
@@ -713,16 +736,105 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 	}
 }
 
+// pruneFiles deletes the oldest block files until the total size of all block
+// files is at or below the provided target size in bytes.  Neither the file
+// with the provided keep number nor any files after it are deleted.  It returns
+// the number of bytes that were freed.
+//
+// Readers attempting to access data in a deleted file will receive an
+// ErrBlockPruned error.
+func (s *blockStore) pruneFiles(targetSize uint64, keepFileNum uint32) (uint64, error) {
+	// The current write file is never pruned.
+	wc := s.writeCursor
+	wc.RLock()
+	curFileNum, curOffset := wc.curFileNum, wc.curOffset
+	wc.RUnlock()
+	if keepFileNum > curFileNum {
+		keepFileNum = curFileNum
+	}
+
+	// Determine the total size of all of the block files along with the
+	// sizes of the ones which are candidates for removal.
+	s.obfMutex.RLock()
+	oldestFileNum := s.oldestFileNum
+	s.obfMutex.RUnlock()
+	totalSize := uint64(curOffset)
+	fileSizes := make([]uint64, 0, curFileNum-oldestFileNum)
+	for fileNum := oldestFileNum; fileNum < curFileNum; fileNum++ {
+		st, err := os.Stat(blockFilePath(s.basePath, fileNum))
+		if err != nil {
+			str := fmt.Sprintf("failed to stat block file %d: %v",
+				fileNum, err)
+			return 0, makeDbErr(database.ErrDriverSpecific, str, err)
+		}
+		fileSizes = append(fileSizes, uint64(st.Size()))
+		totalSize += uint64(st.Size())
+	}
+
+	var freed uint64
+	for fileNum := oldestFileNum; fileNum < keepFileNum; fileNum++ {
+		if totalSize <= targetSize {
+			break
+		}
+
+		// Mark the file pruned so no new readers will attempt to use it
+		// and close it when it is open.  The close is done under the
+		// write lock for the file in case any readers are currently
+		// reading from it so it's not closed out from under them.
+		s.obfMutex.Lock()
+		s.oldestFileNum = fileNum + 1
+		if blockFile, ok := s.openBlockFiles[fileNum]; ok {
+			s.lruMutex.Lock()
+			s.openBlocksLRU.Remove(s.fileNumToLRUElem[fileNum])
+			delete(s.fileNumToLRUElem, fileNum)
+			s.lruMutex.Unlock()
+
+			blockFile.Lock()
+			_ = blockFile.file.Close()
+			blockFile.Unlock()
+			delete(s.openBlockFiles, fileNum)
+		}
+		s.obfMutex.Unlock()
+
+		if err := s.deleteFileFunc(fileNum); err != nil {
+			return freed, err
+		}
+		log.Debugf("Pruned block file %d", fileNum)
+
+		fileSize := fileSizes[fileNum-oldestFileNum]
+		totalSize -= fileSize
+		freed += fileSize
+	}
+
+	return freed, nil
+}
+
 // scanBlockFiles searches the database directory for all flat block files to
-// find the end of the most recent file.  This position is considered the
-// current write cursor which is also stored in the metadata.  Thus, it is used
-// to detect unexpected shutdowns in the middle of writes so the block files
-// can be reconciled.
+// find the oldest file along with the end of the most recent file.  This
+// position is considered the current write cursor which is also stored in the
+// metadata.  Thus, it is used to detect unexpected shutdowns in the middle of
+// writes so the block files can be reconciled.
+//
+// The oldest file is only something other than the first one when the oldest
+// files have been pruned.
 // 扫描数据库路径下的块文件，找到最新的块号和块文件的偏移位置
-func scanBlockFiles(dbPath string) (int, uint32) {
+func scanBlockFiles(dbPath string) (int, int, uint32) {
+	firstFile := 0
+	paths, _ := filepath.Glob(filepath.Join(dbPath, "*.fdb"))
+	for i, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".fdb")
+		fileNum, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			continue
+		}
+		if i == 0 || int(fileNum) < firstFile {
+			firstFile = int(fileNum)
+		}
+	}
+
 	lastFile := -1
 	fileLen := uint32(0)
-	for i := 0; ; i++ {
+	for i := firstFile; ; i++ {
 		filePath := blockFilePath(dbPath, uint32(i)) // ~/.dcrd/data/blocks_ffldb/000000000.fdb
 		st, err := os.Stat(filePath)
 		if err != nil {
@@ -733,8 +845,9 @@ func scanBlockFiles(dbPath string) (int, uint32) {
 		fileLen = uint32(st.Size())
 	}
 
-	log.Tracef("Scan found latest block file #%d with length %d", lastFile, fileLen)
-	return lastFile, fileLen
+	log.Tracef("Scan found oldest block file #%d and latest block file "+
+		"#%d with length %d", firstFile, lastFile, fileLen)
+	return firstFile, lastFile, fileLen
 }
 
 // newBlockStore returns a new block store with the current block file number
@@ -744,8 +857,9 @@ func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
 	// Look for the end of the latest block to file to determine what the
 	// write cursor position is from the viewpoing of the block files on
 	// disk.
-	fileNum, fileOff := scanBlockFiles(basePath) // 返回最新的文件号，和文件偏移
-	if fileNum == -1 {                           // 块文件不存在
+	oldestFileNum, fileNum, fileOff := scanBlockFiles(basePath) // 返回最新的文件号，和文件偏移
+	if fileNum == -1 {                                          // 块文件不存在
+		oldestFileNum = 0
 		fileNum = 0
 		fileOff = 0
 	}
@@ -757,6 +871,7 @@ func newBlockStore(basePath string, network wire.CurrencyNet) *blockStore {
 		openBlockFiles:   make(map[uint32]*lockableFile),
 		openBlocksLRU:    list.New(),
 		fileNumToLRUElem: make(map[uint32]*list.Element),
+		oldestFileNum:    uint32(oldestFileNum),
 
 		writeCursor: &writeCursor{
 			curFile:    &lockableFile{},
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/btcsuite/goleveldb/leveldb"
 	"github.com/btcsuite/goleveldb/leveldb/comparer"
@@ -1793,6 +1794,12 @@ type db struct {
 // Enforce db implements the database.DB interface.
 var _ database.DB = (*db)(nil)
 
+// Enforce db implements the database.Pruner interface.
+var _ database.Pruner = (*db)(nil)
+
+// Enforce db implements the database.CacheFlushCounter interface.
+var _ database.CacheFlushCounter = (*db)(nil)
+
 // Type returns the database driver type the current database instance was
 // created with.
 //
@@ -1997,6 +2004,50 @@ func (db *db) Close() error {
 	return closeErr
 }
 
+// CacheFlushes returns the number of times the database cache was flushed to
+// persistent storage since the database was opened.
+//
+// This function is part of the database.CacheFlushCounter interface
+// implementation.
+func (db *db) CacheFlushes() uint64 {
+	return atomic.LoadUint64(&db.cache.flushes)
+}
+
+// PruneBlocks deletes the flat files which house the oldest blocks until the
+// total size of all flat files is at or below the provided target size in
+// bytes.  The file which contains the block identified by the keep hash and
+// every file after it are never deleted.  It returns the number of bytes that
+// were freed.
+//
+// Since blocks are only ever removed at the granularity of entire flat files,
+// the final size might be less than the target size.
+//
+// This function is part of the database.Pruner interface implementation.
+func (db *db) PruneBlocks(targetSize uint64, keep *chainhash.Hash) (uint64, error) {
+	// Look up the file which houses the block to keep from the block index.
+	var keepFileNum uint32
+	err := db.View(func(dbTx database.Tx) error {
+		blockRow, err := dbTx.(*transaction).fetchBlockRow(keep)
+		if err != nil {
+			return err
+		}
+		keepFileNum = deserializeBlockLoc(blockRow).blockFileNum
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Prevent the database from being closed while the files are pruned.
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.closed {
+		return 0, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr, nil)
+	}
+
+	return db.store.pruneFiles(targetSize, keepFileNum)
+}
+
 // filesExists reports whether the named file or directory exists.
 // 查看name指定的文件是否存在
 func fileExists(name string) bool {
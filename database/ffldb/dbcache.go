@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/goleveldb/leveldb"
@@ -337,6 +338,11 @@ func (snap *dbCacheSnapshot) NewIterator(slice *util.Range) *dbCacheIterator {
 // can commit transactions at will without incurring large performance hits due
 // to frequent disk syncs.
 type dbCache struct {
+	// flushes is the number of times the cache was flushed to persistent
+	// storage.  It must only be accessed atomically and is placed first so
+	// it is 64-bit aligned on 32-bit systems.
+	flushes uint64
+
 	// ldb is the underlying leveldb DB for metadata.
 	ldb *leveldb.DB
 
@@ -502,6 +508,7 @@ func (c *dbCache) flush() error {
 	c.cachedKeys = treap.NewImmutable()
 	c.cachedRemove = treap.NewImmutable()
 	c.cacheLock.Unlock()
+	atomic.AddUint64(&c.flushes, 1)
 
 	return nil
 }
@@ -302,6 +302,7 @@ type Tx interface {
 	// The interface contract guarantees at least the following errors will
 	// be returned (other implementation-specific errors are possible):
 	//   - ErrBlockNotFound if the requested block hash does not exist
+	//   - ErrBlockPruned if the data for the requested block was pruned
 	//   - ErrTxClosed if the transaction has already been closed
 	//   - ErrCorruption if the database has somehow become corrupted
 	//
@@ -320,6 +321,8 @@ type Tx interface {
 	// be returned (other implementation-specific errors are possible):
 	//   - ErrBlockNotFound if the any of the requested block hashes do not
 	//     exist
+	//   - ErrBlockPruned if the data for any of the requested blocks was
+	//     pruned
 	//   - ErrTxClosed if the transaction has already been closed
 	//   - ErrCorruption if the database has somehow become corrupted
 	//
@@ -345,6 +348,7 @@ type Tx interface {
 	// The interface contract guarantees at least the following errors will
 	// be returned (other implementation-specific errors are possible):
 	//   - ErrBlockNotFound if the requested block hash does not exist
+	//   - ErrBlockPruned if the data for the requested block was pruned
 	//   - ErrBlockRegionInvalid if the region exceeds the bounds of the
 	//     associated block
 	//   - ErrTxClosed if the transaction has already been closed
@@ -373,6 +377,8 @@ type Tx interface {
 	// be returned (other implementation-specific errors are possible):
 	//   - ErrBlockNotFound if any of the requested block hashed do not
 	//     exist
+	//   - ErrBlockPruned if the data for any of the requested blocks was
+	//     pruned
 	//   - ErrBlockRegionInvalid if one or more region exceed the bounds of
 	//     the associated block
 	//   - ErrTxClosed if the transaction has already been closed
@@ -460,3 +466,30 @@ type DB interface {
 	// back or committed).
 	Close() error
 }
+
+// Pruner is an optional interface which may be implemented by a DB whose block
+// storage supports deleting the data for the oldest blocks in order to bound
+// the amount of disk space used.
+//
+// Pruning only removes the block data.  The block headers and the knowledge of
+// which blocks are stored are retained, so HasBlock and FetchBlockHeader
+// continue to work for pruned blocks while fetching their data returns
+// ErrBlockPruned.
+type Pruner interface {
+	// PruneBlocks deletes the data for the oldest stored blocks until the
+	// total size of the stored block data is at or below the provided
+	// target size in bytes.  The data for the block identified by the keep
+	// hash along with every block stored after it is never deleted, so
+	// the target might not be reached.  It returns the number of bytes that
+	// were freed.
+	PruneBlocks(targetSize uint64, keep *chainhash.Hash) (uint64, error)
+}
+
+// CacheFlushCounter is an optional interface which may be implemented by a DB
+// that caches writes in memory and periodically flushes them to persistent
+// storage.
+type CacheFlushCounter interface {
+	// CacheFlushes returns the number of times the cache was flushed to
+	// persistent storage since the database was opened.
+	CacheFlushes() uint64
+}
@@ -65,6 +65,14 @@ func dcrdMain() error {
 		return nil
 	}
 
+	// Initialize the block database from a utxo snapshot when requested.
+	if cfg.LoadSnapshot != "" {
+		if err := loadUtxoSnapshot(db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+	}
+
 	// Create server and start it.
 	// 创建server
 	server, err := newServer(cfg.Listeners, db, activeNetParams.Params, // ":9108"
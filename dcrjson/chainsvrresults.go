@@ -26,6 +26,16 @@ type DecodeScriptResult struct {
 	P2sh      string   `json:"p2sh,omitempty"`
 }
 
+// DumpUtxoSnapshotResult models the data returned from the dumputxosnapshot
+// command.
+type DumpUtxoSnapshotResult struct {
+	Height       int64  `json:"height"`
+	Hash         string `json:"hash"`
+	SnapshotHash string `json:"snapshothash"`
+	NumUtxos     uint64 `json:"numutxos"`
+	Path         string `json:"path"`
+}
+
 // EstimateSmartFeeResult models the data returned from the estimatesmartfee
 // command.
 type EstimateSmartFeeResult struct {
@@ -212,6 +222,21 @@ type GetBlockTemplateResult struct {
 	// Block proposal from BIP 0023.
 	Capabilities  []string `json:"capabilities,omitempty"`
 	RejectReasion string   `json:"reject-reason,omitempty"`
+
+	// Convenience fields duplicated from the header.
+	Bits         string `json:"bits"`
+	CurTime      int64  `json:"curtime"`
+	Height       int64  `json:"height"`
+	PreviousHash string `json:"previousblockhash"`
+	Version      int32  `json:"version"`
+
+	// Decred stake fields duplicated from the header.
+	Voters       uint16  `json:"voters"`
+	FreshStake   uint8   `json:"freshstake"`
+	Revocations  uint8   `json:"revocations"`
+	PoolSize     uint32  `json:"poolsize"`
+	SBits        float64 `json:"sbits"`
+	StakeVersion uint32  `json:"stakeversion"`
 }
 
 // GetChainTipsResult models the data returns from the getchaintips command.
@@ -242,11 +267,25 @@ type InfoChainResult struct {
 	Errors          string  `json:"errors"`
 }
 
+// GetIndexInfoResult models the data returned from the getindexinfo command
+// for each of the enabled optional indexes.
+type GetIndexInfoResult struct {
+	Name       string `json:"name"`
+	Height     int64  `json:"height"`
+	Hash       string `json:"hash"`
+	BestHeight int64  `json:"bestheight"`
+	Synced     bool   `json:"synced"`
+}
+
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	MaxMempool    int64   `json:"maxmempool"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
+	Evicted       uint64  `json:"evicted"`
+	EvictedBytes  int64   `json:"evictedbytes"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -295,35 +334,72 @@ type GetNetworkInfoResult struct {
 	LocalAddresses  []LocalAddressesResult `json:"localaddresses"`
 }
 
+// GetNetTotalsResultUploadTarget models the uploadtarget field of the
+// getnettotals command.
+type GetNetTotalsResultUploadTarget struct {
+	TimeFrame             int64  `json:"timeframe"`
+	Target                uint64 `json:"target"`
+	TargetReached         bool   `json:"targetreached"`
+	ServeHistoricalBlocks bool   `json:"servehistoricalblocks"`
+	BytesLeftInCycle      uint64 `json:"bytesleftincycle"`
+	TimeLeftInCycle       int64  `json:"timeleftincycle"`
+}
+
+// GetNetTotalsResultUploadRate models the uploadrate field of the getnettotals
+// command.
+type GetNetTotalsResultUploadRate struct {
+	MaxUploadRate        uint64 `json:"maxuploadrate"`
+	MaxPeerUploadRate    uint64 `json:"maxpeeruploadrate"`
+	ThrottledMillis      int64  `json:"throttledmillis"`
+	PeersThrottledMillis int64  `json:"peersthrottledmillis"`
+}
+
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
-	TotalBytesRecv uint64 `json:"totalbytesrecv"`
-	TotalBytesSent uint64 `json:"totalbytessent"`
-	TimeMillis     int64  `json:"timemillis"`
+	TotalBytesRecv uint64                          `json:"totalbytesrecv"`
+	TotalBytesSent uint64                          `json:"totalbytessent"`
+	TimeMillis     int64                           `json:"timemillis"`
+	UploadTarget   *GetNetTotalsResultUploadTarget `json:"uploadtarget"`
+	UploadRate     *GetNetTotalsResultUploadRate   `json:"uploadrate"`
 }
 
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
 type GetPeerInfoResult struct {
-	ID             int32   `json:"id"`
-	Addr           string  `json:"addr"`
-	AddrLocal      string  `json:"addrlocal,omitempty"`
-	Services       string  `json:"services"`
-	RelayTxes      bool    `json:"relaytxes"`
-	LastSend       int64   `json:"lastsend"`
-	LastRecv       int64   `json:"lastrecv"`
-	BytesSent      uint64  `json:"bytessent"`
-	BytesRecv      uint64  `json:"bytesrecv"`
-	ConnTime       int64   `json:"conntime"`
-	TimeOffset     int64   `json:"timeoffset"`
-	PingTime       float64 `json:"pingtime"`
-	PingWait       float64 `json:"pingwait,omitempty"`
-	Version        uint32  `json:"version"`
-	SubVer         string  `json:"subver"`
-	Inbound        bool    `json:"inbound"`
-	StartingHeight int64   `json:"startingheight"`
-	CurrentHeight  int64   `json:"currentheight,omitempty"`
-	BanScore       int32   `json:"banscore"`
-	SyncNode       bool    `json:"syncnode"`
+	ID               int32                   `json:"id"`
+	Addr             string                  `json:"addr"`
+	AddrLocal        string                  `json:"addrlocal,omitempty"`
+	Services         string                  `json:"services"`
+	RelayTxes        bool                    `json:"relaytxes"`
+	LastSend         int64                   `json:"lastsend"`
+	LastRecv         int64                   `json:"lastrecv"`
+	BytesSent        uint64                  `json:"bytessent"`
+	BytesRecv        uint64                  `json:"bytesrecv"`
+	ConnTime         int64                   `json:"conntime"`
+	TimeOffset       int64                   `json:"timeoffset"`
+	PingTime         float64                 `json:"pingtime"`
+	PingWait         float64                 `json:"pingwait,omitempty"`
+	Version          uint32                  `json:"version"`
+	SubVer           string                  `json:"subver"`
+	Inbound          bool                    `json:"inbound"`
+	BlockRelayOnly   bool                    `json:"blockrelayonly"`
+	StartingHeight   int64                   `json:"startingheight"`
+	CurrentHeight    int64                   `json:"currentheight,omitempty"`
+	BanScore         int32                   `json:"banscore"`
+	SyncNode         bool                    `json:"syncnode"`
+	Encrypted        bool                    `json:"encrypted"`
+	MappedAS         uint32                  `json:"mappedas,omitempty"`
+	TxReconciliation *TxReconciliationResult `json:"txreconciliation,omitempty"`
+}
+
+// TxReconciliationResult models the statistics about the reconciliation of
+// transaction announcements with a peer returned from the getpeerinfo command.
+type TxReconciliationResult struct {
+	Flooding  bool   `json:"flooding"`
+	SetSize   int    `json:"setsize"`
+	Rounds    uint64 `json:"rounds"`
+	Failures  uint64 `json:"failures"`
+	Announced uint64 `json:"announced"`
+	Requested uint64 `json:"requested"`
 }
 
 // GetRawMempoolVerboseResult models the data returned from the getrawmempool
@@ -416,6 +492,17 @@ type GetTxOutResult struct {
 	Coinbase      bool               `json:"coinbase"`
 }
 
+// GetTxOutSetInfoResult models the data from the gettxoutsetinfo command.
+type GetTxOutSetInfoResult struct {
+	Height         int64   `json:"height"`
+	BestBlock      string  `json:"bestblock"`
+	Transactions   int64   `json:"transactions"`
+	TxOuts         int64   `json:"txouts"`
+	SerializedHash string  `json:"serializedhash"`
+	DiskSize       int64   `json:"disksize"`
+	TotalAmount    float64 `json:"totalamount"`
+}
+
 // Choice models an individual choice inside an Agenda.
 type Choice struct {
 	ID          string  `json:"id"`
@@ -463,12 +550,26 @@ type Ticket struct {
 	Owner string `json:"owner"`
 }
 
+// ListBannedResult models the data returned from the listbanned command.
+type ListBannedResult struct {
+	Address     string `json:"address"`
+	BanCreated  int64  `json:"bancreated"`
+	BannedUntil int64  `json:"banneduntil"`
+}
+
 // LiveTicketsResult models the data returned from the livetickets
 // command.
 type LiveTicketsResult struct {
 	Tickets []string `json:"tickets"`
 }
 
+// LoadMempoolResult models the data returned from the loadmempool command.
+type LoadMempoolResult struct {
+	Accepted int    `json:"accepted"`
+	Rejected int    `json:"rejected"`
+	Path     string `json:"path"`
+}
+
 // MissedTicketsResult models the data returned from the missedtickets
 // command.
 type MissedTicketsResult struct {
@@ -526,6 +627,12 @@ type TicketFeeInfoResult struct {
 	FeeInfoWindows []FeeInfoWindow `json:"feeinfowindows"`
 }
 
+// SaveMempoolResult models the data returned from the savemempool command.
+type SaveMempoolResult struct {
+	NumTxns int    `json:"numtxns"`
+	Path    string `json:"path"`
+}
+
 // SearchRawTransactionsResult models the data from the searchrawtransaction
 // command.
 type SearchRawTransactionsResult struct {
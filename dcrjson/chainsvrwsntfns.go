@@ -34,6 +34,11 @@ const (
 	// more details in the notification.
 	TxAcceptedVerboseNtfnMethod = "txacceptedverbose"
 
+	// TxRemovedNtfnMethod is the method used for notifications from the
+	// chain server that a transaction has been removed from the mempool
+	// because it was replaced.
+	TxRemovedNtfnMethod = "txremoved"
+
 	// RelevantTxAcceptedNtfnMethod is the method used for notifications
 	// from the chain server that inform a client that a relevant
 	// transaction was accepted by the mempool.
@@ -183,6 +188,21 @@ func NewTxAcceptedVerboseNtfn(rawTx TxRawResult) *TxAcceptedVerboseNtfn {
 	}
 }
 
+// TxRemovedNtfn defines the txremoved JSON-RPC notification.
+type TxRemovedNtfn struct {
+	TxID       string `json:"txid"`
+	ReplacedBy string `json:"replacedby"`
+}
+
+// NewTxRemovedNtfn returns a new instance which can be used to issue a
+// txremoved JSON-RPC notification.
+func NewTxRemovedNtfn(txHash string, replacedBy string) *TxRemovedNtfn {
+	return &TxRemovedNtfn{
+		TxID:       txHash,
+		ReplacedBy: replacedBy,
+	}
+}
+
 // RelevantTxAcceptedNtfn defines the parameters to the relevanttxaccepted
 // JSON-RPC notification.
 type RelevantTxAcceptedNtfn struct {
@@ -223,6 +243,7 @@ func init() {
 	MustRegisterCmd(ReorganizationNtfnMethod, (*ReorganizationNtfn)(nil), flags)
 	MustRegisterCmd(TxAcceptedNtfnMethod, (*TxAcceptedNtfn)(nil), flags)
 	MustRegisterCmd(TxAcceptedVerboseNtfnMethod, (*TxAcceptedVerboseNtfn)(nil), flags)
+	MustRegisterCmd(TxRemovedNtfnMethod, (*TxRemovedNtfn)(nil), flags)
 	MustRegisterCmd(RelevantTxAcceptedNtfnMethod, (*RelevantTxAcceptedNtfn)(nil), flags)
 	MustRegisterCmd(SpentAndMissedTicketsNtfnMethod, (*SpentAndMissedTicketsNtfn)(nil), flags)
 	MustRegisterCmd(StakeDifficultyNtfnMethod, (*StakeDifficultyNtfn)(nil), flags)
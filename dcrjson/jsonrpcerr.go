@@ -79,6 +79,21 @@ const (
 	ErrRPCDuplicateTx       RPCErrorCode = -40
 )
 
+// Errors that are specific to dcrd.  Their codes are not used by any of the
+// more general errors above so clients are able to tell them apart.
+const (
+	// ErrRPCIndexNotSynced indicates an optional index has not caught up to
+	// the main chain yet and therefore can't provide a definitive answer.
+	// Clients should retry the request once the index is synced rather
+	// than treating it as the data not existing.
+	ErrRPCIndexNotSynced RPCErrorCode = -41
+
+	// ErrRPCBlockPruned indicates the data of a block is no longer available
+	// because the node is running in pruning mode and deleted it.  The block
+	// is still part of the chain and its header remains available.
+	ErrRPCBlockPruned RPCErrorCode = -42
+)
+
 // Errors that are specific to btcd.
 const (
 	ErrRPCNoWallet      RPCErrorCode = -1
@@ -33,6 +33,10 @@ Application Options:
       --maxsameip=          Max number of connections with the same IP -- 0 to
                             disable (default: 5)
       --maxpeers=           Max number of inbound and outbound peers (125)
+      --blockrelayonlypeers=
+                            Number of additional outbound peers that are only
+                            used to relay blocks and headers -- The last known
+                            good ones are reconnected first on startup (2)
       --nobanning           Disable banning of misbehaving peers
       --banduration=        How long to ban misbehaving peers.  Valid time units
                             are {s, m, h}.  Minimum 1 second (24h0m0s)
@@ -40,8 +44,30 @@ Application Options:
                             banning misbehaving peers.
       --whitelist=          Add an IP network or IP that will not be banned.
                             (eg. 192.168.1.0/24 or ::1)
+      --asmap=              Path to a file that maps IP addresses to the
+                            autonomous systems (ASNs) announcing them, which is
+                            used to diversify the peers by autonomous system
+                            rather than by network prefix
+      --maxuploadtarget=    Maximum number of bytes to upload to peers per 24
+                            hours -- Historical blocks are no longer served to
+                            peers that are not whitelisted once the remainder
+                            is needed for new blocks (0 for unlimited)
+      --maxuploadrate=      Maximum combined rate in KiB/s at which data is
+                            uploaded to peers that are not whitelisted (0 for
+                            unlimited)
+      --maxpeeruploadrate=  Maximum rate in KiB/s at which data is uploaded to
+                            each peer that is not whitelisted (0 for unlimited)
+      --p2pencryption       Encrypt the communication with peers that also
+                            support the encrypted transport
+      --p2ppeerkey=         Require the peer at the given IP address to
+                            authenticate with the given identity key over the
+                            encrypted transport -- Requires --p2pencryption
+                            (format: <pubkey>@<ip>)
   -u, --rpcuser=            Username for RPC connections
   -P, --rpcpass=            Password for RPC connections
+      --rpccookiefile=      File to write the random credentials for RPC
+                            connections to when no rpcuser/rpcpass is specified
+                            (default: .cookie in the data directory)
       --rpclimituser=       Username for limited RPC connections
       --rpclimitpass=       Password for limited RPC connections
       --rpclisten=          Add an interface/port to listen for RPC connections
@@ -51,11 +77,18 @@ Application Options:
       --rpcmaxclients=      Max number of RPC clients for standard connections
                             (10)
       --rpcmaxwebsockets=   Max number of RPC websocket connections (25)
-      --norpc               Disable built-in RPC server -- NOTE: The RPC server
-                            is disabled by default if no rpcuser/rpcpass or
-                            rpclimituser/rpclimitpass is specified
+      --norpc               Disable built-in RPC server
       --notls               Disable TLS for the RPC server -- NOTE: This is only
                             allowed if the RPC server is bound to localhost
+      --rest                Serve the unauthenticated and read-only REST
+                            interface under /rest/ on the RPC listeners
+      --restratelimit=      Max number of REST requests per second from each
+                            client IP (10)
+      --grpclisten=         Add an interface/port to serve the gRPC API with the
+                            TLS and authentication settings of the RPC server
+                            (default port: 9113, testnet: 19113)
+      --metricslisten=      Add an interface/port to serve Prometheus metrics
+                            over HTTP (default port: 9108)
       --nodnsseed           Disable DNS seeding for peers
       --externalip=         Add an ip to the list of local addresses we claim to
                             listen on to peers
@@ -98,11 +131,24 @@ Application Options:
                             high priority for relaying
       --maxorphantx=        Max number of orphan transactions to keep in memory
                             (1000)
+      --maxmempool=         Max total size of the transactions in the memory
+                            pool in MiB -- The lowest fee rate transactions are
+                            evicted when it is exceeded (0 = unlimited) (300)
       --generate            Generate (mine) bitcoins using the CPU
       --miningaddr=         Add the specified payment address to the list of
                             addresses to use for generated blocks -- At least
                             one address is required if the generate option is
                             set
+      --stratumlisten=      Add an interface/port to listen for stratum mining
+                            connections -- Requires at least one mining address
+                            (default port: 3333)
+      --stratumworker=      Add a worker name and password, separated by a
+                            colon, that stratum miners may authorize as --
+                            Stratum connections are only accepted on localhost
+                            addresses when no workers are specified
+      --stratumsharediff=   The minimum difficulty, as a multiple of the
+                            minimum network difficulty, of shares accepted from
+                            stratum miners (1)
       --blockminsize=       Mininum block size in bytes to be used when creating
                             a block
       --blockmaxsize=       Maximum block size in bytes to be used when creating
@@ -117,15 +163,73 @@ Application Options:
       --sigcachemaxsize=    The maximum number of entries in the signature
                             verification cache.
       --blocksonly          Do not accept transactions from remote peers.
+      --dandelion           Relay new transactions through a path of single
+                            peers before announcing them to the network
+                            (dandelion) to hide the node they originated from
+      --txreconciliation    Periodically reconcile the transactions announced
+                            to peers that support it instead of announcing
+                            every transaction individually
       --acceptnonstd        Accept and relay non-standard transactions to
                             the network regardless of the default settings
                             for the active network.
       --rejectnonstd        Reject non-standard transactions regardless of the
                             default settings for the active network.
+      --prune=              Reduce storage requirements by deleting the data of
+                            old blocks so the stored blocks stay under the given
+                            target size in MiB (0 = disabled) -- The most
+                            recent blocks needed for reorganizations are always
+                            kept -- Incompatible with --txindex and --addrindex
+      --loadsnapshot=       Initialize a new blockchain database from the UTXO
+                            snapshot at the given path instead of syncing the
+                            blocks prior to it -- The snapshot must be
+                            committed to by the active network parameters,
+                            which currently only commit to the genesis block,
+                            so the option is not yet usable for bootstrapping
+                            -- The blocks prior to the snapshot are only
+                            validated when --backvalidate is set -- Requires
+                            --nocfilters and --noexistsaddrindex and is
+                            incompatible with --txindex and --addrindex
+      --backvalidate        Download and validate the blocks prior to the UTXO
+                            snapshot the blockchain database was initialized
+                            from in the background and shut down when they do
+                            not result in the same UTXO set -- Uses a separate
+                            database that is removed once the history has been
+                            validated
+      --spv                 Run as a light client that only syncs block
+                            headers and uses the committed filters served by
+                            peers to find the blocks relevant to the addresses
+                            and outpoints loaded by websocket clients --
+                            Implies --blocksonly and is incompatible with
+                            --generate, --txindex, --addrindex and
+                            --backvalidate
       --altdnsnames:        Specify additional dns names to use when
                             generating the rpc server certificate
                             [supports DCRD_ALT_DNSNAMES environment variable]
 
+RPC Users:
+      --rpcacluser=         Add an RPC user that authenticates with the password
+                            whose hex-encoded HMAC-SHA256 keyed by the given
+                            salt is given (format:
+                            <name>:<salt>$<passwordhmac>)
+      --rpcaclcertuser=     Add an RPC user that authenticates with a TLS client
+                            certificate with the given common name -- Requires
+                            --rpcclientcafile (format: <name>:<commonname>)
+      --rpcaclallow=        Authorize an RPC user for the given comma-separated
+                            RPC methods, or * for all methods (format:
+                            <name>:<methods>)
+      --rpcacldeny=         Deny an RPC user the given comma-separated RPC
+                            methods, or * for all methods, even when they are
+                            allowed (format: <name>:<methods>)
+      --rpcaclallowntfn=    Authorize an RPC user for the given comma-separated
+                            websocket notifications, or * for all notifications
+                            (format: <name>:<notifications>)
+      --rpcacldenyntfn=     Deny an RPC user the given comma-separated websocket
+                            notifications, or * for all notifications, even
+                            when they are allowed (format:
+                            <name>:<notifications>)
+      --rpcclientcafile=    File containing the certificate authorities that
+                            issue the TLS client certificates of RPC users
+
 Help Options:
   -h, --help           Show this help message
 
@@ -0,0 +1,861 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v2"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/rpc/dcrdrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// API version constants of the gRPC server.
+const (
+	grpcSemverString = "1.0.0"
+	grpcSemverMajor  = 1
+	grpcSemverMinor  = 0
+	grpcSemverPatch  = 0
+)
+
+const (
+	// grpcVersionServicePrefix is the prefix of the full names of the
+	// methods of the version service, which do not require authentication
+	// so clients can check for compatibility before authenticating.
+	grpcVersionServicePrefix = "/dcrdrpc.VersionService/"
+
+	// grpcNtfnBufferSize is the number of notifications buffered for each
+	// notification stream.  Streams whose clients fall further behind are
+	// ended.
+	grpcNtfnBufferSize = 100
+)
+
+// grpcMethodACLNames maps the full names of the authenticated gRPC methods to
+// the names of the equivalent JSON-RPC methods, which the ACLs of the RPC users
+// refer to.  Calls to methods that are neither in the map nor part of the
+// version service are denied.
+var grpcMethodACLNames = map[string]string{
+	"/dcrdrpc.ChainService/BestBlock":                              "getbestblock",
+	"/dcrdrpc.ChainService/BlockchainInfo":                         "getblockchaininfo",
+	"/dcrdrpc.ChainService/BlockHash":                              "getblockhash",
+	"/dcrdrpc.ChainService/Block":                                  "getblock",
+	"/dcrdrpc.ChainService/BlockHeader":                            "getblockheader",
+	"/dcrdrpc.ChainService/Transaction":                            "getrawtransaction",
+	"/dcrdrpc.ChainService/TxOut":                                  "gettxout",
+	"/dcrdrpc.MempoolService/MempoolInfo":                          "getmempoolinfo",
+	"/dcrdrpc.MempoolService/MempoolTransactions":                  "getrawmempool",
+	"/dcrdrpc.MiningService/MiningInfo":                            "getmininginfo",
+	"/dcrdrpc.MiningService/StakeDifficulty":                       "getstakedifficulty",
+	"/dcrdrpc.NetworkService/ConnectionCount":                      "getconnectioncount",
+	"/dcrdrpc.NetworkService/Peers":                                "getpeerinfo",
+	"/dcrdrpc.NetworkService/NetTotals":                            "getnettotals",
+	"/dcrdrpc.NotificationService/BlockNotifications":              "notifyblocks",
+	"/dcrdrpc.NotificationService/ReorganizationNotifications":     "notifyblocks",
+	"/dcrdrpc.NotificationService/WinningTicketsNotifications":     "notifywinningtickets",
+	"/dcrdrpc.NotificationService/MempoolTransactionNotifications": "notifynewtransactions",
+}
+
+// grpcACLKey is the context key of the ACL of the RPC user that made a gRPC
+// call.
+type grpcACLKey struct{}
+
+// grpcACL returns the ACL of the RPC user that made the gRPC call with the
+// passed context.
+func grpcACL(ctx context.Context) *rpcACL {
+	acl, _ := ctx.Value(grpcACLKey{}).(*rpcACL)
+	return acl
+}
+
+// grpcAuthStream wraps a server stream to provide a context that contains the
+// ACL of the authenticated RPC user.
+type grpcAuthStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+func (s *grpcAuthStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcError converts the passed error returned by an RPC handler to a gRPC
+// status error.
+func grpcError(err error) error {
+	if e, ok := err.(*dcrjson.RPCError); ok {
+		switch e.Code {
+		case dcrjson.ErrRPCInvalidAddressOrKey:
+			return status.Error(codes.NotFound, e.Message)
+		case dcrjson.ErrRPCInvalidParameter, dcrjson.ErrRPCDecodeHexString:
+			return status.Error(codes.InvalidArgument, e.Message)
+		}
+		return status.Error(codes.Internal, e.Message)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// grpcHash converts the passed hash of a request to a chainhash.Hash.
+func grpcHash(b []byte) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHash(b)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return hash, nil
+}
+
+// grpcHashString converts the passed hash string of an RPC handler result to
+// the bytes of the hash.  Empty strings result in nil.
+func grpcHashString(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return hash[:], nil
+}
+
+// grpcAtoms converts the passed amount in coins of an RPC handler result to
+// atoms.
+func grpcAtoms(coins float64) int64 {
+	amount, err := dcrutil.NewAmount(coins)
+	if err != nil {
+		return 0
+	}
+	return int64(amount)
+}
+
+// grpcServer provides a gRPC API alongside the JSON-RPC API of the RPC server.
+// It shares the TLS configuration, the RPC users and the handlers of the RPC
+// server.
+type grpcServer struct {
+	started  int32
+	shutdown int32
+
+	// numStreams is the number of streaming calls in progress.  It must
+	// be accessed atomically.
+	numStreams int32
+
+	rpc       *rpcServer
+	server    *grpc.Server
+	listeners []net.Listener
+	wg        sync.WaitGroup
+}
+
+// authenticate authenticates the RPC user making the gRPC call with the passed
+// context to the passed method and returns the ACL of the user.  Users
+// authenticate with basic access authentication in the authorization metadata
+// or with a TLS client certificate.  The methods of the version service do not
+// require authentication, in which case the returned ACL is nil.  Methods that
+// depend on block chain data are unavailable in spv mode.
+func (s *grpcServer) authenticate(ctx context.Context, method string) (*rpcACL, error) {
+	if strings.HasPrefix(method, grpcVersionServicePrefix) {
+		return nil, nil
+	}
+	aclName, ok := grpcMethodACLNames[method]
+	if !ok {
+		rpcsLog.Warnf("gRPC call to unmapped method %s denied", method)
+		return nil, status.Error(codes.PermissionDenied, "method is "+
+			"not permitted")
+	}
+
+	var acl *rpcACL
+	md, _ := metadata.FromIncomingContext(ctx)
+	if auth := md.Get("authorization"); len(auth) > 0 {
+		const prefix = "Basic "
+		var login []byte
+		var err error
+		if strings.HasPrefix(auth[0], prefix) {
+			login, err = base64.StdEncoding.DecodeString(
+				auth[0][len(prefix):])
+		}
+		if err == nil {
+			parts := strings.SplitN(string(login), ":", 2)
+			if len(parts) == 2 {
+				acl = s.rpc.checkLogin(parts[0], parts[1])
+			}
+		}
+	} else if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			acl = s.rpc.certACL(&tlsInfo.State)
+		}
+	}
+	if acl == nil {
+		rpcsLog.Warnf("gRPC authentication failure from %v",
+			grpcPeerAddr(ctx))
+		return nil, status.Error(codes.Unauthenticated, "auth failure")
+	}
+	if !acl.permitsMethod(aclName) {
+		return nil, status.Errorf(codes.PermissionDenied, "user not "+
+			"authorized for this method")
+	}
+	if _, ok := rpcSPV[aclName]; !ok && s.rpc.server.spvSyncer != nil {
+		return nil, status.Error(codes.Unavailable,
+			ErrRPCNoChainData.Message)
+	}
+	return acl, nil
+}
+
+// grpcPeerAddr returns the address of the client that made the gRPC call with
+// the passed context.
+func grpcPeerAddr(ctx context.Context) net.Addr {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr
+	}
+	return nil
+}
+
+// unaryInterceptor authenticates unary gRPC calls.  The calls in progress count
+// towards the maximum number of standard RPC clients.
+func (s *grpcServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if int(atomic.LoadInt32(&s.rpc.numClients)+1) > cfg.RPCMaxClients {
+		rpcsLog.Infof("Max RPC clients exceeded [%d] - rejecting gRPC "+
+			"call from %v", cfg.RPCMaxClients, grpcPeerAddr(ctx))
+		return nil, status.Error(codes.ResourceExhausted, "too busy, "+
+			"try again later")
+	}
+	s.rpc.incrementClients()
+	defer s.rpc.decrementClients()
+
+	acl, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, grpcACLKey{}, acl), req)
+}
+
+// streamInterceptor authenticates streaming gRPC calls.  The streams in
+// progress are limited to the maximum number of websocket clients since they
+// are long-lived like websocket connections.
+func (s *grpcServer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	numStreams := atomic.AddInt32(&s.numStreams, 1)
+	defer atomic.AddInt32(&s.numStreams, -1)
+	if int(numStreams) > cfg.RPCMaxWebsockets {
+		rpcsLog.Infof("Max gRPC streams exceeded [%d] - rejecting "+
+			"gRPC call from %v", cfg.RPCMaxWebsockets,
+			grpcPeerAddr(ss.Context()))
+		return status.Error(codes.ResourceExhausted, "too busy, try "+
+			"again later")
+	}
+
+	acl, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	ctx := context.WithValue(ss.Context(), grpcACLKey{}, acl)
+	return handler(srv, &grpcAuthStream{ss, ctx})
+}
+
+// Start begins serving gRPC calls on the listeners of the server.
+func (s *grpcServer) Start() {
+	if atomic.AddInt32(&s.started, 1) != 1 {
+		return
+	}
+
+	rpcsLog.Trace("Starting gRPC server")
+	for _, listener := range s.listeners {
+		s.wg.Add(1)
+		go func(listener net.Listener) {
+			rpcsLog.Infof("gRPC server listening on %s",
+				listener.Addr())
+			s.server.Serve(listener)
+			rpcsLog.Tracef("gRPC listener done for %s",
+				listener.Addr())
+			s.wg.Done()
+		}(listener)
+	}
+}
+
+// Stop shuts down the gRPC server by closing its listeners and ending all
+// calls in progress.
+func (s *grpcServer) Stop() {
+	if atomic.AddInt32(&s.shutdown, 1) != 1 {
+		rpcsLog.Infof("gRPC server is already in the process of " +
+			"shutting down")
+		return
+	}
+
+	rpcsLog.Warnf("gRPC server shutting down")
+	s.server.Stop()
+	s.wg.Wait()
+	rpcsLog.Infof("gRPC server shutdown complete")
+}
+
+// newGRPCServer returns a new gRPC server for the passed RPC server listening
+// on the passed addresses.  Use Start to begin serving calls.
+func newGRPCServer(listenAddrs []string, rpc *rpcServer) (*grpcServer, error) {
+	ipv4ListenAddrs, ipv6ListenAddrs, _, err := parseListeners(listenAddrs)
+	if err != nil {
+		return nil, err
+	}
+	listeners := make([]net.Listener, 0,
+		len(ipv6ListenAddrs)+len(ipv4ListenAddrs))
+	for _, addr := range ipv4ListenAddrs {
+		listener, err := net.Listen("tcp4", addr)
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	for _, addr := range ipv6ListenAddrs {
+		listener, err := net.Listen("tcp6", addr)
+		if err != nil {
+			rpcsLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("gRPC: no valid listen address")
+	}
+
+	s := &grpcServer{
+		rpc:       rpc,
+		listeners: listeners,
+	}
+	// A single connection may not have more calls in progress than all
+	// clients together are allowed.
+	maxStreams := uint32(cfg.RPCMaxClients + cfg.RPCMaxWebsockets)
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+		grpc.MaxConcurrentStreams(maxStreams),
+	}
+	if rpc.tlsConfig != nil {
+		creds := credentials.NewTLS(rpc.tlsConfig.Clone())
+		opts = append(opts, grpc.Creds(creds))
+	}
+	s.server = grpc.NewServer(opts...)
+	dcrdrpc.RegisterVersionServiceServer(s.server, &grpcVersionServer{})
+	dcrdrpc.RegisterChainServiceServer(s.server, &grpcChainServer{rpc})
+	dcrdrpc.RegisterMempoolServiceServer(s.server, &grpcMempoolServer{rpc})
+	dcrdrpc.RegisterMiningServiceServer(s.server, &grpcMiningServer{rpc})
+	dcrdrpc.RegisterNetworkServiceServer(s.server, &grpcNetworkServer{rpc})
+	dcrdrpc.RegisterNotificationServiceServer(s.server,
+		&grpcNotificationServer{rpc})
+	return s, nil
+}
+
+// grpcVersionServer implements the version service.
+type grpcVersionServer struct{}
+
+// Version returns the version of the API.
+func (*grpcVersionServer) Version(ctx context.Context, req *dcrdrpc.VersionRequest) (*dcrdrpc.VersionResponse, error) {
+	return &dcrdrpc.VersionResponse{
+		VersionString: grpcSemverString,
+		Major:         grpcSemverMajor,
+		Minor:         grpcSemverMinor,
+		Patch:         grpcSemverPatch,
+	}, nil
+}
+
+// grpcChainServer implements the chain service with the handlers of the RPC
+// server.
+type grpcChainServer struct {
+	rpc *rpcServer
+}
+
+// BestBlock returns the hash and height of the best block.
+func (s *grpcChainServer) BestBlock(ctx context.Context, req *dcrdrpc.BestBlockRequest) (*dcrdrpc.BestBlockResponse, error) {
+	result, err := handleGetBestBlock(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	best := result.(*dcrjson.GetBestBlockResult)
+	hash, err := grpcHashString(best.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.BestBlockResponse{Hash: hash, Height: best.Height}, nil
+}
+
+// BlockchainInfo returns the state of the block chain.
+func (s *grpcChainServer) BlockchainInfo(ctx context.Context, req *dcrdrpc.BlockchainInfoRequest) (*dcrdrpc.BlockchainInfoResponse, error) {
+	result, err := handleGetBlockchainInfo(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	info := result.(*dcrjson.GetBlockChainInfoResult)
+	bestHash, err := grpcHashString(info.BestBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.BlockchainInfoResponse{
+		Chain:                info.Chain,
+		Blocks:               info.Blocks,
+		Headers:              info.Headers,
+		SyncHeight:           info.SyncHeight,
+		BestBlockHash:        bestHash,
+		Difficulty:           info.Difficulty,
+		VerificationProgress: info.VerificationProgress,
+		ChainWork:            info.ChainWork,
+		InitialBlockDownload: info.InitialBlockDownload,
+		MaxBlockSize:         info.MaxBlockSize,
+	}, nil
+}
+
+// BlockHash returns the hash of the main chain block at the requested height.
+func (s *grpcChainServer) BlockHash(ctx context.Context, req *dcrdrpc.BlockHashRequest) (*dcrdrpc.BlockHashResponse, error) {
+	cmd := &dcrjson.GetBlockHashCmd{Index: req.Height}
+	result, err := handleGetBlockHash(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	hash, err := grpcHashString(result.(string))
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.BlockHashResponse{Hash: hash}, nil
+}
+
+// blockHeaderResult returns the verbose getblockheader result for the block
+// with the passed hash.
+func (s *grpcChainServer) blockHeaderResult(ctx context.Context, hash *chainhash.Hash) (*dcrjson.GetBlockHeaderVerboseResult, error) {
+	verbose := true
+	cmd := &dcrjson.GetBlockHeaderCmd{Hash: hash.String(), Verbose: &verbose}
+	result, err := handleGetBlockHeader(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return result.(*dcrjson.GetBlockHeaderVerboseResult), nil
+}
+
+// Block returns the serialized block with the requested hash.
+func (s *grpcChainServer) Block(ctx context.Context, req *dcrdrpc.BlockRequest) (*dcrdrpc.BlockResponse, error) {
+	hash, err := grpcHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	verbose := false
+	cmd := &dcrjson.GetBlockCmd{Hash: hash.String(), Verbose: &verbose}
+	result, err := handleGetBlock(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	block, err := hex.DecodeString(result.(string))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	header, err := s.blockHeaderResult(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.BlockResponse{
+		Block:         block,
+		Height:        int64(header.Height),
+		Confirmations: header.Confirmations,
+	}, nil
+}
+
+// BlockHeader returns the serialized header of the block with the requested
+// hash.
+func (s *grpcChainServer) BlockHeader(ctx context.Context, req *dcrdrpc.BlockHeaderRequest) (*dcrdrpc.BlockHeaderResponse, error) {
+	hash, err := grpcHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	verbose := false
+	cmd := &dcrjson.GetBlockHeaderCmd{Hash: hash.String(), Verbose: &verbose}
+	result, err := handleGetBlockHeader(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	header, err := hex.DecodeString(result.(string))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	verboseHeader, err := s.blockHeaderResult(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.BlockHeaderResponse{
+		Header:        header,
+		Height:        int64(verboseHeader.Height),
+		Confirmations: verboseHeader.Confirmations,
+	}, nil
+}
+
+// Transaction returns the serialized transaction with the requested hash.
+func (s *grpcChainServer) Transaction(ctx context.Context, req *dcrdrpc.TransactionRequest) (*dcrdrpc.TransactionResponse, error) {
+	hash, err := grpcHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	verbose := 1
+	cmd := &dcrjson.GetRawTransactionCmd{Txid: hash.String(), Verbose: &verbose}
+	result, err := handleGetRawTransaction(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	txResult := result.(*dcrjson.TxRawResult)
+	tx, err := hex.DecodeString(txResult.Hex)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	blockHash, err := grpcHashString(txResult.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return &dcrdrpc.TransactionResponse{
+		Transaction:   tx,
+		BlockHash:     blockHash,
+		BlockHeight:   txResult.BlockHeight,
+		BlockIndex:    txResult.BlockIndex,
+		Confirmations: txResult.Confirmations,
+	}, nil
+}
+
+// TxOut returns the requested transaction output when it is unspent.
+func (s *grpcChainServer) TxOut(ctx context.Context, req *dcrdrpc.TxOutRequest) (*dcrdrpc.TxOutResponse, error) {
+	hash, err := grpcHash(req.TransactionHash)
+	if err != nil {
+		return nil, err
+	}
+	txOut, err := s.rpc.fetchTxOut(hash, req.OutputIndex, req.IncludeMempool)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if txOut == nil {
+		return &dcrdrpc.TxOutResponse{}, nil
+	}
+	return &dcrdrpc.TxOutResponse{
+		Unspent:            true,
+		Confirmations:      txOut.confirmations,
+		TransactionVersion: uint32(txOut.txVersion),
+		Amount:             txOut.value,
+		ScriptVersion:      uint32(txOut.scriptVersion),
+		PkScript:           txOut.pkScript,
+		Coinbase:           txOut.isCoinbase,
+	}, nil
+}
+
+// grpcMempoolServer implements the mempool service with the handlers of the
+// RPC server.
+type grpcMempoolServer struct {
+	rpc *rpcServer
+}
+
+// MempoolInfo returns the state of the mempool.
+func (s *grpcMempoolServer) MempoolInfo(ctx context.Context, req *dcrdrpc.MempoolInfoRequest) (*dcrdrpc.MempoolInfoResponse, error) {
+	result, err := handleGetMempoolInfo(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	info := result.(*dcrjson.GetMempoolInfoResult)
+	return &dcrdrpc.MempoolInfoResponse{
+		Size:         info.Size,
+		Bytes:        info.Bytes,
+		MaxMempool:   info.MaxMempool,
+		MinFee:       grpcAtoms(info.MempoolMinFee),
+		Evicted:      info.Evicted,
+		EvictedBytes: info.EvictedBytes,
+	}, nil
+}
+
+// grpcTxTypes maps the transaction types of the mempool service to the
+// transaction type filters of the getrawmempool RPC.
+var grpcTxTypes = map[dcrdrpc.TransactionType]dcrjson.GetRawMempoolTxTypeCmd{
+	dcrdrpc.TransactionType_ALL:        dcrjson.GRMAll,
+	dcrdrpc.TransactionType_REGULAR:    dcrjson.GRMRegular,
+	dcrdrpc.TransactionType_TICKET:     dcrjson.GRMTickets,
+	dcrdrpc.TransactionType_VOTE:       dcrjson.GRMVotes,
+	dcrdrpc.TransactionType_REVOCATION: dcrjson.GRMRevocations,
+}
+
+// MempoolTransactions returns the hashes of the transactions of the requested
+// type in the mempool.
+func (s *grpcMempoolServer) MempoolTransactions(ctx context.Context, req *dcrdrpc.MempoolTransactionsRequest) (*dcrdrpc.MempoolTransactionsResponse, error) {
+	txType, ok := grpcTxTypes[req.Type]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unknown transaction type %v", req.Type)
+	}
+	txTypeStr := string(txType)
+	cmd := &dcrjson.GetRawMempoolCmd{TxType: &txTypeStr}
+	result, err := handleGetRawMempool(s.rpc, cmd, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	hashStrs := result.([]string)
+	hashes := make([][]byte, 0, len(hashStrs))
+	for _, hashStr := range hashStrs {
+		hash, err := grpcHashString(hashStr)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return &dcrdrpc.MempoolTransactionsResponse{Hashes: hashes}, nil
+}
+
+// grpcMiningServer implements the mining service with the handlers of the RPC
+// server.
+type grpcMiningServer struct {
+	rpc *rpcServer
+}
+
+// MiningInfo returns the state of mining.
+func (s *grpcMiningServer) MiningInfo(ctx context.Context, req *dcrdrpc.MiningInfoRequest) (*dcrdrpc.MiningInfoResponse, error) {
+	result, err := handleGetMiningInfo(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	info := result.(*dcrjson.GetMiningInfoResult)
+	return &dcrdrpc.MiningInfoResponse{
+		Blocks:           info.Blocks,
+		CurrentBlockSize: info.CurrentBlockSize,
+		CurrentBlockTx:   info.CurrentBlockTx,
+		Difficulty:       info.Difficulty,
+		StakeDifficulty:  info.StakeDifficulty,
+		Generate:         info.Generate,
+		NetworkHashPs:    info.NetworkHashPS,
+		PooledTx:         info.PooledTx,
+	}, nil
+}
+
+// StakeDifficulty returns the current and the next ticket price.
+func (s *grpcMiningServer) StakeDifficulty(ctx context.Context, req *dcrdrpc.StakeDifficultyRequest) (*dcrdrpc.StakeDifficultyResponse, error) {
+	result, err := handleGetStakeDifficulty(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	sdiff := result.(*dcrjson.GetStakeDifficultyResult)
+	return &dcrdrpc.StakeDifficultyResponse{
+		Current: grpcAtoms(sdiff.CurrentStakeDifficulty),
+		Next:    grpcAtoms(sdiff.NextStakeDifficulty),
+	}, nil
+}
+
+// grpcNetworkServer implements the network service with the handlers of the
+// RPC server.
+type grpcNetworkServer struct {
+	rpc *rpcServer
+}
+
+// ConnectionCount returns the number of connected peers.
+func (s *grpcNetworkServer) ConnectionCount(ctx context.Context, req *dcrdrpc.ConnectionCountRequest) (*dcrdrpc.ConnectionCountResponse, error) {
+	result, err := handleGetConnectionCount(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &dcrdrpc.ConnectionCountResponse{Count: result.(int32)}, nil
+}
+
+// Peers returns the details of the connected peers.
+func (s *grpcNetworkServer) Peers(ctx context.Context, req *dcrdrpc.PeersRequest) (*dcrdrpc.PeersResponse, error) {
+	result, err := handleGetPeerInfo(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	infos := result.([]*dcrjson.GetPeerInfoResult)
+	peers := make([]*dcrdrpc.PeersResponse_Peer, 0, len(infos))
+	for _, info := range infos {
+		peers = append(peers, &dcrdrpc.PeersResponse_Peer{
+			Id:             info.ID,
+			Addr:           info.Addr,
+			AddrLocal:      info.AddrLocal,
+			Services:       info.Services,
+			RelayTxes:      info.RelayTxes,
+			LastSend:       info.LastSend,
+			LastRecv:       info.LastRecv,
+			BytesSent:      info.BytesSent,
+			BytesRecv:      info.BytesRecv,
+			ConnTime:       info.ConnTime,
+			TimeOffset:     info.TimeOffset,
+			PingTime:       info.PingTime,
+			Version:        info.Version,
+			SubVer:         info.SubVer,
+			Inbound:        info.Inbound,
+			StartingHeight: info.StartingHeight,
+			CurrentHeight:  info.CurrentHeight,
+			BanScore:       info.BanScore,
+			SyncNode:       info.SyncNode,
+		})
+	}
+	return &dcrdrpc.PeersResponse{Peers: peers}, nil
+}
+
+// NetTotals returns the number of bytes sent to and received from peers.
+func (s *grpcNetworkServer) NetTotals(ctx context.Context, req *dcrdrpc.NetTotalsRequest) (*dcrdrpc.NetTotalsResponse, error) {
+	result, err := handleGetNetTotals(s.rpc, nil, ctx.Done())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	totals := result.(*dcrjson.GetNetTotalsResult)
+	return &dcrdrpc.NetTotalsResponse{
+		TotalBytesRecv: totals.TotalBytesRecv,
+		TotalBytesSent: totals.TotalBytesSent,
+		TimeMillis:     totals.TimeMillis,
+	}, nil
+}
+
+// grpcNotificationServer implements the notification service with
+// subscriptions to the notification manager of the RPC server.
+type grpcNotificationServer struct {
+	rpc *rpcServer
+}
+
+// stream subscribes to the notifications accepted by the passed filter and
+// sends them with the passed send function until the stream with the passed
+// context ends.  Notifications the RPC user is not authorized to receive, as
+// determined by the passed function returning their notification method, are
+// skipped.
+func (s *grpcNotificationServer) stream(ctx context.Context, filter func(n interface{}) bool, method func(n interface{}) string, send func(n interface{}) error) error {
+	acl := grpcACL(ctx)
+	sub := newNtfnSubscription(filter, grpcNtfnBufferSize)
+	s.rpc.ntfnMgr.RegisterSubscription(sub)
+	defer s.rpc.ntfnMgr.UnregisterSubscription(sub)
+
+	for {
+		select {
+		case n, ok := <-sub.c:
+			if !ok {
+				return status.Error(codes.ResourceExhausted,
+					"client is not keeping up with notifications")
+			}
+			if !acl.permitsNotification(method(n)) {
+				continue
+			}
+			if err := send(n); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// BlockNotifications streams notifications about blocks that are connected to
+// and disconnected from the main chain.
+func (s *grpcNotificationServer) BlockNotifications(req *dcrdrpc.BlockNotificationsRequest, stream dcrdrpc.NotificationService_BlockNotificationsServer) error {
+	filter := func(n interface{}) bool {
+		switch n.(type) {
+		case *notificationBlockConnected, *notificationBlockDisconnected:
+			return true
+		}
+		return false
+	}
+	method := func(n interface{}) string {
+		if _, ok := n.(*notificationBlockConnected); ok {
+			return dcrjson.BlockConnectedNtfnMethod
+		}
+		return dcrjson.BlockDisconnectedNtfnMethod
+	}
+	send := func(n interface{}) error {
+		var block *dcrutil.Block
+		resp := new(dcrdrpc.BlockNotificationsResponse)
+		switch n := n.(type) {
+		case *notificationBlockConnected:
+			block = (*dcrutil.Block)(n)
+			resp.Type = dcrdrpc.BlockNotificationsResponse_CONNECTED
+		case *notificationBlockDisconnected:
+			block = (*dcrutil.Block)(n)
+			resp.Type = dcrdrpc.BlockNotificationsResponse_DISCONNECTED
+		}
+		header, err := block.MsgBlock().Header.Bytes()
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		resp.Hash = block.Hash()[:]
+		resp.Height = block.Height()
+		resp.Header = header
+		return stream.Send(resp)
+	}
+	return s.stream(stream.Context(), filter, method, send)
+}
+
+// ReorganizationNotifications streams notifications about reorganizations of
+// the main chain.
+func (s *grpcNotificationServer) ReorganizationNotifications(req *dcrdrpc.ReorganizationNotificationsRequest, stream dcrdrpc.NotificationService_ReorganizationNotificationsServer) error {
+	filter := func(n interface{}) bool {
+		_, ok := n.(*notificationReorganization)
+		return ok
+	}
+	method := func(n interface{}) string {
+		return dcrjson.ReorganizationNtfnMethod
+	}
+	send := func(n interface{}) error {
+		rd := (*blockchain.ReorganizationNtfnsData)(n.(*notificationReorganization))
+		return stream.Send(&dcrdrpc.ReorganizationNotificationsResponse{
+			OldHash:   rd.OldHash[:],
+			OldHeight: rd.OldHeight,
+			NewHash:   rd.NewHash[:],
+			NewHeight: rd.NewHeight,
+		})
+	}
+	return s.stream(stream.Context(), filter, method, send)
+}
+
+// WinningTicketsNotifications streams notifications about the tickets that
+// were selected to vote on new blocks.
+func (s *grpcNotificationServer) WinningTicketsNotifications(req *dcrdrpc.WinningTicketsNotificationsRequest, stream dcrdrpc.NotificationService_WinningTicketsNotificationsServer) error {
+	filter := func(n interface{}) bool {
+		_, ok := n.(*notificationWinningTickets)
+		return ok
+	}
+	method := func(n interface{}) string {
+		return dcrjson.WinningTicketsNtfnMethod
+	}
+	send := func(n interface{}) error {
+		wt := (*WinningTicketsNtfnData)(n.(*notificationWinningTickets))
+		tickets := make([][]byte, 0, len(wt.Tickets))
+		for i := range wt.Tickets {
+			tickets = append(tickets, wt.Tickets[i][:])
+		}
+		return stream.Send(&dcrdrpc.WinningTicketsNotificationsResponse{
+			BlockHash:   wt.BlockHash[:],
+			BlockHeight: wt.BlockHeight,
+			Tickets:     tickets,
+		})
+	}
+	return s.stream(stream.Context(), filter, method, send)
+}
+
+// MempoolTransactionNotifications streams notifications about transactions
+// that are newly accepted to the mempool.
+func (s *grpcNotificationServer) MempoolTransactionNotifications(req *dcrdrpc.MempoolTransactionNotificationsRequest, stream dcrdrpc.NotificationService_MempoolTransactionNotificationsServer) error {
+	filter := func(n interface{}) bool {
+		ntfn, ok := n.(*notificationTxAcceptedByMempool)
+		return ok && ntfn.isNew
+	}
+	method := func(n interface{}) string {
+		if req.IncludeTransaction {
+			return dcrjson.TxAcceptedVerboseNtfnMethod
+		}
+		return dcrjson.TxAcceptedNtfnMethod
+	}
+	send := func(n interface{}) error {
+		tx := n.(*notificationTxAcceptedByMempool).tx
+		resp := &dcrdrpc.MempoolTransactionNotificationsResponse{
+			Hash: tx.Hash()[:],
+		}
+		if req.IncludeTransaction {
+			txBytes, err := tx.MsgTx().Bytes()
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+			resp.Transaction = txBytes
+		}
+		return stream.Send(resp)
+	}
+	return s.stream(stream.Context(), filter, method, send)
+}
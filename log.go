@@ -14,6 +14,7 @@ import (
 	"github.com/decred/dcrd/fees"
 	"github.com/decred/dcrd/mempool/v2"
 	"github.com/decred/dcrd/peer"
+	"github.com/decred/dcrd/spv"
 	"github.com/decred/dcrd/txscript"
 	"github.com/decred/slog"
 	"github.com/jrick/logrotate/rotator"
@@ -59,6 +60,7 @@ var (
 	peerLog = backendLog.Logger("PEER")
 	rpcsLog = backendLog.Logger("RPCS")
 	scrpLog = backendLog.Logger("SCRP")
+	spvsLog = backendLog.Logger("SPVS")
 	srvrLog = backendLog.Logger("SRVR")
 	stkeLog = backendLog.Logger("STKE")
 	txmpLog = backendLog.Logger("TXMP")
@@ -74,6 +76,7 @@ func init() {
 	indexers.UseLogger(indxLog)
 	mempool.UseLogger(txmpLog)
 	peer.UseLogger(peerLog)
+	spv.UseLogger(spvsLog)
 	stake.UseLogger(stkeLog)
 	txscript.UseLogger(scrpLog)
 }
@@ -50,6 +50,8 @@ be an exhaustive list.
   - Reject invalid transactions according to the network consensus rules
   - Full script execution and validation with signature cache support
   - Individual transaction query support
+  - Opt-in replacement of regular transactions by conflicting transactions that
+    pay higher fees (signaled by an input sequence number below 0xfffffffe)
 - Stake transaction support (ticket purchases, votes and revocations)
   - Option to accept or reject old votes
 - Orphan transaction support (transactions that spend from unknown outputs)
@@ -65,6 +67,8 @@ be an exhaustive list.
   - Max signature operations per transaction
   - Max orphan transaction size
   - Max number of orphan transactions allowed
+  - Max total size of the pool with eviction of the lowest fee rate
+    transactions and their descendants and a dynamic minimum fee rate
 - Additional metadata tracking for each transaction
   - Timestamp when the transaction was added to the pool
   - Most recent block height when the transaction was added to the pool
@@ -72,6 +76,10 @@ be an exhaustive list.
   - The starting priority for the transaction
 - Manual control of transaction removal
   - Recursive removal of all dependent transactions
+- Serialization of the regular transactions and ticket purchases in the pool in
+  dependency order so they can be added back after a restart
+- Separate stem pool for transactions in the stem phase of the dandelion relay
+  that are validated against the pool without becoming visible through it
 
 Errors
 
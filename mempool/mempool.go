@@ -8,6 +8,7 @@ package mempool
 import (
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -55,6 +56,21 @@ const (
 	// maxNullDataOutputs is the maximum number of OP_RETURN null data
 	// pushes in a transaction, after which it is considered non-standard.
 	maxNullDataOutputs = 4
+
+	// minFeeRateHalfLife is the half-life of the decay of the dynamic
+	// minimum fee rate that is raised when transactions are evicted to limit
+	// the size of the pool.  The decay is faster when the pool is well under
+	// its maximum size.
+	minFeeRateHalfLife = time.Hour * 12
+
+	// maxReplacementEvictions is the maximum number of transactions,
+	// including descendants, a replacement transaction may evict from the
+	// pool.
+	maxReplacementEvictions = 100
+
+	// maxStemTxns is the maximum number of transactions in the stem phase
+	// of the dandelion relay that are kept in the stem pool.
+	maxStemTxns = 1000
 )
 
 // Config is a descriptor containing the memory pool configuration.
@@ -128,6 +144,12 @@ type Config struct {
 	// OnVoteReceived defines the function used to signal receiving a new
 	// vote in the mempool.
 	OnVoteReceived func(voteTx *wire.MsgTx)
+
+	// OnTxReplaced defines an optional function to be called whenever a
+	// transaction is removed from the mempool because it, or a transaction
+	// it depends on, was replaced by a transaction paying a higher fee.  It
+	// is called after the mempool lock is released.
+	OnTxReplaced func(replaced, replacement *dcrutil.Tx)
 }
 
 // Policy houses the policy (configuration parameters) which is used to
@@ -170,6 +192,12 @@ type Policy struct {
 	// considered a non-zero fee.
 	MinRelayTxFee dcrutil.Amount
 
+	// MaxPoolSize is the maximum total serialized size in bytes of the
+	// transactions in the pool.  The transactions with the lowest fee rates
+	// are evicted along with their descendants when it is exceeded.  A
+	// value of 0 disables the limit.
+	MaxPoolSize int64
+
 	// AllowOldVotes defines whether or not votes on old blocks will be
 	// admitted and relayed.
 	AllowOldVotes bool
@@ -236,6 +264,93 @@ type TxPool struct {
 
 	pennyTotal    float64 // exponentially decaying total for penny spends.
 	lastPennyUnix int64   // unix time of last ``penny spend''
+
+	// poolSize is the total serialized size of the transactions in the
+	// pool.
+	poolSize int64
+
+	// minFeeRate is the dynamic minimum fee rate in atoms/kB that is raised
+	// when transactions are evicted to limit the size of the pool and decays
+	// over time from when it was last updated.
+	minFeeRate        float64
+	lastMinFeeRateUpd time.Time
+
+	// evictedTxns and evictedBytes track the number and total size of the
+	// transactions evicted to limit the size of the pool.
+	evictedTxns  uint64
+	evictedBytes int64
+
+	// stemPool houses the transactions in the stem phase of the dandelion
+	// relay along with the outpoints they spend.  They are validated
+	// against the main pool, but are not part of it, so they are neither
+	// announced nor returned by any of the functions that query the pool.
+	stemPool      map[chainhash.Hash]*stemTx
+	stemOutpoints map[wire.OutPoint]*dcrutil.Tx
+
+	// replacements houses the replacements made while the mempool lock is
+	// held so the OnTxReplaced callback can be invoked for them once it is
+	// released.
+	replacements []txReplacement
+}
+
+// txReplacement describes a transaction that was removed from the pool because
+// it, or a transaction it depends on, was replaced by another transaction.
+type txReplacement struct {
+	replaced    *dcrutil.Tx
+	replacement *dcrutil.Tx
+}
+
+// takeReplacements returns the replacements made since it was last called.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) takeReplacements() []txReplacement {
+	replacements := mp.replacements
+	mp.replacements = nil
+	return replacements
+}
+
+// notifyReplacements invokes the OnTxReplaced callback for the passed
+// replacements.
+//
+// This function MUST NOT be called with the mempool lock held.
+func (mp *TxPool) notifyReplacements(replacements []txReplacement) {
+	if mp.cfg.OnTxReplaced == nil {
+		return
+	}
+	for _, r := range replacements {
+		mp.cfg.OnTxReplaced(r.replaced, r.replacement)
+	}
+}
+
+// stemTx describes a transaction in the stem pool along with the time after
+// which it is no longer kept there.
+type stemTx struct {
+	tx      *dcrutil.Tx
+	embargo time.Time
+}
+
+// SizeInfo houses information about the size of the pool and the transactions
+// evicted from it in order to limit it.
+type SizeInfo struct {
+	// Count is the number of transactions in the pool.
+	Count int
+
+	// Bytes is the total serialized size of the transactions in the pool.
+	Bytes int64
+
+	// MaxBytes is the maximum total size of the pool.  It is 0 when the
+	// size of the pool is not limited.
+	MaxBytes int64
+
+	// MinFeeRate is the dynamic minimum fee rate per kB that transactions
+	// must pay to be accepted to the pool.  It is 0 when no transactions
+	// have been evicted recently.
+	MinFeeRate dcrutil.Amount
+
+	// EvictedTxns and EvictedBytes are the number and total size of the
+	// transactions that have been evicted to limit the size of the pool.
+	EvictedTxns  uint64
+	EvictedBytes int64
 }
 
 // insertVote inserts a vote into the map of block votes.
@@ -629,6 +744,7 @@ func (mp *TxPool) removeTransaction(tx *dcrutil.Tx, removeRedeemers bool) {
 			delete(mp.outpoints, txIn.PreviousOutPoint)
 		}
 		delete(mp.pool, *txHash)
+		mp.poolSize -= int64(txDesc.Tx.MsgTx().SerializeSize())
 		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 
 		// Inform associated fee estimator that the transaction has been removed
@@ -699,6 +815,11 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint,
 	for _, txIn := range msgTx.TxIn {
 		mp.outpoints[txIn.PreviousOutPoint] = tx
 	}
+	mp.poolSize += int64(msgTx.SerializeSize())
+
+	// The transaction is no longer in the stem phase once it is part of the
+	// main pool.
+	mp.removeStemTransaction(tx.Hash())
 	atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 
 	// Add unconfirmed address index entries associated with the transaction
@@ -718,27 +839,413 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint,
 	}
 }
 
+// feeRate returns the fee rate in atoms/kB for the passed fee and size.
+func feeRate(fee, size int64) float64 {
+	return float64(fee) * 1000 / float64(size)
+}
+
+// txDescendants returns all transactions in the pool that spend outputs of the
+// passed transaction either directly or through other transactions in the
+// pool.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txDescendants(tx *dcrutil.Tx) []*dcrutil.Tx {
+	var descendants []*dcrutil.Tx
+	seen := make(map[chainhash.Hash]struct{})
+	processList := []*dcrutil.Tx{tx}
+	for len(processList) > 0 {
+		processItem := processList[0]
+		processList = processList[1:]
+
+		txType := stake.DetermineTxType(processItem.MsgTx())
+		tree := wire.TxTreeRegular
+		if txType != stake.TxTypeRegular {
+			tree = wire.TxTreeStake
+		}
+
+		prevOut := wire.OutPoint{Hash: *processItem.Hash(), Tree: tree}
+		for i := range processItem.MsgTx().TxOut {
+			prevOut.Index = uint32(i)
+			redeemer, exists := mp.outpoints[prevOut]
+			if !exists {
+				continue
+			}
+			if _, ok := seen[*redeemer.Hash()]; ok {
+				continue
+			}
+			seen[*redeemer.Hash()] = struct{}{}
+			descendants = append(descendants, redeemer)
+			processList = append(processList, redeemer)
+		}
+	}
+	return descendants
+}
+
+// dynamicMinFeeRate returns the dynamic minimum fee rate per kB after decaying
+// it for the time that passed since it was last updated.  The decay is faster
+// when the pool is well under its maximum size, and the rate drops to zero
+// once it decays below half of the minimum relay fee.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) dynamicMinFeeRate() dcrutil.Amount {
+	if mp.minFeeRate == 0 {
+		return 0
+	}
+
+	halfLife := minFeeRateHalfLife
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	if mp.poolSize < maxSize/4 {
+		halfLife /= 4
+	} else if mp.poolSize < maxSize/2 {
+		halfLife /= 2
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(mp.lastMinFeeRateUpd)
+	mp.minFeeRate /= math.Pow(2, elapsed.Seconds()/halfLife.Seconds())
+	mp.lastMinFeeRateUpd = now
+	if mp.minFeeRate < float64(mp.cfg.Policy.MinRelayTxFee)/2 {
+		mp.minFeeRate = 0
+	}
+
+	return dcrutil.Amount(mp.minFeeRate)
+}
+
+// evictionCandidate is a transaction that may be evicted to limit the size of
+// the pool along with the score that determines the order of eviction.
+type evictionCandidate struct {
+	tx    *dcrutil.Tx
+	score float64
+}
+
+// txAncestors returns the set of transactions in the pool whose outputs the
+// passed transaction spends either directly or through other transactions in
+// the pool.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) txAncestors(tx *dcrutil.Tx) map[chainhash.Hash]struct{} {
+	ancestors := make(map[chainhash.Hash]struct{})
+	processList := []*dcrutil.Tx{tx}
+	for len(processList) > 0 {
+		processItem := processList[0]
+		processList = processList[1:]
+		for _, txIn := range processItem.MsgTx().TxIn {
+			hash := txIn.PreviousOutPoint.Hash
+			if _, ok := ancestors[hash]; ok {
+				continue
+			}
+			desc, ok := mp.pool[hash]
+			if !ok {
+				continue
+			}
+			ancestors[hash] = struct{}{}
+			processList = append(processList, desc.Tx)
+		}
+	}
+	return ancestors
+}
+
+// evictionCandidates returns the transactions that may be evicted to limit the
+// size of the pool sorted by increasing score.  Each transaction is scored by
+// the higher of its own fee rate and the fee rate of it together with its
+// descendants so that transactions with descendants that pay for them are
+// evicted last.  Votes on the current best block are never evicted since they
+// are required to extend the chain.
+//
+// The pool is scored as if the transactions in the passed excluded set were not
+// in it and the passed extra transaction, which pays the passed fee, was, which
+// allows determining the effect of a replacement before making it.  The
+// excluded set must include the descendants of every transaction in it.  The
+// extra transaction may be nil.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) evictionCandidates(excluded map[chainhash.Hash]struct{}, extra *dcrutil.Tx, extraFee int64) []evictionCandidate {
+	var extraAncestors map[chainhash.Hash]struct{}
+	var extraSize int64
+	if extra != nil {
+		extraAncestors = mp.txAncestors(extra)
+		extraSize = int64(extra.MsgTx().SerializeSize())
+	}
+
+	bestHash := mp.cfg.BestHash()
+	candidates := make([]evictionCandidate, 0, len(mp.pool)+1)
+	for hash, desc := range mp.pool {
+		if _, ok := excluded[hash]; ok {
+			continue
+		}
+		if desc.Type == stake.TxTypeSSGen {
+			votedOn, _ := stake.SSGenBlockVotedOn(desc.Tx.MsgTx())
+			if votedOn == *bestHash {
+				continue
+			}
+		}
+
+		size := int64(desc.Tx.MsgTx().SerializeSize())
+		pkgFee, pkgSize := desc.Fee, size
+		for _, descendant := range mp.txDescendants(desc.Tx) {
+			if _, ok := excluded[*descendant.Hash()]; ok {
+				continue
+			}
+			descendantDesc := mp.pool[*descendant.Hash()]
+			pkgFee += descendantDesc.Fee
+			pkgSize += int64(descendant.MsgTx().SerializeSize())
+		}
+		if _, ok := extraAncestors[hash]; ok {
+			pkgFee += extraFee
+			pkgSize += extraSize
+		}
+		score := math.Max(feeRate(desc.Fee, size), feeRate(pkgFee, pkgSize))
+		candidates = append(candidates, evictionCandidate{desc.Tx, score})
+	}
+	if extra != nil {
+		candidates = append(candidates, evictionCandidate{extra,
+			feeRate(extraFee, extraSize)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].score < candidates[j].score
+	})
+	return candidates
+}
+
+// replacementEvicted returns whether or not the passed transaction, which pays
+// the passed fee, would be evicted right away to limit the size of the pool if
+// it replaced the passed transactions.  Such replacements are rejected before
+// the transactions they replace are removed since those could not be restored.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) replacementEvicted(tx *dcrutil.Tx, txFee int64, replaced []*dcrutil.Tx) bool {
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	poolSize := mp.poolSize + int64(tx.MsgTx().SerializeSize())
+	evicted := make(map[chainhash.Hash]struct{}, len(replaced))
+	for _, replacedTx := range replaced {
+		evicted[*replacedTx.Hash()] = struct{}{}
+		poolSize -= int64(replacedTx.MsgTx().SerializeSize())
+	}
+	if maxSize <= 0 || poolSize <= maxSize {
+		return false
+	}
+
+	// Evict the candidates the same way limitPoolSize does until either the
+	// pool is small enough or the replacement, or a transaction it depends
+	// on, would be evicted.
+	ancestors := mp.txAncestors(tx)
+	for _, candidate := range mp.evictionCandidates(evicted, tx, txFee) {
+		if poolSize <= maxSize {
+			return false
+		}
+		hash := candidate.tx.Hash()
+		if *hash == *tx.Hash() {
+			return true
+		}
+		if _, ok := ancestors[*hash]; ok {
+			return true
+		}
+		if _, ok := evicted[*hash]; ok {
+			continue
+		}
+
+		evicted[*hash] = struct{}{}
+		poolSize -= int64(candidate.tx.MsgTx().SerializeSize())
+		for _, descendant := range mp.txDescendants(candidate.tx) {
+			if _, ok := evicted[*descendant.Hash()]; ok {
+				continue
+			}
+			evicted[*descendant.Hash()] = struct{}{}
+			poolSize -= int64(descendant.MsgTx().SerializeSize())
+		}
+	}
+	return false
+}
+
+// limitPoolSize evicts transactions along with all of their descendants in the
+// pool, in order of increasing score as determined by evictionCandidates, until
+// the total size of the pool is within the configured maximum.
+//
+// The dynamic minimum fee rate is raised above the score of every evicted
+// transaction by the minimum relay fee so that transactions that would be
+// evicted again right away are not accepted.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) limitPoolSize() {
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	if maxSize <= 0 || mp.poolSize <= maxSize {
+		return
+	}
+
+	candidates := mp.evictionCandidates(nil, nil, 0)
+
+	// Evict the candidates until the pool is small enough.  The scores are
+	// not recalculated as descendants are evicted since that would only
+	// ever raise the scores of the remaining candidates.
+	for _, candidate := range candidates {
+		if mp.poolSize <= maxSize {
+			break
+		}
+		if !mp.isTransactionInPool(candidate.tx.Hash()) {
+			continue
+		}
+
+		numTxns, poolSize := len(mp.pool), mp.poolSize
+		mp.removeTransaction(candidate.tx, true)
+		mp.evictedTxns += uint64(numTxns - len(mp.pool))
+		mp.evictedBytes += poolSize - mp.poolSize
+		log.Debugf("Evicted transaction %v and %d descendants with a fee "+
+			"rate of %.0f atoms/kB to limit the mempool size",
+			candidate.tx.Hash(), numTxns-len(mp.pool)-1, candidate.score)
+
+		newMinFeeRate := candidate.score +
+			float64(mp.cfg.Policy.MinRelayTxFee)
+		if newMinFeeRate > float64(mp.dynamicMinFeeRate()) {
+			mp.minFeeRate = newMinFeeRate
+			mp.lastMinFeeRateUpd = time.Now()
+		}
+	}
+}
+
+// SizeInfo returns information about the size of the pool along with the
+// transactions evicted from it in order to limit it.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) SizeInfo() *SizeInfo {
+	mp.mtx.Lock()
+	info := &SizeInfo{
+		Count:        len(mp.pool),
+		Bytes:        mp.poolSize,
+		MaxBytes:     mp.cfg.Policy.MaxPoolSize,
+		MinFeeRate:   mp.dynamicMinFeeRate(),
+		EvictedTxns:  mp.evictedTxns,
+		EvictedBytes: mp.evictedBytes,
+	}
+	mp.mtx.Unlock()
+
+	return info
+}
+
+// signalsReplacement returns whether or not the passed transaction signals
+// that it may be replaced by a conflicting transaction that pays a higher fee.
+// A transaction signals replaceability when any of its inputs has a sequence
+// number less than the maximum minus one.
+func signalsReplacement(msgTx *wire.MsgTx) bool {
+	for _, txIn := range msgTx.TxIn {
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			return true
+		}
+	}
+	return false
+}
+
 // checkPoolDoubleSpend checks whether or not the passed transaction is
 // attempting to spend coins already spent by other transactions in the pool.
 // Note it does not check for double spends against transactions already in the
 // main chain.
 //
+// New regular transactions are allowed to spend the same coins as regular
+// transactions in the pool that signal replaceability.  The conflicting
+// transactions are returned in that case so the caller can ensure the
+// transaction is a valid replacement for them via validateReplacement.
+//
 // This function MUST be called with the mempool lock held (for reads).
-func (mp *TxPool) checkPoolDoubleSpend(tx *dcrutil.Tx, txType stake.TxType) error {
+func (mp *TxPool) checkPoolDoubleSpend(tx *dcrutil.Tx, txType stake.TxType, isNew bool) (map[chainhash.Hash]*TxDesc, error) {
+	var conflicts map[chainhash.Hash]*TxDesc
 	for i, txIn := range tx.MsgTx().TxIn {
 		// We don't care about double spends of stake bases.
 		if i == 0 && (txType == stake.TxTypeSSGen || txType == stake.TxTypeSSRtx) {
 			continue
 		}
 
-		if txR, exists := mp.outpoints[txIn.PreviousOutPoint]; exists {
+		txR, exists := mp.outpoints[txIn.PreviousOutPoint]
+		if !exists {
+			continue
+		}
+		conflict := mp.pool[*txR.Hash()]
+		if !isNew || txType != stake.TxTypeRegular || conflict == nil ||
+			conflict.Type != stake.TxTypeRegular ||
+			!signalsReplacement(txR.MsgTx()) {
+
 			str := fmt.Sprintf("transaction %v in the pool "+
 				"already spends the same coins", txR.Hash())
-			return txRuleError(wire.RejectDuplicate, str)
+			return nil, txRuleError(wire.RejectDuplicate, str)
 		}
+
+		if conflicts == nil {
+			conflicts = make(map[chainhash.Hash]*TxDesc)
+		}
+		conflicts[*txR.Hash()] = conflict
 	}
 
-	return nil
+	return conflicts, nil
+}
+
+// validateReplacement ensures the passed transaction, which pays the provided
+// fee, is allowed to replace the passed conflicting transactions in the pool
+// and returns all of the transactions that would be evicted by doing so, which
+// consist of the conflicts and their descendants.
+//
+// A replacement must pay a higher fee rate than every transaction it directly
+// conflicts with and a higher absolute fee than all of the evicted
+// transactions combined.  The additional fee must also cover the minimum relay
+// fee for the replacement itself, since it consumes bandwidth on top of what
+// was already paid for by the evicted transactions.  Finally, a replacement may
+// not spend outputs of the transactions it evicts and is limited in the number
+// of transactions it may evict.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) validateReplacement(tx *dcrutil.Tx, txFee int64, conflicts map[chainhash.Hash]*TxDesc) ([]*dcrutil.Tx, error) {
+	txHash := tx.Hash()
+	txSize := int64(tx.MsgTx().SerializeSize())
+	txFeeRate := feeRate(txFee, txSize)
+
+	evicted := make(map[chainhash.Hash]*TxDesc)
+	for hash, conflict := range conflicts {
+		conflictFeeRate := feeRate(conflict.Fee,
+			int64(conflict.Tx.MsgTx().SerializeSize()))
+		if txFeeRate <= conflictFeeRate {
+			str := fmt.Sprintf("replacement transaction %v has a fee "+
+				"rate of %.0f atoms/kB which is not higher than the "+
+				"%.0f atoms/kB of replaced transaction %v", txHash,
+				txFeeRate, conflictFeeRate, hash)
+			return nil, txRuleError(wire.RejectInsufficientFee, str)
+		}
+
+		evicted[hash] = conflict
+		for _, descendant := range mp.txDescendants(conflict.Tx) {
+			evicted[*descendant.Hash()] = mp.pool[*descendant.Hash()]
+		}
+	}
+	if len(evicted) > maxReplacementEvictions {
+		str := fmt.Sprintf("replacement transaction %v would evict %d "+
+			"transactions which exceeds the maximum of %d", txHash,
+			len(evicted), maxReplacementEvictions)
+		return nil, txRuleError(wire.RejectNonstandard, str)
+	}
+
+	for _, txIn := range tx.MsgTx().TxIn {
+		prevHash := &txIn.PreviousOutPoint.Hash
+		if _, ok := evicted[*prevHash]; ok {
+			str := fmt.Sprintf("replacement transaction %v spends "+
+				"outputs of transaction %v which it replaces", txHash,
+				prevHash)
+			return nil, txRuleError(wire.RejectInvalid, str)
+		}
+	}
+
+	var evictedFees int64
+	evictedTxns := make([]*dcrutil.Tx, 0, len(evicted))
+	for _, desc := range evicted {
+		evictedFees += desc.Fee
+		evictedTxns = append(evictedTxns, desc.Tx)
+	}
+	minFee := evictedFees + calcMinRequiredTxRelayFee(txSize,
+		mp.cfg.Policy.MinRelayTxFee)
+	if txFee <= evictedFees || txFee < minFee {
+		str := fmt.Sprintf("replacement transaction %v has %v fees which "+
+			"is under the required amount of %v to replace %d "+
+			"transactions paying %v fees", txHash, txFee, minFee,
+			len(evicted), evictedFees)
+		return nil, txRuleError(wire.RejectInsufficientFee, str)
+	}
+
+	return evictedTxns, nil
 }
 
 // checkVoteDoubleSpend checks whether or not the passed vote is for a block
@@ -813,10 +1320,10 @@ func (mp *TxPool) IsRegTxTreeKnownDisapproved(hash *chainhash.Hash) bool {
 // fetchInputUtxos loads utxo details about the input transactions referenced by
 // the passed transaction.  First, it loads the details from the viewpoint of
 // the main chain, then it adjusts them based upon the contents of the
-// transaction pool.
+// transaction pool and, when the include stem flag is set, the stem pool.
 //
 // This function MUST be called with the mempool lock held (for reads).
-func (mp *TxPool) fetchInputUtxos(tx *dcrutil.Tx) (*blockchain.UtxoViewpoint, error) {
+func (mp *TxPool) fetchInputUtxos(tx *dcrutil.Tx, includeStem bool) (*blockchain.UtxoViewpoint, error) {
 	knownDisapproved := mp.IsRegTxTreeKnownDisapproved(mp.cfg.BestHash())
 	utxoView, err := mp.cfg.FetchUtxoView(tx, !knownDisapproved)
 	if err != nil {
@@ -832,6 +1339,11 @@ func (mp *TxPool) fetchInputUtxos(tx *dcrutil.Tx) (*blockchain.UtxoViewpoint, er
 		if poolTxDesc, exists := mp.pool[originHash]; exists {
 			utxoView.AddTxOuts(poolTxDesc.Tx, mining.UnminedHeight,
 				wire.NullBlockIndex)
+			continue
+		}
+		if stx, exists := mp.stemPool[originHash]; includeStem && exists {
+			utxoView.AddTxOuts(stx.tx, mining.UnminedHeight,
+				wire.NullBlockIndex)
 		}
 	}
 
@@ -858,7 +1370,10 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 
 // maybeAcceptTransaction is the internal function which implements the public
 // MaybeAcceptTransaction.  See the comment for MaybeAcceptTransaction for
-// more details.
+// more details.  The pool is left unchanged when the check only flag is set,
+// which only determines whether or not the transaction would be accepted.  The
+// outputs of transactions in the stem pool are only available to transactions
+// that are checked this way since they are bound for the stem pool as well.
 //
 // This function MUST be called with the mempool lock held (for writes).
 // DECRED - TODO
@@ -866,7 +1381,7 @@ func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*dcrutil.Tx, error)
 // so that we can easily pick different stake tx types from the mempool later.
 // This should probably be done at the bottom using "IsSStx" etc functions.
 // It should also set the dcrutil tree type for the tx as well.
-func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allowHighFees, rejectDupOrphans bool) ([]*chainhash.Hash, error) {
+func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allowHighFees, rejectDupOrphans, checkOnly bool) ([]*chainhash.Hash, error) {
 	msgTx := tx.MsgTx()
 	txHash := tx.Hash()
 	// Don't accept the transaction if it already exists in the pool.  This
@@ -1013,8 +1528,9 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	// that happens later after fetching the referenced transaction inputs from
 	// the main chain which examines the actual spend data and prevents double
 	// spends.
+	var conflicts map[chainhash.Hash]*TxDesc
 	if !isVote && !isRevocation {
-		err = mp.checkPoolDoubleSpend(tx, txType)
+		conflicts, err = mp.checkPoolDoubleSpend(tx, txType, isNew)
 		if err != nil {
 			return nil, err
 		}
@@ -1077,7 +1593,7 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	// to this transaction.  This function also attempts to fetch the
 	// transaction itself to be used for detecting a duplicate transaction
 	// without needing to do a separate lookup.
-	utxoView, err := mp.fetchInputUtxos(tx)
+	utxoView, err := mp.fetchInputUtxos(tx, checkOnly)
 	if err != nil {
 		if cerr, ok := err.(blockchain.RuleError); ok {
 			return nil, chainRuleError(cerr)
@@ -1287,6 +1803,23 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		}
 	}
 
+	// Don't allow new regular transactions and ticket purchases that pay less
+	// than the dynamic minimum fee rate which is raised when transactions are
+	// evicted to limit the size of the pool, since they would otherwise
+	// likely be evicted again right away.  Votes and revocations are exempt.
+	if isNew && (txType == stake.TxTypeRegular || isTicket) {
+		if minFeeRate := mp.dynamicMinFeeRate(); minFeeRate > 0 {
+			minPoolFee := calcMinRequiredTxRelayFee(serializedSize,
+				minFeeRate)
+			if txFee < minPoolFee {
+				str := fmt.Sprintf("transaction %v has %v fees which "+
+					"is under the current mempool minimum of %v",
+					txHash, txFee, minPoolFee)
+				return nil, txRuleError(wire.RejectInsufficientFee, str)
+			}
+		}
+	}
+
 	// Check whether allowHighFees is set to false (default), if so, then make
 	// sure the current fee is sensible.  If people would like to avoid this
 	// check then they can AllowHighFees = true
@@ -1301,6 +1834,25 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		}
 	}
 
+	// Ensure a transaction that conflicts with replaceable transactions in
+	// the pool is a valid replacement for them.
+	var replacedTxns []*dcrutil.Tx
+	if len(conflicts) > 0 {
+		replacedTxns, err = mp.validateReplacement(tx, txFee, conflicts)
+		if err != nil {
+			return nil, err
+		}
+
+		// Reject the replacement when it would be evicted to limit the
+		// size of the pool right after the transactions it replaces were
+		// removed.
+		if mp.replacementEvicted(tx, txFee, replacedTxns) {
+			str := fmt.Sprintf("replacement transaction %v was not "+
+				"accepted because the mempool is full", txHash)
+			return nil, txRuleError(wire.RejectInsufficientFee, str)
+		}
+	}
+
 	// Verify crypto signatures for each input and reject the transaction if
 	// any don't verify.
 	flags, err := mp.cfg.Policy.StandardVerifyFlags()
@@ -1316,6 +1868,25 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		return nil, err
 	}
 
+	// Leave the pool unchanged when the transaction is only being checked.
+	if checkOnly {
+		return nil, nil
+	}
+
+	// Remove the transactions being replaced along with their descendants
+	// now that the replacement is known to be valid.
+	for _, conflict := range conflicts {
+		mp.removeTransaction(conflict.Tx, true)
+	}
+	for _, replaced := range replacedTxns {
+		log.Debugf("Replaced transaction %v with %v", replaced.Hash(),
+			txHash)
+		mp.replacements = append(mp.replacements, txReplacement{
+			replaced:    replaced,
+			replacement: tx,
+		})
+	}
+
 	// Add to transaction pool.
 	mp.addTransaction(utxoView, tx, txType, bestHeight, txFee)
 
@@ -1329,6 +1900,15 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 		}
 	}
 
+	// Evict the transactions with the lowest fee rates when the pool is
+	// now too large.  This might include the transaction itself.
+	mp.limitPoolSize()
+	if !mp.isTransactionInPool(txHash) {
+		str := fmt.Sprintf("transaction %v was not accepted because "+
+			"the mempool is full", txHash)
+		return nil, txRuleError(wire.RejectInsufficientFee, str)
+	}
+
 	log.Debugf("Accepted transaction %v (pool size: %v)", txHash,
 		len(mp.pool))
 
@@ -1346,8 +1926,11 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 func (mp *TxPool) MaybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit bool) ([]*chainhash.Hash, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	hashes, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true, true)
+	hashes, err := mp.maybeAcceptTransaction(tx, isNew, rateLimit, true,
+		true, false)
+	replacements := mp.takeReplacements()
 	mp.mtx.Unlock()
+	mp.notifyReplacements(replacements)
 
 	return hashes, err
 }
@@ -1395,7 +1978,7 @@ func (mp *TxPool) processOrphans(acceptedTx *dcrutil.Tx) []*dcrutil.Tx {
 			// Potentially accept an orphan into the tx pool.
 			for _, tx := range orphans {
 				missing, err := mp.maybeAcceptTransaction(
-					tx, true, true, true, false)
+					tx, true, true, true, false, false)
 				if err != nil {
 					// The orphan is now invalid, so there
 					// is no way any other orphans which
@@ -1512,7 +2095,9 @@ func (mp *TxPool) PruneExpiredTx() {
 func (mp *TxPool) ProcessOrphans(acceptedTx *dcrutil.Tx) []*dcrutil.Tx {
 	mp.mtx.Lock()
 	acceptedTxns := mp.processOrphans(acceptedTx)
+	replacements := mp.takeReplacements()
 	mp.mtx.Unlock()
+	mp.notifyReplacements(replacements)
 	return acceptedTxns
 }
 
@@ -1530,7 +2115,19 @@ func (mp *TxPool) ProcessOrphans(acceptedTx *dcrutil.Tx) []*dcrutil.Tx {
 func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, allowHighFees bool) ([]*dcrutil.Tx, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
-	defer mp.mtx.Unlock()
+	acceptedTxs, err := mp.processTransaction(tx, allowOrphan, rateLimit,
+		allowHighFees)
+	replacements := mp.takeReplacements()
+	mp.mtx.Unlock()
+	mp.notifyReplacements(replacements)
+	return acceptedTxs, err
+}
+
+// processTransaction is the internal function which implements the public
+// ProcessTransaction.  See the comment for ProcessTransaction for more details.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) processTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, allowHighFees bool) ([]*dcrutil.Tx, error) {
 	var err error
 	defer func() {
 		if err != nil {
@@ -1541,7 +2138,7 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, all
 
 	// Potentially accept the transaction to the memory pool.
 	missingParents, err := mp.maybeAcceptTransaction(tx, true, rateLimit,
-		allowHighFees, true)
+		allowHighFees, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -1586,6 +2183,115 @@ func (mp *TxPool) ProcessTransaction(tx *dcrutil.Tx, allowOrphan, rateLimit, all
 	return nil, err
 }
 
+// removeStemTransaction removes the transaction with the passed hash from the
+// stem pool, if it is there, and returns it.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) removeStemTransaction(hash *chainhash.Hash) *dcrutil.Tx {
+	stx, exists := mp.stemPool[*hash]
+	if !exists {
+		return nil
+	}
+	for _, txIn := range stx.tx.MsgTx().TxIn {
+		delete(mp.stemOutpoints, txIn.PreviousOutPoint)
+	}
+	delete(mp.stemPool, *hash)
+	return stx.tx
+}
+
+// ProcessStemTransaction adds the passed transaction to the stem pool after
+// ensuring it would be accepted to the main pool, so it can be relayed in the
+// stem phase of the dandelion relay.  Transactions in the stem pool are not
+// visible through any of the other functions of the pool.  Only regular
+// transactions that don't depend on unconfirmed transactions which are in
+// neither the main pool nor the stem pool are accepted.
+//
+// The transaction is kept until it is added to the main pool or the passed
+// embargo time, or that of a transaction in the stem pool that depends on it,
+// has passed, after which it is returned by ExpiredStemTransactions.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ProcessStemTransaction(tx *dcrutil.Tx, embargo time.Time, allowHighFees bool) error {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	txHash := tx.Hash()
+	if _, exists := mp.stemPool[*txHash]; exists {
+		str := fmt.Sprintf("already have stem transaction %v", txHash)
+		return txRuleError(wire.RejectDuplicate, str)
+	}
+	if stake.DetermineTxType(tx.MsgTx()) != stake.TxTypeRegular {
+		str := fmt.Sprintf("stake transaction %v can't be relayed in "+
+			"the stem phase", txHash)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+	if len(mp.stemPool) >= maxStemTxns {
+		str := fmt.Sprintf("stem transaction %v was not accepted "+
+			"because the stem pool is full", txHash)
+		return txRuleError(wire.RejectInsufficientFee, str)
+	}
+	for _, txIn := range tx.MsgTx().TxIn {
+		if spender, ok := mp.stemOutpoints[txIn.PreviousOutPoint]; ok {
+			str := fmt.Sprintf("stem transaction %v spends output "+
+				"%v already spent by stem transaction %v", txHash,
+				txIn.PreviousOutPoint, spender.Hash())
+			return txRuleError(wire.RejectDuplicate, str)
+		}
+	}
+
+	// Ensure the transaction would be accepted to the main pool.  The free
+	// transaction rate limit is applied once the transaction is added to
+	// the main pool.
+	missingParents, err := mp.maybeAcceptTransaction(tx, true, false,
+		allowHighFees, true, true)
+	if err != nil {
+		return err
+	}
+	if len(missingParents) > 0 {
+		str := fmt.Sprintf("stem transaction %v references outputs of "+
+			"unknown or fully-spent transaction %v", txHash,
+			missingParents[0])
+		return txRuleError(wire.RejectDuplicate, str)
+	}
+
+	mp.stemPool[*txHash] = &stemTx{tx: tx, embargo: embargo}
+	for _, txIn := range tx.MsgTx().TxIn {
+		mp.stemOutpoints[txIn.PreviousOutPoint] = tx
+	}
+	log.Debugf("Accepted stem transaction %v (stem pool size: %v)", txHash,
+		len(mp.stemPool))
+	return nil
+}
+
+// ExpiredStemTransactions removes the transactions whose embargo time is before
+// the passed time from the stem pool, along with the transactions in the stem
+// pool they depend on, and returns them.  Transactions are returned after the
+// ones they depend on so they can be added to the main pool in order.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) ExpiredStemTransactions(now time.Time) []*dcrutil.Tx {
+	mp.mtx.Lock()
+	var expired []*dcrutil.Tx
+	var removeWithParents func(tx *dcrutil.Tx)
+	removeWithParents = func(tx *dcrutil.Tx) {
+		mp.removeStemTransaction(tx.Hash())
+		for _, txIn := range tx.MsgTx().TxIn {
+			parent, exists := mp.stemPool[txIn.PreviousOutPoint.Hash]
+			if exists {
+				removeWithParents(parent.tx)
+			}
+		}
+		expired = append(expired, tx)
+	}
+	for _, stx := range mp.stemPool {
+		if stx.embargo.Before(now) {
+			removeWithParents(stx.tx)
+		}
+	}
+	mp.mtx.Unlock()
+	return expired
+}
+
 // Count returns the number of transactions in the main pool.  It does not
 // include the orphan pool.
 //
@@ -1653,7 +2359,7 @@ func (mp *TxPool) VerboseTxDescs() []*VerboseTxDesc {
 		// some reason.
 		tx := desc.Tx
 		var currentPriority float64
-		utxos, err := mp.fetchInputUtxos(tx)
+		utxos, err := mp.fetchInputUtxos(tx, false)
 		if err == nil {
 			currentPriority = mining.CalcPriority(tx.MsgTx(), utxos,
 				bestHeight+1)
@@ -1713,5 +2419,7 @@ func New(cfg *Config) *TxPool {
 		orphansByPrev: make(map[wire.OutPoint]map[chainhash.Hash]*dcrutil.Tx),
 		outpoints:     make(map[wire.OutPoint]*dcrutil.Tx),
 		votes:         make(map[chainhash.Hash][]mining.VoteDesc),
+		stemPool:      make(map[chainhash.Hash]*stemTx),
+		stemOutpoints: make(map[wire.OutPoint]*dcrutil.Tx),
 	}
 }
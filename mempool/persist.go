@@ -0,0 +1,123 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/blockchain/stake"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// The persisted pool contents consist of the regular transactions and ticket
+// purchases in the pool ordered such that every transaction comes after any
+// transactions in the pool it spends.  Votes and revocations are not persisted
+// since they are only relevant for a short time and are rebroadcast by their
+// creators as needed.
+//
+// The serialized format is:
+//
+//   <version><num txns><tx 1>...<tx n>
+//
+//   Field       Type     Size
+//   version     uint32   4 (little endian)
+//   num txns    varint   variable
+//   tx          wire     variable (transaction wire serialization)
+// -----------------------------------------------------------------------------
+
+// persistVersion is the current version of the persisted pool format.
+const persistVersion = 1
+
+// WriteTxns writes the regular transactions and ticket purchases in the pool
+// to the passed writer using the format described above and returns the
+// number of transactions that were written.  The transactions may be added back
+// to a pool in the written order by passing the result of ReadTxns to
+// ProcessTransaction.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) WriteTxns(w io.Writer) (int, error) {
+	mp.mtx.RLock()
+	txns := make([]*dcrutil.Tx, 0, len(mp.pool))
+	visited := make(map[chainhash.Hash]struct{}, len(mp.pool))
+	var visit func(desc *TxDesc)
+	visit = func(desc *TxDesc) {
+		if _, ok := visited[*desc.Tx.Hash()]; ok {
+			return
+		}
+		visited[*desc.Tx.Hash()] = struct{}{}
+
+		// Visit any transactions in the pool spent by the transaction
+		// first so they are written before it.
+		for _, txIn := range desc.Tx.MsgTx().TxIn {
+			prevHash := &txIn.PreviousOutPoint.Hash
+			if prevDesc, ok := mp.pool[*prevHash]; ok {
+				visit(prevDesc)
+			}
+		}
+
+		if desc.Type == stake.TxTypeRegular || desc.Type == stake.TxTypeSStx {
+			txns = append(txns, desc.Tx)
+		}
+	}
+	for _, desc := range mp.pool {
+		visit(desc)
+	}
+	mp.mtx.RUnlock()
+
+	var version [4]byte
+	binary.LittleEndian.PutUint32(version[:], persistVersion)
+	if _, err := w.Write(version[:]); err != nil {
+		return 0, err
+	}
+	err := wire.WriteVarInt(w, wire.ProtocolVersion, uint64(len(txns)))
+	if err != nil {
+		return 0, err
+	}
+	for i, tx := range txns {
+		if err := tx.MsgTx().Serialize(w); err != nil {
+			return i, err
+		}
+	}
+
+	return len(txns), nil
+}
+
+// ReadTxns reads transactions written by WriteTxns from the passed reader.
+// The transactions are returned in the order they were written and have not
+// been validated in any way.
+func ReadTxns(r io.Reader) ([]*dcrutil.Tx, error) {
+	var version [4]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, err
+	}
+	if v := binary.LittleEndian.Uint32(version[:]); v != persistVersion {
+		return nil, fmt.Errorf("unsupported persisted mempool version %d",
+			v)
+	}
+
+	count, err := wire.ReadVarInt(r, wire.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Avoid trusting the count for the initial allocation since it is read
+	// from an external source.
+	var txns []*dcrutil.Tx
+	for i := uint64(0); i < count; i++ {
+		var msgTx wire.MsgTx
+		if err := msgTx.Deserialize(r); err != nil {
+			return nil, fmt.Errorf("unable to read persisted mempool "+
+				"transaction %d: %v", i, err)
+		}
+		txns = append(txns, dcrutil.NewTx(&msgTx))
+	}
+
+	return txns, nil
+}
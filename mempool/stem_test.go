@@ -0,0 +1,124 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/txscript"
+	"github.com/decred/dcrd/wire"
+)
+
+// spendTx returns a transaction that spends the first output of the passed
+// transaction to a single output that anyone can spend, paying the passed fee.
+func spendTx(parent *dcrutil.Tx, fee int64) *dcrutil.Tx {
+	parentOut := parent.MsgTx().TxOut[0]
+	tx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(parent.Hash(), 0, wire.TxTreeRegular)
+	tx.AddTxIn(wire.NewTxIn(prevOut, parentOut.Value, nil))
+	tx.AddTxOut(wire.NewTxOut(parentOut.Value-fee,
+		[]byte{txscript.OP_TRUE}))
+	return dcrutil.NewTx(tx)
+}
+
+// TestStemTransactionChain ensures transactions in the stem pool may be spent
+// by other transactions in the stem pool and that they are fluffed before the
+// transactions that depend on them.
+func TestStemTransactionChain(t *testing.T) {
+	// Create a confirmed transaction with an output anyone can spend.
+	funding := wire.NewMsgTx()
+	funding.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	funding.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_TRUE}))
+	fundingTx := dcrutil.NewTx(funding)
+
+	params := &chaincfg.RegNetParams
+	bestHash := chainhash.Hash{0x01}
+	const bestHeight = 100
+	mp := New(&Config{
+		Policy: Policy{
+			MaxTxVersion:         1,
+			DisableRelayPriority: true,
+			AcceptNonStd:         true,
+			MaxOrphanTxs:         5,
+			MaxOrphanTxSize:      1000,
+			MaxSigOpsPerTx:       blockchain.MaxSigOpsPerBlock / 5,
+			MinRelayTxFee:        1000,
+			StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
+				return txscript.ScriptVerifyCleanStack, nil
+			},
+			AcceptSequenceLocks: func() (bool, error) {
+				return true, nil
+			},
+		},
+		ChainParams: params,
+		FetchUtxoView: func(tx *dcrutil.Tx, treeValid bool) (*blockchain.UtxoViewpoint, error) {
+			view := blockchain.NewUtxoViewpoint()
+			view.AddTxOuts(fundingTx, bestHeight-10, 0)
+			entries := view.Entries()
+			for _, txIn := range tx.MsgTx().TxIn {
+				hash := txIn.PreviousOutPoint.Hash
+				if _, ok := entries[hash]; !ok {
+					entries[hash] = nil
+				}
+			}
+			return view, nil
+		},
+		BestHash:   func() *chainhash.Hash { return &bestHash },
+		BestHeight: func() int64 { return bestHeight },
+		PastMedianTime: func() time.Time {
+			return time.Now().Add(-time.Hour)
+		},
+		CalcSequenceLock: func(*dcrutil.Tx, *blockchain.UtxoViewpoint) (*blockchain.SequenceLock, error) {
+			return &blockchain.SequenceLock{MinHeight: -1, MinTime: -1}, nil
+		},
+		SubsidyCache: blockchain.NewSubsidyCache(bestHeight, params),
+		SigCache:     txscript.NewSigCache(1000),
+	})
+
+	// Add a parent and a child that spends it to the stem pool with the
+	// child being embargoed for a shorter time than the parent.
+	parent := spendTx(fundingTx, 1e5)
+	child := spendTx(parent, 1e5)
+	now := time.Now()
+	err := mp.ProcessStemTransaction(parent, now.Add(time.Minute), false)
+	if err != nil {
+		t.Fatalf("unable to add parent to the stem pool: %v", err)
+	}
+	err = mp.ProcessStemTransaction(child, now.Add(time.Second), false)
+	if err != nil {
+		t.Fatalf("unable to add child to the stem pool: %v", err)
+	}
+	if mp.HaveTransaction(parent.Hash()) || mp.HaveTransaction(child.Hash()) {
+		t.Fatal("stem transactions are visible in the main pool")
+	}
+
+	// Expiring the child must fluff the parent along with it and before it.
+	if expired := mp.ExpiredStemTransactions(now); len(expired) != 0 {
+		t.Fatalf("%d stem transactions expired early", len(expired))
+	}
+	expired := mp.ExpiredStemTransactions(now.Add(2 * time.Second))
+	if len(expired) != 2 || expired[0] != parent || expired[1] != child {
+		t.Fatalf("unexpected expired stem transactions %v", expired)
+	}
+	for _, tx := range expired {
+		_, err := mp.ProcessTransaction(tx, false, false, true)
+		if err != nil {
+			t.Fatalf("unable to add fluffed transaction %v: %v",
+				tx.Hash(), err)
+		}
+	}
+	if !mp.HaveTransaction(parent.Hash()) || !mp.HaveTransaction(child.Hash()) {
+		t.Fatal("fluffed transactions are not in the main pool")
+	}
+	if expired := mp.ExpiredStemTransactions(now.Add(time.Hour)); len(expired) != 0 {
+		t.Fatalf("%d stem transactions left in the stem pool",
+			len(expired))
+	}
+}
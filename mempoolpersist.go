@@ -0,0 +1,154 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/mempool/v2"
+)
+
+// mempoolDataFilename is the name of the file in the data directory the
+// contents of the memory pool are persisted to across restarts.
+const mempoolDataFilename = "mempool.dat"
+
+// mempoolDataPath returns the path of the file the contents of the memory pool
+// are persisted to.
+func mempoolDataPath() string {
+	return filepath.Join(cfg.DataDir, mempoolDataFilename)
+}
+
+// saveMempool writes the regular transactions and ticket purchases in the
+// memory pool to the mempool data file, replacing any existing one, and
+// returns the number of transactions written.
+//
+// This function is safe for concurrent access.
+func (s *server) saveMempool() (int, error) {
+	s.mempoolPersistMtx.Lock()
+	defer s.mempoolPersistMtx.Unlock()
+
+	// Write the transactions to a temporary file which is only moved over
+	// the existing data file once it is complete so an interrupted write
+	// does not lose the previously saved contents.
+	path := mempoolDataPath()
+	tmpPath := path + ".new"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC,
+		0600)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(file)
+	count, err := s.txMemPool.WriteTxns(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return 0, err
+	}
+
+	srvrLog.Infof("Saved %d transactions from the memory pool to %s", count,
+		path)
+	return count, nil
+}
+
+// loadMempool adds the transactions in the mempool data file to the memory
+// pool and returns the number of them that were accepted and rejected.  The
+// transactions are processed with the same rules as transactions received
+// from the network, so transactions that have expired, have become invalid, or
+// no longer meet the required fee rate are rejected.  Transactions that are
+// accepted are announced to peers and notification clients.
+//
+// Nothing is loaded and no error is returned when the data file does not
+// exist.
+//
+// This function is safe for concurrent access.
+func (s *server) loadMempool() (int, int, error) {
+	s.mempoolPersistMtx.Lock()
+	defer s.mempoolPersistMtx.Unlock()
+
+	path := mempoolDataPath()
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	txns, err := mempool.ReadTxns(bufio.NewReader(file))
+	file.Close()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var accepted, rejected int
+	for _, tx := range txns {
+		select {
+		case <-s.quit:
+			return accepted, rejected, nil
+		default:
+		}
+
+		acceptedTxs, err := s.blockManager.ProcessTransaction(tx, false,
+			false, true)
+		if err == errBlockManagerShutdown {
+			return accepted, rejected, nil
+		}
+		if err != nil {
+			srvrLog.Debugf("Rejected persisted mempool transaction %v: %v",
+				tx.Hash(), err)
+			rejected++
+			continue
+		}
+		accepted += len(acceptedTxs)
+		s.AnnounceNewTransactions(acceptedTxs)
+	}
+
+	srvrLog.Infof("Loaded %d of %d transactions from %s into the memory pool",
+		accepted, len(txns), path)
+	return accepted, rejected, nil
+}
+
+// loadMempoolOnStart loads the persisted memory pool contents when the server
+// starts and marks the memory pool as loaded so that it is saved again on
+// shutdown.  The mark is not set when the load fails or is interrupted in
+// order to avoid replacing the saved contents with a partial pool.
+//
+// This must be run as a goroutine.
+func (s *server) loadMempoolOnStart() {
+	defer s.wg.Done()
+
+	// Nothing to do when the server is shutting down before the load
+	// starts.
+	select {
+	case <-s.quit:
+		return
+	default:
+	}
+
+	if _, _, err := s.loadMempool(); err != nil {
+		srvrLog.Errorf("Unable to load the memory pool from %s: %v",
+			mempoolDataPath(), err)
+		return
+	}
+
+	select {
+	case <-s.quit:
+	default:
+		atomic.StoreInt32(&s.mempoolLoaded, 1)
+	}
+}
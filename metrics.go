@@ -0,0 +1,427 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/database"
+	"github.com/decred/dcrd/peer"
+)
+
+const (
+	// metricsReadTimeout is the amount of time allowed for reading a
+	// request to the metrics server.
+	metricsReadTimeout = time.Second * 10
+
+	// metricsWriteTimeout is the amount of time allowed for writing the
+	// response to a request to the metrics server.
+	metricsWriteTimeout = time.Second * 30
+
+	// metricsContentType is the content type of the Prometheus text
+	// exposition format served by the metrics server.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var (
+	// blockTimeBuckets are the upper bounds in seconds of the histogram
+	// buckets used for the block validation and connection times.
+	blockTimeBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1,
+		0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+	// rpcTimeBuckets are the upper bounds in seconds of the histogram
+	// buckets used for the RPC call latencies.
+	rpcTimeBuckets = []float64{0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5,
+		1, 5, 10}
+
+	// labelValueReplacer escapes the characters of label values that have
+	// a special meaning in the Prometheus text exposition format.
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`,
+		"\n", `\n`)
+)
+
+// metricsHistogram counts observed durations in cumulative buckets as
+// described by the Prometheus histogram metric type.
+//
+// It is not safe for concurrent access.
+type metricsHistogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// newMetricsHistogram returns a histogram with buckets for the passed upper
+// bounds in seconds, which must be sorted in increasing order.
+func newMetricsHistogram(bounds []float64) *metricsHistogram {
+	return &metricsHistogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+// observe adds the passed duration to the histogram.
+func (h *metricsHistogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	for i := sort.SearchFloat64s(h.bounds, seconds); i < len(h.counts); i++ {
+		h.counts[i]++
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// clone returns a copy of the histogram.
+func (h *metricsHistogram) clone() *metricsHistogram {
+	return &metricsHistogram{
+		bounds: h.bounds,
+		counts: append([]uint64(nil), h.counts...),
+		sum:    h.sum,
+		count:  h.count,
+	}
+}
+
+// write writes the samples of the histogram with the passed name and labels,
+// which may be empty, in the text exposition format.
+func (h *metricsHistogram) write(w io.Writer, name, labels string) {
+	sep := ""
+	if labels != "" {
+		sep = ","
+	}
+	for i, bound := range h.bounds {
+		fmt.Fprintf(w, "%s_bucket{%s%sle=\"%s\"} %d\n", name, labels,
+			sep, formatMetricValue(bound), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{%s%sle=\"+Inf\"} %d\n", name, labels, sep,
+		h.count)
+	writeMetric(w, name+"_sum", labels, h.sum)
+	writeMetric(w, name+"_count", labels, float64(h.count))
+}
+
+// formatMetricValue returns the passed value formatted for the text exposition
+// format.
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// metricLabel returns the passed label name and value formatted for the text
+// exposition format.
+func metricLabel(name, value string) string {
+	return fmt.Sprintf("%s=\"%s\"", name, labelValueReplacer.Replace(value))
+}
+
+// writeMetricHeader writes the help text and type of the metric with the
+// passed name in the text exposition format.
+func writeMetricHeader(w io.Writer, name, metricType, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name,
+		metricType)
+}
+
+// writeMetric writes a sample of the metric with the passed name and labels,
+// which may be empty, in the text exposition format.
+func writeMetric(w io.Writer, name, labels string, value float64) {
+	if labels != "" {
+		name += "{" + labels + "}"
+	}
+	fmt.Fprintf(w, "%s %s\n", name, formatMetricValue(value))
+}
+
+// metricsServer serves statistics about the node, the chain, the memory pool
+// and the connected peers over HTTP in the Prometheus text exposition format.
+type metricsServer struct {
+	// The following variables must only be used atomically.  Putting the
+	// uint64s first makes them 64-bit aligned for 32-bit systems.
+	reorgs   uint64
+	started  int32
+	shutdown int32
+
+	server     *server
+	listeners  []net.Listener
+	httpServer *http.Server
+	wg         sync.WaitGroup
+
+	// The following fields track the durations observed by the server and
+	// are protected by the mutex.
+	mtx           sync.Mutex
+	validateTimes *metricsHistogram
+	connectTimes  *metricsHistogram
+	rpcTimes      map[string]*metricsHistogram
+}
+
+// observeConnectTimings records the amount of time spent validating a block and
+// connecting it to the main chain.  Blocks whose validation was skipped are
+// only included in the connection times.
+//
+// This function is safe for concurrent access.
+func (m *metricsServer) observeConnectTimings(validate, connect time.Duration) {
+	m.mtx.Lock()
+	if validate != 0 {
+		m.validateTimes.observe(validate)
+	}
+	m.connectTimes.observe(connect)
+	m.mtx.Unlock()
+}
+
+// reorganized records a reorganization of the chain.
+//
+// This function is safe for concurrent access.
+func (m *metricsServer) reorganized() {
+	atomic.AddUint64(&m.reorgs, 1)
+}
+
+// observeRPC records the amount of time spent on an RPC call of the passed
+// method that started at the passed time.
+//
+// This function is safe for concurrent access.
+func (m *metricsServer) observeRPC(method string, start time.Time) {
+	elapsed := time.Since(start)
+
+	m.mtx.Lock()
+	h, ok := m.rpcTimes[method]
+	if !ok {
+		h = newMetricsHistogram(rpcTimeBuckets)
+		m.rpcTimes[method] = h
+	}
+	h.observe(elapsed)
+	m.mtx.Unlock()
+}
+
+// writeMetrics writes all metrics in the text exposition format.
+//
+// The durations observed by the server are copied up front so the mutex that
+// protects them is not held while the other metrics are gathered and written.
+func (m *metricsServer) writeMetrics(w io.Writer) {
+	s := m.server
+
+	m.mtx.Lock()
+	validateTimes := m.validateTimes.clone()
+	connectTimes := m.connectTimes.clone()
+	methods := make([]string, 0, len(m.rpcTimes))
+	rpcTimes := make(map[string]*metricsHistogram, len(m.rpcTimes))
+	for method, h := range m.rpcTimes {
+		methods = append(methods, method)
+		rpcTimes[method] = h.clone()
+	}
+	m.mtx.Unlock()
+
+	// Chain.
+	var bestHeight int64
+	if s.spvSyncer != nil {
+		_, height, err := s.spvSyncer.BestBlock()
+		if err == nil {
+			bestHeight = height
+		}
+	} else {
+		bestHeight = s.blockManager.chain.BestSnapshot().Height
+	}
+	writeMetricHeader(w, "dcrd_best_block_height", "gauge",
+		"Height of the tip of the best chain.")
+	writeMetric(w, "dcrd_best_block_height", "", float64(bestHeight))
+	writeMetricHeader(w, "dcrd_chain_reorganizations_total", "counter",
+		"Number of reorganizations of the best chain.")
+	writeMetric(w, "dcrd_chain_reorganizations_total", "",
+		float64(atomic.LoadUint64(&m.reorgs)))
+
+	writeMetricHeader(w, "dcrd_block_validation_seconds", "histogram",
+		"Time spent validating blocks connected to the best chain.")
+	validateTimes.write(w, "dcrd_block_validation_seconds", "")
+	writeMetricHeader(w, "dcrd_block_connect_seconds", "histogram",
+		"Time spent connecting blocks to the best chain.")
+	connectTimes.write(w, "dcrd_block_connect_seconds", "")
+
+	// Memory pool.
+	sizeInfo := s.txMemPool.SizeInfo()
+	writeMetricHeader(w, "dcrd_mempool_transactions", "gauge",
+		"Number of transactions in the memory pool.")
+	writeMetric(w, "dcrd_mempool_transactions", "", float64(sizeInfo.Count))
+	writeMetricHeader(w, "dcrd_mempool_bytes", "gauge",
+		"Total serialized size of the transactions in the memory pool.")
+	writeMetric(w, "dcrd_mempool_bytes", "", float64(sizeInfo.Bytes))
+
+	// Signature cache.
+	hits, misses := s.sigCache.Stats()
+	writeMetricHeader(w, "dcrd_sigcache_hits_total", "counter",
+		"Number of signature cache lookups that found a match.")
+	writeMetric(w, "dcrd_sigcache_hits_total", "", float64(hits))
+	writeMetricHeader(w, "dcrd_sigcache_misses_total", "counter",
+		"Number of signature cache lookups that did not find a match.")
+	writeMetric(w, "dcrd_sigcache_misses_total", "", float64(misses))
+	var hitRatio float64
+	if hits+misses != 0 {
+		hitRatio = float64(hits) / float64(hits+misses)
+	}
+	writeMetricHeader(w, "dcrd_sigcache_hit_ratio", "gauge",
+		"Fraction of signature cache lookups that found a match.")
+	writeMetric(w, "dcrd_sigcache_hit_ratio", "", hitRatio)
+
+	// Database.
+	if counter, ok := s.db.(database.CacheFlushCounter); ok {
+		writeMetricHeader(w, "dcrd_database_cache_flushes_total",
+			"counter", "Number of database cache flushes.")
+		writeMetric(w, "dcrd_database_cache_flushes_total", "",
+			float64(counter.CacheFlushes()))
+	}
+
+	// Peers.
+	bytesRecv, bytesSent := s.NetTotals()
+	writeMetricHeader(w, "dcrd_net_bytes_received_total", "counter",
+		"Number of bytes received from all peers.")
+	writeMetric(w, "dcrd_net_bytes_received_total", "", float64(bytesRecv))
+	writeMetricHeader(w, "dcrd_net_bytes_sent_total", "counter",
+		"Number of bytes sent to all peers.")
+	writeMetric(w, "dcrd_net_bytes_sent_total", "", float64(bytesSent))
+
+	peers := s.Peers()
+	writeMetricHeader(w, "dcrd_peers", "gauge",
+		"Number of connected peers.")
+	writeMetric(w, "dcrd_peers", "", float64(len(peers)))
+	labels := make([]string, len(peers))
+	stats := make([]*peer.StatsSnap, len(peers))
+	for i, sp := range peers {
+		stats[i] = sp.StatsSnapshot()
+		labels[i] = metricLabel("id", strconv.Itoa(int(stats[i].ID))) +
+			"," + metricLabel("addr", stats[i].Addr)
+	}
+	writeMetricHeader(w, "dcrd_peer_bytes_received_total", "counter",
+		"Number of bytes received from a peer.")
+	for i := range peers {
+		writeMetric(w, "dcrd_peer_bytes_received_total", labels[i],
+			float64(stats[i].BytesRecv))
+	}
+	writeMetricHeader(w, "dcrd_peer_bytes_sent_total", "counter",
+		"Number of bytes sent to a peer.")
+	for i := range peers {
+		writeMetric(w, "dcrd_peer_bytes_sent_total", labels[i],
+			float64(stats[i].BytesSent))
+	}
+	writeMetricHeader(w, "dcrd_peer_ping_seconds", "gauge",
+		"Round trip time of the last ping to a peer.")
+	for i := range peers {
+		ping := time.Duration(stats[i].LastPingMicros) * time.Microsecond
+		writeMetric(w, "dcrd_peer_ping_seconds", labels[i], ping.Seconds())
+	}
+
+	// RPC.
+	sort.Strings(methods)
+	writeMetricHeader(w, "dcrd_rpc_request_duration_seconds", "histogram",
+		"Time spent handling RPC calls by method.")
+	for _, method := range methods {
+		rpcTimes[method].write(w, "dcrd_rpc_request_duration_seconds",
+			metricLabel("method", method))
+	}
+}
+
+// handleMetrics serves all metrics in the text exposition format.
+func (m *metricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method Not Allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The metrics are rendered in full before any of them are sent so slow
+	// clients do not hold up gathering them.
+	var buf bytes.Buffer
+	m.writeMetrics(&buf)
+	w.Header().Set("Content-Type", metricsContentType)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		srvrLog.Debugf("Failed to write metrics to %s: %v",
+			r.RemoteAddr, err)
+	}
+}
+
+// Start begins serving metrics on the configured listeners.
+func (m *metricsServer) Start() {
+	if atomic.AddInt32(&m.started, 1) != 1 {
+		return
+	}
+
+	srvrLog.Trace("Starting metrics server")
+	for _, listener := range m.listeners {
+		m.wg.Add(1)
+		go func(listener net.Listener) {
+			srvrLog.Infof("Metrics server listening on %s",
+				listener.Addr())
+			m.httpServer.Serve(listener)
+			srvrLog.Tracef("Metrics listener done for %s",
+				listener.Addr())
+			m.wg.Done()
+		}(listener)
+	}
+}
+
+// Stop shuts down the metrics server by closing its listeners and connections.
+func (m *metricsServer) Stop() {
+	if atomic.AddInt32(&m.shutdown, 1) != 1 {
+		srvrLog.Infof("Metrics server is already in the process of " +
+			"shutting down")
+		return
+	}
+
+	srvrLog.Warnf("Metrics server shutting down")
+	m.httpServer.Close()
+	for _, listener := range m.listeners {
+		listener.Close()
+	}
+	m.wg.Wait()
+	srvrLog.Infof("Metrics server shutdown complete")
+}
+
+// newMetricsServer returns a new metrics server for the passed server listening
+// on the passed addresses.  Use Start to begin serving metrics.
+func newMetricsServer(listenAddrs []string, s *server) (*metricsServer, error) {
+	ipv4ListenAddrs, ipv6ListenAddrs, _, err := parseListeners(listenAddrs)
+	if err != nil {
+		return nil, err
+	}
+	listeners := make([]net.Listener, 0,
+		len(ipv6ListenAddrs)+len(ipv4ListenAddrs))
+	for _, addr := range ipv4ListenAddrs {
+		listener, err := net.Listen("tcp4", addr)
+		if err != nil {
+			srvrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	for _, addr := range ipv6ListenAddrs {
+		listener, err := net.Listen("tcp6", addr)
+		if err != nil {
+			srvrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("metrics: no valid listen address")
+	}
+
+	m := &metricsServer{
+		server:        s,
+		listeners:     listeners,
+		validateTimes: newMetricsHistogram(blockTimeBuckets),
+		connectTimes:  newMetricsHistogram(blockTimeBuckets),
+		rpcTimes:      make(map[string]*metricsHistogram),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	m.httpServer = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  metricsReadTimeout,
+		WriteTimeout: metricsWriteTimeout,
+	}
+	return m, nil
+}
@@ -2145,6 +2145,27 @@ func (g *BgBlkTmplGenerator) RequestTemplateUpdate() chan *BlockTemplate {
 	return updateChan
 }
 
+// UnsubscribeTemplateUpdate removes the subscription for a block template
+// update made with RequestTemplateUpdate when it has not been updated yet.
+func (g *BgBlkTmplGenerator) UnsubscribeTemplateUpdate(updateChan chan *BlockTemplate) {
+	g.subscriptionMtx.Lock()
+	delete(g.subscribers, updateChan)
+	g.subscriptionMtx.Unlock()
+}
+
+// CurrentTemplate returns the most recently generated block template.  It
+// returns nil when no template has been generated since the last block was
+// connected or disconnected.  The returned template must be treated as
+// immutable since it is shared with other callers.
+//
+// This function is safe for concurrent access.
+func (g *BgBlkTmplGenerator) CurrentTemplate() *BlockTemplate {
+	g.templateMtx.Lock()
+	template := g.currentTemplate
+	g.templateMtx.Unlock()
+	return template
+}
+
 // regenTemplate regenerates the block template.  This must be run as a
 // goroutine.
 func (g *BgBlkTmplGenerator) regenTemplate() {
@@ -2187,13 +2208,15 @@ func (g *BgBlkTmplGenerator) regenTemplate() {
 	g.templatePool[templateKey(&msgBlock.Header)] = &msgBlock
 	g.templatePoolMtx.Unlock()
 
-	t := *template
-	g.notifyChan <- &t
-
+	// Update the current template before notifying subscribers so they
+	// observe the new template when querying it in response.
 	g.templateMtx.Lock()
 	g.currentTemplate = template
 	g.templateMtx.Unlock()
 
+	t := *template
+	g.notifyChan <- &t
+
 	// Update the last template regen time.
 	atomic.StoreInt64(&g.lastRegen, time.Now().Unix())
 }
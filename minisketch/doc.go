@@ -0,0 +1,35 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package minisketch provides an API for building, merging and decoding sketches
+of sets of 32-bit elements.
+
+Set Sketches
+
+A sketch of a set with a capacity of c is a compact summary of the set of size
+4*c bytes regardless of the number of elements in the set.  Two sketches of the
+same capacity can be merged into a sketch of the symmetric difference of both
+sets, from which the elements of the difference can be recovered as long as
+there are no more than c of them.  This allows two parties that hold similar
+sets to learn the differences by exchanging a single sketch whose size only
+depends on the expected number of differences.
+
+Decoding a sketch that holds more elements than its capacity usually fails, but
+it may also yield a wrong set of elements.  ComputeCapacity returns a capacity
+with enough extra room to make that unlikely.
+
+The sketches are PinSketches, which are the odd power sums of the elements in
+the binary field GF(2^32).  Decoding uses the Berlekamp-Massey algorithm to find
+the polynomial with the elements as its roots and the Berlekamp trace algorithm
+to find the roots.  The same approach is used by the minisketch library.
+
+Sketch use in Decred
+
+Sketches are used for the reconciliation of transaction announcements between
+peers, in which the peers exchange sketches of the short ids of the
+transactions they would otherwise announce to each other with inventory
+vectors.
+*/
+package minisketch
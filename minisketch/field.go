@@ -0,0 +1,57 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package minisketch
+
+// The elements of the sketches are elements of the binary field GF(2^32)
+// represented as polynomials over GF(2) modulo the irreducible polynomial
+// x^32 + x^7 + x^3 + x^2 + 1.  Addition of field elements is XOR.
+
+// fieldModulus houses the low terms of the field modulus that remain after
+// reducing the x^32 term.
+const fieldModulus = 0x8d
+
+// reduce reduces the passed product of two field elements modulo the field
+// modulus.
+func reduce(r uint64) uint32 {
+	for i := 0; i < 2; i++ {
+		h := r >> 32
+		r = (r & 0xffffffff) ^ h ^ h<<2 ^ h<<3 ^ h<<7
+	}
+	return uint32(r)
+}
+
+// mul returns the product of the passed field elements.
+func mul(a, b uint32) uint32 {
+	// Carry-less multiplication using a table of the multiples of a by
+	// all 4-bit polynomials.
+	var tbl [16]uint64
+	tbl[1] = uint64(a)
+	for i := 2; i < 16; i += 2 {
+		tbl[i] = tbl[i/2] << 1
+		tbl[i+1] = tbl[i] ^ uint64(a)
+	}
+
+	var r uint64
+	for shift := uint(0); shift < 32; shift += 4 {
+		r ^= tbl[(b>>shift)&0xf] << shift
+	}
+	return reduce(r)
+}
+
+// sqr returns the square of the passed field element.
+func sqr(a uint32) uint32 {
+	return mul(a, a)
+}
+
+// inv returns the multiplicative inverse of the passed nonzero field element,
+// which is a^(2^32-2).
+func inv(a uint32) uint32 {
+	r := uint32(1)
+	for i := 0; i < 31; i++ {
+		a = sqr(a)
+		r = mul(r, a)
+	}
+	return r
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package minisketch
+
+// Polynomials over the field are represented by their coefficients ordered
+// from the constant term to the leading term.  Leading zero coefficients are
+// trimmed so the degree of a nonzero polynomial is its length minus one.
+
+// trim removes the leading zero coefficients of the passed polynomial.
+func trim(p []uint32) []uint32 {
+	for len(p) > 0 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+// polyMod returns the remainder of the division of a by the nonzero
+// polynomial m.  The passed polynomial a is modified.
+func polyMod(a, m []uint32) []uint32 {
+	a = trim(a)
+	dm := len(m) - 1
+	leadInv := inv(m[dm])
+	for len(a) > dm {
+		da := len(a) - 1
+		coef := mul(a[da], leadInv)
+		for i := 0; i <= dm; i++ {
+			a[da-dm+i] ^= mul(coef, m[i])
+		}
+		a = trim(a[:da])
+	}
+	return a
+}
+
+// polyDiv returns the quotient of the division of a by the nonzero polynomial
+// m.  The passed polynomial a is modified.
+func polyDiv(a, m []uint32) []uint32 {
+	a = trim(a)
+	dm := len(m) - 1
+	if len(a) <= dm {
+		return nil
+	}
+	leadInv := inv(m[dm])
+	q := make([]uint32, len(a)-dm)
+	for da := len(a) - 1; da >= dm; da-- {
+		coef := mul(a[da], leadInv)
+		q[da-dm] = coef
+		for i := 0; i <= dm; i++ {
+			a[da-dm+i] ^= mul(coef, m[i])
+		}
+	}
+	return trim(q)
+}
+
+// polySqrMod returns the square of a modulo m.
+func polySqrMod(a, m []uint32) []uint32 {
+	// Squaring is linear in fields of characteristic two, so the square
+	// only consists of the squares of the coefficients at even positions.
+	if len(a) == 0 {
+		return nil
+	}
+	r := make([]uint32, 2*len(a)-1)
+	for i, c := range a {
+		r[2*i] = sqr(c)
+	}
+	return polyMod(r, m)
+}
+
+// polyMonic scales the passed nonzero polynomial in place so its leading
+// coefficient is one.
+func polyMonic(p []uint32) []uint32 {
+	leadInv := inv(p[len(p)-1])
+	for i := range p {
+		p[i] = mul(p[i], leadInv)
+	}
+	return p
+}
+
+// polyGCD returns the monic greatest common divisor of the passed polynomials.
+// The passed polynomials are modified.
+func polyGCD(a, b []uint32) []uint32 {
+	a, b = trim(a), trim(b)
+	for len(b) > 0 {
+		a, b = b, polyMod(a, b)
+	}
+	if len(a) == 0 {
+		return nil
+	}
+	return polyMonic(a)
+}
+
+// polyTrace returns Tr(beta*x) modulo m, which is the sum of (beta*x)^(2^i)
+// for i from 0 to 31.  Every root r of m is a root of either the result or
+// the result plus one depending on the trace of beta*r.
+func polyTrace(beta uint32, m []uint32) []uint32 {
+	t := polyMod([]uint32{0, beta}, m)
+	sum := make([]uint32, len(m)-1)
+	copy(sum, t)
+	for i := 1; i < 32; i++ {
+		t = polySqrMod(t, m)
+		for j, c := range t {
+			sum[j] ^= c
+		}
+	}
+	return trim(sum)
+}
+
+// splitsDistinct returns whether or not the passed monic polynomial is the
+// product of distinct linear factors, which is the case when it divides
+// x^(2^32) - x.
+func splitsDistinct(p []uint32) bool {
+	x := polyMod([]uint32{0, 1}, p)
+	t := x
+	for i := 0; i < 32; i++ {
+		t = polySqrMod(t, p)
+	}
+	if len(t) != len(x) {
+		return false
+	}
+	for i := range t {
+		if t[i] != x[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// findRoots appends the roots of the passed monic polynomial, which must be
+// the product of distinct linear factors, to roots.  The polynomial is split
+// recursively using the Berlekamp trace algorithm.
+func findRoots(p []uint32, roots []uint32) []uint32 {
+	switch len(p) {
+	case 0, 1:
+		return roots
+	case 2:
+		// The root of x + c is c in fields of characteristic two.
+		return append(roots, p[0])
+	}
+
+	// The trace of beta*r is either zero or one for every root r and, since
+	// the trace is a nondegenerate linear form, any two distinct roots
+	// differ in the trace of beta*r for at least one element beta of a
+	// basis of the field.
+	for i := uint(0); i < 32; i++ {
+		t := polyTrace(1<<i, p)
+		g := polyGCD(append([]uint32(nil), p...), t)
+		if len(g) < 2 || len(g) == len(p) {
+			continue
+		}
+		q := polyDiv(append([]uint32(nil), p...), g)
+		roots = findRoots(g, roots)
+		return findRoots(q, roots)
+	}
+	return roots
+}
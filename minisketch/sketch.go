@@ -0,0 +1,241 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package minisketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// ElementSize is the size in bytes of the elements of a sketch and the size
+// every unit of the capacity of a sketch adds to its serialization.
+const ElementSize = 4
+
+var (
+	// ErrZeroElement signifies that zero was added to a sketch, which is
+	// not a valid element.
+	ErrZeroElement = errors.New("zero is not a valid sketch element")
+
+	// ErrMisserialized signifies a sketch was misserialized.
+	ErrMisserialized = errors.New("misserialized sketch")
+
+	// ErrCapacityMismatch signifies that sketches of different capacities
+	// were merged.
+	ErrCapacityMismatch = errors.New("sketch capacities differ")
+
+	// ErrDecodeFailed signifies that the elements of a sketch could not be
+	// recovered because it holds more elements than its capacity.
+	ErrDecodeFailed = errors.New("sketch holds more elements than its " +
+		"capacity")
+)
+
+// Sketch is a PinSketch of a set of nonzero 32-bit elements.  A sketch with a
+// capacity of c consists of the odd power sums s1, s3, ..., s(2c-1) of the
+// elements in GF(2^32) and allows up to c elements to be recovered.
+type Sketch struct {
+	syndromes []uint32
+}
+
+// New returns a new empty sketch with the passed capacity.
+func New(capacity int) *Sketch {
+	return &Sketch{syndromes: make([]uint32, capacity)}
+}
+
+// Capacity returns the maximum number of elements that can be recovered from
+// the sketch.
+func (s *Sketch) Capacity() int {
+	return len(s.syndromes)
+}
+
+// Add toggles the presence of the passed element in the sketch.  Adding an
+// element that is already in the sketch removes it.
+func (s *Sketch) Add(element uint32) error {
+	if element == 0 {
+		return ErrZeroElement
+	}
+
+	sq := sqr(element)
+	p := element
+	for i := range s.syndromes {
+		s.syndromes[i] ^= p
+		p = mul(p, sq)
+	}
+	return nil
+}
+
+// baseFPBits returns the approximate number of bits of protection against
+// false positive decodes provided by a sketch with the passed capacity when it
+// holds more elements than its capacity.  The syndromes of such a sketch are
+// effectively random, and a random polynomial of degree c only has c distinct
+// roots with a probability of about 1/c!, so this is log2(c!) rounded down.
+func baseFPBits(capacity int) int {
+	lgamma, _ := math.Lgamma(float64(capacity) + 1)
+	return int(lgamma / math.Ln2)
+}
+
+// ComputeCapacity returns the capacity of a sketch that is able to recover up
+// to maxElements elements and is only decoded to a wrong set of elements with a
+// probability of at most 2^-fpBits when it holds more elements than that.
+//
+// The capacity is increased beyond maxElements when the sketch would not
+// provide the requested protection on its own, which is the case for small
+// numbers of elements.  Every unit of capacity that is added provides another
+// 32 bits of protection when the sketch is decoded with DecodeMax and
+// maxElements since the additional syndrome must match.  This is the same way
+// the minisketch library computes capacities.
+func ComputeCapacity(maxElements, fpBits int) int {
+	base := baseFPBits(maxElements)
+	if base >= fpBits {
+		return maxElements
+	}
+	return maxElements + (fpBits-base+ElementSize*8-1)/(ElementSize*8)
+}
+
+// MaxElements returns the maximum number of elements that can be decoded from a
+// sketch with the passed capacity while still being protected against false
+// positive decodes by fpBits bits.  It is the inverse of ComputeCapacity.
+func MaxElements(capacity, fpBits int) int {
+	for maxElements := capacity; maxElements > 0; maxElements-- {
+		if ComputeCapacity(maxElements, fpBits) <= capacity {
+			return maxElements
+		}
+	}
+	return 0
+}
+
+// Merge adds the elements of the passed sketch, which must have the same
+// capacity, to the sketch.  Since elements present in both sketches cancel
+// out, the result is a sketch of the symmetric difference of both sets.
+func (s *Sketch) Merge(other *Sketch) error {
+	if len(other.syndromes) != len(s.syndromes) {
+		return ErrCapacityMismatch
+	}
+	for i, syndrome := range other.syndromes {
+		s.syndromes[i] ^= syndrome
+	}
+	return nil
+}
+
+// Serialize returns the serialization of the sketch, which is ElementSize
+// bytes per unit of capacity.
+func (s *Sketch) Serialize() []byte {
+	b := make([]byte, len(s.syndromes)*ElementSize)
+	for i, syndrome := range s.syndromes {
+		binary.LittleEndian.PutUint32(b[i*ElementSize:], syndrome)
+	}
+	return b
+}
+
+// Deserialize returns the sketch with the passed serialization.
+func Deserialize(b []byte) (*Sketch, error) {
+	if len(b)%ElementSize != 0 {
+		return nil, ErrMisserialized
+	}
+	s := New(len(b) / ElementSize)
+	for i := range s.syndromes {
+		s.syndromes[i] = binary.LittleEndian.Uint32(b[i*ElementSize:])
+	}
+	return s, nil
+}
+
+// berlekampMassey returns the shortest connection polynomial generating the
+// passed sequence.
+func berlekampMassey(seq []uint32) []uint32 {
+	c := []uint32{1}
+	b := []uint32{1}
+	var l int
+	m := 1
+	lastDiscrepancy := uint32(1)
+	for n := range seq {
+		d := seq[n]
+		for i := 1; i <= l && i < len(c); i++ {
+			d ^= mul(c[i], seq[n-i])
+		}
+		if d == 0 {
+			m++
+			continue
+		}
+
+		coef := mul(d, inv(lastDiscrepancy))
+		prev := append([]uint32(nil), c...)
+		if len(b)+m > len(c) {
+			c = append(c, make([]uint32, len(b)+m-len(c))...)
+		}
+		for i, bc := range b {
+			c[i+m] ^= mul(coef, bc)
+		}
+		if 2*l <= n {
+			l = n + 1 - l
+			b = prev
+			lastDiscrepancy = d
+			m = 1
+			continue
+		}
+		m++
+	}
+	c = trim(c)
+	if len(c) > l+1 {
+		return nil
+	}
+	return append(c, make([]uint32, l+1-len(c))...)
+}
+
+// Decode returns the elements in the sketch.  ErrDecodeFailed is returned when
+// the sketch holds more elements than its capacity.  It is not guaranteed that
+// such a sketch is detected, but the chance that a wrong set of elements is
+// returned is negligible unless the capacity is close to the number of
+// elements in the sketch.  Use DecodeMax with a capacity that includes a
+// margin to bound that chance.
+func (s *Sketch) Decode() ([]uint32, error) {
+	return s.DecodeMax(len(s.syndromes))
+}
+
+// DecodeMax returns the elements in the sketch when it holds no more than
+// maxElements of them.  ErrDecodeFailed is returned otherwise.  The syndromes
+// beyond the ones required to decode maxElements elements serve as a check,
+// so a sketch with the capacity returned by ComputeCapacity for maxElements
+// and fpBits is only decoded to a wrong set of elements with a probability of
+// at most 2^-fpBits.
+func (s *Sketch) DecodeMax(maxElements int) ([]uint32, error) {
+	// Derive the even power sums from the odd ones since s(2i) = s(i)^2.
+	capacity := len(s.syndromes)
+	if maxElements > capacity {
+		maxElements = capacity
+	}
+	sums := make([]uint32, 2*capacity)
+	for i := 0; i < capacity; i++ {
+		sums[2*i] = s.syndromes[i]
+	}
+	for i := 0; i < capacity; i++ {
+		sums[2*i+1] = sqr(sums[i])
+	}
+
+	// The connection polynomial is the error locator polynomial with the
+	// inverses of the elements as its roots.
+	locator := berlekampMassey(sums)
+	l := len(locator) - 1
+	if locator == nil || l > maxElements || locator[l] == 0 {
+		return nil, ErrDecodeFailed
+	}
+	if l == 0 {
+		return nil, nil
+	}
+
+	// Reversing the coefficients yields a monic polynomial with the
+	// elements themselves as its roots, which must all be distinct.
+	poly := make([]uint32, l+1)
+	for i, c := range locator {
+		poly[l-i] = c
+	}
+	if !splitsDistinct(poly) {
+		return nil, ErrDecodeFailed
+	}
+	elements := findRoots(poly, make([]uint32, 0, l))
+	if len(elements) != l {
+		return nil, ErrDecodeFailed
+	}
+	return elements, nil
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package minisketch
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// randElements returns the passed number of distinct random nonzero elements
+// sorted in ascending order.
+func randElements(rng *rand.Rand, n int) []uint32 {
+	seen := make(map[uint32]struct{}, n)
+	elements := make([]uint32, 0, n)
+	for len(elements) < n {
+		e := rng.Uint32()
+		if _, ok := seen[e]; ok || e == 0 {
+			continue
+		}
+		seen[e] = struct{}{}
+		elements = append(elements, e)
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i] < elements[j]
+	})
+	return elements
+}
+
+// sketchOf returns a sketch of the passed elements with the given capacity.
+func sketchOf(t *testing.T, capacity int, elements []uint32) *Sketch {
+	t.Helper()
+	s := New(capacity)
+	for _, e := range elements {
+		if err := s.Add(e); err != nil {
+			t.Fatalf("Add(%d): unexpected error %v", e, err)
+		}
+	}
+	return s
+}
+
+// decodeSorted decodes the passed sketch and returns the elements sorted in
+// ascending order.
+func decodeSorted(s *Sketch) ([]uint32, error) {
+	elements, err := s.Decode()
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i] < elements[j]
+	})
+	return elements, err
+}
+
+// TestRoundTrip ensures the elements of sketches that hold up to their
+// capacity are recovered, including after a serialization round trip.
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, capacity := range []int{1, 2, 3, 5, 8, 16, 50} {
+		for n := 0; n <= capacity; n++ {
+			want := randElements(rng, n)
+			s, err := Deserialize(sketchOf(t, capacity, want).Serialize())
+			if err != nil {
+				t.Fatalf("capacity %d, %d elements: unexpected "+
+					"deserialize error %v", capacity, n, err)
+			}
+			if s.Capacity() != capacity {
+				t.Fatalf("capacity %d, %d elements: deserialized "+
+					"capacity %d", capacity, n, s.Capacity())
+			}
+			got, err := decodeSorted(s)
+			if err != nil {
+				t.Fatalf("capacity %d, %d elements: unexpected "+
+					"decode error %v", capacity, n, err)
+			}
+			if len(got) != 0 || len(want) != 0 {
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("capacity %d, %d elements: got %v, "+
+						"want %v", capacity, n, got, want)
+				}
+			}
+		}
+	}
+
+	// Adding an element twice removes it again.
+	s := sketchOf(t, 2, []uint32{5, 7, 5})
+	got, err := decodeSorted(s)
+	if err != nil || !reflect.DeepEqual(got, []uint32{7}) {
+		t.Fatalf("toggled element: got %v (err %v), want [7]", got, err)
+	}
+
+	// Zero is not a valid element.
+	if err := New(1).Add(0); err != ErrZeroElement {
+		t.Fatalf("Add(0): got error %v, want %v", err, ErrZeroElement)
+	}
+
+	// Serializations must be a multiple of the element size.
+	if _, err := Deserialize(make([]byte, ElementSize+1)); err != ErrMisserialized {
+		t.Fatalf("Deserialize: got error %v, want %v", err,
+			ErrMisserialized)
+	}
+}
+
+// TestDecodePastCapacity ensures sketches that hold more elements than the
+// maximum number of elements they are decoded with are not decoded when their
+// capacity includes the margin returned by ComputeCapacity.
+func TestDecodePastCapacity(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for maxElements := 1; maxElements <= 20; maxElements++ {
+		// The capacity for a number of elements may also provide enough
+		// protection for more elements.
+		capacity := ComputeCapacity(maxElements, 16)
+		decodeMax := MaxElements(capacity, 16)
+		if decodeMax < maxElements {
+			t.Fatalf("MaxElements(%d, 16): got %d, want at least %d",
+				capacity, decodeMax, maxElements)
+		}
+		for extra := 1; extra <= 10; extra++ {
+			for i := 0; i < 20; i++ {
+				elements := randElements(rng, decodeMax+extra)
+				s := sketchOf(t, capacity, elements)
+				got, err := s.DecodeMax(decodeMax)
+				if err != ErrDecodeFailed {
+					t.Fatalf("capacity %d, %d elements: got %v "+
+						"(err %v), want %v", capacity,
+						len(elements), got, err,
+						ErrDecodeFailed)
+				}
+			}
+		}
+	}
+}
+
+// TestMerge ensures merging sketches results in a sketch of the symmetric
+// difference of both sets.
+func TestMerge(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	const capacity = 10
+	for i := 0; i < 50; i++ {
+		shared := randElements(rng, 100)
+		diff := randElements(rng, rng.Intn(capacity+1))
+		split := rng.Intn(len(diff) + 1)
+		a := sketchOf(t, capacity, append(append([]uint32(nil),
+			shared...), diff[:split]...))
+		b := sketchOf(t, capacity, append(append([]uint32(nil),
+			shared...), diff[split:]...))
+		if err := a.Merge(b); err != nil {
+			t.Fatalf("Merge: unexpected error %v", err)
+		}
+		got, err := decodeSorted(a)
+		if err != nil {
+			t.Fatalf("%d differences: unexpected decode error %v",
+				len(diff), err)
+		}
+		if len(got) != 0 || len(diff) != 0 {
+			if !reflect.DeepEqual(got, diff) {
+				t.Fatalf("%d differences: got %v, want %v",
+					len(diff), got, diff)
+			}
+		}
+	}
+
+	if err := New(1).Merge(New(2)); err != ErrCapacityMismatch {
+		t.Fatalf("Merge: got error %v, want %v", err,
+			ErrCapacityMismatch)
+	}
+}
+
+// TestComputeCapacity ensures the capacity is only increased beyond the number
+// of elements when the sketch does not provide the requested protection
+// against false positives on its own.
+func TestComputeCapacity(t *testing.T) {
+	tests := []struct {
+		maxElements int
+		fpBits      int
+		want        int
+	}{
+		{0, 0, 0},
+		{0, 16, 1},
+		{1, 16, 2},
+		{8, 16, 9},
+		{9, 16, 9},
+		{100, 16, 100},
+		{1, 32, 2},
+		{1, 33, 3},
+		{20, 61, 20},
+		{20, 62, 21},
+		{20, 100, 22},
+	}
+	for _, test := range tests {
+		got := ComputeCapacity(test.maxElements, test.fpBits)
+		if got != test.want {
+			t.Errorf("ComputeCapacity(%d, %d): got %d, want %d",
+				test.maxElements, test.fpBits, got, test.want)
+		}
+	}
+}
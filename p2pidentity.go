@@ -0,0 +1,49 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1"
+)
+
+// p2pIdentityFilename is the name of the file in the data directory the
+// identity key used to authenticate with peers over the encrypted transport is
+// stored in.
+const p2pIdentityFilename = "p2pidentity.key"
+
+// loadP2PIdentity returns the identity key stored in the file at the passed
+// path.  A new key is generated and written to the file when it does not exist
+// yet so that the node keeps the same identity across restarts.
+func loadP2PIdentity(path string) (*secp256k1.PrivateKey, error) {
+	keyHex, err := ioutil.ReadFile(path)
+	if err == nil {
+		keyBytes, err := hex.DecodeString(strings.TrimSpace(string(keyHex)))
+		if err != nil || len(keyBytes) != secp256k1.PrivKeyBytesLen {
+			return nil, fmt.Errorf("malformed identity key file %s", path)
+		}
+		key, _ := secp256k1.PrivKeyFromBytes(keyBytes)
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	keyHex = []byte(hex.EncodeToString(key.Serialize()) + "\n")
+	if err := ioutil.WriteFile(path, keyHex, 0600); err != nil {
+		return nil, err
+	}
+	srvrLog.Infof("Generated new peer identity key file %s", path)
+	return key, nil
+}
@@ -12,7 +12,8 @@ var activeNetParams = &mainNetParams
 // network and test networks.
 type params struct {
 	*chaincfg.Params
-	rpcPort string
+	rpcPort  string
+	grpcPort string
 }
 
 // mainNetParams contains parameters specific to the main network
@@ -22,6 +23,31 @@ type params struct {
 // it does not handle on to dcrd.  This approach allows the wallet process
 // to emulate the full reference implementation RPC API.
 var mainNetParams = params{
-	Params:  &chaincfg.MainNetParams,
-	rpcPort: "9109",
-}
\ No newline at end of file
+	Params:   &chaincfg.MainNetParams,
+	rpcPort:  "9109",
+	grpcPort: "9113",
+}
+
+// testNet3Params contains parameters specific to the test network (version 3)
+// (wire.TestNet3).
+var testNet3Params = params{
+	Params:   &chaincfg.TestNet3Params,
+	rpcPort:  "19109",
+	grpcPort: "19113",
+}
+
+// simNetParams contains parameters specific to the simulation test network
+// (wire.SimNet).
+var simNetParams = params{
+	Params:   &chaincfg.SimNetParams,
+	rpcPort:  "19556",
+	grpcPort: "19560",
+}
+
+// regNetParams contains parameters specific to the regression test
+// network (wire.RegNet).
+var regNetParams = params{
+	Params:   &chaincfg.RegNetParams,
+	rpcPort:  "18656",
+	grpcPort: "18660",
+}
@@ -37,6 +37,11 @@ A quick overview of the major features peer provides are as follows:
    and avoidance
  - Automatic periodic keep-alive pinging and pong responses
  - Random nonce generation and self connection detection
+ - Optional encrypted and authenticated transport negotiated with peers that
+   also support it along with pinning of remote peer identities
+ - Optional reconciliation of transaction announcements with sketches in place
+   of flooding them with inventory messages
+ - Optional token bucket rate limiting of writes per peer and across peers
  - Snapshottable peer statistics such as the total number of bytes read and
    written, the remote address, user agent, and negotiated protocol version
  - Helper functions pushing addresses, getblocks, getheaders, and reject
@@ -125,6 +130,32 @@ appropriate reject message based on the provided parameters as well as
 optionally provides a flag to cause it to block until the message is actually
 sent.
 
+Encrypted Transport
+
+When the Services field of the Config struct includes wire.SFNodeEncryption and
+the remote peer advertises it as well, the peers negotiate an encrypted
+transport immediately after exchanging version messages.  The key exchange uses
+ephemeral secp256k1 keys and all further messages are encrypted and
+authenticated with AES-256-GCM.  Communication with peers that do not advertise
+the service flag falls back to the plaintext protocol.
+
+Each side also authenticates with a static identity key, which is specified via
+the IdentityKey field of the Config struct.  The PinnedIdentity callback can be
+used to require specific remote peers to authenticate with a known identity key,
+in which case they are disconnected when they fail to do so.  The Encrypted and
+RemoteIdentity functions report the outcome of the negotiation.
+
+Transaction Reconciliation
+
+When the TxReconciliation field of the Config struct is set and the remote peer
+offers it as well, transactions queued with QueueInventory are collected in a
+reconciliation set instead of being announced individually.  The outbound side
+of the connection periodically requests a sketch of the set of the inbound side,
+decodes the difference of both sets from it and both sides then only announce
+the transactions the other one is missing.  SetTxFlooding can be used to keep
+announcing transactions individually to selected peers and TxReconStats reports
+statistics about the reconciliation rounds.
+
 Peer Statistics
 
 A snapshot of the current peer statistics can be obtained with the StatsSnapshot
@@ -168,6 +168,10 @@ func messageSummary(msg wire.Message) string {
 			msg.TxHash(), len(msg.TxIn), len(msg.TxOut),
 			formatLockTime(msg.LockTime))
 
+	case *wire.MsgStemTx:
+		return fmt.Sprintf("hash %s, %d inputs, %d outputs",
+			msg.Tx.TxHash(), len(msg.Tx.TxIn), len(msg.Tx.TxOut))
+
 	case *wire.MsgBlock:
 		header := &msg.Header
 		return fmt.Sprintf("hash %s, ver %d, %d tx, %s", msg.BlockHash(),
@@ -191,6 +195,36 @@ func messageSummary(msg wire.Message) string {
 	case *wire.MsgHeaders:
 		return fmt.Sprintf("num %d", len(msg.Headers))
 
+	case *wire.MsgSendCmpct:
+		return fmt.Sprintf("announce %t, ver %d", msg.Announce,
+			msg.Version)
+
+	case *wire.MsgCmpctBlock:
+		return fmt.Sprintf("hash %s, %d tx, %d stx",
+			msg.Header.BlockHash(), msg.TxCount(), msg.STxCount())
+
+	case *wire.MsgGetBlockTxn:
+		return fmt.Sprintf("hash %s, %d tx, %d stx", msg.BlockHash,
+			len(msg.Indexes), len(msg.StakeIndexes))
+
+	case *wire.MsgBlockTxn:
+		return fmt.Sprintf("hash %s, %d tx, %d stx", msg.BlockHash,
+			len(msg.Transactions), len(msg.STransactions))
+
+	case *wire.MsgSendTxRcncl:
+		return fmt.Sprintf("ver %d", msg.Version)
+
+	case *wire.MsgReqRecon:
+		return fmt.Sprintf("set size %d, q %d", msg.SetSize, msg.Q)
+
+	case *wire.MsgSketch:
+		return fmt.Sprintf("capacity %d",
+			len(msg.SketchData)/wire.SketchElementSize)
+
+	case *wire.MsgReconcilDiff:
+		return fmt.Sprintf("success %t, ask %d", msg.Success,
+			len(msg.AskShortIDs))
+
 	case *wire.MsgReject:
 		// Ensure the variable length strings don't contain any
 		// characters which are even remotely dangerous such as HTML
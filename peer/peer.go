@@ -22,13 +22,14 @@ import (
 	"github.com/decred/dcrd/blockchain"
 	"github.com/decred/dcrd/chaincfg"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrec/secp256k1"
 	"github.com/decred/dcrd/lru"
 	"github.com/decred/dcrd/wire"
 )
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.NodeCFVersion
+	MaxProtocolVersion = wire.TxReconciliationVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -122,6 +123,9 @@ type MessageListeners struct {
 	// OnTx is invoked when a peer receives a tx wire message.
 	OnTx func(p *Peer, msg *wire.MsgTx)
 
+	// OnStemTx is invoked when a peer receives a stemtx wire message.
+	OnStemTx func(p *Peer, msg *wire.MsgStemTx)
+
 	// OnBlock is invoked when a peer receives a block wire message.
 	OnBlock func(p *Peer, msg *wire.MsgBlock, buf []byte)
 
@@ -184,6 +188,20 @@ type MessageListeners struct {
 	// message.
 	OnSendHeaders func(p *Peer, msg *wire.MsgSendHeaders)
 
+	// OnSendCmpct is invoked when a peer receives a sendcmpct wire message.
+	OnSendCmpct func(p *Peer, msg *wire.MsgSendCmpct)
+
+	// OnCmpctBlock is invoked when a peer receives a cmpctblock wire
+	// message.
+	OnCmpctBlock func(p *Peer, msg *wire.MsgCmpctBlock)
+
+	// OnGetBlockTxn is invoked when a peer receives a getblocktxn wire
+	// message.
+	OnGetBlockTxn func(p *Peer, msg *wire.MsgGetBlockTxn)
+
+	// OnBlockTxn is invoked when a peer receives a blocktxn wire message.
+	OnBlockTxn func(p *Peer, msg *wire.MsgBlockTxn)
+
 	// OnRead is invoked when a peer receives a wire message.  It consists
 	// of the number of bytes read, the message, and whether or not an error
 	// in the read occurred.  Typically, callers will opt to use the
@@ -262,6 +280,38 @@ type Config struct {
 	// not send inv messages for transactions.
 	DisableRelayTx bool
 
+	// IdentityKey specifies the static key the local peer authenticates
+	// with when the encrypted transport is negotiated, which is the case
+	// when both peers include wire.SFNodeEncryption in their advertised
+	// services.  This field can be omitted in which case a key that is only
+	// used for a single connection is generated as needed.
+	IdentityKey *secp256k1.PrivateKey
+
+	// PinnedIdentity specifies a callback which returns the identity key
+	// the passed remote peer is required to authenticate with, or nil when
+	// any identity is acceptable.  Peers with a pinned identity that do not
+	// negotiate the encrypted transport or that authenticate with a
+	// different key are disconnected.  This field can be omitted in which
+	// case no identities are pinned.
+	PinnedIdentity func(p *Peer) *secp256k1.PublicKey
+
+	// TxReconciliation specifies if the reconciliation of transaction
+	// announcements should be offered to the remote peer.  When both peers
+	// offer it, transactions are announced in periodic reconciliation
+	// rounds instead of individually unless the peer is marked as a
+	// flooding peer with SetTxFlooding.
+	TxReconciliation bool
+
+	// MaxUploadRate specifies the maximum number of bytes per second to
+	// write to the remote peer.  This field can be omitted in which case
+	// the rate is only limited by UploadLimiter.
+	MaxUploadRate uint64
+
+	// UploadLimiter specifies a rate limiter shared with other peers that
+	// limits the combined rate at which data is written to them.  This
+	// field can be omitted in which case there is no shared limit.
+	UploadLimiter *RateLimiter
+
 	// Listeners houses callback functions to be invoked on receiving peer
 	// messages.
 	Listeners MessageListeners
@@ -367,6 +417,7 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+	Encrypted      bool
 }
 
 // HashFunc is a function which returns a block hash, height and error
@@ -418,6 +469,12 @@ type Peer struct {
 
 	conn net.Conn
 
+	// transport is what messages are read from and written to.  It is the
+	// connection itself unless the encrypted transport is negotiated, and
+	// it is only modified during protocol negotiation before any of the
+	// message handlers are started.
+	transport io.ReadWriter
+
 	// These fields are set at creation time and never modified, so they are
 	// safe to read from concurrently without a mutex.
 	addr    string
@@ -433,10 +490,14 @@ type Peer struct {
 	advertisedProtoVer   uint32 // protocol version advertised by remote
 	protocolVersion      uint32 // negotiated protocol version
 	sendHeadersPreferred bool   // peer sent a sendheaders message
+	sendCmpctPreferred   bool   // peer sent a sendcmpct message to announce
 	versionSent          bool
 	verAckReceived       bool
+	remoteIdentity       *secp256k1.PublicKey // set when encrypted
 
 	knownInventory     lru.Cache
+	txRecon            txReconState
+	uploadLimiter      *RateLimiter
 	prevGetBlocksMtx   sync.Mutex
 	prevGetBlocksBegin *chainhash.Hash
 	prevGetBlocksStop  *chainhash.Hash
@@ -506,6 +567,14 @@ func (p *Peer) AddKnownInventory(invVect *wire.InvVect) {
 	p.knownInventory.Add(invVect)
 }
 
+// IsKnownInventory returns whether or not the passed inventory is in the cache
+// of known inventory for the peer.
+//
+// This function is safe for concurrent access.
+func (p *Peer) IsKnownInventory(invVect *wire.InvVect) bool {
+	return p.knownInventory.Contains(invVect)
+}
+
 // StatsSnapshot returns a snapshot of the current peer flags and statistics.
 //
 // This function is safe for concurrent access.
@@ -518,6 +587,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 	userAgent := p.userAgent
 	services := p.services
 	protocolVersion := p.advertisedProtoVer
+	encrypted := p.remoteIdentity != nil
 	p.flagsMtx.Unlock()
 
 	// Get a copy of all relevant flags and stats.
@@ -539,6 +609,7 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 		LastPingNonce:  p.lastPingNonce,
 		LastPingMicros: p.lastPingMicros,
 		LastPingTime:   p.lastPingTime,
+		Encrypted:      encrypted,
 	}
 
 	p.statsMtx.RUnlock()
@@ -782,6 +853,18 @@ func (p *Peer) WantsHeaders() bool {
 	return sendHeadersPreferred
 }
 
+// WantsCompactBlocks returns if the peer wants new blocks to be announced with
+// compact block messages instead of headers or inventory vectors.
+//
+// This function is safe for concurrent access.
+func (p *Peer) WantsCompactBlocks() bool {
+	p.flagsMtx.Lock()
+	sendCmpctPreferred := p.sendCmpctPreferred
+	p.flagsMtx.Unlock()
+
+	return sendCmpctPreferred
+}
+
 // PushAddrMsg sends an addr message to the connected peer using the provided
 // addresses.  This function is useful over manually sending the message via
 // QueueMessage since it automatically limits the addresses to the maximum
@@ -968,7 +1051,7 @@ func (p *Peer) handlePongMsg(msg *wire.MsgPong) {
 
 // readMessage reads the next wire message from the peer with logging.
 func (p *Peer) readMessage() (wire.Message, []byte, error) {
-	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
+	n, msg, buf, err := wire.ReadMessageN(p.transport, p.ProtocolVersion(),
 		p.cfg.Net)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
 	if p.cfg.Listeners.OnRead != nil {
@@ -1030,11 +1113,15 @@ func (p *Peer) writeMessage(msg wire.Message) error {
 	}))
 
 	// Write the message to the peer.
-	n, err := wire.WriteMessageN(p.conn, msg, p.ProtocolVersion(), p.cfg.Net)
+	n, err := wire.WriteMessageN(p.transport, msg, p.ProtocolVersion(),
+		p.cfg.Net)
 	atomic.AddUint64(&p.bytesSent, uint64(n))
 	if p.cfg.Listeners.OnWrite != nil {
 		p.cfg.Listeners.OnWrite(p, n, msg, err)
 	}
+	if err == nil {
+		p.throttleWrite(n)
+	}
 	return err
 }
 
@@ -1100,6 +1187,14 @@ func (p *Peer) maybeAddDeadline(pendingResponses map[string]time.Time, msgCmd st
 
 	case wire.CmdGetMiningState:
 		pendingResponses[wire.CmdMiningState] = deadline
+
+	case wire.CmdGetBlockTxn:
+		// Expects a blocktxn message.
+		pendingResponses[wire.CmdBlockTxn] = deadline
+
+	case wire.CmdReqRecon:
+		// Expects a sketch message.
+		pendingResponses[wire.CmdSketch] = deadline
 	}
 }
 
@@ -1361,6 +1456,11 @@ out:
 				p.cfg.Listeners.OnTx(p, msg)
 			}
 
+		case *wire.MsgStemTx:
+			if p.cfg.Listeners.OnStemTx != nil {
+				p.cfg.Listeners.OnStemTx(p, msg)
+			}
+
 		case *wire.MsgBlock:
 			if p.cfg.Listeners.OnBlock != nil {
 				p.cfg.Listeners.OnBlock(p, msg, buf)
@@ -1445,6 +1545,46 @@ out:
 				p.cfg.Listeners.OnSendHeaders(p, msg)
 			}
 
+		case *wire.MsgSendCmpct:
+			// Only announcements using a known version of the
+			// compact block encoding are honored.
+			if msg.Version == wire.CmpctBlockVersion {
+				p.flagsMtx.Lock()
+				p.sendCmpctPreferred = msg.Announce
+				p.flagsMtx.Unlock()
+			}
+
+			if p.cfg.Listeners.OnSendCmpct != nil {
+				p.cfg.Listeners.OnSendCmpct(p, msg)
+			}
+
+		case *wire.MsgCmpctBlock:
+			if p.cfg.Listeners.OnCmpctBlock != nil {
+				p.cfg.Listeners.OnCmpctBlock(p, msg)
+			}
+
+		case *wire.MsgGetBlockTxn:
+			if p.cfg.Listeners.OnGetBlockTxn != nil {
+				p.cfg.Listeners.OnGetBlockTxn(p, msg)
+			}
+
+		case *wire.MsgBlockTxn:
+			if p.cfg.Listeners.OnBlockTxn != nil {
+				p.cfg.Listeners.OnBlockTxn(p, msg)
+			}
+
+		case *wire.MsgSendTxRcncl:
+			p.handleSendTxRcncl(msg)
+
+		case *wire.MsgReqRecon:
+			p.handleReqRecon(msg)
+
+		case *wire.MsgSketch:
+			p.handleSketch(msg)
+
+		case *wire.MsgReconcilDiff:
+			p.handleReconcilDiff(msg)
+
 		default:
 			log.Debugf("Received unhandled message of type %v "+
 				"from %v", rmsg.Command(), p)
@@ -1474,6 +1614,8 @@ func (p *Peer) queueHandler() {
 	var invSendQueue []*wire.InvVect
 	trickleTicker := time.NewTicker(trickleTimeout)
 	defer trickleTicker.Stop()
+	txReconTicker := time.NewTicker(txReconInterval)
+	defer txReconTicker.Stop()
 
 	// We keep the waiting flag so that we know if we have a message queued
 	// to the outHandler or not.  We could use the presence of a head of
@@ -1518,8 +1660,26 @@ out:
 
 		case iv := <-p.outputInvChan:
 			// No handshake?  They'll find out soon enough.
-			if p.VersionKnown() {
-				invSendQueue = append(invSendQueue, iv)
+			if !p.VersionKnown() {
+				continue
+			}
+
+			// Transactions are announced in the next round of
+			// reconciliation when it was negotiated.
+			if iv.Type == wire.InvTypeTx && p.addTxRecon(iv) {
+				continue
+			}
+			invSendQueue = append(invSendQueue, iv)
+
+		case <-txReconTicker.C:
+			// Rounds of reconciliation are only started with
+			// outbound peers.
+			if p.inbound || atomic.LoadInt32(&p.disconnect) != 0 {
+				continue
+			}
+			if msg := p.startTxReconRound(); msg != nil {
+				waiting = queuePacket(outMsg{msg: msg},
+					&pendingMsgs, waiting)
 			}
 
 		case <-trickleTicker.C:
@@ -1950,7 +2110,11 @@ func (p *Peer) negotiateInboundProtocol() error {
 		return err
 	}
 
-	return p.writeLocalVersionMsg()
+	if err := p.writeLocalVersionMsg(); err != nil {
+		return err
+	}
+
+	return p.negotiateTransport()
 }
 
 // negotiateOutboundProtocol sends our version message then waits to receive a
@@ -1961,7 +2125,11 @@ func (p *Peer) negotiateOutboundProtocol() error {
 		return err
 	}
 
-	return p.readRemoteVersionMsg()
+	if err := p.readRemoteVersionMsg(); err != nil {
+		return err
+	}
+
+	return p.negotiateTransport()
 }
 
 // start begins processing input and output messages.
@@ -1990,6 +2158,19 @@ func (p *Peer) start() error {
 	}
 	log.Debugf("Connected to %s", p.Addr())
 
+	// Generate the salt for the short ids used in the reconciliation of
+	// transaction announcements before the remote salt can be received.
+	offerTxRecon := p.cfg.TxReconciliation && !p.cfg.DisableRelayTx &&
+		p.ProtocolVersion() >= wire.TxReconciliationVersion
+	if offerTxRecon {
+		salt, err := wire.RandomUint64()
+		if err != nil {
+			p.Disconnect()
+			return err
+		}
+		p.txRecon.localSalt = salt
+	}
+
 	// The protocol has been negotiated successfully so start processing input
 	// and output messages.
 	go p.stallHandler()
@@ -1997,6 +2178,13 @@ func (p *Peer) start() error {
 	go p.queueHandler()
 	go p.outHandler()
 
+	// Offer the reconciliation of transaction announcements when enabled
+	// and supported by the negotiated protocol version.
+	if offerTxRecon {
+		p.QueueMessage(wire.NewMsgSendTxRcncl(wire.TxRcnclVersion,
+			p.txRecon.localSalt), nil)
+	}
+
 	// Send our verack message now that the IO processing machinery has started.
 	p.QueueMessage(wire.NewMsgVerAck(), nil)
 	return nil
@@ -2012,6 +2200,7 @@ func (p *Peer) AssociateConnection(conn net.Conn) {
 	}
 
 	p.conn = conn
+	p.transport = conn
 	p.timeConnected = time.Now()
 
 	if p.inbound {
@@ -2085,6 +2274,9 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		services:        cfg.Services,
 		protocolVersion: protocolVersion,
 	}
+	if cfg.MaxUploadRate != 0 {
+		p.uploadLimiter = NewRateLimiter(cfg.MaxUploadRate)
+	}
 	return &p
 }
 
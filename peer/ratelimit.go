@@ -0,0 +1,115 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that limits the rate at which data is written
+// to peers.  Tokens, which correspond to bytes, are added to the bucket at the
+// configured rate up to a capacity of one second worth of tokens.  Writes take
+// the tokens for the written bytes from the bucket and, once it runs dry, are
+// delayed until the bucket has been refilled.  A limiter may be shared by any
+// number of peers to limit their combined rate.
+type RateLimiter struct {
+	mtx       sync.Mutex
+	rate      float64
+	tokens    float64
+	last      time.Time
+	throttled time.Duration
+}
+
+// NewRateLimiter returns a new rate limiter that limits writes to the passed
+// number of bytes per second.
+func NewRateLimiter(bytesPerSec uint64) *RateLimiter {
+	return &RateLimiter{
+		rate:   float64(bytesPerSec),
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the number of bytes per second the limiter allows.
+//
+// This function is safe for concurrent access.
+func (l *RateLimiter) Rate() uint64 {
+	return uint64(l.rate)
+}
+
+// Throttled returns the total amount of time writes have been delayed by the
+// limiter.
+//
+// This function is safe for concurrent access.
+func (l *RateLimiter) Throttled() time.Duration {
+	l.mtx.Lock()
+	throttled := l.throttled
+	l.mtx.Unlock()
+	return throttled
+}
+
+// take takes the tokens for the passed number of written bytes from the bucket
+// and returns how long the next write must be delayed for the bucket to be
+// refilled.  The bucket is allowed to go into debt so writes of messages larger
+// than its capacity are possible.
+//
+// This function is safe for concurrent access.
+func (l *RateLimiter) take(n int) time.Duration {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.throttled += delay
+	return delay
+}
+
+// UploadThrottled returns the total amount of time writes to the peer have been
+// delayed by its own rate limiter configured with the MaxUploadRate field of
+// the Config struct.
+//
+// This function is safe for concurrent access.
+func (p *Peer) UploadThrottled() time.Duration {
+	if p.uploadLimiter == nil {
+		return 0
+	}
+	return p.uploadLimiter.Throttled()
+}
+
+// throttleWrite delays the caller as needed to keep the rate at which data is
+// written to the peer within the limits of the per-peer and shared rate
+// limiters after the passed number of bytes were written.  It returns early
+// when the peer disconnects.
+func (p *Peer) throttleWrite(n int) {
+	var delay time.Duration
+	for _, l := range []*RateLimiter{p.uploadLimiter, p.cfg.UploadLimiter} {
+		if l == nil {
+			continue
+		}
+		if d := l.take(n); d > delay {
+			delay = d
+		}
+	}
+	if delay == 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	select {
+	case <-timer.C:
+	case <-p.quit:
+		timer.Stop()
+	}
+}
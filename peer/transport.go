@@ -0,0 +1,408 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/dcrec/secp256k1"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// The encrypted transport is negotiated immediately after the version
+// messages have been exchanged when both peers advertise the
+// wire.SFNodeEncryption service flag.  Peers that do not advertise it continue
+// to use the legacy plaintext protocol.
+//
+// The handshake consists of the following steps:
+//
+// 1) Each side sends a fresh ephemeral secp256k1 public key in compressed form
+//    (33 bytes) in plaintext
+// 2) Each side performs ECDH with its ephemeral private key and the remote
+//    ephemeral public key and derives one AES-256-GCM key per direction by
+//    computing HMAC-SHA256 keyed by the shared secret over a direction label
+//    and the handshake transcript
+// 3) All further data, starting with the authentication messages below, is
+//    sent as encrypted frames
+// 4) Each side sends its static identity public key along with a signature
+//    over the transcript and its role in the connection, which proves
+//    possession of the identity key and binds it to this connection
+//
+// The handshake transcript is the SHA256 hash of a protocol label followed by
+// the ephemeral public key of the side that initiated the connection and the
+// ephemeral public key of the side that accepted it.
+//
+// Each encrypted frame is serialized as:
+//
+//   <length><ciphertext>
+//
+//   Field       Type     Size
+//   length      uint32   4 (little endian, length of the ciphertext)
+//   ciphertext  []byte   variable (includes the 16 byte authentication tag)
+//
+// The length is authenticated as additional data and the nonce is a counter
+// of the frames previously sent in the same direction.
+// -----------------------------------------------------------------------------
+
+const (
+	// transportLabel is mixed into the handshake transcript to separate it
+	// from any other protocol that might use the same key exchange.
+	transportLabel = "dcrd encrypted transport v1"
+
+	// maxFramePayload is the maximum number of plaintext bytes that are
+	// sent in a single encrypted frame.  Larger writes are split across
+	// multiple frames.
+	maxFramePayload = 1 << 16
+
+	// frameOverhead is the number of bytes each encrypted frame adds to the
+	// plaintext it carries.
+	frameOverhead = 4 + 16
+
+	// maxAuthMsgLen is the maximum length of a serialized authentication
+	// message, which consists of a compressed public key, a length byte and
+	// a DER encoded signature.
+	maxAuthMsgLen = 33 + 1 + 72
+)
+
+var (
+	// initiatorKeyLabel and responderKeyLabel are used to derive the keys
+	// that encrypt the data sent by the side which initiated and accepted
+	// the connection, respectively.
+	initiatorKeyLabel = []byte("initiator")
+	responderKeyLabel = []byte("responder")
+)
+
+// encryptedTransport wraps the connection to a remote peer in order to
+// encrypt and authenticate everything written to it and decrypt and verify
+// everything read from it.
+type encryptedTransport struct {
+	rw io.ReadWriter
+
+	// These fields are used when writing and are protected by the write
+	// mutex.
+	writeMtx  sync.Mutex
+	sendAEAD  cipher.AEAD
+	sendNonce uint64
+
+	// These fields are only accessed by the single reader of the
+	// connection.
+	recvAEAD  cipher.AEAD
+	recvNonce uint64
+	readBuf   []byte
+}
+
+// Ensure encryptedTransport implements the io.ReadWriter interface.
+var _ io.ReadWriter = (*encryptedTransport)(nil)
+
+// newAEAD returns an AES-256-GCM cipher using the passed key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newEncryptedTransport returns an encrypted transport over the passed
+// connection which encrypts data it sends with sendKey and decrypts data it
+// receives with recvKey.
+func newEncryptedTransport(rw io.ReadWriter, sendKey, recvKey []byte) (*encryptedTransport, error) {
+	sendAEAD, err := newAEAD(sendKey)
+	if err != nil {
+		return nil, err
+	}
+	recvAEAD, err := newAEAD(recvKey)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedTransport{
+		rw:       rw,
+		sendAEAD: sendAEAD,
+		recvAEAD: recvAEAD,
+	}, nil
+}
+
+// frameNonce returns the nonce for the frame with the passed counter.
+func frameNonce(counter uint64, size int) []byte {
+	nonce := make([]byte, size)
+	binary.LittleEndian.PutUint64(nonce, counter)
+	return nonce
+}
+
+// Write encrypts the passed data and writes it to the underlying connection
+// as one or more frames.
+//
+// This function is safe for concurrent access.
+func (t *encryptedTransport) Write(b []byte) (int, error) {
+	t.writeMtx.Lock()
+	defer t.writeMtx.Unlock()
+
+	var written int
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > maxFramePayload {
+			chunk = chunk[:maxFramePayload]
+		}
+
+		frame := make([]byte, 4, len(chunk)+frameOverhead)
+		binary.LittleEndian.PutUint32(frame, uint32(len(chunk)+
+			t.sendAEAD.Overhead()))
+		nonce := frameNonce(t.sendNonce, t.sendAEAD.NonceSize())
+		frame = t.sendAEAD.Seal(frame, nonce, chunk, frame[:4])
+		t.sendNonce++
+		if _, err := t.rw.Write(frame); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
+
+// Read reads data from the underlying connection into the passed buffer,
+// reading and decrypting the next frame when there is no remaining data from
+// the previous one.  An error is returned when a frame fails to authenticate.
+//
+// This function is NOT safe for concurrent access.
+func (t *encryptedTransport) Read(b []byte) (int, error) {
+	if len(t.readBuf) == 0 {
+		var hdr [4]byte
+		if _, err := io.ReadFull(t.rw, hdr[:]); err != nil {
+			return 0, err
+		}
+		frameLen := binary.LittleEndian.Uint32(hdr[:])
+		overhead := uint32(t.recvAEAD.Overhead())
+		if frameLen < overhead || frameLen > maxFramePayload+overhead {
+			return 0, fmt.Errorf("invalid encrypted frame length %d",
+				frameLen)
+		}
+		ciphertext := make([]byte, frameLen)
+		if _, err := io.ReadFull(t.rw, ciphertext); err != nil {
+			return 0, err
+		}
+		nonce := frameNonce(t.recvNonce, t.recvAEAD.NonceSize())
+		plaintext, err := t.recvAEAD.Open(ciphertext[:0], nonce,
+			ciphertext, hdr[:])
+		if err != nil {
+			return 0, errors.New("encrypted frame failed to authenticate")
+		}
+		t.recvNonce++
+		t.readBuf = plaintext
+	}
+
+	n := copy(b, t.readBuf)
+	t.readBuf = t.readBuf[n:]
+	return n, nil
+}
+
+// deriveKey derives a symmetric key from the shared secret of the key
+// exchange for the passed label and handshake transcript.
+func deriveKey(secret, label, transcript []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(label)
+	mac.Write(transcript)
+	return mac.Sum(nil)
+}
+
+// authHash returns the hash the side of the connection identified by the
+// passed label signs with its identity key to authenticate itself.
+func authHash(label, transcript []byte) []byte {
+	h := sha256.New()
+	h.Write(label)
+	h.Write(transcript)
+	return h.Sum(nil)
+}
+
+// shouldEncrypt returns whether or not the encrypted transport is to be
+// negotiated with the peer, which is the case when both the local and remote
+// peer advertise support for it.
+func (p *Peer) shouldEncrypt() bool {
+	p.flagsMtx.Lock()
+	remoteServices := p.services
+	p.flagsMtx.Unlock()
+
+	return p.cfg.Services&wire.SFNodeEncryption != 0 &&
+		remoteServices&wire.SFNodeEncryption != 0
+}
+
+// negotiateEncryption performs the encrypted transport handshake described
+// above with the remote peer and switches all further communication with it to
+// the encrypted transport.  The identity of the remote peer is verified
+// against the key returned by the PinnedIdentity callback when one is
+// configured.
+//
+// This must only be called during protocol negotiation.
+func (p *Peer) negotiateEncryption() error {
+	ephemeral, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return err
+	}
+	identity := p.cfg.IdentityKey
+	if identity == nil {
+		// Authenticate with a key that is only used for this connection
+		// when no identity key is configured.  The connection is still
+		// encrypted, but the remote peer can't recognize the local peer.
+		identity, err = secp256k1.GeneratePrivateKey()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Exchange ephemeral public keys.
+	localEphemeral := ephemeral.PubKey().SerializeCompressed()
+	n, err := p.conn.Write(localEphemeral)
+	atomic.AddUint64(&p.bytesSent, uint64(n))
+	if err != nil {
+		return err
+	}
+	remoteEphemeral := make([]byte, secp256k1.PubKeyBytesLenCompressed)
+	n, err = io.ReadFull(p.conn, remoteEphemeral)
+	atomic.AddUint64(&p.bytesReceived, uint64(n))
+	if err != nil {
+		return err
+	}
+	remoteEphemeralKey, err := secp256k1.ParsePubKey(remoteEphemeral)
+	if err != nil {
+		return fmt.Errorf("invalid ephemeral key: %v", err)
+	}
+
+	// Derive the keys for both directions from the shared secret and the
+	// transcript of the handshake.
+	initiatorEphemeral, responderEphemeral := localEphemeral, remoteEphemeral
+	localLabel, remoteLabel := initiatorKeyLabel, responderKeyLabel
+	if p.inbound {
+		initiatorEphemeral, responderEphemeral = remoteEphemeral, localEphemeral
+		localLabel, remoteLabel = responderKeyLabel, initiatorKeyLabel
+	}
+	th := sha256.New()
+	th.Write([]byte(transportLabel))
+	th.Write(initiatorEphemeral)
+	th.Write(responderEphemeral)
+	transcript := th.Sum(nil)
+	secret := secp256k1.GenerateSharedSecret(ephemeral, remoteEphemeralKey)
+	sendKey := deriveKey(secret, localLabel, transcript)
+	recvKey := deriveKey(secret, remoteLabel, transcript)
+	transport, err := newEncryptedTransport(p.conn, sendKey, recvKey)
+	if err != nil {
+		return err
+	}
+
+	// Authenticate both sides with their identity keys over the encrypted
+	// transport.
+	sig, err := identity.Sign(authHash(localLabel, transcript))
+	if err != nil {
+		return err
+	}
+	sigBytes := sig.Serialize()
+	authMsg := make([]byte, 0, maxAuthMsgLen)
+	authMsg = append(authMsg, identity.PubKey().SerializeCompressed()...)
+	authMsg = append(authMsg, byte(len(sigBytes)))
+	authMsg = append(authMsg, sigBytes...)
+	if _, err := transport.Write(authMsg); err != nil {
+		return err
+	}
+	atomic.AddUint64(&p.bytesSent, uint64(len(authMsg)+frameOverhead))
+
+	remoteAuthMsg := make([]byte, maxAuthMsgLen)
+	n, err = transport.Read(remoteAuthMsg)
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&p.bytesReceived, uint64(n+frameOverhead))
+	remoteAuthMsg = remoteAuthMsg[:n]
+	pkLen := secp256k1.PubKeyBytesLenCompressed
+	if len(remoteAuthMsg) < pkLen+1 ||
+		len(remoteAuthMsg) != pkLen+1+int(remoteAuthMsg[pkLen]) {
+		return errors.New("malformed transport authentication message")
+	}
+	remoteIdentity, err := secp256k1.ParsePubKey(remoteAuthMsg[:pkLen])
+	if err != nil {
+		return fmt.Errorf("invalid identity key: %v", err)
+	}
+	remoteSig, err := secp256k1.ParseDERSignature(remoteAuthMsg[pkLen+1:])
+	if err != nil {
+		return fmt.Errorf("invalid identity signature: %v", err)
+	}
+	if !remoteSig.Verify(authHash(remoteLabel, transcript), remoteIdentity) {
+		return errors.New("identity signature verification failed")
+	}
+	if pinned := p.pinnedIdentity(); pinned != nil &&
+		!pinned.IsEqual(remoteIdentity) {
+
+		return fmt.Errorf("identity key %x does not match the pinned "+
+			"key %x", remoteIdentity.SerializeCompressed(),
+			pinned.SerializeCompressed())
+	}
+
+	p.transport = transport
+	p.flagsMtx.Lock()
+	p.remoteIdentity = remoteIdentity
+	p.flagsMtx.Unlock()
+
+	log.Debugf("Negotiated encrypted transport with %s (identity %x)", p,
+		remoteIdentity.SerializeCompressed())
+	return nil
+}
+
+// negotiateTransport switches communication with the remote peer to the
+// encrypted transport when both peers support it.  An error is returned when
+// the remote peer has a pinned identity, but does not support it.
+//
+// This must only be called during protocol negotiation after the version
+// messages have been exchanged.
+func (p *Peer) negotiateTransport() error {
+	if p.shouldEncrypt() {
+		return p.negotiateEncryption()
+	}
+	if p.pinnedIdentity() != nil {
+		return errors.New("peer with a pinned identity does not support " +
+			"the encrypted transport")
+	}
+	return nil
+}
+
+// pinnedIdentity returns the identity key the remote peer is required to
+// authenticate with, if any.
+func (p *Peer) pinnedIdentity() *secp256k1.PublicKey {
+	if p.cfg.PinnedIdentity == nil {
+		return nil
+	}
+	return p.cfg.PinnedIdentity(p)
+}
+
+// Encrypted returns whether or not the communication with the peer uses the
+// encrypted transport.
+//
+// This function is safe for concurrent access.
+func (p *Peer) Encrypted() bool {
+	p.flagsMtx.Lock()
+	encrypted := p.remoteIdentity != nil
+	p.flagsMtx.Unlock()
+
+	return encrypted
+}
+
+// RemoteIdentity returns the identity key the remote peer authenticated with
+// when the encrypted transport is used, and nil otherwise.
+//
+// This function is safe for concurrent access.
+func (p *Peer) RemoteIdentity() *secp256k1.PublicKey {
+	p.flagsMtx.Lock()
+	remoteIdentity := p.remoteIdentity
+	p.flagsMtx.Unlock()
+
+	return remoteIdentity
+}
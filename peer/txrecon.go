@@ -0,0 +1,427 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/dchest/siphash"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/minisketch"
+	"github.com/decred/dcrd/wire"
+)
+
+// -----------------------------------------------------------------------------
+// Transaction reconciliation replaces the announcement of every transaction
+// with an inventory vector by periodic rounds in which two peers learn which
+// transactions the other one is missing.  It is negotiated with sendtxrcncl
+// messages, which are exchanged when both peers have it enabled and the
+// negotiated protocol version is at least wire.TxReconciliationVersion.
+//
+// Both peers collect the transactions they would announce to each other in a
+// reconciliation set instead of queueing them for inventory trickling.  The
+// outbound side of the connection starts a round at a regular interval:
+//
+//   1. The outbound peer sends a reqrecon message with the size of its set
+//   2. The inbound peer replies with a sketch message containing a sketch of
+//      the short ids of its set whose capacity is the estimated size of the
+//      difference of both sets
+//   3. The outbound peer merges the sketch with a sketch of its own set and
+//      decodes the difference
+//   4. The outbound peer announces the transactions from its set the inbound
+//      peer is missing and asks for the others with a reconcildiff message,
+//      upon which the inbound peer announces them
+//
+// When the sketch can't be decoded, the outbound peer sends a reconcildiff
+// message signaling failure and both peers announce their complete sets.
+//
+// Transactions are still announced individually to peers that are marked as
+// flooding peers with SetTxFlooding, which the server does for a small number
+// of outbound peers in order to keep the propagation of transactions fast.
+// -----------------------------------------------------------------------------
+
+const (
+	// txReconInterval is the interval at which rounds of transaction
+	// reconciliation are started with outbound peers.
+	txReconInterval = 8 * time.Second
+
+	// txReconQ is the coefficient used to estimate the size of the
+	// difference of two reconciliation sets from their sizes, which is 0.25
+	// scaled by 2^15-1 as it is transmitted in reqrecon messages.
+	txReconQ = 8191
+
+	// maxTxReconSetSize is the maximum number of transactions in the
+	// reconciliation set of a peer.  Transactions that don't fit are
+	// announced individually instead.
+	maxTxReconSetSize = 3000
+
+	// txReconFPBits is the number of bits of protection against decoding
+	// a wrong difference from a sketch whose capacity is smaller than the
+	// actual difference.  The capacity of sketches is increased as needed
+	// to provide it.
+	txReconFPBits = 16
+)
+
+// TxReconStats houses statistics about the reconciliation of transaction
+// announcements with a peer.
+type TxReconStats struct {
+	// Flooding is whether or not transactions are still announced to the
+	// peer individually.
+	Flooding bool
+
+	// SetSize is the number of transactions waiting for the next round of
+	// reconciliation.
+	SetSize int
+
+	// Rounds and Failures are the number of completed rounds and the number
+	// of those in which the difference could not be decoded.
+	Rounds   uint64
+	Failures uint64
+
+	// Announced and Requested are the number of transactions announced to
+	// the peer as a result of reconciliation and the number of transactions
+	// announced by the peer that were asked for.
+	Announced uint64
+	Requested uint64
+}
+
+// txReconState houses the state of the transaction reconciliation with a peer.
+type txReconState struct {
+	mtx       sync.Mutex
+	localSalt uint64
+	enabled   bool
+	k0, k1    uint64
+
+	// set houses the transactions to announce to the peer in the next round
+	// keyed by their short ids while snapshot houses the set of the round
+	// in progress, if any.
+	set      map[uint32]*wire.InvVect
+	snapshot map[uint32]*wire.InvVect
+
+	stats TxReconStats
+}
+
+// shortID returns the short id of the transaction with the passed hash used in
+// the sketches.  Zero is not a valid sketch element, so it is mapped to one.
+//
+// This function MUST be called with the reconciliation state mutex held.
+func (r *txReconState) shortID(hash *chainhash.Hash) uint32 {
+	id := uint32(siphash.Hash(r.k0, r.k1, hash[:]))
+	if id == 0 {
+		id = 1
+	}
+	return id
+}
+
+// sketchSet returns a sketch of the passed set with the given capacity.
+func sketchSet(set map[uint32]*wire.InvVect, capacity int) *minisketch.Sketch {
+	sketch := minisketch.New(capacity)
+	for id := range set {
+		// The short ids are never zero, so this can't fail.
+		sketch.Add(id)
+	}
+	return sketch
+}
+
+// setInvVects returns the inventory vectors of the passed set.
+func setInvVects(set map[uint32]*wire.InvVect) []*wire.InvVect {
+	invVects := make([]*wire.InvVect, 0, len(set))
+	for _, iv := range set {
+		invVects = append(invVects, iv)
+	}
+	return invVects
+}
+
+// TxReconStats returns statistics about the reconciliation of transaction
+// announcements with the peer, or nil when it was not negotiated.
+//
+// This function is safe for concurrent access.
+func (p *Peer) TxReconStats() *TxReconStats {
+	r := &p.txRecon
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if !r.enabled {
+		return nil
+	}
+	stats := r.stats
+	stats.SetSize = len(r.set)
+	return &stats
+}
+
+// SetTxFlooding sets whether or not transactions are announced to the peer
+// individually even when the reconciliation of transaction announcements was
+// negotiated.  Transactions are always announced individually to peers that
+// did not negotiate it.
+//
+// This function is safe for concurrent access.
+func (p *Peer) SetTxFlooding(flood bool) {
+	r := &p.txRecon
+	r.mtx.Lock()
+	r.stats.Flooding = flood
+	r.mtx.Unlock()
+}
+
+// TxFlooding returns whether or not transactions are announced to the peer
+// individually even when the reconciliation of transaction announcements was
+// negotiated.
+//
+// This function is safe for concurrent access.
+func (p *Peer) TxFlooding() bool {
+	r := &p.txRecon
+	r.mtx.Lock()
+	flood := r.stats.Flooding
+	r.mtx.Unlock()
+	return flood
+}
+
+// addTxRecon adds the passed transaction inventory vector to the
+// reconciliation set and returns whether or not it was added.  Transactions
+// that are not added must be announced individually.
+func (p *Peer) addTxRecon(iv *wire.InvVect) bool {
+	r := &p.txRecon
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if !r.enabled || r.stats.Flooding || len(r.set) >= maxTxReconSetSize {
+		return false
+	}
+	id := r.shortID(&iv.Hash)
+	if other, ok := r.set[id]; ok {
+		// Colliding short ids can't be reconciled, so the transaction
+		// is announced individually unless it's the same one.
+		return other.Hash == iv.Hash
+	}
+	r.set[id] = iv
+	return true
+}
+
+// announceTxRecon queues inventory messages announcing the passed transactions
+// unless the peer already knows about them and returns the number of announced
+// transactions.
+func (p *Peer) announceTxRecon(invVects []*wire.InvVect) int {
+	var announced int
+	invMsg := wire.NewMsgInvSizeHint(uint(len(invVects)))
+	for _, iv := range invVects {
+		if p.knownInventory.Contains(iv) {
+			continue
+		}
+		invMsg.AddInvVect(iv)
+		if len(invMsg.InvList) >= maxInvTrickleSize {
+			p.QueueMessage(invMsg, nil)
+			invMsg = wire.NewMsgInvSizeHint(uint(len(invVects)))
+		}
+		p.AddKnownInventory(iv)
+		announced++
+	}
+	if len(invMsg.InvList) > 0 {
+		p.QueueMessage(invMsg, nil)
+	}
+	return announced
+}
+
+// handleSendTxRcncl enables the reconciliation of transaction announcements
+// when it is offered by the remote peer while enabled locally.
+func (p *Peer) handleSendTxRcncl(msg *wire.MsgSendTxRcncl) {
+	if !p.cfg.TxReconciliation || p.cfg.DisableRelayTx ||
+		msg.Version < wire.TxRcnclVersion {
+		return
+	}
+
+	r := &p.txRecon
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.enabled {
+		log.Debugf("Ignoring duplicate sendtxrcncl from %v", p)
+		return
+	}
+
+	// The short id keys are derived from the hash of both salts in
+	// ascending order so both peers arrive at the same keys.
+	salt1, salt2 := r.localSalt, msg.Salt
+	if salt1 > salt2 {
+		salt1, salt2 = salt2, salt1
+	}
+	var salts [16]byte
+	binary.LittleEndian.PutUint64(salts[0:8], salt1)
+	binary.LittleEndian.PutUint64(salts[8:16], salt2)
+	hash := chainhash.HashB(salts[:])
+	r.k0 = binary.LittleEndian.Uint64(hash[0:8])
+	r.k1 = binary.LittleEndian.Uint64(hash[8:16])
+	r.set = make(map[uint32]*wire.InvVect)
+	r.enabled = true
+	log.Debugf("Reconciling transaction announcements with %v", p)
+}
+
+// startTxReconRound starts a new round of transaction reconciliation and
+// returns the reqrecon message to send, or nil when no round is to be started.
+// It is only used for outbound peers.
+func (p *Peer) startTxReconRound() *wire.MsgReqRecon {
+	r := &p.txRecon
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	// Unresponsive peers are disconnected by the stall handler, so there
+	// is nothing to do while a round is still in progress.
+	if !r.enabled || r.snapshot != nil {
+		return nil
+	}
+	r.snapshot = r.set
+	r.set = make(map[uint32]*wire.InvVect)
+	return wire.NewMsgReqRecon(uint16(minUint32(uint32(len(r.snapshot)),
+		1<<16-1)), txReconQ)
+}
+
+// handleReqRecon replies to a reqrecon message with a sketch of the
+// reconciliation set.  It is only used for inbound peers.
+func (p *Peer) handleReqRecon(msg *wire.MsgReqRecon) {
+	if !p.inbound {
+		log.Debugf("Ignoring reqrecon from outbound peer %v", p)
+		return
+	}
+
+	r := &p.txRecon
+	r.mtx.Lock()
+	if !r.enabled {
+		r.mtx.Unlock()
+		return
+	}
+
+	// Return the transactions of a round that was never concluded to the
+	// set so they are reconciled in this round.
+	for id, iv := range r.snapshot {
+		if _, ok := r.set[id]; !ok {
+			r.set[id] = iv
+		}
+	}
+	r.snapshot = r.set
+	r.set = make(map[uint32]*wire.InvVect)
+
+	// Estimate the size of the difference of both sets from their sizes
+	// and add enough capacity to make it unlikely that a wrong difference
+	// is decoded when the estimate is too small.  An empty sketch is sent
+	// when the difference is expected to be too large to be decoded.
+	localSize, remoteSize := len(r.snapshot), int(msg.SetSize)
+	capacity, minSize := localSize-remoteSize, remoteSize
+	if capacity < 0 {
+		capacity, minSize = -capacity, localSize
+	}
+	capacity += minSize*int(msg.Q)/(1<<15-1) + 1
+	capacity = minisketch.ComputeCapacity(capacity, txReconFPBits)
+	var sketchData []byte
+	if capacity <= wire.MaxSketchCapacity {
+		sketchData = sketchSet(r.snapshot, capacity).Serialize()
+	}
+	r.mtx.Unlock()
+
+	p.QueueMessage(wire.NewMsgSketch(sketchData), nil)
+}
+
+// handleSketch decodes the difference of the reconciliation set and the set of
+// the remote peer from the passed sketch, announces the transactions the
+// remote peer is missing and asks for the others.  It is only used for
+// outbound peers.
+func (p *Peer) handleSketch(msg *wire.MsgSketch) {
+	r := &p.txRecon
+	r.mtx.Lock()
+	if !r.enabled || r.snapshot == nil {
+		r.mtx.Unlock()
+		log.Debugf("Ignoring unrequested sketch from %v", p)
+		return
+	}
+	snapshot := r.snapshot
+	r.snapshot = nil
+
+	var diff []uint32
+	remote, err := minisketch.Deserialize(msg.SketchData)
+	if err == nil && remote.Capacity() == 0 {
+		err = minisketch.ErrDecodeFailed
+	}
+	if err == nil {
+		// Only decode as many differences as the capacity of the
+		// sketch allows while keeping the margin against decoding a
+		// wrong difference.
+		capacity := remote.Capacity()
+		sketch := sketchSet(snapshot, capacity)
+		sketch.Merge(remote)
+		diff, err = sketch.DecodeMax(minisketch.MaxElements(capacity,
+			txReconFPBits))
+	}
+	if err != nil {
+		// Both peers announce their complete sets when the difference
+		// can't be decoded.
+		r.stats.Rounds++
+		r.stats.Failures++
+		r.mtx.Unlock()
+
+		log.Debugf("Transaction reconciliation with %v failed: %v", p,
+			err)
+		p.QueueMessage(wire.NewMsgReconcilDiff(false, nil), nil)
+		announced := p.announceTxRecon(setInvVects(snapshot))
+
+		r.mtx.Lock()
+		r.stats.Announced += uint64(announced)
+		r.mtx.Unlock()
+		return
+	}
+
+	var invVects []*wire.InvVect
+	var ask []uint32
+	for _, id := range diff {
+		if iv, ok := snapshot[id]; ok {
+			invVects = append(invVects, iv)
+			continue
+		}
+		ask = append(ask, id)
+	}
+	r.stats.Rounds++
+	r.stats.Requested += uint64(len(ask))
+	r.mtx.Unlock()
+
+	p.QueueMessage(wire.NewMsgReconcilDiff(true, ask), nil)
+	announced := p.announceTxRecon(invVects)
+
+	r.mtx.Lock()
+	r.stats.Announced += uint64(announced)
+	r.mtx.Unlock()
+}
+
+// handleReconcilDiff concludes a round of transaction reconciliation by
+// announcing the transactions the remote peer asked for, or the complete
+// reconciliation set when the round failed.  It is only used for inbound
+// peers.
+func (p *Peer) handleReconcilDiff(msg *wire.MsgReconcilDiff) {
+	r := &p.txRecon
+	r.mtx.Lock()
+	if !r.enabled || r.snapshot == nil {
+		r.mtx.Unlock()
+		log.Debugf("Ignoring unexpected reconcildiff from %v", p)
+		return
+	}
+	snapshot := r.snapshot
+	r.snapshot = nil
+
+	var invVects []*wire.InvVect
+	if msg.Success {
+		for _, id := range msg.AskShortIDs {
+			if iv, ok := snapshot[id]; ok {
+				invVects = append(invVects, iv)
+			}
+		}
+	} else {
+		invVects = setInvVects(snapshot)
+		r.stats.Failures++
+	}
+	r.stats.Rounds++
+	r.mtx.Unlock()
+
+	announced := p.announceTxRecon(invVects)
+
+	r.mtx.Lock()
+	r.stats.Announced += uint64(announced)
+	r.mtx.Unlock()
+}
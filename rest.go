@@ -0,0 +1,552 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v2"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// restPathPrefix is the path of the RPC listeners the REST interface is
+	// served under.
+	restPathPrefix = "/rest/"
+
+	// restMaxHeaders is the maximum number of block headers that may be
+	// requested at once.
+	restMaxHeaders = 2000
+
+	// restMaxOutpoints is the maximum number of outpoints that may be
+	// looked up at once.
+	restMaxOutpoints = 15
+
+	// restMempoolHeight is the height reported in the binary getutxos
+	// response for outputs of transactions in the mempool.
+	restMempoolHeight = 0x7fffffff
+
+	// restRateLimiterExpiry is the duration after which the rate limiter
+	// forgets about clients that have not made any requests.
+	restRateLimiterExpiry = time.Minute
+)
+
+// restFormat identifies the format of a REST response, which is selected by
+// the extension of the requested path.
+type restFormat int
+
+const (
+	restFormatBinary restFormat = iota
+	restFormatHex
+	restFormatJSON
+)
+
+// restFormats maps the path extensions to the response formats.
+var restFormats = map[string]restFormat{
+	"bin":  restFormatBinary,
+	"hex":  restFormatHex,
+	"json": restFormatJSON,
+}
+
+// restError is an error that is reported to the REST client with the contained
+// HTTP status code.
+type restError struct {
+	status  int
+	message string
+}
+
+// Error satisfies the error interface.
+func (e *restError) Error() string {
+	return e.message
+}
+
+// restBadRequest returns a restError with a bad request status code and the
+// passed formatted message.
+func restBadRequest(fmtStr string, args ...interface{}) *restError {
+	return &restError{
+		status:  http.StatusBadRequest,
+		message: fmt.Sprintf(fmtStr, args...),
+	}
+}
+
+// restErrorStatus returns the HTTP status code and message to respond with for
+// the passed error.  RPC errors returned by the shared RPC handlers are mapped
+// to the closest status code.
+func restErrorStatus(err error) (int, string) {
+	switch e := err.(type) {
+	case *restError:
+		return e.status, e.message
+	case *dcrjson.RPCError:
+		switch e.Code {
+		case dcrjson.ErrRPCInvalidAddressOrKey:
+			return http.StatusNotFound, e.Message
+		case dcrjson.ErrRPCInvalidParameter, dcrjson.ErrRPCDecodeHexString:
+			return http.StatusBadRequest, e.Message
+		}
+		return http.StatusInternalServerError, e.Message
+	}
+	return http.StatusInternalServerError, err.Error()
+}
+
+// restRequest houses the parsed path of a REST request.
+type restRequest struct {
+	// params are the segments of the path following the resource name
+	// with the extension of the last one removed.
+	params []string
+	format restFormat
+
+	closeChan <-chan struct{}
+}
+
+// restHandler describes a callback function used to handle a REST resource.
+// It returns the serialized response for the binary and hex formats and the
+// value to marshal for the JSON format.
+type restHandler func(s *rpcServer, r *restRequest) (interface{}, error)
+
+// restHandlers maps the REST resources to their handlers along with the
+// formats they support.
+var restHandlers = map[string]struct {
+	handler restHandler
+	formats []restFormat
+	params  int
+}{
+	"block":     {restHandleBlock, []restFormat{restFormatBinary, restFormatHex, restFormatJSON}, 1},
+	"tx":        {restHandleTx, []restFormat{restFormatBinary, restFormatHex, restFormatJSON}, 1},
+	"headers":   {restHandleHeaders, []restFormat{restFormatBinary, restFormatHex, restFormatJSON}, 2},
+	"chaininfo": {restHandleChainInfo, []restFormat{restFormatJSON}, 0},
+	"mempool":   {restHandleMempool, []restFormat{restFormatJSON}, 1},
+	"getutxos":  {restHandleUTXOs, []restFormat{restFormatBinary, restFormatHex, restFormatJSON}, -1},
+}
+
+// restParseHash parses the passed hash string and returns a bad request error
+// when it is invalid.
+func restParseHash(str string) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHashFromStr(str)
+	if err != nil || len(str) != chainhash.MaxHashStringSize {
+		return nil, restBadRequest("invalid hash: %s", str)
+	}
+	return hash, nil
+}
+
+// restHandleBlock handles /rest/block/<hash>.<bin|hex|json> requests.
+func restHandleBlock(s *rpcServer, r *restRequest) (interface{}, error) {
+	hash, err := restParseHash(r.params[0])
+	if err != nil {
+		return nil, err
+	}
+	verbose := r.format == restFormatJSON
+	cmd := &dcrjson.GetBlockCmd{
+		Hash:      hash.String(),
+		Verbose:   &verbose,
+		VerboseTx: &verbose,
+	}
+	return restRPCResult(handleGetBlock(s, cmd, r.closeChan))
+}
+
+// restHandleTx handles /rest/tx/<hash>.<bin|hex|json> requests.  Transactions
+// that are not in the mempool are only available with the transaction index.
+func restHandleTx(s *rpcServer, r *restRequest) (interface{}, error) {
+	hash, err := restParseHash(r.params[0])
+	if err != nil {
+		return nil, err
+	}
+	verbose := 0
+	if r.format == restFormatJSON {
+		verbose = 1
+	}
+	cmd := &dcrjson.GetRawTransactionCmd{
+		Txid:    hash.String(),
+		Verbose: &verbose,
+	}
+	return restRPCResult(handleGetRawTransaction(s, cmd, r.closeChan))
+}
+
+// restHandleHeaders handles /rest/headers/<count>/<hash>.<bin|hex|json>
+// requests.  It responds with up to count headers of the main chain starting
+// with the block with the passed hash, or none when the block is not part of
+// the main chain.
+func restHandleHeaders(s *rpcServer, r *restRequest) (interface{}, error) {
+	count, err := strconv.Atoi(r.params[0])
+	if err != nil || count < 1 || count > restMaxHeaders {
+		return nil, restBadRequest("header count must be between 1 "+
+			"and %d: %s", restMaxHeaders, r.params[0])
+	}
+	hash, err := restParseHash(r.params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var headers []wire.BlockHeader
+	if s.chain.MainChainHasBlock(hash) {
+		start, err := s.chain.HeaderByHash(hash)
+		if err != nil {
+			return nil, &restError{http.StatusNotFound, err.Error()}
+		}
+		headers = append(headers, start)
+		for height := int64(start.Height) + 1; len(headers) < count; height++ {
+			header, err := s.chain.HeaderByHeight(height)
+			if err != nil {
+				break
+			}
+			headers = append(headers, header)
+		}
+	}
+
+	if r.format != restFormatJSON {
+		var buf bytes.Buffer
+		for i := range headers {
+			if err := headers[i].Serialize(&buf); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	}
+
+	results := make([]interface{}, 0, len(headers))
+	for i := range headers {
+		verbose := true
+		cmd := &dcrjson.GetBlockHeaderCmd{
+			Hash:    headers[i].BlockHash().String(),
+			Verbose: &verbose,
+		}
+		result, err := handleGetBlockHeader(s, cmd, r.closeChan)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// restHandleChainInfo handles /rest/chaininfo.json requests.
+func restHandleChainInfo(s *rpcServer, r *restRequest) (interface{}, error) {
+	return handleGetBlockchainInfo(s, nil, r.closeChan)
+}
+
+// restHandleMempool handles /rest/mempool/info.json and
+// /rest/mempool/contents.json requests.
+func restHandleMempool(s *rpcServer, r *restRequest) (interface{}, error) {
+	switch r.params[0] {
+	case "info":
+		return handleGetMempoolInfo(s, nil, r.closeChan)
+	case "contents":
+		verbose := true
+		cmd := &dcrjson.GetRawMempoolCmd{Verbose: &verbose}
+		return handleGetRawMempool(s, cmd, r.closeChan)
+	}
+	return nil, &restError{http.StatusNotFound, "unknown mempool resource"}
+}
+
+// restUTXOsResult models the data returned by the getutxos resource in the
+// JSON format.
+type restUTXOsResult struct {
+	ChainHeight  int64                     `json:"chainheight"`
+	ChainTipHash string                    `json:"chaintiphash"`
+	Bitmap       string                    `json:"bitmap"`
+	UTXOs        []*dcrjson.GetTxOutResult `json:"utxos"`
+}
+
+// restHandleUTXOs handles /rest/getutxos[/checkmempool]/<txid>-<n>/...
+// requests, which look up the unspent outputs of a list of outpoints.
+//
+// The response contains the height and hash of the best block, a bitmap with a
+// bit set for each outpoint that is unspent, and the unspent outputs.  The
+// binary format serializes them as the height as a uint32, the hash, the
+// bitmap as variable length bytes and a variable length list of outputs, each
+// of which consists of the transaction version as a uint16, the height of the
+// block of the transaction as a uint32, the amount as an int64, the script
+// version as a uint16 and the script as variable length bytes.
+func restHandleUTXOs(s *rpcServer, r *restRequest) (interface{}, error) {
+	params := r.params
+	includeMempool := len(params) > 0 && params[0] == "checkmempool"
+	if includeMempool {
+		params = params[1:]
+	}
+	if len(params) == 0 || len(params) > restMaxOutpoints {
+		return nil, restBadRequest("between 1 and %d outpoints must be "+
+			"requested", restMaxOutpoints)
+	}
+
+	// Parse all outpoints before looking up any of them.
+	type outpoint struct {
+		hash  *chainhash.Hash
+		index uint32
+	}
+	outpoints := make([]outpoint, 0, len(params))
+	for _, param := range params {
+		parts := strings.Split(param, "-")
+		if len(parts) != 2 {
+			return nil, restBadRequest("invalid outpoint: %s", param)
+		}
+		hash, err := restParseHash(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, restBadRequest("invalid outpoint: %s", param)
+		}
+		outpoints = append(outpoints, outpoint{hash, uint32(index)})
+	}
+
+	best := s.chain.BestSnapshot()
+	bitmap := make([]byte, (len(outpoints)+7)/8)
+	var txOuts []*txOutInfo
+	for i, op := range outpoints {
+		txOut, err := s.fetchTxOut(op.hash, op.index, includeMempool)
+		if err != nil {
+			return nil, err
+		}
+		if txOut == nil {
+			continue
+		}
+		bitmap[i/8] |= 1 << uint(i%8)
+		txOuts = append(txOuts, txOut)
+	}
+
+	if r.format == restFormatJSON {
+		result := &restUTXOsResult{
+			ChainHeight:  best.Height,
+			ChainTipHash: best.Hash.String(),
+			Bitmap:       hex.EncodeToString(bitmap),
+			UTXOs:        make([]*dcrjson.GetTxOutResult, 0, len(txOuts)),
+		}
+		for _, txOut := range txOuts {
+			result.UTXOs = append(result.UTXOs, s.txOutResult(txOut))
+		}
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	pver := wire.ProtocolVersion
+	binary.Write(&buf, binary.LittleEndian, uint32(best.Height))
+	buf.Write(best.Hash[:])
+	if err := wire.WriteVarBytes(&buf, pver, bitmap); err != nil {
+		return nil, err
+	}
+	err := wire.WriteVarInt(&buf, pver, uint64(len(txOuts)))
+	if err != nil {
+		return nil, err
+	}
+	for _, txOut := range txOuts {
+		height := uint32(restMempoolHeight)
+		if txOut.confirmations > 0 {
+			height = uint32(txOut.bestHeight - txOut.confirmations + 1)
+		}
+		binary.Write(&buf, binary.LittleEndian, txOut.txVersion)
+		binary.Write(&buf, binary.LittleEndian, height)
+		binary.Write(&buf, binary.LittleEndian, txOut.value)
+		binary.Write(&buf, binary.LittleEndian, txOut.scriptVersion)
+		err := wire.WriteVarBytes(&buf, pver, txOut.pkScript)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// restRPCResult converts the result of an RPC handler invoked with the verbose
+// flag unset, which is a hex string, to the serialized bytes expected for the
+// binary and hex formats.  Verbose results are returned unchanged.
+func restRPCResult(result interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	hexStr, ok := result.(string)
+	if !ok {
+		return result, nil
+	}
+	return hex.DecodeString(hexStr)
+}
+
+// parseRESTRequest parses the passed path, which excludes the REST path
+// prefix, into the resource name and the request.
+func parseRESTRequest(path string) (string, *restRequest, error) {
+	ext := strings.LastIndex(path, ".")
+	if ext == -1 || strings.Contains(path[ext:], "/") {
+		return "", nil, restBadRequest("format extension required " +
+			"(.bin, .hex or .json)")
+	}
+	format, ok := restFormats[path[ext+1:]]
+	if !ok {
+		return "", nil, restBadRequest("unknown format extension %q",
+			path[ext+1:])
+	}
+
+	segments := strings.Split(path[:ext], "/")
+	return segments[0], &restRequest{
+		params: segments[1:],
+		format: format,
+	}, nil
+}
+
+// handleREST serves the unauthenticated and read-only REST interface.
+func (s *rpcServer) handleREST(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "405 Method not allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Limit the number of connections to max allowed and the rate at which
+	// each client may make requests.
+	if s.limitConnections(w, r.RemoteAddr) {
+		return
+	}
+	s.incrementClients()
+	defer s.decrementClients()
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !s.restLimiter.allow(host) {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "429 Too many requests.",
+			http.StatusTooManyRequests)
+		return
+	}
+
+	writeError := func(err error) {
+		status, message := restErrorStatus(err)
+		http.Error(w, message, status)
+	}
+	// All resources depend on block chain data that is not kept when the
+	// node runs as a light client.
+	if s.server.spvSyncer != nil {
+		writeError(&restError{http.StatusServiceUnavailable,
+			"unavailable in spv mode"})
+		return
+	}
+	name, req, err := parseRESTRequest(strings.TrimPrefix(r.URL.Path,
+		restPathPrefix))
+	if err != nil {
+		writeError(err)
+		return
+	}
+	req.closeChan = r.Context().Done()
+	resource, ok := restHandlers[name]
+	if !ok || (resource.params != -1 && len(req.params) != resource.params) {
+		writeError(&restError{http.StatusNotFound, "unknown resource"})
+		return
+	}
+	var supported bool
+	for _, format := range resource.formats {
+		if format == req.format {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		writeError(restBadRequest("the %s resource does not support "+
+			"the requested format", name))
+		return
+	}
+
+	result, err := resource.handler(s, req)
+	if err != nil {
+		writeError(err)
+		return
+	}
+	switch req.format {
+	case restFormatBinary:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(result.([]byte))
+
+	case restFormatHex:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(hex.EncodeToString(result.([]byte)) + "\n"))
+
+	case restFormatJSON:
+		reply, err := json.Marshal(result)
+		if err != nil {
+			writeError(err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(reply, '\n'))
+	}
+}
+
+// restRateLimiter limits the rate at which each client, identified by its IP
+// address, may make REST requests with a token bucket per client.  Tokens are
+// added to the buckets at the configured rate up to a capacity of one second
+// worth of tokens, and each request takes one token.
+type restRateLimiter struct {
+	mtx       sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*restBucket
+	lastPrune time.Time
+}
+
+// restBucket is the token bucket of a client of the REST interface.
+type restBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRESTRateLimiter returns a new rate limiter that allows each client the
+// passed number of requests per second.
+func newRESTRateLimiter(requestsPerSec float64) *restRateLimiter {
+	burst := requestsPerSec
+	if burst < 1 {
+		burst = 1
+	}
+	return &restRateLimiter{
+		rate:      requestsPerSec,
+		burst:     burst,
+		buckets:   make(map[string]*restBucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// allow takes a token from the bucket of the client with the passed host and
+// returns whether or not the client may make the request.
+//
+// This function is safe for concurrent access.
+func (l *restRateLimiter) allow(host string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	// Forget about the clients that have not made any requests for a while
+	// since their buckets are full again anyways.
+	now := time.Now()
+	if now.Sub(l.lastPrune) >= restRateLimiterExpiry {
+		for host, b := range l.buckets {
+			if now.Sub(b.last) >= restRateLimiterExpiry {
+				delete(l.buckets, host)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = &restBucket{tokens: l.burst}
+		l.buckets[host] = b
+	} else {
+		b.tokens += now.Sub(b.last).Seconds() * l.rate
+		if b.tokens > l.burst {
+			b.tokens = l.burst
+		}
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,3312 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: api.proto
+
+package dcrdrpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type TransactionType int32
+
+const (
+	TransactionType_ALL        TransactionType = 0
+	TransactionType_REGULAR    TransactionType = 1
+	TransactionType_TICKET     TransactionType = 2
+	TransactionType_VOTE       TransactionType = 3
+	TransactionType_REVOCATION TransactionType = 4
+)
+
+var TransactionType_name = map[int32]string{
+	0: "ALL",
+	1: "REGULAR",
+	2: "TICKET",
+	3: "VOTE",
+	4: "REVOCATION",
+}
+
+var TransactionType_value = map[string]int32{
+	"ALL":        0,
+	"REGULAR":    1,
+	"TICKET":     2,
+	"VOTE":       3,
+	"REVOCATION": 4,
+}
+
+func (x TransactionType) String() string {
+	return proto.EnumName(TransactionType_name, int32(x))
+}
+
+func (TransactionType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+type BlockNotificationsResponse_Type int32
+
+const (
+	BlockNotificationsResponse_CONNECTED    BlockNotificationsResponse_Type = 0
+	BlockNotificationsResponse_DISCONNECTED BlockNotificationsResponse_Type = 1
+)
+
+var BlockNotificationsResponse_Type_name = map[int32]string{
+	0: "CONNECTED",
+	1: "DISCONNECTED",
+}
+
+var BlockNotificationsResponse_Type_value = map[string]int32{
+	"CONNECTED":    0,
+	"DISCONNECTED": 1,
+}
+
+func (x BlockNotificationsResponse_Type) String() string {
+	return proto.EnumName(BlockNotificationsResponse_Type_name, int32(x))
+}
+
+func (BlockNotificationsResponse_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{31, 0}
+}
+
+type VersionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionRequest) Reset()         { *m = VersionRequest{} }
+func (m *VersionRequest) String() string { return proto.CompactTextString(m) }
+func (*VersionRequest) ProtoMessage()    {}
+func (*VersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+func (m *VersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionRequest.Unmarshal(m, b)
+}
+func (m *VersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionRequest.Marshal(b, m, deterministic)
+}
+func (m *VersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionRequest.Merge(m, src)
+}
+func (m *VersionRequest) XXX_Size() int {
+	return xxx_messageInfo_VersionRequest.Size(m)
+}
+func (m *VersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionRequest proto.InternalMessageInfo
+
+type VersionResponse struct {
+	VersionString        string   `protobuf:"bytes,1,opt,name=version_string,json=versionString,proto3" json:"version_string,omitempty"`
+	Major                uint32   `protobuf:"varint,2,opt,name=major,proto3" json:"major,omitempty"`
+	Minor                uint32   `protobuf:"varint,3,opt,name=minor,proto3" json:"minor,omitempty"`
+	Patch                uint32   `protobuf:"varint,4,opt,name=patch,proto3" json:"patch,omitempty"`
+	Prerelease           string   `protobuf:"bytes,5,opt,name=prerelease,proto3" json:"prerelease,omitempty"`
+	BuildMetadata        string   `protobuf:"bytes,6,opt,name=build_metadata,json=buildMetadata,proto3" json:"build_metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VersionResponse) Reset()         { *m = VersionResponse{} }
+func (m *VersionResponse) String() string { return proto.CompactTextString(m) }
+func (*VersionResponse) ProtoMessage()    {}
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
+func (m *VersionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VersionResponse.Unmarshal(m, b)
+}
+func (m *VersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VersionResponse.Marshal(b, m, deterministic)
+}
+func (m *VersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VersionResponse.Merge(m, src)
+}
+func (m *VersionResponse) XXX_Size() int {
+	return xxx_messageInfo_VersionResponse.Size(m)
+}
+func (m *VersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VersionResponse proto.InternalMessageInfo
+
+func (m *VersionResponse) GetVersionString() string {
+	if m != nil {
+		return m.VersionString
+	}
+	return ""
+}
+
+func (m *VersionResponse) GetMajor() uint32 {
+	if m != nil {
+		return m.Major
+	}
+	return 0
+}
+
+func (m *VersionResponse) GetMinor() uint32 {
+	if m != nil {
+		return m.Minor
+	}
+	return 0
+}
+
+func (m *VersionResponse) GetPatch() uint32 {
+	if m != nil {
+		return m.Patch
+	}
+	return 0
+}
+
+func (m *VersionResponse) GetPrerelease() string {
+	if m != nil {
+		return m.Prerelease
+	}
+	return ""
+}
+
+func (m *VersionResponse) GetBuildMetadata() string {
+	if m != nil {
+		return m.BuildMetadata
+	}
+	return ""
+}
+
+type BestBlockRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BestBlockRequest) Reset()         { *m = BestBlockRequest{} }
+func (m *BestBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BestBlockRequest) ProtoMessage()    {}
+func (*BestBlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+}
+
+func (m *BestBlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BestBlockRequest.Unmarshal(m, b)
+}
+func (m *BestBlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BestBlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BestBlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BestBlockRequest.Merge(m, src)
+}
+func (m *BestBlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BestBlockRequest.Size(m)
+}
+func (m *BestBlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BestBlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BestBlockRequest proto.InternalMessageInfo
+
+type BestBlockResponse struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height               int64    `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BestBlockResponse) Reset()         { *m = BestBlockResponse{} }
+func (m *BestBlockResponse) String() string { return proto.CompactTextString(m) }
+func (*BestBlockResponse) ProtoMessage()    {}
+func (*BestBlockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+func (m *BestBlockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BestBlockResponse.Unmarshal(m, b)
+}
+func (m *BestBlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BestBlockResponse.Marshal(b, m, deterministic)
+}
+func (m *BestBlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BestBlockResponse.Merge(m, src)
+}
+func (m *BestBlockResponse) XXX_Size() int {
+	return xxx_messageInfo_BestBlockResponse.Size(m)
+}
+func (m *BestBlockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BestBlockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BestBlockResponse proto.InternalMessageInfo
+
+func (m *BestBlockResponse) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *BestBlockResponse) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type BlockchainInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockchainInfoRequest) Reset()         { *m = BlockchainInfoRequest{} }
+func (m *BlockchainInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockchainInfoRequest) ProtoMessage()    {}
+func (*BlockchainInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *BlockchainInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockchainInfoRequest.Unmarshal(m, b)
+}
+func (m *BlockchainInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockchainInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockchainInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockchainInfoRequest.Merge(m, src)
+}
+func (m *BlockchainInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockchainInfoRequest.Size(m)
+}
+func (m *BlockchainInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockchainInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockchainInfoRequest proto.InternalMessageInfo
+
+type BlockchainInfoResponse struct {
+	Chain                string   `protobuf:"bytes,1,opt,name=chain,proto3" json:"chain,omitempty"`
+	Blocks               int64    `protobuf:"varint,2,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Headers              int64    `protobuf:"varint,3,opt,name=headers,proto3" json:"headers,omitempty"`
+	SyncHeight           int64    `protobuf:"varint,4,opt,name=sync_height,json=syncHeight,proto3" json:"sync_height,omitempty"`
+	BestBlockHash        []byte   `protobuf:"bytes,5,opt,name=best_block_hash,json=bestBlockHash,proto3" json:"best_block_hash,omitempty"`
+	Difficulty           uint32   `protobuf:"varint,6,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	VerificationProgress float64  `protobuf:"fixed64,7,opt,name=verification_progress,json=verificationProgress,proto3" json:"verification_progress,omitempty"`
+	ChainWork            string   `protobuf:"bytes,8,opt,name=chain_work,json=chainWork,proto3" json:"chain_work,omitempty"`
+	InitialBlockDownload bool     `protobuf:"varint,9,opt,name=initial_block_download,json=initialBlockDownload,proto3" json:"initial_block_download,omitempty"`
+	MaxBlockSize         int64    `protobuf:"varint,10,opt,name=max_block_size,json=maxBlockSize,proto3" json:"max_block_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockchainInfoResponse) Reset()         { *m = BlockchainInfoResponse{} }
+func (m *BlockchainInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockchainInfoResponse) ProtoMessage()    {}
+func (*BlockchainInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
+func (m *BlockchainInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockchainInfoResponse.Unmarshal(m, b)
+}
+func (m *BlockchainInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockchainInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockchainInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockchainInfoResponse.Merge(m, src)
+}
+func (m *BlockchainInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockchainInfoResponse.Size(m)
+}
+func (m *BlockchainInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockchainInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockchainInfoResponse proto.InternalMessageInfo
+
+func (m *BlockchainInfoResponse) GetChain() string {
+	if m != nil {
+		return m.Chain
+	}
+	return ""
+}
+
+func (m *BlockchainInfoResponse) GetBlocks() int64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *BlockchainInfoResponse) GetHeaders() int64 {
+	if m != nil {
+		return m.Headers
+	}
+	return 0
+}
+
+func (m *BlockchainInfoResponse) GetSyncHeight() int64 {
+	if m != nil {
+		return m.SyncHeight
+	}
+	return 0
+}
+
+func (m *BlockchainInfoResponse) GetBestBlockHash() []byte {
+	if m != nil {
+		return m.BestBlockHash
+	}
+	return nil
+}
+
+func (m *BlockchainInfoResponse) GetDifficulty() uint32 {
+	if m != nil {
+		return m.Difficulty
+	}
+	return 0
+}
+
+func (m *BlockchainInfoResponse) GetVerificationProgress() float64 {
+	if m != nil {
+		return m.VerificationProgress
+	}
+	return 0
+}
+
+func (m *BlockchainInfoResponse) GetChainWork() string {
+	if m != nil {
+		return m.ChainWork
+	}
+	return ""
+}
+
+func (m *BlockchainInfoResponse) GetInitialBlockDownload() bool {
+	if m != nil {
+		return m.InitialBlockDownload
+	}
+	return false
+}
+
+func (m *BlockchainInfoResponse) GetMaxBlockSize() int64 {
+	if m != nil {
+		return m.MaxBlockSize
+	}
+	return 0
+}
+
+type BlockHashRequest struct {
+	Height               int64    `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHashRequest) Reset()         { *m = BlockHashRequest{} }
+func (m *BlockHashRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockHashRequest) ProtoMessage()    {}
+func (*BlockHashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *BlockHashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHashRequest.Unmarshal(m, b)
+}
+func (m *BlockHashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHashRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockHashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHashRequest.Merge(m, src)
+}
+func (m *BlockHashRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockHashRequest.Size(m)
+}
+func (m *BlockHashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHashRequest proto.InternalMessageInfo
+
+func (m *BlockHashRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type BlockHashResponse struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHashResponse) Reset()         { *m = BlockHashResponse{} }
+func (m *BlockHashResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockHashResponse) ProtoMessage()    {}
+func (*BlockHashResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *BlockHashResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHashResponse.Unmarshal(m, b)
+}
+func (m *BlockHashResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHashResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockHashResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHashResponse.Merge(m, src)
+}
+func (m *BlockHashResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockHashResponse.Size(m)
+}
+func (m *BlockHashResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHashResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHashResponse proto.InternalMessageInfo
+
+func (m *BlockHashResponse) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type BlockRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockRequest) Reset()         { *m = BlockRequest{} }
+func (m *BlockRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockRequest) ProtoMessage()    {}
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *BlockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockRequest.Unmarshal(m, b)
+}
+func (m *BlockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockRequest.Merge(m, src)
+}
+func (m *BlockRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockRequest.Size(m)
+}
+func (m *BlockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockRequest proto.InternalMessageInfo
+
+func (m *BlockRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type BlockResponse struct {
+	// block is the serialized block.
+	Block  []byte `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Height int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// confirmations is -1 for blocks that are not part of the main chain.
+	Confirmations        int64    `protobuf:"varint,3,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockResponse) Reset()         { *m = BlockResponse{} }
+func (m *BlockResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockResponse) ProtoMessage()    {}
+func (*BlockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *BlockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockResponse.Unmarshal(m, b)
+}
+func (m *BlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockResponse.Merge(m, src)
+}
+func (m *BlockResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockResponse.Size(m)
+}
+func (m *BlockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockResponse proto.InternalMessageInfo
+
+func (m *BlockResponse) GetBlock() []byte {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BlockResponse) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockResponse) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+type BlockHeaderRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeaderRequest) Reset()         { *m = BlockHeaderRequest{} }
+func (m *BlockHeaderRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockHeaderRequest) ProtoMessage()    {}
+func (*BlockHeaderRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *BlockHeaderRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeaderRequest.Unmarshal(m, b)
+}
+func (m *BlockHeaderRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeaderRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockHeaderRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeaderRequest.Merge(m, src)
+}
+func (m *BlockHeaderRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockHeaderRequest.Size(m)
+}
+func (m *BlockHeaderRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeaderRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeaderRequest proto.InternalMessageInfo
+
+func (m *BlockHeaderRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type BlockHeaderResponse struct {
+	// header is the serialized block header.
+	Header []byte `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Height int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// confirmations is -1 for blocks that are not part of the main chain.
+	Confirmations        int64    `protobuf:"varint,3,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeaderResponse) Reset()         { *m = BlockHeaderResponse{} }
+func (m *BlockHeaderResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockHeaderResponse) ProtoMessage()    {}
+func (*BlockHeaderResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *BlockHeaderResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeaderResponse.Unmarshal(m, b)
+}
+func (m *BlockHeaderResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeaderResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockHeaderResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeaderResponse.Merge(m, src)
+}
+func (m *BlockHeaderResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockHeaderResponse.Size(m)
+}
+func (m *BlockHeaderResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeaderResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeaderResponse proto.InternalMessageInfo
+
+func (m *BlockHeaderResponse) GetHeader() []byte {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *BlockHeaderResponse) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockHeaderResponse) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+// TransactionRequest requests a transaction of the mempool or the block chain.
+// Transactions that are not in the mempool are only available when the server
+// maintains the transaction index.
+type TransactionRequest struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionRequest) Reset()         { *m = TransactionRequest{} }
+func (m *TransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*TransactionRequest) ProtoMessage()    {}
+func (*TransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *TransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionRequest.Unmarshal(m, b)
+}
+func (m *TransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionRequest.Marshal(b, m, deterministic)
+}
+func (m *TransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionRequest.Merge(m, src)
+}
+func (m *TransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_TransactionRequest.Size(m)
+}
+func (m *TransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionRequest proto.InternalMessageInfo
+
+func (m *TransactionRequest) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type TransactionResponse struct {
+	// transaction is the serialized transaction.
+	Transaction []byte `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// The block fields are only set for mined transactions.
+	BlockHash            []byte   `protobuf:"bytes,2,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockHeight          int64    `protobuf:"varint,3,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	BlockIndex           uint32   `protobuf:"varint,4,opt,name=block_index,json=blockIndex,proto3" json:"block_index,omitempty"`
+	Confirmations        int64    `protobuf:"varint,5,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionResponse) Reset()         { *m = TransactionResponse{} }
+func (m *TransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionResponse) ProtoMessage()    {}
+func (*TransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *TransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionResponse.Unmarshal(m, b)
+}
+func (m *TransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionResponse.Marshal(b, m, deterministic)
+}
+func (m *TransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionResponse.Merge(m, src)
+}
+func (m *TransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_TransactionResponse.Size(m)
+}
+func (m *TransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionResponse proto.InternalMessageInfo
+
+func (m *TransactionResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+func (m *TransactionResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *TransactionResponse) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetBlockIndex() uint32 {
+	if m != nil {
+		return m.BlockIndex
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+type TxOutRequest struct {
+	TransactionHash      []byte   `protobuf:"bytes,1,opt,name=transaction_hash,json=transactionHash,proto3" json:"transaction_hash,omitempty"`
+	OutputIndex          uint32   `protobuf:"varint,2,opt,name=output_index,json=outputIndex,proto3" json:"output_index,omitempty"`
+	IncludeMempool       bool     `protobuf:"varint,3,opt,name=include_mempool,json=includeMempool,proto3" json:"include_mempool,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxOutRequest) Reset()         { *m = TxOutRequest{} }
+func (m *TxOutRequest) String() string { return proto.CompactTextString(m) }
+func (*TxOutRequest) ProtoMessage()    {}
+func (*TxOutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *TxOutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxOutRequest.Unmarshal(m, b)
+}
+func (m *TxOutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxOutRequest.Marshal(b, m, deterministic)
+}
+func (m *TxOutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxOutRequest.Merge(m, src)
+}
+func (m *TxOutRequest) XXX_Size() int {
+	return xxx_messageInfo_TxOutRequest.Size(m)
+}
+func (m *TxOutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxOutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxOutRequest proto.InternalMessageInfo
+
+func (m *TxOutRequest) GetTransactionHash() []byte {
+	if m != nil {
+		return m.TransactionHash
+	}
+	return nil
+}
+
+func (m *TxOutRequest) GetOutputIndex() uint32 {
+	if m != nil {
+		return m.OutputIndex
+	}
+	return 0
+}
+
+func (m *TxOutRequest) GetIncludeMempool() bool {
+	if m != nil {
+		return m.IncludeMempool
+	}
+	return false
+}
+
+type TxOutResponse struct {
+	// unspent is false when the output is spent or does not exist, in which
+	// case the remaining fields are not set.
+	Unspent              bool     `protobuf:"varint,1,opt,name=unspent,proto3" json:"unspent,omitempty"`
+	Confirmations        int64    `protobuf:"varint,2,opt,name=confirmations,proto3" json:"confirmations,omitempty"`
+	TransactionVersion   uint32   `protobuf:"varint,3,opt,name=transaction_version,json=transactionVersion,proto3" json:"transaction_version,omitempty"`
+	Amount               int64    `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	ScriptVersion        uint32   `protobuf:"varint,5,opt,name=script_version,json=scriptVersion,proto3" json:"script_version,omitempty"`
+	PkScript             []byte   `protobuf:"bytes,6,opt,name=pk_script,json=pkScript,proto3" json:"pk_script,omitempty"`
+	Coinbase             bool     `protobuf:"varint,7,opt,name=coinbase,proto3" json:"coinbase,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxOutResponse) Reset()         { *m = TxOutResponse{} }
+func (m *TxOutResponse) String() string { return proto.CompactTextString(m) }
+func (*TxOutResponse) ProtoMessage()    {}
+func (*TxOutResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *TxOutResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxOutResponse.Unmarshal(m, b)
+}
+func (m *TxOutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxOutResponse.Marshal(b, m, deterministic)
+}
+func (m *TxOutResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxOutResponse.Merge(m, src)
+}
+func (m *TxOutResponse) XXX_Size() int {
+	return xxx_messageInfo_TxOutResponse.Size(m)
+}
+func (m *TxOutResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxOutResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxOutResponse proto.InternalMessageInfo
+
+func (m *TxOutResponse) GetUnspent() bool {
+	if m != nil {
+		return m.Unspent
+	}
+	return false
+}
+
+func (m *TxOutResponse) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *TxOutResponse) GetTransactionVersion() uint32 {
+	if m != nil {
+		return m.TransactionVersion
+	}
+	return 0
+}
+
+func (m *TxOutResponse) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *TxOutResponse) GetScriptVersion() uint32 {
+	if m != nil {
+		return m.ScriptVersion
+	}
+	return 0
+}
+
+func (m *TxOutResponse) GetPkScript() []byte {
+	if m != nil {
+		return m.PkScript
+	}
+	return nil
+}
+
+func (m *TxOutResponse) GetCoinbase() bool {
+	if m != nil {
+		return m.Coinbase
+	}
+	return false
+}
+
+type MempoolInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolInfoRequest) Reset()         { *m = MempoolInfoRequest{} }
+func (m *MempoolInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*MempoolInfoRequest) ProtoMessage()    {}
+func (*MempoolInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *MempoolInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolInfoRequest.Unmarshal(m, b)
+}
+func (m *MempoolInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *MempoolInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolInfoRequest.Merge(m, src)
+}
+func (m *MempoolInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_MempoolInfoRequest.Size(m)
+}
+func (m *MempoolInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolInfoRequest proto.InternalMessageInfo
+
+type MempoolInfoResponse struct {
+	Size       int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Bytes      int64 `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	MaxMempool int64 `protobuf:"varint,3,opt,name=max_mempool,json=maxMempool,proto3" json:"max_mempool,omitempty"`
+	// min_fee is the minimum fee rate in atoms per kB transactions must pay
+	// to be accepted.
+	MinFee               int64    `protobuf:"varint,4,opt,name=min_fee,json=minFee,proto3" json:"min_fee,omitempty"`
+	Evicted              uint64   `protobuf:"varint,5,opt,name=evicted,proto3" json:"evicted,omitempty"`
+	EvictedBytes         int64    `protobuf:"varint,6,opt,name=evicted_bytes,json=evictedBytes,proto3" json:"evicted_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolInfoResponse) Reset()         { *m = MempoolInfoResponse{} }
+func (m *MempoolInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*MempoolInfoResponse) ProtoMessage()    {}
+func (*MempoolInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *MempoolInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolInfoResponse.Unmarshal(m, b)
+}
+func (m *MempoolInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *MempoolInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolInfoResponse.Merge(m, src)
+}
+func (m *MempoolInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_MempoolInfoResponse.Size(m)
+}
+func (m *MempoolInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolInfoResponse proto.InternalMessageInfo
+
+func (m *MempoolInfoResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *MempoolInfoResponse) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *MempoolInfoResponse) GetMaxMempool() int64 {
+	if m != nil {
+		return m.MaxMempool
+	}
+	return 0
+}
+
+func (m *MempoolInfoResponse) GetMinFee() int64 {
+	if m != nil {
+		return m.MinFee
+	}
+	return 0
+}
+
+func (m *MempoolInfoResponse) GetEvicted() uint64 {
+	if m != nil {
+		return m.Evicted
+	}
+	return 0
+}
+
+func (m *MempoolInfoResponse) GetEvictedBytes() int64 {
+	if m != nil {
+		return m.EvictedBytes
+	}
+	return 0
+}
+
+type MempoolTransactionsRequest struct {
+	Type                 TransactionType `protobuf:"varint,1,opt,name=type,proto3,enum=dcrdrpc.TransactionType" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MempoolTransactionsRequest) Reset()         { *m = MempoolTransactionsRequest{} }
+func (m *MempoolTransactionsRequest) String() string { return proto.CompactTextString(m) }
+func (*MempoolTransactionsRequest) ProtoMessage()    {}
+func (*MempoolTransactionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *MempoolTransactionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTransactionsRequest.Unmarshal(m, b)
+}
+func (m *MempoolTransactionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTransactionsRequest.Marshal(b, m, deterministic)
+}
+func (m *MempoolTransactionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTransactionsRequest.Merge(m, src)
+}
+func (m *MempoolTransactionsRequest) XXX_Size() int {
+	return xxx_messageInfo_MempoolTransactionsRequest.Size(m)
+}
+func (m *MempoolTransactionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTransactionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTransactionsRequest proto.InternalMessageInfo
+
+func (m *MempoolTransactionsRequest) GetType() TransactionType {
+	if m != nil {
+		return m.Type
+	}
+	return TransactionType_ALL
+}
+
+type MempoolTransactionsResponse struct {
+	Hashes               [][]byte `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTransactionsResponse) Reset()         { *m = MempoolTransactionsResponse{} }
+func (m *MempoolTransactionsResponse) String() string { return proto.CompactTextString(m) }
+func (*MempoolTransactionsResponse) ProtoMessage()    {}
+func (*MempoolTransactionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+
+func (m *MempoolTransactionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTransactionsResponse.Unmarshal(m, b)
+}
+func (m *MempoolTransactionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTransactionsResponse.Marshal(b, m, deterministic)
+}
+func (m *MempoolTransactionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTransactionsResponse.Merge(m, src)
+}
+func (m *MempoolTransactionsResponse) XXX_Size() int {
+	return xxx_messageInfo_MempoolTransactionsResponse.Size(m)
+}
+func (m *MempoolTransactionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTransactionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTransactionsResponse proto.InternalMessageInfo
+
+func (m *MempoolTransactionsResponse) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+type MiningInfoRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MiningInfoRequest) Reset()         { *m = MiningInfoRequest{} }
+func (m *MiningInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*MiningInfoRequest) ProtoMessage()    {}
+func (*MiningInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+
+func (m *MiningInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MiningInfoRequest.Unmarshal(m, b)
+}
+func (m *MiningInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MiningInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *MiningInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MiningInfoRequest.Merge(m, src)
+}
+func (m *MiningInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_MiningInfoRequest.Size(m)
+}
+func (m *MiningInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MiningInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MiningInfoRequest proto.InternalMessageInfo
+
+type MiningInfoResponse struct {
+	Blocks           int64   `protobuf:"varint,1,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	CurrentBlockSize uint64  `protobuf:"varint,2,opt,name=current_block_size,json=currentBlockSize,proto3" json:"current_block_size,omitempty"`
+	CurrentBlockTx   uint64  `protobuf:"varint,3,opt,name=current_block_tx,json=currentBlockTx,proto3" json:"current_block_tx,omitempty"`
+	Difficulty       float64 `protobuf:"fixed64,4,opt,name=difficulty,proto3" json:"difficulty,omitempty"`
+	// stake_difficulty is the ticket price in atoms.
+	StakeDifficulty      int64    `protobuf:"varint,5,opt,name=stake_difficulty,json=stakeDifficulty,proto3" json:"stake_difficulty,omitempty"`
+	Generate             bool     `protobuf:"varint,6,opt,name=generate,proto3" json:"generate,omitempty"`
+	NetworkHashPs        int64    `protobuf:"varint,7,opt,name=network_hash_ps,json=networkHashPs,proto3" json:"network_hash_ps,omitempty"`
+	PooledTx             uint64   `protobuf:"varint,8,opt,name=pooled_tx,json=pooledTx,proto3" json:"pooled_tx,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MiningInfoResponse) Reset()         { *m = MiningInfoResponse{} }
+func (m *MiningInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*MiningInfoResponse) ProtoMessage()    {}
+func (*MiningInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+
+func (m *MiningInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MiningInfoResponse.Unmarshal(m, b)
+}
+func (m *MiningInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MiningInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *MiningInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MiningInfoResponse.Merge(m, src)
+}
+func (m *MiningInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_MiningInfoResponse.Size(m)
+}
+func (m *MiningInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MiningInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MiningInfoResponse proto.InternalMessageInfo
+
+func (m *MiningInfoResponse) GetBlocks() int64 {
+	if m != nil {
+		return m.Blocks
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetCurrentBlockSize() uint64 {
+	if m != nil {
+		return m.CurrentBlockSize
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetCurrentBlockTx() uint64 {
+	if m != nil {
+		return m.CurrentBlockTx
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetDifficulty() float64 {
+	if m != nil {
+		return m.Difficulty
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetStakeDifficulty() int64 {
+	if m != nil {
+		return m.StakeDifficulty
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetGenerate() bool {
+	if m != nil {
+		return m.Generate
+	}
+	return false
+}
+
+func (m *MiningInfoResponse) GetNetworkHashPs() int64 {
+	if m != nil {
+		return m.NetworkHashPs
+	}
+	return 0
+}
+
+func (m *MiningInfoResponse) GetPooledTx() uint64 {
+	if m != nil {
+		return m.PooledTx
+	}
+	return 0
+}
+
+type StakeDifficultyRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StakeDifficultyRequest) Reset()         { *m = StakeDifficultyRequest{} }
+func (m *StakeDifficultyRequest) String() string { return proto.CompactTextString(m) }
+func (*StakeDifficultyRequest) ProtoMessage()    {}
+func (*StakeDifficultyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+
+func (m *StakeDifficultyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeDifficultyRequest.Unmarshal(m, b)
+}
+func (m *StakeDifficultyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeDifficultyRequest.Marshal(b, m, deterministic)
+}
+func (m *StakeDifficultyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeDifficultyRequest.Merge(m, src)
+}
+func (m *StakeDifficultyRequest) XXX_Size() int {
+	return xxx_messageInfo_StakeDifficultyRequest.Size(m)
+}
+func (m *StakeDifficultyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeDifficultyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeDifficultyRequest proto.InternalMessageInfo
+
+type StakeDifficultyResponse struct {
+	// The ticket prices are in atoms.
+	Current              int64    `protobuf:"varint,1,opt,name=current,proto3" json:"current,omitempty"`
+	Next                 int64    `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StakeDifficultyResponse) Reset()         { *m = StakeDifficultyResponse{} }
+func (m *StakeDifficultyResponse) String() string { return proto.CompactTextString(m) }
+func (*StakeDifficultyResponse) ProtoMessage()    {}
+func (*StakeDifficultyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *StakeDifficultyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StakeDifficultyResponse.Unmarshal(m, b)
+}
+func (m *StakeDifficultyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StakeDifficultyResponse.Marshal(b, m, deterministic)
+}
+func (m *StakeDifficultyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StakeDifficultyResponse.Merge(m, src)
+}
+func (m *StakeDifficultyResponse) XXX_Size() int {
+	return xxx_messageInfo_StakeDifficultyResponse.Size(m)
+}
+func (m *StakeDifficultyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StakeDifficultyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StakeDifficultyResponse proto.InternalMessageInfo
+
+func (m *StakeDifficultyResponse) GetCurrent() int64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *StakeDifficultyResponse) GetNext() int64 {
+	if m != nil {
+		return m.Next
+	}
+	return 0
+}
+
+type ConnectionCountRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionCountRequest) Reset()         { *m = ConnectionCountRequest{} }
+func (m *ConnectionCountRequest) String() string { return proto.CompactTextString(m) }
+func (*ConnectionCountRequest) ProtoMessage()    {}
+func (*ConnectionCountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *ConnectionCountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionCountRequest.Unmarshal(m, b)
+}
+func (m *ConnectionCountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionCountRequest.Marshal(b, m, deterministic)
+}
+func (m *ConnectionCountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionCountRequest.Merge(m, src)
+}
+func (m *ConnectionCountRequest) XXX_Size() int {
+	return xxx_messageInfo_ConnectionCountRequest.Size(m)
+}
+func (m *ConnectionCountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionCountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionCountRequest proto.InternalMessageInfo
+
+type ConnectionCountResponse struct {
+	Count                int32    `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConnectionCountResponse) Reset()         { *m = ConnectionCountResponse{} }
+func (m *ConnectionCountResponse) String() string { return proto.CompactTextString(m) }
+func (*ConnectionCountResponse) ProtoMessage()    {}
+func (*ConnectionCountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *ConnectionCountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConnectionCountResponse.Unmarshal(m, b)
+}
+func (m *ConnectionCountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConnectionCountResponse.Marshal(b, m, deterministic)
+}
+func (m *ConnectionCountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConnectionCountResponse.Merge(m, src)
+}
+func (m *ConnectionCountResponse) XXX_Size() int {
+	return xxx_messageInfo_ConnectionCountResponse.Size(m)
+}
+func (m *ConnectionCountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConnectionCountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConnectionCountResponse proto.InternalMessageInfo
+
+func (m *ConnectionCountResponse) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type PeersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeersRequest) Reset()         { *m = PeersRequest{} }
+func (m *PeersRequest) String() string { return proto.CompactTextString(m) }
+func (*PeersRequest) ProtoMessage()    {}
+func (*PeersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *PeersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersRequest.Unmarshal(m, b)
+}
+func (m *PeersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersRequest.Marshal(b, m, deterministic)
+}
+func (m *PeersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersRequest.Merge(m, src)
+}
+func (m *PeersRequest) XXX_Size() int {
+	return xxx_messageInfo_PeersRequest.Size(m)
+}
+func (m *PeersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersRequest proto.InternalMessageInfo
+
+type PeersResponse struct {
+	Peers                []*PeersResponse_Peer `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PeersResponse) Reset()         { *m = PeersResponse{} }
+func (m *PeersResponse) String() string { return proto.CompactTextString(m) }
+func (*PeersResponse) ProtoMessage()    {}
+func (*PeersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *PeersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersResponse.Unmarshal(m, b)
+}
+func (m *PeersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersResponse.Marshal(b, m, deterministic)
+}
+func (m *PeersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersResponse.Merge(m, src)
+}
+func (m *PeersResponse) XXX_Size() int {
+	return xxx_messageInfo_PeersResponse.Size(m)
+}
+func (m *PeersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersResponse proto.InternalMessageInfo
+
+func (m *PeersResponse) GetPeers() []*PeersResponse_Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type PeersResponse_Peer struct {
+	Id                   int32    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	AddrLocal            string   `protobuf:"bytes,3,opt,name=addr_local,json=addrLocal,proto3" json:"addr_local,omitempty"`
+	Services             string   `protobuf:"bytes,4,opt,name=services,proto3" json:"services,omitempty"`
+	RelayTxes            bool     `protobuf:"varint,5,opt,name=relay_txes,json=relayTxes,proto3" json:"relay_txes,omitempty"`
+	LastSend             int64    `protobuf:"varint,6,opt,name=last_send,json=lastSend,proto3" json:"last_send,omitempty"`
+	LastRecv             int64    `protobuf:"varint,7,opt,name=last_recv,json=lastRecv,proto3" json:"last_recv,omitempty"`
+	BytesSent            uint64   `protobuf:"varint,8,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	BytesRecv            uint64   `protobuf:"varint,9,opt,name=bytes_recv,json=bytesRecv,proto3" json:"bytes_recv,omitempty"`
+	ConnTime             int64    `protobuf:"varint,10,opt,name=conn_time,json=connTime,proto3" json:"conn_time,omitempty"`
+	TimeOffset           int64    `protobuf:"varint,11,opt,name=time_offset,json=timeOffset,proto3" json:"time_offset,omitempty"`
+	PingTime             float64  `protobuf:"fixed64,12,opt,name=ping_time,json=pingTime,proto3" json:"ping_time,omitempty"`
+	Version              uint32   `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
+	SubVer               string   `protobuf:"bytes,14,opt,name=sub_ver,json=subVer,proto3" json:"sub_ver,omitempty"`
+	Inbound              bool     `protobuf:"varint,15,opt,name=inbound,proto3" json:"inbound,omitempty"`
+	StartingHeight       int64    `protobuf:"varint,16,opt,name=starting_height,json=startingHeight,proto3" json:"starting_height,omitempty"`
+	CurrentHeight        int64    `protobuf:"varint,17,opt,name=current_height,json=currentHeight,proto3" json:"current_height,omitempty"`
+	BanScore             int32    `protobuf:"varint,18,opt,name=ban_score,json=banScore,proto3" json:"ban_score,omitempty"`
+	SyncNode             bool     `protobuf:"varint,19,opt,name=sync_node,json=syncNode,proto3" json:"sync_node,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeersResponse_Peer) Reset()         { *m = PeersResponse_Peer{} }
+func (m *PeersResponse_Peer) String() string { return proto.CompactTextString(m) }
+func (*PeersResponse_Peer) ProtoMessage()    {}
+func (*PeersResponse_Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27, 0}
+}
+
+func (m *PeersResponse_Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersResponse_Peer.Unmarshal(m, b)
+}
+func (m *PeersResponse_Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersResponse_Peer.Marshal(b, m, deterministic)
+}
+func (m *PeersResponse_Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersResponse_Peer.Merge(m, src)
+}
+func (m *PeersResponse_Peer) XXX_Size() int {
+	return xxx_messageInfo_PeersResponse_Peer.Size(m)
+}
+func (m *PeersResponse_Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersResponse_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersResponse_Peer proto.InternalMessageInfo
+
+func (m *PeersResponse_Peer) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *PeersResponse_Peer) GetAddrLocal() string {
+	if m != nil {
+		return m.AddrLocal
+	}
+	return ""
+}
+
+func (m *PeersResponse_Peer) GetServices() string {
+	if m != nil {
+		return m.Services
+	}
+	return ""
+}
+
+func (m *PeersResponse_Peer) GetRelayTxes() bool {
+	if m != nil {
+		return m.RelayTxes
+	}
+	return false
+}
+
+func (m *PeersResponse_Peer) GetLastSend() int64 {
+	if m != nil {
+		return m.LastSend
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetLastRecv() int64 {
+	if m != nil {
+		return m.LastRecv
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetBytesSent() uint64 {
+	if m != nil {
+		return m.BytesSent
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetBytesRecv() uint64 {
+	if m != nil {
+		return m.BytesRecv
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetConnTime() int64 {
+	if m != nil {
+		return m.ConnTime
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetTimeOffset() int64 {
+	if m != nil {
+		return m.TimeOffset
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetPingTime() float64 {
+	if m != nil {
+		return m.PingTime
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetSubVer() string {
+	if m != nil {
+		return m.SubVer
+	}
+	return ""
+}
+
+func (m *PeersResponse_Peer) GetInbound() bool {
+	if m != nil {
+		return m.Inbound
+	}
+	return false
+}
+
+func (m *PeersResponse_Peer) GetStartingHeight() int64 {
+	if m != nil {
+		return m.StartingHeight
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetCurrentHeight() int64 {
+	if m != nil {
+		return m.CurrentHeight
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetBanScore() int32 {
+	if m != nil {
+		return m.BanScore
+	}
+	return 0
+}
+
+func (m *PeersResponse_Peer) GetSyncNode() bool {
+	if m != nil {
+		return m.SyncNode
+	}
+	return false
+}
+
+type NetTotalsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetTotalsRequest) Reset()         { *m = NetTotalsRequest{} }
+func (m *NetTotalsRequest) String() string { return proto.CompactTextString(m) }
+func (*NetTotalsRequest) ProtoMessage()    {}
+func (*NetTotalsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+
+func (m *NetTotalsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetTotalsRequest.Unmarshal(m, b)
+}
+func (m *NetTotalsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetTotalsRequest.Marshal(b, m, deterministic)
+}
+func (m *NetTotalsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetTotalsRequest.Merge(m, src)
+}
+func (m *NetTotalsRequest) XXX_Size() int {
+	return xxx_messageInfo_NetTotalsRequest.Size(m)
+}
+func (m *NetTotalsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetTotalsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetTotalsRequest proto.InternalMessageInfo
+
+type NetTotalsResponse struct {
+	TotalBytesRecv       uint64   `protobuf:"varint,1,opt,name=total_bytes_recv,json=totalBytesRecv,proto3" json:"total_bytes_recv,omitempty"`
+	TotalBytesSent       uint64   `protobuf:"varint,2,opt,name=total_bytes_sent,json=totalBytesSent,proto3" json:"total_bytes_sent,omitempty"`
+	TimeMillis           int64    `protobuf:"varint,3,opt,name=time_millis,json=timeMillis,proto3" json:"time_millis,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NetTotalsResponse) Reset()         { *m = NetTotalsResponse{} }
+func (m *NetTotalsResponse) String() string { return proto.CompactTextString(m) }
+func (*NetTotalsResponse) ProtoMessage()    {}
+func (*NetTotalsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{29}
+}
+
+func (m *NetTotalsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NetTotalsResponse.Unmarshal(m, b)
+}
+func (m *NetTotalsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NetTotalsResponse.Marshal(b, m, deterministic)
+}
+func (m *NetTotalsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NetTotalsResponse.Merge(m, src)
+}
+func (m *NetTotalsResponse) XXX_Size() int {
+	return xxx_messageInfo_NetTotalsResponse.Size(m)
+}
+func (m *NetTotalsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NetTotalsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NetTotalsResponse proto.InternalMessageInfo
+
+func (m *NetTotalsResponse) GetTotalBytesRecv() uint64 {
+	if m != nil {
+		return m.TotalBytesRecv
+	}
+	return 0
+}
+
+func (m *NetTotalsResponse) GetTotalBytesSent() uint64 {
+	if m != nil {
+		return m.TotalBytesSent
+	}
+	return 0
+}
+
+func (m *NetTotalsResponse) GetTimeMillis() int64 {
+	if m != nil {
+		return m.TimeMillis
+	}
+	return 0
+}
+
+type BlockNotificationsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockNotificationsRequest) Reset()         { *m = BlockNotificationsRequest{} }
+func (m *BlockNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockNotificationsRequest) ProtoMessage()    {}
+func (*BlockNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{30}
+}
+
+func (m *BlockNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockNotificationsRequest.Unmarshal(m, b)
+}
+func (m *BlockNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockNotificationsRequest.Merge(m, src)
+}
+func (m *BlockNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockNotificationsRequest.Size(m)
+}
+func (m *BlockNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockNotificationsRequest proto.InternalMessageInfo
+
+type BlockNotificationsResponse struct {
+	Type   BlockNotificationsResponse_Type `protobuf:"varint,1,opt,name=type,proto3,enum=dcrdrpc.BlockNotificationsResponse_Type" json:"type,omitempty"`
+	Hash   []byte                          `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height int64                           `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	// header is the serialized block header.
+	Header               []byte   `protobuf:"bytes,4,opt,name=header,proto3" json:"header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockNotificationsResponse) Reset()         { *m = BlockNotificationsResponse{} }
+func (m *BlockNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockNotificationsResponse) ProtoMessage()    {}
+func (*BlockNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{31}
+}
+
+func (m *BlockNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockNotificationsResponse.Unmarshal(m, b)
+}
+func (m *BlockNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockNotificationsResponse.Merge(m, src)
+}
+func (m *BlockNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockNotificationsResponse.Size(m)
+}
+func (m *BlockNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockNotificationsResponse proto.InternalMessageInfo
+
+func (m *BlockNotificationsResponse) GetType() BlockNotificationsResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return BlockNotificationsResponse_CONNECTED
+}
+
+func (m *BlockNotificationsResponse) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *BlockNotificationsResponse) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockNotificationsResponse) GetHeader() []byte {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type ReorganizationNotificationsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReorganizationNotificationsRequest) Reset()         { *m = ReorganizationNotificationsRequest{} }
+func (m *ReorganizationNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReorganizationNotificationsRequest) ProtoMessage()    {}
+func (*ReorganizationNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{32}
+}
+
+func (m *ReorganizationNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReorganizationNotificationsRequest.Unmarshal(m, b)
+}
+func (m *ReorganizationNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReorganizationNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReorganizationNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReorganizationNotificationsRequest.Merge(m, src)
+}
+func (m *ReorganizationNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReorganizationNotificationsRequest.Size(m)
+}
+func (m *ReorganizationNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReorganizationNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReorganizationNotificationsRequest proto.InternalMessageInfo
+
+type ReorganizationNotificationsResponse struct {
+	OldHash              []byte   `protobuf:"bytes,1,opt,name=old_hash,json=oldHash,proto3" json:"old_hash,omitempty"`
+	OldHeight            int64    `protobuf:"varint,2,opt,name=old_height,json=oldHeight,proto3" json:"old_height,omitempty"`
+	NewHash              []byte   `protobuf:"bytes,3,opt,name=new_hash,json=newHash,proto3" json:"new_hash,omitempty"`
+	NewHeight            int64    `protobuf:"varint,4,opt,name=new_height,json=newHeight,proto3" json:"new_height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReorganizationNotificationsResponse) Reset()         { *m = ReorganizationNotificationsResponse{} }
+func (m *ReorganizationNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReorganizationNotificationsResponse) ProtoMessage()    {}
+func (*ReorganizationNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{33}
+}
+
+func (m *ReorganizationNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReorganizationNotificationsResponse.Unmarshal(m, b)
+}
+func (m *ReorganizationNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReorganizationNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReorganizationNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReorganizationNotificationsResponse.Merge(m, src)
+}
+func (m *ReorganizationNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReorganizationNotificationsResponse.Size(m)
+}
+func (m *ReorganizationNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReorganizationNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReorganizationNotificationsResponse proto.InternalMessageInfo
+
+func (m *ReorganizationNotificationsResponse) GetOldHash() []byte {
+	if m != nil {
+		return m.OldHash
+	}
+	return nil
+}
+
+func (m *ReorganizationNotificationsResponse) GetOldHeight() int64 {
+	if m != nil {
+		return m.OldHeight
+	}
+	return 0
+}
+
+func (m *ReorganizationNotificationsResponse) GetNewHash() []byte {
+	if m != nil {
+		return m.NewHash
+	}
+	return nil
+}
+
+func (m *ReorganizationNotificationsResponse) GetNewHeight() int64 {
+	if m != nil {
+		return m.NewHeight
+	}
+	return 0
+}
+
+type WinningTicketsNotificationsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WinningTicketsNotificationsRequest) Reset()         { *m = WinningTicketsNotificationsRequest{} }
+func (m *WinningTicketsNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*WinningTicketsNotificationsRequest) ProtoMessage()    {}
+func (*WinningTicketsNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{34}
+}
+
+func (m *WinningTicketsNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WinningTicketsNotificationsRequest.Unmarshal(m, b)
+}
+func (m *WinningTicketsNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WinningTicketsNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *WinningTicketsNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WinningTicketsNotificationsRequest.Merge(m, src)
+}
+func (m *WinningTicketsNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_WinningTicketsNotificationsRequest.Size(m)
+}
+func (m *WinningTicketsNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WinningTicketsNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WinningTicketsNotificationsRequest proto.InternalMessageInfo
+
+type WinningTicketsNotificationsResponse struct {
+	BlockHash            []byte   `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockHeight          int64    `protobuf:"varint,2,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Tickets              [][]byte `protobuf:"bytes,3,rep,name=tickets,proto3" json:"tickets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WinningTicketsNotificationsResponse) Reset()         { *m = WinningTicketsNotificationsResponse{} }
+func (m *WinningTicketsNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*WinningTicketsNotificationsResponse) ProtoMessage()    {}
+func (*WinningTicketsNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{35}
+}
+
+func (m *WinningTicketsNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WinningTicketsNotificationsResponse.Unmarshal(m, b)
+}
+func (m *WinningTicketsNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WinningTicketsNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *WinningTicketsNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WinningTicketsNotificationsResponse.Merge(m, src)
+}
+func (m *WinningTicketsNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_WinningTicketsNotificationsResponse.Size(m)
+}
+func (m *WinningTicketsNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WinningTicketsNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WinningTicketsNotificationsResponse proto.InternalMessageInfo
+
+func (m *WinningTicketsNotificationsResponse) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *WinningTicketsNotificationsResponse) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *WinningTicketsNotificationsResponse) GetTickets() [][]byte {
+	if m != nil {
+		return m.Tickets
+	}
+	return nil
+}
+
+type MempoolTransactionNotificationsRequest struct {
+	// include_transaction requests the serialized transactions in addition
+	// to their hashes.
+	IncludeTransaction   bool     `protobuf:"varint,1,opt,name=include_transaction,json=includeTransaction,proto3" json:"include_transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTransactionNotificationsRequest) Reset() {
+	*m = MempoolTransactionNotificationsRequest{}
+}
+func (m *MempoolTransactionNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*MempoolTransactionNotificationsRequest) ProtoMessage()    {}
+func (*MempoolTransactionNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{36}
+}
+
+func (m *MempoolTransactionNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTransactionNotificationsRequest.Unmarshal(m, b)
+}
+func (m *MempoolTransactionNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTransactionNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *MempoolTransactionNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTransactionNotificationsRequest.Merge(m, src)
+}
+func (m *MempoolTransactionNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_MempoolTransactionNotificationsRequest.Size(m)
+}
+func (m *MempoolTransactionNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTransactionNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTransactionNotificationsRequest proto.InternalMessageInfo
+
+func (m *MempoolTransactionNotificationsRequest) GetIncludeTransaction() bool {
+	if m != nil {
+		return m.IncludeTransaction
+	}
+	return false
+}
+
+type MempoolTransactionNotificationsResponse struct {
+	Hash                 []byte   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Transaction          []byte   `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MempoolTransactionNotificationsResponse) Reset() {
+	*m = MempoolTransactionNotificationsResponse{}
+}
+func (m *MempoolTransactionNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*MempoolTransactionNotificationsResponse) ProtoMessage()    {}
+func (*MempoolTransactionNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{37}
+}
+
+func (m *MempoolTransactionNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MempoolTransactionNotificationsResponse.Unmarshal(m, b)
+}
+func (m *MempoolTransactionNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MempoolTransactionNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *MempoolTransactionNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MempoolTransactionNotificationsResponse.Merge(m, src)
+}
+func (m *MempoolTransactionNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_MempoolTransactionNotificationsResponse.Size(m)
+}
+func (m *MempoolTransactionNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MempoolTransactionNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MempoolTransactionNotificationsResponse proto.InternalMessageInfo
+
+func (m *MempoolTransactionNotificationsResponse) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *MempoolTransactionNotificationsResponse) GetTransaction() []byte {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("dcrdrpc.TransactionType", TransactionType_name, TransactionType_value)
+	proto.RegisterEnum("dcrdrpc.BlockNotificationsResponse_Type", BlockNotificationsResponse_Type_name, BlockNotificationsResponse_Type_value)
+	proto.RegisterType((*VersionRequest)(nil), "dcrdrpc.VersionRequest")
+	proto.RegisterType((*VersionResponse)(nil), "dcrdrpc.VersionResponse")
+	proto.RegisterType((*BestBlockRequest)(nil), "dcrdrpc.BestBlockRequest")
+	proto.RegisterType((*BestBlockResponse)(nil), "dcrdrpc.BestBlockResponse")
+	proto.RegisterType((*BlockchainInfoRequest)(nil), "dcrdrpc.BlockchainInfoRequest")
+	proto.RegisterType((*BlockchainInfoResponse)(nil), "dcrdrpc.BlockchainInfoResponse")
+	proto.RegisterType((*BlockHashRequest)(nil), "dcrdrpc.BlockHashRequest")
+	proto.RegisterType((*BlockHashResponse)(nil), "dcrdrpc.BlockHashResponse")
+	proto.RegisterType((*BlockRequest)(nil), "dcrdrpc.BlockRequest")
+	proto.RegisterType((*BlockResponse)(nil), "dcrdrpc.BlockResponse")
+	proto.RegisterType((*BlockHeaderRequest)(nil), "dcrdrpc.BlockHeaderRequest")
+	proto.RegisterType((*BlockHeaderResponse)(nil), "dcrdrpc.BlockHeaderResponse")
+	proto.RegisterType((*TransactionRequest)(nil), "dcrdrpc.TransactionRequest")
+	proto.RegisterType((*TransactionResponse)(nil), "dcrdrpc.TransactionResponse")
+	proto.RegisterType((*TxOutRequest)(nil), "dcrdrpc.TxOutRequest")
+	proto.RegisterType((*TxOutResponse)(nil), "dcrdrpc.TxOutResponse")
+	proto.RegisterType((*MempoolInfoRequest)(nil), "dcrdrpc.MempoolInfoRequest")
+	proto.RegisterType((*MempoolInfoResponse)(nil), "dcrdrpc.MempoolInfoResponse")
+	proto.RegisterType((*MempoolTransactionsRequest)(nil), "dcrdrpc.MempoolTransactionsRequest")
+	proto.RegisterType((*MempoolTransactionsResponse)(nil), "dcrdrpc.MempoolTransactionsResponse")
+	proto.RegisterType((*MiningInfoRequest)(nil), "dcrdrpc.MiningInfoRequest")
+	proto.RegisterType((*MiningInfoResponse)(nil), "dcrdrpc.MiningInfoResponse")
+	proto.RegisterType((*StakeDifficultyRequest)(nil), "dcrdrpc.StakeDifficultyRequest")
+	proto.RegisterType((*StakeDifficultyResponse)(nil), "dcrdrpc.StakeDifficultyResponse")
+	proto.RegisterType((*ConnectionCountRequest)(nil), "dcrdrpc.ConnectionCountRequest")
+	proto.RegisterType((*ConnectionCountResponse)(nil), "dcrdrpc.ConnectionCountResponse")
+	proto.RegisterType((*PeersRequest)(nil), "dcrdrpc.PeersRequest")
+	proto.RegisterType((*PeersResponse)(nil), "dcrdrpc.PeersResponse")
+	proto.RegisterType((*PeersResponse_Peer)(nil), "dcrdrpc.PeersResponse.Peer")
+	proto.RegisterType((*NetTotalsRequest)(nil), "dcrdrpc.NetTotalsRequest")
+	proto.RegisterType((*NetTotalsResponse)(nil), "dcrdrpc.NetTotalsResponse")
+	proto.RegisterType((*BlockNotificationsRequest)(nil), "dcrdrpc.BlockNotificationsRequest")
+	proto.RegisterType((*BlockNotificationsResponse)(nil), "dcrdrpc.BlockNotificationsResponse")
+	proto.RegisterType((*ReorganizationNotificationsRequest)(nil), "dcrdrpc.ReorganizationNotificationsRequest")
+	proto.RegisterType((*ReorganizationNotificationsResponse)(nil), "dcrdrpc.ReorganizationNotificationsResponse")
+	proto.RegisterType((*WinningTicketsNotificationsRequest)(nil), "dcrdrpc.WinningTicketsNotificationsRequest")
+	proto.RegisterType((*WinningTicketsNotificationsResponse)(nil), "dcrdrpc.WinningTicketsNotificationsResponse")
+	proto.RegisterType((*MempoolTransactionNotificationsRequest)(nil), "dcrdrpc.MempoolTransactionNotificationsRequest")
+	proto.RegisterType((*MempoolTransactionNotificationsResponse)(nil), "dcrdrpc.MempoolTransactionNotificationsResponse")
+}
+
+func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 2095 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4f, 0x73, 0x1b, 0x49,
+	0x15, 0xdf, 0xd1, 0x1f, 0x4b, 0xf3, 0x2c, 0xc9, 0x4a, 0xdb, 0x71, 0x26, 0xe3, 0x6c, 0x62, 0xc6,
+	0xd9, 0x8d, 0x59, 0x52, 0xc9, 0x92, 0x05, 0x4e, 0x5b, 0x45, 0xc5, 0x8e, 0xd9, 0x18, 0x62, 0x3b,
+	0x8c, 0x85, 0xb7, 0xe0, 0x32, 0x8c, 0x66, 0xda, 0xf6, 0x60, 0xa9, 0x47, 0xcc, 0xb4, 0x1c, 0x39,
+	0x37, 0x0e, 0x50, 0x7c, 0x05, 0xbe, 0x03, 0x45, 0x71, 0xe4, 0xc0, 0x01, 0x4e, 0x7c, 0x0f, 0xee,
+	0x9c, 0x38, 0x00, 0x37, 0xaa, 0x5f, 0x77, 0x8f, 0x5a, 0xd2, 0xd8, 0x4e, 0xd5, 0x9e, 0xa4, 0xf7,
+	0x7b, 0xaf, 0xbb, 0xdf, 0xeb, 0xf7, 0xb7, 0x07, 0xec, 0x70, 0x94, 0x3c, 0x1b, 0x65, 0x29, 0x4f,
+	0x49, 0x23, 0x8e, 0xb2, 0x38, 0x1b, 0x45, 0x5e, 0x17, 0x3a, 0x27, 0x34, 0xcb, 0x93, 0x94, 0xf9,
+	0xf4, 0xd7, 0x63, 0x9a, 0x73, 0xef, 0xef, 0x16, 0xac, 0x14, 0x50, 0x3e, 0x4a, 0x59, 0x4e, 0xc9,
+	0x27, 0xd0, 0xb9, 0x94, 0x50, 0x90, 0xf3, 0x2c, 0x61, 0x67, 0x8e, 0xb5, 0x69, 0x6d, 0xdb, 0x7e,
+	0x5b, 0xa1, 0xc7, 0x08, 0x92, 0x35, 0xa8, 0x0f, 0xc3, 0x5f, 0xa5, 0x99, 0x53, 0xd9, 0xb4, 0xb6,
+	0xdb, 0xbe, 0x24, 0x10, 0x4d, 0x58, 0x9a, 0x39, 0x55, 0x85, 0x26, 0x4c, 0xa2, 0xa3, 0x90, 0x47,
+	0xe7, 0x4e, 0x4d, 0xa2, 0x48, 0x90, 0x87, 0x00, 0xa3, 0x8c, 0x66, 0x74, 0x40, 0xc3, 0x9c, 0x3a,
+	0x75, 0x3c, 0xc4, 0x40, 0x84, 0x22, 0xfd, 0x71, 0x32, 0x88, 0x83, 0x21, 0xe5, 0x61, 0x1c, 0xf2,
+	0xd0, 0x59, 0x92, 0x8a, 0x20, 0x7a, 0xa0, 0x40, 0x8f, 0x40, 0x77, 0x87, 0xe6, 0x7c, 0x67, 0x90,
+	0x46, 0x17, 0xda, 0xae, 0x1f, 0xc2, 0x1d, 0x03, 0x53, 0x86, 0x11, 0xa8, 0x9d, 0x87, 0xf9, 0x39,
+	0x9a, 0xd3, 0xf2, 0xf1, 0x3f, 0x59, 0x87, 0xa5, 0x73, 0x9a, 0x9c, 0x9d, 0x73, 0x34, 0xa3, 0xea,
+	0x2b, 0xca, 0xbb, 0x07, 0x77, 0x71, 0x71, 0x74, 0x1e, 0x26, 0x6c, 0x9f, 0x9d, 0xa6, 0x7a, 0xe7,
+	0xff, 0x55, 0x60, 0x7d, 0x9e, 0xa3, 0xf6, 0x5f, 0x83, 0x3a, 0x82, 0xea, 0xbe, 0x24, 0x21, 0x4e,
+	0xe8, 0x0b, 0xf9, 0x5c, 0x9f, 0x20, 0x29, 0xe2, 0x40, 0xe3, 0x9c, 0x86, 0x31, 0xcd, 0x72, 0xbc,
+	0xab, 0xaa, 0xaf, 0x49, 0xf2, 0x08, 0x96, 0xf3, 0x2b, 0x16, 0x05, 0x4a, 0xb1, 0x1a, 0x72, 0x41,
+	0x40, 0xaf, 0x11, 0x21, 0x9f, 0xc2, 0x4a, 0x9f, 0xe6, 0x3c, 0xc0, 0x9d, 0x02, 0xb4, 0xa9, 0x8e,
+	0x36, 0xb5, 0xfb, 0xda, 0xe8, 0xd7, 0xc2, 0xb8, 0x87, 0x00, 0x71, 0x72, 0x7a, 0x9a, 0x44, 0xe3,
+	0x01, 0xbf, 0xc2, 0xcb, 0x6b, 0xfb, 0x06, 0x42, 0xbe, 0x80, 0xbb, 0x97, 0x34, 0x4b, 0x4e, 0x93,
+	0x28, 0xe4, 0xc2, 0xdd, 0xa3, 0x2c, 0x3d, 0xcb, 0x68, 0x9e, 0x3b, 0x8d, 0x4d, 0x6b, 0xdb, 0xf2,
+	0xd7, 0x4c, 0xe6, 0x5b, 0xc5, 0x23, 0x1f, 0x03, 0xa0, 0x61, 0xc1, 0xbb, 0x34, 0xbb, 0x70, 0x9a,
+	0x68, 0xaa, 0x8d, 0xc8, 0xd7, 0x69, 0x76, 0x41, 0xbe, 0x07, 0xeb, 0x09, 0x4b, 0x78, 0x12, 0x0e,
+	0x94, 0x7a, 0x71, 0xfa, 0x8e, 0x0d, 0xd2, 0x30, 0x76, 0xec, 0x4d, 0x6b, 0xbb, 0xe9, 0xaf, 0x29,
+	0x2e, 0x6a, 0xf9, 0x4a, 0xf1, 0xc8, 0x63, 0xe8, 0x0c, 0xc3, 0x89, 0x5a, 0x91, 0x27, 0xef, 0xa9,
+	0x03, 0x68, 0x75, 0x6b, 0x18, 0x4e, 0x50, 0xf2, 0x38, 0x79, 0x4f, 0xbd, 0xcf, 0xa0, 0x5b, 0x18,
+	0xa7, 0xfc, 0x61, 0x38, 0xd0, 0x9a, 0x71, 0xe0, 0x13, 0xb8, 0x63, 0xc8, 0x5e, 0x1f, 0x01, 0x9e,
+	0x07, 0x2d, 0x33, 0x74, 0x4a, 0x65, 0x22, 0x68, 0xcf, 0x86, 0xd2, 0x1a, 0xd4, 0x51, 0x57, 0x25,
+	0x25, 0x89, 0xeb, 0x82, 0x89, 0x3c, 0x86, 0x76, 0x94, 0xb2, 0xd3, 0x24, 0x1b, 0xe2, 0x55, 0x6a,
+	0x87, 0xcf, 0x82, 0xde, 0x36, 0x10, 0xa9, 0x31, 0x86, 0xc1, 0x4d, 0xea, 0x5c, 0xc0, 0xea, 0x8c,
+	0xa4, 0x52, 0x0a, 0x8f, 0x17, 0x88, 0x12, 0x56, 0xd4, 0x37, 0x57, 0xab, 0x97, 0x85, 0x2c, 0x0f,
+	0x23, 0x3e, 0x2d, 0x1c, 0xa5, 0x6a, 0xfd, 0xcd, 0x82, 0xd5, 0x19, 0x51, 0xa5, 0xd7, 0x26, 0x2c,
+	0xf3, 0x29, 0xac, 0x96, 0x98, 0x90, 0x88, 0x29, 0x23, 0x96, 0x2b, 0x28, 0x60, 0xf7, 0x8b, 0x38,
+	0xfe, 0x16, 0xb4, 0x14, 0x5b, 0x9a, 0x21, 0xf5, 0x5c, 0x96, 0x02, 0xd2, 0x96, 0x47, 0x20, 0xc9,
+	0x20, 0x61, 0x31, 0x9d, 0xa8, 0x3a, 0x23, 0x37, 0xdd, 0x17, 0xc8, 0xa2, 0xb1, 0xf5, 0x32, 0x63,
+	0x7f, 0x6b, 0x41, 0xab, 0x37, 0x39, 0x1a, 0x73, 0x6d, 0xe7, 0xb7, 0xa1, 0x6b, 0x28, 0x1a, 0x18,
+	0x36, 0xaf, 0x18, 0xb8, 0xd6, 0x32, 0x1d, 0xf3, 0xd1, 0x98, 0x2b, 0x1d, 0x64, 0x5d, 0x5c, 0x96,
+	0x98, 0x54, 0xe2, 0x09, 0xac, 0x24, 0x2c, 0x1a, 0x8c, 0x63, 0x1a, 0x0c, 0xe9, 0x70, 0x94, 0xa6,
+	0x03, 0xb4, 0xa5, 0xe9, 0x77, 0x14, 0x7c, 0x20, 0x51, 0xef, 0xbf, 0x16, 0xb4, 0x95, 0x1e, 0xea,
+	0x12, 0x1d, 0x68, 0x8c, 0x59, 0x3e, 0xa2, 0x4c, 0x06, 0x7a, 0xd3, 0xd7, 0xe4, 0xa2, 0x65, 0x95,
+	0x12, 0xcb, 0xc8, 0x73, 0x58, 0x35, 0x0d, 0x51, 0xb5, 0x5c, 0x95, 0x69, 0x62, 0xb0, 0x54, 0x3b,
+	0x10, 0x51, 0x13, 0x0e, 0xd3, 0x31, 0xd3, 0x05, 0x48, 0x51, 0xa2, 0x2a, 0xe7, 0x51, 0x96, 0x8c,
+	0x78, 0xb1, 0x47, 0x1d, 0xf7, 0x68, 0x4b, 0x54, 0x2f, 0xdf, 0x00, 0x7b, 0x74, 0x11, 0x48, 0x0c,
+	0x4b, 0x4f, 0xcb, 0x6f, 0x8e, 0x2e, 0x8e, 0x91, 0x26, 0x2e, 0x34, 0xa3, 0x34, 0x61, 0x7d, 0x51,
+	0xf7, 0x1b, 0x68, 0x4d, 0x41, 0x7b, 0x6b, 0x40, 0xd4, 0x2d, 0x98, 0x65, 0xf7, 0x2f, 0x16, 0xac,
+	0xce, 0xc0, 0xd3, 0x8c, 0xc6, 0x72, 0x21, 0x93, 0x1f, 0xff, 0x63, 0x72, 0x5e, 0x71, 0xaa, 0x2f,
+	0x42, 0x12, 0x22, 0x42, 0x44, 0x89, 0x31, 0xef, 0xbd, 0xea, 0xc3, 0x30, 0x9c, 0xa8, 0x6d, 0xc9,
+	0x3d, 0x68, 0x0c, 0x13, 0x16, 0x9c, 0x52, 0xaa, 0x2d, 0x1e, 0x26, 0xec, 0x47, 0x14, 0xaf, 0x9e,
+	0x5e, 0x26, 0x11, 0xa7, 0x31, 0x9a, 0x5a, 0xf3, 0x35, 0x49, 0xb6, 0xa0, 0xad, 0xfe, 0x06, 0xf2,
+	0xc4, 0x25, 0x59, 0xb5, 0x14, 0xb8, 0x23, 0x30, 0xef, 0xc7, 0xe0, 0xaa, 0x23, 0x8c, 0xe4, 0xc8,
+	0x75, 0x80, 0x3d, 0x85, 0x1a, 0xbf, 0x1a, 0x49, 0x03, 0x3a, 0x2f, 0x9c, 0x67, 0xaa, 0x57, 0x3f,
+	0x33, 0x64, 0x7b, 0x57, 0x23, 0xea, 0xa3, 0x94, 0xf7, 0x7d, 0xd8, 0x28, 0xdd, 0xcb, 0xa8, 0x00,
+	0x61, 0x7e, 0x4e, 0x73, 0xc7, 0xda, 0xac, 0x62, 0x05, 0x40, 0xca, 0x5b, 0x85, 0x3b, 0x07, 0x09,
+	0x4b, 0xd8, 0x99, 0x79, 0xa5, 0x7f, 0xae, 0x00, 0x31, 0xd1, 0xe9, 0x1e, 0xaa, 0x5f, 0x59, 0x33,
+	0xfd, 0xea, 0x29, 0x90, 0x68, 0x9c, 0x65, 0x94, 0x71, 0xb3, 0x4c, 0x57, 0xf0, 0x42, 0xba, 0x8a,
+	0x53, 0x94, 0x6a, 0xb2, 0x0d, 0xdd, 0x59, 0x69, 0x3e, 0xc1, 0x2b, 0xaf, 0xf9, 0x1d, 0x53, 0xb6,
+	0x37, 0x99, 0x6b, 0x52, 0x35, 0xec, 0x3c, 0x06, 0x22, 0x32, 0x30, 0xe7, 0xe1, 0x05, 0x0d, 0x0c,
+	0x29, 0x99, 0xbb, 0x2b, 0x88, 0xbf, 0x9a, 0x8a, 0xba, 0xd0, 0x3c, 0xa3, 0x8c, 0x66, 0x21, 0xa7,
+	0xe8, 0x89, 0xa6, 0x5f, 0xd0, 0xa2, 0x67, 0x32, 0xca, 0x45, 0xcf, 0xc2, 0x24, 0x0e, 0x46, 0xb2,
+	0xcb, 0x55, 0xfd, 0xb6, 0x82, 0x45, 0x0e, 0xbf, 0xcd, 0x31, 0x6e, 0xd3, 0x74, 0x40, 0x63, 0xa1,
+	0x71, 0x13, 0x35, 0x6e, 0x4a, 0xa0, 0x37, 0xf1, 0x1c, 0x58, 0x3f, 0x9e, 0x3d, 0x53, 0x5f, 0xe6,
+	0x57, 0x70, 0x6f, 0x81, 0x33, 0xcd, 0x5c, 0x65, 0xb2, 0xba, 0x51, 0x4d, 0x8a, 0xe0, 0x65, 0x74,
+	0xa2, 0xcb, 0x32, 0xfe, 0x17, 0x47, 0xec, 0xa6, 0x8c, 0x51, 0xf4, 0xec, 0xae, 0xc8, 0x38, 0x7d,
+	0xc4, 0x73, 0xb8, 0xb7, 0xc0, 0x31, 0x26, 0x8f, 0x74, 0xac, 0x0e, 0xa8, 0xfb, 0x92, 0xf0, 0x3a,
+	0xd0, 0x7a, 0x4b, 0x69, 0xa6, 0x43, 0xcd, 0xfb, 0x77, 0x0d, 0xda, 0x0a, 0x50, 0xeb, 0xbe, 0x0b,
+	0xf5, 0x91, 0x00, 0x30, 0x5c, 0x96, 0x5f, 0x6c, 0x14, 0xd1, 0x37, 0x23, 0x86, 0x94, 0x2f, 0x25,
+	0xdd, 0x3f, 0xd5, 0xa0, 0x26, 0x68, 0xd2, 0x81, 0x4a, 0x12, 0xab, 0x03, 0x2b, 0x49, 0x2c, 0x8c,
+	0x09, 0xe3, 0x58, 0x8e, 0x83, 0xb6, 0x8f, 0xff, 0x45, 0x5d, 0x17, 0xbf, 0xc1, 0x20, 0x8d, 0x42,
+	0x99, 0x72, 0xb6, 0x6f, 0x0b, 0xe4, 0x8d, 0x00, 0x84, 0xbf, 0x72, 0x9a, 0x5d, 0x26, 0x11, 0xcd,
+	0xd1, 0xf1, 0xb6, 0x5f, 0xd0, 0x62, 0x69, 0x46, 0x07, 0xe1, 0x55, 0xc0, 0x27, 0x54, 0x16, 0xeb,
+	0xa6, 0x6f, 0x23, 0xd2, 0x9b, 0x50, 0x74, 0xd3, 0x20, 0xcc, 0x79, 0x90, 0x53, 0x16, 0xab, 0xac,
+	0x6b, 0x0a, 0xe0, 0x98, 0xb2, 0xb8, 0x60, 0x66, 0x34, 0xba, 0x74, 0x1a, 0x53, 0xa6, 0x4f, 0xa3,
+	0x4b, 0xec, 0x35, 0x22, 0x2f, 0xc5, 0x52, 0xae, 0x3c, 0x6c, 0x23, 0x72, 0x2c, 0x7c, 0x52, 0xb0,
+	0x71, 0xb1, 0x6d, 0xb0, 0x71, 0xf5, 0x06, 0xd8, 0x51, 0xca, 0x58, 0xc0, 0x93, 0xa1, 0x9e, 0x51,
+	0x9a, 0x02, 0xe8, 0x25, 0x43, 0x2a, 0x4a, 0x8c, 0xc0, 0x83, 0xf4, 0xf4, 0x34, 0xa7, 0xdc, 0x59,
+	0x46, 0x36, 0x08, 0xe8, 0x08, 0x11, 0x0c, 0xae, 0x84, 0x9d, 0xc9, 0xd5, 0x2d, 0x0c, 0xf5, 0xa6,
+	0x00, 0x70, 0xb5, 0x03, 0x0d, 0x5d, 0x51, 0xdb, 0x58, 0x51, 0x35, 0x29, 0x2a, 0x53, 0x3e, 0xee,
+	0x8b, 0x7a, 0xeb, 0x74, 0xf0, 0x9a, 0x96, 0xf2, 0x71, 0xff, 0x84, 0x66, 0x62, 0x49, 0xc2, 0xfa,
+	0xe9, 0x98, 0xc5, 0xce, 0x8a, 0x6c, 0x0a, 0x8a, 0x14, 0x9d, 0x26, 0xe7, 0x61, 0xc6, 0xc5, 0x69,
+	0xaa, 0x6b, 0x76, 0x51, 0x9d, 0x8e, 0x86, 0x55, 0xe3, 0xfc, 0x04, 0x74, 0x42, 0x6a, 0xb9, 0x3b,
+	0xaa, 0x7d, 0x48, 0x54, 0x89, 0x6d, 0x80, 0xdd, 0x0f, 0x59, 0x90, 0x47, 0x69, 0x46, 0x1d, 0x82,
+	0x4e, 0x6f, 0xf6, 0x43, 0x76, 0x2c, 0x68, 0xc1, 0xc4, 0x81, 0x95, 0xa5, 0x31, 0x75, 0x56, 0x65,
+	0xe2, 0x09, 0xe0, 0x30, 0x8d, 0xa9, 0x18, 0xcf, 0x0f, 0x29, 0xef, 0xa5, 0x3c, 0x1c, 0x14, 0x91,
+	0xf8, 0x7b, 0x0b, 0xee, 0x18, 0xa0, 0x8a, 0xc6, 0x6d, 0xe8, 0x72, 0x81, 0x04, 0x86, 0x03, 0x2c,
+	0x59, 0x33, 0x10, 0xdf, 0x29, 0xbc, 0x30, 0x27, 0x89, 0x9e, 0xac, 0xcc, 0x4b, 0xa2, 0x3b, 0xb5,
+	0x4b, 0x86, 0xc9, 0x60, 0x90, 0xe8, 0x09, 0x07, 0x5d, 0x72, 0x80, 0x88, 0xb7, 0x01, 0xf7, 0xb1,
+	0x12, 0x1d, 0xa6, 0xbc, 0x98, 0x75, 0x0b, 0x3d, 0xff, 0x61, 0x81, 0x5b, 0xc6, 0x55, 0x0a, 0x7f,
+	0x39, 0x53, 0xbb, 0xb7, 0x8b, 0xec, 0xb9, 0x7e, 0xc9, 0xb3, 0x69, 0x2d, 0x2f, 0x46, 0xa8, 0x4a,
+	0xe9, 0x73, 0xa4, 0x3a, 0x33, 0xaa, 0x4d, 0x47, 0xbb, 0x9a, 0x39, 0xda, 0x79, 0x4f, 0xa0, 0x26,
+	0x76, 0x24, 0x6d, 0xb0, 0x77, 0x8f, 0x0e, 0x0f, 0xf7, 0x76, 0x7b, 0x7b, 0xaf, 0xba, 0x1f, 0x91,
+	0x2e, 0xb4, 0x5e, 0xed, 0x1f, 0x4f, 0x11, 0xcb, 0x7b, 0x0c, 0x9e, 0x4f, 0xd3, 0xec, 0x2c, 0x64,
+	0xc9, 0x7b, 0x54, 0xa9, 0xd4, 0xde, 0x3f, 0x58, 0xb0, 0x75, 0xa3, 0x98, 0x32, 0xfc, 0x3e, 0x34,
+	0xd3, 0x41, 0x6c, 0x4e, 0x43, 0x8d, 0x74, 0x10, 0xe3, 0x14, 0xf4, 0x31, 0x00, 0xb2, 0xcc, 0x81,
+	0xd3, 0x16, 0x4c, 0x69, 0xc8, 0x7d, 0x68, 0x32, 0xfa, 0x4e, 0xae, 0xac, 0xca, 0x95, 0x8c, 0xbe,
+	0xd3, 0x2b, 0x91, 0x65, 0xbe, 0x7a, 0x6c, 0xc1, 0x44, 0x40, 0x58, 0xf0, 0x75, 0xc2, 0x18, 0x66,
+	0x4b, 0x74, 0x41, 0x79, 0x5e, 0x6a, 0xc1, 0x6f, 0x2c, 0xd8, 0xba, 0x51, 0x4c, 0x59, 0x30, 0x3b,
+	0x71, 0x5a, 0xb7, 0x4d, 0x9c, 0x95, 0xc5, 0x89, 0xd3, 0x81, 0x06, 0x97, 0x27, 0x38, 0x55, 0x6c,
+	0xb6, 0x9a, 0xf4, 0x7e, 0x0e, 0x9f, 0x2e, 0x36, 0xe9, 0x32, 0x6d, 0xc5, 0x50, 0xa6, 0xe7, 0xc1,
+	0xf9, 0x09, 0xb9, 0xe9, 0x13, 0xc5, 0x32, 0x36, 0xf1, 0x02, 0x78, 0x72, 0xeb, 0xd6, 0x37, 0xbc,
+	0x76, 0xe7, 0x26, 0xf1, 0xca, 0xc2, 0x24, 0xfe, 0xd9, 0x01, 0xac, 0xcc, 0x4d, 0x1e, 0xa4, 0x01,
+	0xd5, 0x97, 0x6f, 0xde, 0x74, 0x3f, 0x22, 0xcb, 0xd0, 0xf0, 0xf7, 0xbe, 0xfa, 0xd9, 0x9b, 0x97,
+	0x7e, 0xd7, 0x22, 0x00, 0x4b, 0xbd, 0xfd, 0xdd, 0x9f, 0xec, 0xf5, 0xba, 0x15, 0xd2, 0x84, 0xda,
+	0xc9, 0x51, 0x6f, 0xaf, 0x5b, 0x25, 0x1d, 0x00, 0x7f, 0xef, 0xe4, 0x68, 0xf7, 0x65, 0x6f, 0xff,
+	0xe8, 0xb0, 0x5b, 0x7b, 0x71, 0x58, 0x7c, 0x71, 0x38, 0x96, 0x85, 0x9d, 0x7c, 0x09, 0x8d, 0x13,
+	0x5d, 0xd6, 0x8a, 0x84, 0x99, 0xfd, 0x2a, 0xe1, 0x3a, 0x8b, 0x0c, 0x69, 0xd4, 0x8b, 0x7f, 0x55,
+	0xa1, 0xb5, 0x2b, 0xde, 0x9a, 0x7a, 0xbb, 0x1d, 0xb0, 0x8b, 0x87, 0x3e, 0xb9, 0x3f, 0xcd, 0xc0,
+	0xb9, 0x0f, 0x02, 0xae, 0x5b, 0xc6, 0x52, 0x37, 0xf5, 0x53, 0xe8, 0xcc, 0xbe, 0xe8, 0xc9, 0xc3,
+	0xd9, 0x54, 0x9e, 0xff, 0x08, 0xe0, 0x3e, 0xba, 0x96, 0xaf, 0xb6, 0x14, 0x6a, 0x15, 0xc1, 0x74,
+	0x7f, 0x56, 0xda, 0x78, 0xbd, 0xba, 0x6e, 0x19, 0x4b, 0xed, 0xf1, 0x03, 0xa8, 0x4b, 0xb3, 0xee,
+	0xce, 0x0a, 0xe9, 0xb5, 0xeb, 0xf3, 0xb0, 0x5a, 0xf7, 0x1a, 0x96, 0x8d, 0xd7, 0x21, 0xd9, 0x98,
+	0x3b, 0xc2, 0x7c, 0x5d, 0xba, 0x0f, 0xca, 0x99, 0xd3, 0x9d, 0x8c, 0x60, 0x30, 0x76, 0x5a, 0x7c,
+	0x10, 0xba, 0x0f, 0xca, 0x99, 0x53, 0x5b, 0xf0, 0x39, 0x63, 0xd8, 0x62, 0x3e, 0xb3, 0xdc, 0xf5,
+	0x79, 0x58, 0xf9, 0xfb, 0xaf, 0x16, 0x74, 0x54, 0xc0, 0x6b, 0x8f, 0xbf, 0x86, 0x65, 0xe3, 0x21,
+	0x60, 0x28, 0xb5, 0xf8, 0x6a, 0x70, 0x1f, 0x94, 0x33, 0x95, 0x52, 0xbf, 0x2c, 0x9e, 0x14, 0x86,
+	0xca, 0x39, 0xd9, 0x9a, 0x5f, 0x54, 0x32, 0xb6, 0xbb, 0x8f, 0x6f, 0x16, 0x52, 0xea, 0xff, 0xd1,
+	0x82, 0xb6, 0x1c, 0xb1, 0xb5, 0xf6, 0x7b, 0x00, 0xd3, 0x99, 0x9b, 0x4c, 0xdd, 0xbf, 0x30, 0x9e,
+	0xbb, 0x1b, 0xa5, 0x3c, 0xa5, 0x7a, 0x0f, 0x56, 0xe6, 0xc6, 0x4d, 0x32, 0x8d, 0xc9, 0xf2, 0x11,
+	0xd5, 0xdd, 0xbc, 0x5e, 0x40, 0xa9, 0xfb, 0x4f, 0x0b, 0x3a, 0x87, 0x72, 0x1a, 0xd6, 0xfa, 0xf6,
+	0x60, 0x65, 0x6e, 0xe8, 0x34, 0x0e, 0x2a, 0x1f, 0x54, 0xdd, 0xcd, 0xeb, 0x05, 0xa6, 0xe1, 0x80,
+	0x13, 0xa6, 0x11, 0x0e, 0xe6, 0xa4, 0xea, 0xae, 0xcf, 0xc3, 0xd3, 0xb4, 0x2a, 0xc6, 0x06, 0x23,
+	0xad, 0xe6, 0xe7, 0x0b, 0xd7, 0x2d, 0x63, 0x29, 0x23, 0xff, 0x53, 0x85, 0x55, 0xb3, 0x62, 0x6a,
+	0x4b, 0x03, 0xf5, 0xf9, 0xc5, 0xe4, 0xe5, 0xc4, 0xbb, 0xb1, 0xa9, 0xcb, 0xd3, 0xb6, 0x3e, 0xa0,
+	0xf1, 0x7f, 0x6e, 0x91, 0xf7, 0xb0, 0x71, 0x43, 0x6f, 0x25, 0xdf, 0x29, 0x76, 0xb9, 0xbd, 0x51,
+	0xbb, 0x4f, 0x3f, 0x4c, 0xd8, 0x3c, 0xfb, 0x86, 0xae, 0x68, 0x9c, 0x7d, 0x7b, 0x8b, 0x75, 0x9f,
+	0x7e, 0x98, 0x70, 0x71, 0xf6, 0xef, 0x2c, 0x78, 0x74, 0x4b, 0xd3, 0x22, 0xcf, 0x6f, 0x48, 0xa7,
+	0x52, 0x25, 0x3e, 0xff, 0xf0, 0x05, 0x5a, 0x91, 0x1d, 0xfb, 0x17, 0xfa, 0x4b, 0x78, 0x7f, 0x09,
+	0xbf, 0x8c, 0x7f, 0xf1, 0xff, 0x01, 0x00, 0x6b, 0x09, 0x5a, 0xeb, 0x26, 0x17, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// VersionServiceClient is the client API for VersionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type VersionServiceClient interface {
+	Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+}
+
+type versionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewVersionServiceClient(cc *grpc.ClientConn) VersionServiceClient {
+	return &versionServiceClient{cc}
+}
+
+func (c *versionServiceClient) Version(ctx context.Context, in *VersionRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.VersionService/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VersionServiceServer is the server API for VersionService service.
+type VersionServiceServer interface {
+	Version(context.Context, *VersionRequest) (*VersionResponse, error)
+}
+
+// UnimplementedVersionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedVersionServiceServer struct {
+}
+
+func (*UnimplementedVersionServiceServer) Version(ctx context.Context, req *VersionRequest) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+
+func RegisterVersionServiceServer(s *grpc.Server, srv VersionServiceServer) {
+	s.RegisterService(&_VersionService_serviceDesc, srv)
+}
+
+func _VersionService_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VersionServiceServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.VersionService/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VersionServiceServer).Version(ctx, req.(*VersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _VersionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.VersionService",
+	HandlerType: (*VersionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Version",
+			Handler:    _VersionService_Version_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// ChainServiceClient is the client API for ChainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChainServiceClient interface {
+	BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*BestBlockResponse, error)
+	BlockchainInfo(ctx context.Context, in *BlockchainInfoRequest, opts ...grpc.CallOption) (*BlockchainInfoResponse, error)
+	BlockHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockHashResponse, error)
+	Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	BlockHeader(ctx context.Context, in *BlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeaderResponse, error)
+	Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	TxOut(ctx context.Context, in *TxOutRequest, opts ...grpc.CallOption) (*TxOutResponse, error)
+}
+
+type chainServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChainServiceClient(cc *grpc.ClientConn) ChainServiceClient {
+	return &chainServiceClient{cc}
+}
+
+func (c *chainServiceClient) BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*BestBlockResponse, error) {
+	out := new(BestBlockResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockchainInfo(ctx context.Context, in *BlockchainInfoRequest, opts ...grpc.CallOption) (*BlockchainInfoResponse, error) {
+	out := new(BlockchainInfoResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BlockchainInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockHashResponse, error) {
+	out := new(BlockHashResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BlockHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/Block", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockHeader(ctx context.Context, in *BlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeaderResponse, error) {
+	out := new(BlockHeaderResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BlockHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/Transaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) TxOut(ctx context.Context, in *TxOutRequest, opts ...grpc.CallOption) (*TxOutResponse, error) {
+	out := new(TxOutResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/TxOut", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChainServiceServer is the server API for ChainService service.
+type ChainServiceServer interface {
+	BestBlock(context.Context, *BestBlockRequest) (*BestBlockResponse, error)
+	BlockchainInfo(context.Context, *BlockchainInfoRequest) (*BlockchainInfoResponse, error)
+	BlockHash(context.Context, *BlockHashRequest) (*BlockHashResponse, error)
+	Block(context.Context, *BlockRequest) (*BlockResponse, error)
+	BlockHeader(context.Context, *BlockHeaderRequest) (*BlockHeaderResponse, error)
+	Transaction(context.Context, *TransactionRequest) (*TransactionResponse, error)
+	TxOut(context.Context, *TxOutRequest) (*TxOutResponse, error)
+}
+
+// UnimplementedChainServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedChainServiceServer struct {
+}
+
+func (*UnimplementedChainServiceServer) BestBlock(ctx context.Context, req *BestBlockRequest) (*BestBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BestBlock not implemented")
+}
+func (*UnimplementedChainServiceServer) BlockchainInfo(ctx context.Context, req *BlockchainInfoRequest) (*BlockchainInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockchainInfo not implemented")
+}
+func (*UnimplementedChainServiceServer) BlockHash(ctx context.Context, req *BlockHashRequest) (*BlockHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockHash not implemented")
+}
+func (*UnimplementedChainServiceServer) Block(ctx context.Context, req *BlockRequest) (*BlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Block not implemented")
+}
+func (*UnimplementedChainServiceServer) BlockHeader(ctx context.Context, req *BlockHeaderRequest) (*BlockHeaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockHeader not implemented")
+}
+func (*UnimplementedChainServiceServer) Transaction(ctx context.Context, req *TransactionRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transaction not implemented")
+}
+func (*UnimplementedChainServiceServer) TxOut(ctx context.Context, req *TxOutRequest) (*TxOutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxOut not implemented")
+}
+
+func RegisterChainServiceServer(s *grpc.Server, srv ChainServiceServer) {
+	s.RegisterService(&_ChainService_serviceDesc, srv)
+}
+
+func _ChainService_BestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BestBlock(ctx, req.(*BestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockchainInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockchainInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BlockchainInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BlockchainInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BlockchainInfo(ctx, req.(*BlockchainInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BlockHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BlockHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BlockHash(ctx, req.(*BlockHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_Block_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).Block(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/Block",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).Block(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BlockHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BlockHeader(ctx, req.(*BlockHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_Transaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).Transaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/Transaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).Transaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_TxOut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxOutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).TxOut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/TxOut",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).TxOut(ctx, req.(*TxOutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ChainService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.ChainService",
+	HandlerType: (*ChainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BestBlock",
+			Handler:    _ChainService_BestBlock_Handler,
+		},
+		{
+			MethodName: "BlockchainInfo",
+			Handler:    _ChainService_BlockchainInfo_Handler,
+		},
+		{
+			MethodName: "BlockHash",
+			Handler:    _ChainService_BlockHash_Handler,
+		},
+		{
+			MethodName: "Block",
+			Handler:    _ChainService_Block_Handler,
+		},
+		{
+			MethodName: "BlockHeader",
+			Handler:    _ChainService_BlockHeader_Handler,
+		},
+		{
+			MethodName: "Transaction",
+			Handler:    _ChainService_Transaction_Handler,
+		},
+		{
+			MethodName: "TxOut",
+			Handler:    _ChainService_TxOut_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// MempoolServiceClient is the client API for MempoolService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MempoolServiceClient interface {
+	MempoolInfo(ctx context.Context, in *MempoolInfoRequest, opts ...grpc.CallOption) (*MempoolInfoResponse, error)
+	MempoolTransactions(ctx context.Context, in *MempoolTransactionsRequest, opts ...grpc.CallOption) (*MempoolTransactionsResponse, error)
+}
+
+type mempoolServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMempoolServiceClient(cc *grpc.ClientConn) MempoolServiceClient {
+	return &mempoolServiceClient{cc}
+}
+
+func (c *mempoolServiceClient) MempoolInfo(ctx context.Context, in *MempoolInfoRequest, opts ...grpc.CallOption) (*MempoolInfoResponse, error) {
+	out := new(MempoolInfoResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.MempoolService/MempoolInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mempoolServiceClient) MempoolTransactions(ctx context.Context, in *MempoolTransactionsRequest, opts ...grpc.CallOption) (*MempoolTransactionsResponse, error) {
+	out := new(MempoolTransactionsResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.MempoolService/MempoolTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MempoolServiceServer is the server API for MempoolService service.
+type MempoolServiceServer interface {
+	MempoolInfo(context.Context, *MempoolInfoRequest) (*MempoolInfoResponse, error)
+	MempoolTransactions(context.Context, *MempoolTransactionsRequest) (*MempoolTransactionsResponse, error)
+}
+
+// UnimplementedMempoolServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMempoolServiceServer struct {
+}
+
+func (*UnimplementedMempoolServiceServer) MempoolInfo(ctx context.Context, req *MempoolInfoRequest) (*MempoolInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MempoolInfo not implemented")
+}
+func (*UnimplementedMempoolServiceServer) MempoolTransactions(ctx context.Context, req *MempoolTransactionsRequest) (*MempoolTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MempoolTransactions not implemented")
+}
+
+func RegisterMempoolServiceServer(s *grpc.Server, srv MempoolServiceServer) {
+	s.RegisterService(&_MempoolService_serviceDesc, srv)
+}
+
+func _MempoolService_MempoolInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MempoolServiceServer).MempoolInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.MempoolService/MempoolInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MempoolServiceServer).MempoolInfo(ctx, req.(*MempoolInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MempoolService_MempoolTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MempoolServiceServer).MempoolTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.MempoolService/MempoolTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MempoolServiceServer).MempoolTransactions(ctx, req.(*MempoolTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MempoolService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.MempoolService",
+	HandlerType: (*MempoolServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MempoolInfo",
+			Handler:    _MempoolService_MempoolInfo_Handler,
+		},
+		{
+			MethodName: "MempoolTransactions",
+			Handler:    _MempoolService_MempoolTransactions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// MiningServiceClient is the client API for MiningService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type MiningServiceClient interface {
+	MiningInfo(ctx context.Context, in *MiningInfoRequest, opts ...grpc.CallOption) (*MiningInfoResponse, error)
+	StakeDifficulty(ctx context.Context, in *StakeDifficultyRequest, opts ...grpc.CallOption) (*StakeDifficultyResponse, error)
+}
+
+type miningServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewMiningServiceClient(cc *grpc.ClientConn) MiningServiceClient {
+	return &miningServiceClient{cc}
+}
+
+func (c *miningServiceClient) MiningInfo(ctx context.Context, in *MiningInfoRequest, opts ...grpc.CallOption) (*MiningInfoResponse, error) {
+	out := new(MiningInfoResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.MiningService/MiningInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *miningServiceClient) StakeDifficulty(ctx context.Context, in *StakeDifficultyRequest, opts ...grpc.CallOption) (*StakeDifficultyResponse, error) {
+	out := new(StakeDifficultyResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.MiningService/StakeDifficulty", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MiningServiceServer is the server API for MiningService service.
+type MiningServiceServer interface {
+	MiningInfo(context.Context, *MiningInfoRequest) (*MiningInfoResponse, error)
+	StakeDifficulty(context.Context, *StakeDifficultyRequest) (*StakeDifficultyResponse, error)
+}
+
+// UnimplementedMiningServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMiningServiceServer struct {
+}
+
+func (*UnimplementedMiningServiceServer) MiningInfo(ctx context.Context, req *MiningInfoRequest) (*MiningInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MiningInfo not implemented")
+}
+func (*UnimplementedMiningServiceServer) StakeDifficulty(ctx context.Context, req *StakeDifficultyRequest) (*StakeDifficultyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StakeDifficulty not implemented")
+}
+
+func RegisterMiningServiceServer(s *grpc.Server, srv MiningServiceServer) {
+	s.RegisterService(&_MiningService_serviceDesc, srv)
+}
+
+func _MiningService_MiningInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MiningInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiningServiceServer).MiningInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.MiningService/MiningInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiningServiceServer).MiningInfo(ctx, req.(*MiningInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MiningService_StakeDifficulty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StakeDifficultyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MiningServiceServer).StakeDifficulty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.MiningService/StakeDifficulty",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MiningServiceServer).StakeDifficulty(ctx, req.(*StakeDifficultyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _MiningService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.MiningService",
+	HandlerType: (*MiningServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MiningInfo",
+			Handler:    _MiningService_MiningInfo_Handler,
+		},
+		{
+			MethodName: "StakeDifficulty",
+			Handler:    _MiningService_StakeDifficulty_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// NetworkServiceClient is the client API for NetworkService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NetworkServiceClient interface {
+	ConnectionCount(ctx context.Context, in *ConnectionCountRequest, opts ...grpc.CallOption) (*ConnectionCountResponse, error)
+	Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+	NetTotals(ctx context.Context, in *NetTotalsRequest, opts ...grpc.CallOption) (*NetTotalsResponse, error)
+}
+
+type networkServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNetworkServiceClient(cc *grpc.ClientConn) NetworkServiceClient {
+	return &networkServiceClient{cc}
+}
+
+func (c *networkServiceClient) ConnectionCount(ctx context.Context, in *ConnectionCountRequest, opts ...grpc.CallOption) (*ConnectionCountResponse, error) {
+	out := new(ConnectionCountResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.NetworkService/ConnectionCount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.NetworkService/Peers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *networkServiceClient) NetTotals(ctx context.Context, in *NetTotalsRequest, opts ...grpc.CallOption) (*NetTotalsResponse, error) {
+	out := new(NetTotalsResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.NetworkService/NetTotals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NetworkServiceServer is the server API for NetworkService service.
+type NetworkServiceServer interface {
+	ConnectionCount(context.Context, *ConnectionCountRequest) (*ConnectionCountResponse, error)
+	Peers(context.Context, *PeersRequest) (*PeersResponse, error)
+	NetTotals(context.Context, *NetTotalsRequest) (*NetTotalsResponse, error)
+}
+
+// UnimplementedNetworkServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNetworkServiceServer struct {
+}
+
+func (*UnimplementedNetworkServiceServer) ConnectionCount(ctx context.Context, req *ConnectionCountRequest) (*ConnectionCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectionCount not implemented")
+}
+func (*UnimplementedNetworkServiceServer) Peers(ctx context.Context, req *PeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Peers not implemented")
+}
+func (*UnimplementedNetworkServiceServer) NetTotals(ctx context.Context, req *NetTotalsRequest) (*NetTotalsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetTotals not implemented")
+}
+
+func RegisterNetworkServiceServer(s *grpc.Server, srv NetworkServiceServer) {
+	s.RegisterService(&_NetworkService_serviceDesc, srv)
+}
+
+func _NetworkService_ConnectionCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).ConnectionCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.NetworkService/ConnectionCount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).ConnectionCount(ctx, req.(*ConnectionCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).Peers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.NetworkService/Peers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).Peers(ctx, req.(*PeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NetworkService_NetTotals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetTotalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetworkServiceServer).NetTotals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.NetworkService/NetTotals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetworkServiceServer).NetTotals(ctx, req.(*NetTotalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NetworkService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.NetworkService",
+	HandlerType: (*NetworkServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ConnectionCount",
+			Handler:    _NetworkService_ConnectionCount_Handler,
+		},
+		{
+			MethodName: "Peers",
+			Handler:    _NetworkService_Peers_Handler,
+		},
+		{
+			MethodName: "NetTotals",
+			Handler:    _NetworkService_NetTotals_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api.proto",
+}
+
+// NotificationServiceClient is the client API for NotificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationServiceClient interface {
+	BlockNotifications(ctx context.Context, in *BlockNotificationsRequest, opts ...grpc.CallOption) (NotificationService_BlockNotificationsClient, error)
+	ReorganizationNotifications(ctx context.Context, in *ReorganizationNotificationsRequest, opts ...grpc.CallOption) (NotificationService_ReorganizationNotificationsClient, error)
+	WinningTicketsNotifications(ctx context.Context, in *WinningTicketsNotificationsRequest, opts ...grpc.CallOption) (NotificationService_WinningTicketsNotificationsClient, error)
+	MempoolTransactionNotifications(ctx context.Context, in *MempoolTransactionNotificationsRequest, opts ...grpc.CallOption) (NotificationService_MempoolTransactionNotificationsClient, error)
+}
+
+type notificationServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNotificationServiceClient(cc *grpc.ClientConn) NotificationServiceClient {
+	return &notificationServiceClient{cc}
+}
+
+func (c *notificationServiceClient) BlockNotifications(ctx context.Context, in *BlockNotificationsRequest, opts ...grpc.CallOption) (NotificationService_BlockNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NotificationService_serviceDesc.Streams[0], "/dcrdrpc.NotificationService/BlockNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notificationServiceBlockNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NotificationService_BlockNotificationsClient interface {
+	Recv() (*BlockNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type notificationServiceBlockNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *notificationServiceBlockNotificationsClient) Recv() (*BlockNotificationsResponse, error) {
+	m := new(BlockNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *notificationServiceClient) ReorganizationNotifications(ctx context.Context, in *ReorganizationNotificationsRequest, opts ...grpc.CallOption) (NotificationService_ReorganizationNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NotificationService_serviceDesc.Streams[1], "/dcrdrpc.NotificationService/ReorganizationNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notificationServiceReorganizationNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NotificationService_ReorganizationNotificationsClient interface {
+	Recv() (*ReorganizationNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type notificationServiceReorganizationNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *notificationServiceReorganizationNotificationsClient) Recv() (*ReorganizationNotificationsResponse, error) {
+	m := new(ReorganizationNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *notificationServiceClient) WinningTicketsNotifications(ctx context.Context, in *WinningTicketsNotificationsRequest, opts ...grpc.CallOption) (NotificationService_WinningTicketsNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NotificationService_serviceDesc.Streams[2], "/dcrdrpc.NotificationService/WinningTicketsNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notificationServiceWinningTicketsNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NotificationService_WinningTicketsNotificationsClient interface {
+	Recv() (*WinningTicketsNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type notificationServiceWinningTicketsNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *notificationServiceWinningTicketsNotificationsClient) Recv() (*WinningTicketsNotificationsResponse, error) {
+	m := new(WinningTicketsNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *notificationServiceClient) MempoolTransactionNotifications(ctx context.Context, in *MempoolTransactionNotificationsRequest, opts ...grpc.CallOption) (NotificationService_MempoolTransactionNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_NotificationService_serviceDesc.Streams[3], "/dcrdrpc.NotificationService/MempoolTransactionNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &notificationServiceMempoolTransactionNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type NotificationService_MempoolTransactionNotificationsClient interface {
+	Recv() (*MempoolTransactionNotificationsResponse, error)
+	grpc.ClientStream
+}
+
+type notificationServiceMempoolTransactionNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *notificationServiceMempoolTransactionNotificationsClient) Recv() (*MempoolTransactionNotificationsResponse, error) {
+	m := new(MempoolTransactionNotificationsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NotificationServiceServer is the server API for NotificationService service.
+type NotificationServiceServer interface {
+	BlockNotifications(*BlockNotificationsRequest, NotificationService_BlockNotificationsServer) error
+	ReorganizationNotifications(*ReorganizationNotificationsRequest, NotificationService_ReorganizationNotificationsServer) error
+	WinningTicketsNotifications(*WinningTicketsNotificationsRequest, NotificationService_WinningTicketsNotificationsServer) error
+	MempoolTransactionNotifications(*MempoolTransactionNotificationsRequest, NotificationService_MempoolTransactionNotificationsServer) error
+}
+
+// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNotificationServiceServer struct {
+}
+
+func (*UnimplementedNotificationServiceServer) BlockNotifications(req *BlockNotificationsRequest, srv NotificationService_BlockNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method BlockNotifications not implemented")
+}
+func (*UnimplementedNotificationServiceServer) ReorganizationNotifications(req *ReorganizationNotificationsRequest, srv NotificationService_ReorganizationNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReorganizationNotifications not implemented")
+}
+func (*UnimplementedNotificationServiceServer) WinningTicketsNotifications(req *WinningTicketsNotificationsRequest, srv NotificationService_WinningTicketsNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WinningTicketsNotifications not implemented")
+}
+func (*UnimplementedNotificationServiceServer) MempoolTransactionNotifications(req *MempoolTransactionNotificationsRequest, srv NotificationService_MempoolTransactionNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method MempoolTransactionNotifications not implemented")
+}
+
+func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
+	s.RegisterService(&_NotificationService_serviceDesc, srv)
+}
+
+func _NotificationService_BlockNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlockNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).BlockNotifications(m, &notificationServiceBlockNotificationsServer{stream})
+}
+
+type NotificationService_BlockNotificationsServer interface {
+	Send(*BlockNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type notificationServiceBlockNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *notificationServiceBlockNotificationsServer) Send(m *BlockNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _NotificationService_ReorganizationNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReorganizationNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).ReorganizationNotifications(m, &notificationServiceReorganizationNotificationsServer{stream})
+}
+
+type NotificationService_ReorganizationNotificationsServer interface {
+	Send(*ReorganizationNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type notificationServiceReorganizationNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *notificationServiceReorganizationNotificationsServer) Send(m *ReorganizationNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _NotificationService_WinningTicketsNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WinningTicketsNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).WinningTicketsNotifications(m, &notificationServiceWinningTicketsNotificationsServer{stream})
+}
+
+type NotificationService_WinningTicketsNotificationsServer interface {
+	Send(*WinningTicketsNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type notificationServiceWinningTicketsNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *notificationServiceWinningTicketsNotificationsServer) Send(m *WinningTicketsNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _NotificationService_MempoolTransactionNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MempoolTransactionNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotificationServiceServer).MempoolTransactionNotifications(m, &notificationServiceMempoolTransactionNotificationsServer{stream})
+}
+
+type NotificationService_MempoolTransactionNotificationsServer interface {
+	Send(*MempoolTransactionNotificationsResponse) error
+	grpc.ServerStream
+}
+
+type notificationServiceMempoolTransactionNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *notificationServiceMempoolTransactionNotificationsServer) Send(m *MempoolTransactionNotificationsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _NotificationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BlockNotifications",
+			Handler:       _NotificationService_BlockNotifications_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ReorganizationNotifications",
+			Handler:       _NotificationService_ReorganizationNotifications_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WinningTicketsNotifications",
+			Handler:       _NotificationService_WinningTicketsNotifications_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MempoolTransactionNotifications",
+			Handler:       _NotificationService_MempoolTransactionNotifications_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package dcrdrpc provides the gRPC API of dcrd.
+
+The API is defined in api.proto and is served alongside the JSON-RPC API when
+dcrd is started with the --grpclisten option.  It uses the TLS certificate and
+the RPC users of the JSON-RPC server.  Clients authenticate with basic access
+authentication in the authorization metadata of each call or with a TLS client
+certificate.  The methods of VersionService do not require authentication.
+
+The API is versioned with semantic versioning, which VersionService reports, so
+clients are able to detect incompatible changes.
+
+Hashes are the raw bytes of the hash in internal byte order, which is the reverse
+of the byte order of the hex strings used by the JSON-RPC API.
+
+The Go bindings in api.pb.go are generated with protoc and protoc-gen-go and
+must be regenerated after modifying api.proto.
+*/
+package dcrdrpc
+
+//go:generate protoc -I. api.proto --go_out=plugins=grpc:.
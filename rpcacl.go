@@ -0,0 +1,250 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// rpcACLWildcard is the name in an allow or deny list of an RPC user that
+// matches every RPC method or websocket notification.
+const rpcACLWildcard = "*"
+
+// rpcACLList houses the names of the RPC methods or websocket notifications an
+// RPC user is explicitly allowed and denied.  Denied names take precedence over
+// allowed names.
+type rpcACLList struct {
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+// permits returns whether or not the list permits the passed name.
+func (l *rpcACLList) permits(name string) bool {
+	if _, ok := l.deny[name]; ok {
+		return false
+	}
+	if _, ok := l.deny[rpcACLWildcard]; ok {
+		return false
+	}
+	if _, ok := l.allow[name]; ok {
+		return true
+	}
+	_, ok := l.allow[rpcACLWildcard]
+	return ok
+}
+
+// add adds the comma-separated names of the passed value to the allow list or
+// the deny list.
+func (l *rpcACLList) add(value string, deny bool) {
+	list := &l.allow
+	if deny {
+		list = &l.deny
+	}
+	if *list == nil {
+		*list = make(map[string]struct{})
+	}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" {
+			(*list)[name] = struct{}{}
+		}
+	}
+}
+
+// rpcACL describes the RPC methods an RPC user is authorized to call and the
+// websocket notifications it is authorized to receive.
+type rpcACL struct {
+	methods       rpcACLList
+	notifications rpcACLList
+}
+
+// permitsMethod returns whether or not the ACL permits calling the passed RPC
+// method.  A nil ACL, which belongs to a client that has not authenticated,
+// permits nothing.
+func (a *rpcACL) permitsMethod(method string) bool {
+	return a != nil && a.methods.permits(method)
+}
+
+// permitsNotification returns whether or not the ACL permits receiving the
+// websocket notification with the passed method.  A nil ACL, which belongs to a
+// client that has not authenticated, permits nothing.
+func (a *rpcACL) permitsNotification(method string) bool {
+	return a != nil && a.notifications.permits(method)
+}
+
+var (
+	// rpcAllowAll is an allow list that permits everything.
+	rpcAllowAll = rpcACLList{
+		allow: map[string]struct{}{rpcACLWildcard: {}},
+	}
+
+	// rpcAdminACL is the ACL of the admin user configured with rpcuser and
+	// rpcpass, which is authorized for everything.
+	rpcAdminACL = &rpcACL{
+		methods:       rpcAllowAll,
+		notifications: rpcAllowAll,
+	}
+
+	// rpcLimitedACL is the ACL of the limited user configured with
+	// rpclimituser and rpclimitpass, which is only authorized for the
+	// methods in rpcLimited.
+	rpcLimitedACL = &rpcACL{
+		methods:       rpcACLList{allow: rpcLimited},
+		notifications: rpcAllowAll,
+	}
+)
+
+// rpcACLUser describes an RPC user configured in the RPC Users section of the
+// configuration.  Users authenticate either with a password or with a TLS
+// client certificate.
+type rpcACLUser struct {
+	name string
+
+	// passSalt is the salt the password of the user is hashed with and
+	// passHMAC is the HMAC-SHA256 of the password keyed by the salt.  The
+	// HMAC is nil for users that authenticate with a client certificate.
+	passSalt string
+	passHMAC []byte
+
+	// certCommonName is the common name of the client certificate of the
+	// user.  It is empty for users that authenticate with a password.
+	certCommonName string
+
+	acl rpcACL
+}
+
+// checkPassword returns whether or not the passed password is the password of
+// the user.  Users that authenticate with a client certificate have no
+// password.
+//
+// This check is time-constant.
+func (u *rpcACLUser) checkPassword(password string) bool {
+	if u.passHMAC == nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(u.passSalt))
+	mac.Write([]byte(password))
+	return subtle.ConstantTimeCompare(mac.Sum(nil), u.passHMAC) == 1
+}
+
+// rpcACLUsers houses the RPC users configured in the RPC Users section of the
+// configuration by the name of the password users and the certificate common
+// name of the certificate users.
+type rpcACLUsers struct {
+	byName       map[string]*rpcACLUser
+	byCommonName map[string]*rpcACLUser
+}
+
+// splitRPCACLOption splits the passed RPC user option of the form
+// <name>:<value> into the name and the value.
+func splitRPCACLOption(option string) (string, string, bool) {
+	parts := strings.SplitN(option, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// parseRPCACLUsers parses the users and their allow and deny lists from the
+// passed RPC Users options.
+func parseRPCACLUsers(opts *rpcUsersOptions) (*rpcACLUsers, error) {
+	users := &rpcACLUsers{
+		byName:       make(map[string]*rpcACLUser),
+		byCommonName: make(map[string]*rpcACLUser),
+	}
+	addUser := func(user *rpcACLUser) error {
+		if _, ok := users.byName[user.name]; ok {
+			return fmt.Errorf("the RPC user %q is defined more "+
+				"than once", user.name)
+		}
+		users.byName[user.name] = user
+		return nil
+	}
+
+	for _, option := range opts.Users {
+		name, auth, ok := splitRPCACLOption(option)
+		var salt, passHMACStr string
+		if ok {
+			parts := strings.SplitN(auth, "$", 2)
+			ok = len(parts) == 2 && parts[0] != ""
+			if ok {
+				salt, passHMACStr = parts[0], parts[1]
+			}
+		}
+		passHMAC, err := hex.DecodeString(passHMACStr)
+		if !ok || err != nil || len(passHMAC) != sha256.Size {
+			return nil, fmt.Errorf("the rpcacluser option must be a "+
+				"name, a salt and the hex-encoded HMAC-SHA256 of "+
+				"the password keyed by the salt in the form "+
+				"<name>:<salt>$<passwordhmac> -- parsed [%s]",
+				option)
+		}
+		user := &rpcACLUser{name: name, passSalt: salt,
+			passHMAC: passHMAC}
+		if err := addUser(user); err != nil {
+			return nil, err
+		}
+	}
+	for _, option := range opts.CertUsers {
+		name, commonName, ok := splitRPCACLOption(option)
+		if !ok {
+			return nil, fmt.Errorf("the rpcaclcertuser option must "+
+				"be a name and a certificate common name in the "+
+				"form <name>:<commonname> -- parsed [%s]", option)
+		}
+		if _, ok := users.byCommonName[commonName]; ok {
+			return nil, fmt.Errorf("the certificate common name "+
+				"%q is used by more than one RPC user",
+				commonName)
+		}
+		user := &rpcACLUser{name: name, certCommonName: commonName}
+		if err := addUser(user); err != nil {
+			return nil, err
+		}
+		users.byCommonName[commonName] = user
+	}
+
+	// Parse the allow and deny lists of the users.
+	lists := []struct {
+		optionName    string
+		options       []string
+		notifications bool
+		deny          bool
+	}{
+		{"rpcaclallow", opts.Allow, false, false},
+		{"rpcacldeny", opts.Deny, false, true},
+		{"rpcaclallowntfn", opts.AllowNtfns, true, false},
+		{"rpcacldenyntfn", opts.DenyNtfns, true, true},
+	}
+	for _, list := range lists {
+		for _, option := range list.options {
+			name, value, ok := splitRPCACLOption(option)
+			if !ok {
+				return nil, fmt.Errorf("the %s option must be "+
+					"a user name and a comma-separated list "+
+					"in the form <name>:<list> -- parsed [%s]",
+					list.optionName, option)
+			}
+			user, ok := users.byName[name]
+			if !ok {
+				return nil, fmt.Errorf("the %s option refers to "+
+					"the undefined RPC user %q",
+					list.optionName, name)
+			}
+			if list.notifications {
+				user.acl.notifications.add(value, list.deny)
+			} else {
+				user.acl.methods.add(value, list.deny)
+			}
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+)
+
+// testRPCACLUser is an RPC user option for the user explorer with the password
+// "password" and the salt c0e54e4c2a32a4d9a9b1f1e1c1d0c8f2.
+const testRPCACLUser = "explorer:c0e54e4c2a32a4d9a9b1f1e1c1d0c8f2$" +
+	"e33e2e79db0a62080e7cb2d92c6f4a108a6123a37bde87857213a8b07da8fb45"
+
+// TestParseRPCACLUsers ensures the RPC Users options are parsed into users with
+// the expected passwords and permissions, and that invalid options are
+// rejected.
+func TestParseRPCACLUsers(t *testing.T) {
+	users, err := parseRPCACLUsers(&rpcUsersOptions{
+		Users:     []string{testRPCACLUser},
+		CertUsers: []string{"monitor:monitor.example.com"},
+		Allow: []string{
+			"explorer:getblock, GetBlockHash",
+			"explorer:getbestblock,stop",
+			"monitor:*",
+		},
+		Deny: []string{
+			"explorer:stop",
+			"monitor:stop,node",
+		},
+		AllowNtfns: []string{"explorer:*"},
+		DenyNtfns:  []string{"monitor:*", "explorer:txaccepted"},
+	})
+	if err != nil {
+		t.Fatalf("unable to parse RPC users: %v", err)
+	}
+
+	explorer := users.byName["explorer"]
+	if explorer == nil {
+		t.Fatal("missing password user")
+	}
+	if !explorer.checkPassword("password") {
+		t.Fatal("password user rejected its password")
+	}
+	for _, password := range []string{"", "Password", "password "} {
+		if explorer.checkPassword(password) {
+			t.Fatalf("password user accepted password %q", password)
+		}
+	}
+	monitor := users.byCommonName["monitor.example.com"]
+	if monitor == nil || users.byName["monitor"] != monitor {
+		t.Fatal("missing certificate user")
+	}
+	if monitor.checkPassword("") {
+		t.Fatal("certificate user accepted a password")
+	}
+
+	tests := []struct {
+		name         string
+		user         *rpcACLUser
+		method       string
+		notification bool
+		permits      bool
+	}{
+		// Explicitly allowed names are permitted regardless of case and
+		// whitespace in the lists.
+		{"allowed", explorer, "getblock", false, true},
+		{"allowed mixed case", explorer, "getblockhash", false, true},
+		{"allowed later option", explorer, "getbestblock", false, true},
+		{"not allowed", explorer, "getinfo", false, false},
+
+		// Denied names take precedence over allowed ones, including the
+		// wildcard.
+		{"deny beats allow", explorer, "stop", false, false},
+		{"deny beats wildcard", monitor, "node", false, false},
+		{"allowed by wildcard", monitor, "getpeerinfo", false, true},
+
+		// Notifications are permitted separately from methods.
+		{"notification wildcard", explorer, "blockconnected", true, true},
+		{"notification denied", explorer, "txaccepted", true, false},
+		{"notification deny wildcard", monitor, "blockconnected", true,
+			false},
+	}
+	for _, test := range tests {
+		var permits bool
+		if test.notification {
+			permits = test.user.acl.permitsNotification(test.method)
+		} else {
+			permits = test.user.acl.permitsMethod(test.method)
+		}
+		if permits != test.permits {
+			t.Errorf("%s: permits %s: got %v, want %v", test.name,
+				test.method, permits, test.permits)
+		}
+	}
+
+	// A nil ACL belongs to clients that did not authenticate and permits
+	// nothing.
+	var acl *rpcACL
+	if acl.permitsMethod("getblock") || acl.permitsNotification("blockconnected") {
+		t.Fatal("nil ACL permits a method or notification")
+	}
+
+	invalid := []struct {
+		name string
+		opts rpcUsersOptions
+	}{
+		{"missing salt", rpcUsersOptions{
+			Users: []string{"explorer:e33e2e79db0a62080e7cb2d92c6f4a108a6123a37bde87857213a8b07da8fb45"},
+		}},
+		{"empty salt", rpcUsersOptions{
+			Users: []string{"explorer:$e33e2e79db0a62080e7cb2d92c6f4a108a6123a37bde87857213a8b07da8fb45"},
+		}},
+		{"short hmac", rpcUsersOptions{
+			Users: []string{"explorer:salt$e33e2e79"},
+		}},
+		{"non-hex hmac", rpcUsersOptions{
+			Users: []string{"explorer:salt$password"},
+		}},
+		{"missing name", rpcUsersOptions{
+			Users: []string{":c0e54e4c2a32a4d9a9b1f1e1c1d0c8f2$e33e2e79db0a62080e7cb2d92c6f4a108a6123a37bde87857213a8b07da8fb45"},
+		}},
+		{"duplicate password user", rpcUsersOptions{
+			Users: []string{testRPCACLUser, testRPCACLUser},
+		}},
+		{"duplicate password and certificate user", rpcUsersOptions{
+			Users:     []string{testRPCACLUser},
+			CertUsers: []string{"explorer:explorer.example.com"},
+		}},
+		{"duplicate common name", rpcUsersOptions{
+			CertUsers: []string{
+				"monitor:monitor.example.com",
+				"backup:monitor.example.com",
+			},
+		}},
+		{"unknown user in allow list", rpcUsersOptions{
+			Users: []string{testRPCACLUser},
+			Allow: []string{"mallory:*"},
+		}},
+		{"unknown user in deny list", rpcUsersOptions{
+			Users: []string{testRPCACLUser},
+			Deny:  []string{"mallory:stop"},
+		}},
+		{"unknown user in notification allow list", rpcUsersOptions{
+			AllowNtfns: []string{"mallory:*"},
+		}},
+		{"unknown user in notification deny list", rpcUsersOptions{
+			DenyNtfns: []string{"mallory:*"},
+		}},
+		{"malformed allow list", rpcUsersOptions{
+			Users: []string{testRPCACLUser},
+			Allow: []string{"explorer"},
+		}},
+	}
+	for _, test := range invalid {
+		if _, err := parseRPCACLUsers(&test.opts); err == nil {
+			t.Errorf("%s: parsed invalid options", test.name)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// defaultRPCCookieFilename is the name of the file in the data
+	// directory the RPC authentication cookie is written to by default.
+	defaultRPCCookieFilename = ".cookie"
+
+	// rpcCookieUser is the username of the credentials in the RPC
+	// authentication cookie.
+	rpcCookieUser = "__cookie__"
+
+	// rpcCookieSecretSize is the number of random bytes of the password of
+	// the credentials in the RPC authentication cookie.
+	rpcCookieSecretSize = 32
+)
+
+// genRPCCookie returns random credentials for the RPC authentication cookie.
+func genRPCCookie() (user, pass string, err error) {
+	secret := make([]byte, rpcCookieSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	return rpcCookieUser, hex.EncodeToString(secret), nil
+}
+
+// writeRPCCookie writes the passed credentials to the cookie file at the passed
+// path in the form <user>:<pass>.  The file is only readable by the current
+// user and is replaced atomically so clients never read a partial cookie.
+func writeRPCCookie(path, user, pass string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath)
+	err = ioutil.WriteFile(tmpPath, []byte(user+":"+pass), 0600)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// removeRPCCookie removes the cookie file at the passed path.  It is not an
+// error when the file does not exist.
+func removeRPCCookie(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"crypto/subtle"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -31,6 +32,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/blockchain/indexers"
 	"github.com/decred/dcrd/blockchain/stake"
 	"github.com/decred/dcrd/certgen"
 	"github.com/decred/dcrd/chaincfg"
@@ -149,6 +151,14 @@ var (
 		Message: "This implementation does not implement wallet commands",
 	}
 
+	// ErrRPCNoChainData is an error returned to RPC clients when the
+	// provided command requires block chain data that is not kept when the
+	// node runs as a light client.
+	ErrRPCNoChainData = &dcrjson.RPCError{
+		Code:    dcrjson.ErrRPCMisc,
+		Message: "Command unavailable in spv mode",
+	}
+
 	// ErrInvalidLongPoll is an internal error code to indicate that
 	// longpollid is not formated properly.
 	ErrInvalidLongPoll = errors.New("invalid longpollid format")
@@ -162,12 +172,14 @@ type commandHandler func(*rpcServer, interface{}, <-chan struct{}) (interface{},
 var rpcHandlers map[string]commandHandler
 var rpcHandlersBeforeInit = map[string]commandHandler{
 	"addnode":               handleAddNode,
+	"clearbanned":           handleClearBanned,
 	"createrawsstx":         handleCreateRawSStx,
 	"createrawssrtx":        handleCreateRawSSRtx,
 	"createrawtransaction":  handleCreateRawTransaction,
 	"debuglevel":            handleDebugLevel,
 	"decoderawtransaction":  handleDecodeRawTransaction,
 	"decodescript":          handleDecodeScript,
+	"dumputxosnapshot":      handleDumpUtxoSnapshot,
 	"estimatefee":           handleEstimateFee,
 	"estimatesmartfee":      handleEstimateSmartFee,
 	"estimatestakediff":     handleEstimateStakeDiff,
@@ -188,6 +200,7 @@ var rpcHandlersBeforeInit = map[string]commandHandler{
 	"getblockhash":          handleGetBlockHash,
 	"getblockheader":        handleGetBlockHeader,
 	"getblocksubsidy":       handleGetBlockSubsidy,
+	"getblocktemplate":      handleGetBlockTemplate,
 	"getchaintips":          handleGetChainTips,
 	"getcoinsupply":         handleGetCoinSupply,
 	"getconnectioncount":    handleGetConnectionCount,
@@ -198,6 +211,7 @@ var rpcHandlersBeforeInit = map[string]commandHandler{
 	"getcfilter":            handleGetCFilter,
 	"getcfilterheader":      handleGetCFilterHeader,
 	"getheaders":            handleGetHeaders,
+	"getindexinfo":          handleGetIndexInfo,
 	"getinfo":               handleGetInfo,
 	"getmempoolinfo":        handleGetMempoolInfo,
 	"getmininginfo":         handleGetMiningInfo,
@@ -212,16 +226,21 @@ var rpcHandlersBeforeInit = map[string]commandHandler{
 	"getticketpoolvalue":    handleGetTicketPoolValue,
 	"getvoteinfo":           handleGetVoteInfo,
 	"gettxout":              handleGetTxOut,
+	"gettxoutsetinfo":       handleGetTxOutSetInfo,
 	"getwork":               handleGetWork,
 	"help":                  handleHelp,
+	"listbanned":            handleListBanned,
 	"livetickets":           handleLiveTickets,
+	"loadmempool":           handleLoadMempool,
 	"missedtickets":         handleMissedTickets,
 	"node":                  handleNode,
 	"ping":                  handlePing,
 	"searchrawtransactions": handleSearchRawTransactions,
 	"rebroadcastmissed":     handleRebroadcastMissed,
 	"rebroadcastwinners":    handleRebroadcastWinners,
+	"savemempool":           handleSaveMempool,
 	"sendrawtransaction":    handleSendRawTransaction,
+	"setban":                handleSetBan,
 	"setgenerate":           handleSetGenerate,
 	"stop":                  handleStop,
 	"submitblock":           handleSubmitBlock,
@@ -258,7 +277,6 @@ var rpcAskWallet = map[string]struct{}{
 	"getstakeinfo":            {},
 	"getvotechoices":          {},
 	"gettransaction":          {},
-	"gettxoutsetinfo":         {},
 	"getunconfirmedbalance":   {},
 	"importprivkey":           {},
 	"keypoolrefill":           {},
@@ -291,7 +309,6 @@ var rpcAskWallet = map[string]struct{}{
 // Commands that are currently unimplemented, but should ultimately be.
 var rpcUnimplemented = map[string]struct{}{
 	"estimatepriority": {},
-	"getblocktemplate": {},
 	"getnetworkinfo":   {},
 }
 
@@ -321,12 +338,14 @@ var rpcLimited = map[string]struct{}{
 	"getchaintips":          {},
 	"getcurrentnet":         {},
 	"getdifficulty":         {},
+	"getindexinfo":          {},
 	"getinfo":               {},
 	"getnettotals":          {},
 	"getnetworkhashps":      {},
 	"getrawmempool":         {},
 	"getrawtransaction":     {},
 	"gettxout":              {},
+	"gettxoutsetinfo":       {},
 	"searchrawtransactions": {},
 	"sendrawtransaction":    {},
 	"submitblock":           {},
@@ -335,6 +354,36 @@ var rpcLimited = map[string]struct{}{
 	"version":               {},
 }
 
+// Commands that are available when the node runs as a light client, which
+// does not keep the block chain data the other commands depend on.
+var rpcSPV = map[string]struct{}{
+	"addnode":              {},
+	"clearbanned":          {},
+	"createrawsstx":        {},
+	"createrawssrtx":       {},
+	"createrawtransaction": {},
+	"debuglevel":           {},
+	"decoderawtransaction": {},
+	"decodescript":         {},
+	"getaddednodeinfo":     {},
+	"getbestblock":         {},
+	"getbestblockhash":     {},
+	"getblockcount":        {},
+	"getconnectioncount":   {},
+	"getcurrentnet":        {},
+	"getnettotals":         {},
+	"getpeerinfo":          {},
+	"help":                 {},
+	"listbanned":           {},
+	"node":                 {},
+	"ping":                 {},
+	"setban":               {},
+	"stop":                 {},
+	"validateaddress":      {},
+	"verifymessage":        {},
+	"version":              {},
+}
+
 // builderScript is a convenience function which is used for hard-coded scripts
 // built with the script builder.   Any errors are converted to a panic since it
 // is only, and must only, be used with hard-coded, and therefore, known good,
@@ -416,6 +465,26 @@ func rpcNoTxInfoError(txHash *chainhash.Hash) *dcrjson.RPCError {
 			txHash))
 }
 
+// rpcIndexNotSyncedError is a convenience function for returning a nicely
+// formatted RPC error which indicates the named optional index has not yet
+// caught up to the main chain and therefore can't provide a definitive answer.
+func rpcIndexNotSyncedError(indexName string) *dcrjson.RPCError {
+	return dcrjson.NewRPCError(dcrjson.ErrRPCIndexNotSynced,
+		fmt.Sprintf("The %s is not synced to the main chain yet",
+			indexName))
+}
+
+// indexSynced returns whether or not the provided optional index has caught up
+// to the current best chain tip.  It is considered synced when the server is
+// not running an index manager since the index is then maintained in lockstep
+// with the chain.
+func (s *rpcServer) indexSynced(indexer indexers.Indexer) bool {
+	if s.server.indexManager == nil {
+		return true
+	}
+	return s.server.indexManager.IsSynced(indexer)
+}
+
 // rpcMiscError is a convenience function for returning a nicely formatted RPC
 // error which indicates there is a unquantifiable error.  Use this sparingly;
 // misc return codes are a cop out.
@@ -456,6 +525,7 @@ type gbtWorkState struct {
 	prevHash      *chainhash.Hash
 	minTimestamp  time.Time
 	template      *BlockTemplate
+	bgTemplate    *BlockTemplate
 	notifyMap     map[chainhash.Hash]map[int64]chan struct{}
 	timeSource    blockchain.MedianTimeSource
 }
@@ -598,6 +668,79 @@ func peerExists(peers []*serverPeer, addr string, nodeID int32) bool {
 	return false
 }
 
+// handleSetBan implements the setban command.
+func handleSetBan(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*dcrjson.SetBanCmd)
+
+	subnet, err := parseBanSubnet(c.Subnet)
+	if err != nil {
+		return nil, rpcInvalidError("%v", err)
+	}
+
+	switch c.SubCmd {
+	case "add":
+		banDuration := cfg.BanDuration
+		if c.BanTime != nil && *c.BanTime != 0 {
+			if *c.BanTime < 0 {
+				return nil, rpcInvalidError("Ban time must not be " +
+					"negative")
+			}
+			banDuration = time.Duration(*c.BanTime) * time.Second
+		}
+		err := s.server.banList.Ban(subnet, time.Now().Add(banDuration))
+		if err != nil {
+			return nil, rpcInternalError(err.Error(),
+				"Could not save ban list")
+		}
+
+		// Disconnect any connected peers in the newly banned subnet.
+		for _, sp := range s.server.Peers() {
+			if subnet.Contains(sp.NA().IP) {
+				srvrLog.Infof("Disconnecting banned peer %s", sp)
+				sp.Disconnect()
+			}
+		}
+
+	case "remove":
+		found, err := s.server.banList.Unban(subnet)
+		if err != nil {
+			return nil, rpcInternalError(err.Error(),
+				"Could not save ban list")
+		}
+		if !found {
+			return nil, rpcInvalidError("%v is not banned", subnet)
+		}
+
+	default:
+		return nil, rpcInvalidError("%v: invalid subcommand for setban",
+			c.SubCmd)
+	}
+
+	return nil, nil
+}
+
+// handleListBanned implements the listbanned command.
+func handleListBanned(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	entries := s.server.banList.Entries()
+	result := make([]dcrjson.ListBannedResult, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, dcrjson.ListBannedResult{
+			Address:     e.subnet.String(),
+			BanCreated:  e.created.Unix(),
+			BannedUntil: e.until.Unix(),
+		})
+	}
+	return result, nil
+}
+
+// handleClearBanned implements the clearbanned command.
+func handleClearBanned(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	if err := s.server.banList.Clear(); err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not save ban list")
+	}
+	return nil, nil
+}
+
 // messageToHex serializes a message to the wire protocol encoding using the
 // latest protocol version and returns a hex-encoded string of the result.
 func messageToHex(msg wire.Message) (string, error) {
@@ -1350,6 +1493,60 @@ func handleDecodeScript(s *rpcServer, cmd interface{}, closeChan <-chan struct{}
 	return reply, nil
 }
 
+// handleDumpUtxoSnapshot implements the dumputxosnapshot command.
+func handleDumpUtxoSnapshot(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	c := cmd.(*dcrjson.DumpUtxoSnapshotCmd)
+
+	// Ensure the requested block is in the main chain.
+	best := s.chain.BestSnapshot()
+	if c.Height != nil && (*c.Height < 0 || *c.Height > best.Height) {
+		return nil, rpcInvalidError("Height %d is not in the main chain "+
+			"(best height %d)", *c.Height, best.Height)
+	}
+
+	// Refuse to overwrite existing files.
+	path := cleanAndExpandPath(c.Path)
+	if _, err := os.Stat(path); err == nil {
+		return nil, rpcInvalidError("%s already exists", path)
+	}
+
+	// Write the snapshot to a temporary file which is only moved to the
+	// requested path once the snapshot is complete.
+	tmpPath := path + ".incomplete"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL,
+		0600)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not create file")
+	}
+	height := best.Height
+	if c.Height != nil {
+		height = *c.Height
+	}
+	info, err := s.chain.WriteUtxoSnapshot(file, height)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return nil, rpcInternalError(err.Error(),
+			"Could not write UTXO snapshot")
+	}
+
+	return &dcrjson.DumpUtxoSnapshotResult{
+		Height:       info.Height,
+		Hash:         info.Hash.String(),
+		SnapshotHash: info.SnapshotHash.String(),
+		NumUtxos:     info.NumUtxos,
+		Path:         path,
+	}, nil
+}
+
 // handleEstimateFee implenents the estimatefee command.
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
@@ -1472,6 +1669,12 @@ func handleExistsAddress(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 		return nil, rpcInvalidError("Could not query address: %v", err)
 	}
 
+	// A negative answer can't be trusted while the index is still catching
+	// up to the main chain.
+	if !exists && !s.indexSynced(existsAddrIndex) {
+		return nil, rpcIndexNotSyncedError(existsAddrIndex.Name())
+	}
+
 	return exists, nil
 }
 
@@ -1500,12 +1703,18 @@ func handleExistsAddresses(s *rpcServer, cmd interface{}, closeChan <-chan struc
 		return nil, rpcInvalidError("Could not query address: %v", err)
 	}
 
-	// Convert the slice of bools into a compacted set of bit flags.
+	// Convert the slice of bools into a compacted set of bit flags.  A
+	// negative answer for any of the addresses can't be trusted while the
+	// index is still catching up to the main chain.
 	set := bitset.NewBytes(len(c.Addresses))
 	for i := range exists {
-		if exists[i] {
-			set.Set(i)
+		if !exists[i] {
+			if !s.indexSynced(existsAddrIndex) {
+				return nil, rpcIndexNotSyncedError(existsAddrIndex.Name())
+			}
+			continue
 		}
+		set.Set(i)
 	}
 
 	return hex.EncodeToString([]byte(set)), nil
@@ -1761,18 +1970,24 @@ func handleGetAddedNodeInfo(s *rpcServer, cmd interface{}, closeChan <-chan stru
 func handleGetBestBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	// All other "get block" commands give either the height, the hash, or
 	// both but require the block SHA.  This gets both for the best block.
-	best := s.chain.BestSnapshot()
+	hash, height, err := s.bestBlock()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not get best block")
+	}
 	result := &dcrjson.GetBestBlockResult{
-		Hash:   best.Hash.String(),
-		Height: best.Height,
+		Hash:   hash.String(),
+		Height: height,
 	}
 	return result, nil
 }
 
 // handleGetBestBlockHash implements the getbestblockhash command.
 func handleGetBestBlockHash(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	best := s.chain.BestSnapshot()
-	return best.Hash.String(), nil
+	hash, _, err := s.bestBlock()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not get best block")
+	}
+	return hash.String(), nil
 }
 
 // getDifficultyRatio returns the proof-of-work difficulty as a multiple of the
@@ -1805,6 +2020,13 @@ func handleGetBlock(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		return nil, rpcDecodeHexError(c.Hash)
 	}
 	blk, err := s.server.blockManager.chain.BlockByHash(hash)
+	if database.IsError(err, database.ErrBlockPruned) {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCBlockPruned,
+			Message: fmt.Sprintf("Block not available (pruned data): "+
+				"%v", hash),
+		}
+	}
 	if err != nil {
 		return nil, &dcrjson.RPCError{
 			Code:    dcrjson.ErrRPCBlockNotFound,
@@ -2004,8 +2226,11 @@ func handleGetBlockchainInfo(s *rpcServer, cmd interface{}, closeChan <-chan str
 
 // handleGetBlockCount implements the getblockcount command.
 func handleGetBlockCount(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	best := s.chain.BestSnapshot()
-	return best.Height, nil
+	_, height, err := s.bestBlock()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not get best block")
+	}
+	return height, nil
 }
 
 // handleGetBlockHash implements the getblockhash command.
@@ -2279,9 +2504,11 @@ func (state *gbtWorkState) templateUpdateChan(prevHash *chainhash.Hash, lastGene
 }
 
 // updateBlockTemplate creates or updates a block template for the work state.
-// A new block template will be generated when the current best block has
-// changed or the transactions in the memory pool have been updated and it has
-// been long enough since the last template was generated.  Otherwise, the
+// The template most recently generated by the background block template
+// generator is used whenever one is available.  Otherwise, a new block
+// template will be generated when the current best block has changed or the
+// transactions in the memory pool have been updated and it has been long
+// enough since the last template was generated.  Otherwise, the
 // timestamp for the existing block template is updated (and possibly the
 // difficulty on testnet per the consesus rules).  Finally, if the
 // useCoinbaseValue flag is false and the existing block template does not
@@ -2305,9 +2532,42 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 	best := s.server.blockManager.chain.BestSnapshot()
 	latestHash := best.Hash
 	template := state.template
+
+	// Use the template from the background block template generator when
+	// it has generated a new one since it was last used.  Since the
+	// background generator regenerates its templates as votes and new
+	// transactions arrive, there is no need to generate templates here
+	// while it has a current template.
+	var bgTemplate *BlockTemplate
+	if s.server.bg != nil {
+		bgTemplate = s.server.bg.CurrentTemplate()
+	}
+	if bgTemplate != nil && (bgTemplate != state.bgTemplate ||
+		state.prevHash == nil || !state.prevHash.IsEqual(&best.Hash)) {
+
+		template = deepCopyBlockTemplate(bgTemplate)
+		msgBlock = template.Block
+		state.template = template
+		state.bgTemplate = bgTemplate
+		state.lastGenerated = time.Now()
+		state.lastTxUpdate = lastTxUpdate
+		state.prevHash = &latestHash
+		state.minTimestamp = minimumMedianTime(best)
+
+		rpcsLog.Debugf("Using background block template (timestamp %v, "+
+			"target %064x, merkle root %s)", msgBlock.Header.Timestamp,
+			blockchain.CompactToBig(msgBlock.Header.Bits),
+			msgBlock.Header.MerkleRoot)
+
+		// Notify any clients that are long polling about the new
+		// template.
+		state.notifyLongPollers(&best.Hash, state.lastGenerated)
+		return nil
+	}
+
 	if template == nil || state.prevHash == nil ||
 		!state.prevHash.IsEqual(&best.Hash) ||
-		(state.lastTxUpdate != lastTxUpdate &&
+		(bgTemplate == nil && state.lastTxUpdate != lastTxUpdate &&
 			time.Now().After(state.lastGenerated.Add(time.Second*
 				gbtRegenerateSeconds))) {
 
@@ -2352,6 +2612,7 @@ func (state *gbtWorkState) updateBlockTemplate(s *rpcServer, useCoinbaseValue bo
 		// Update work state to ensure another block template isn't
 		// generated until needed.
 		state.template = deepCopyBlockTemplate(template)
+		state.bgTemplate = nil
 		state.lastGenerated = time.Now()
 		state.lastTxUpdate = lastTxUpdate
 		state.prevHash = &latestHash
@@ -2604,10 +2865,28 @@ func (state *gbtWorkState) blockTemplateResult(bm *blockManager, useCoinbaseValu
 		Mutable:       gbtMutableFields,
 		NonceRange:    gbtNonceRange,
 		Capabilities:  gbtCapabilities,
+		Bits:          fmt.Sprintf("%08x", header.Bits),
+		CurTime:       header.Timestamp.Unix(),
+		Height:        int64(header.Height),
+		PreviousHash:  header.PrevBlock.String(),
+		Version:       header.Version,
+		Voters:        header.Voters,
+		FreshStake:    header.FreshStake,
+		Revocations:   header.Revocations,
+		PoolSize:      header.PoolSize,
+		SBits:         dcrutil.Amount(header.SBits).ToCoin(),
+		StakeVersion:  header.StakeVersion,
 	}
 	if useCoinbaseValue {
+		// The first output of the coinbase pays the treasury and the
+		// second one commits to the height and extra nonce, so the
+		// value available to the miner is the sum of the rest.
+		var coinbaseValue int64
+		for _, txOut := range msgBlock.Transactions[0].TxOut[2:] {
+			coinbaseValue += txOut.Value
+		}
 		reply.CoinbaseAux = gbtCoinbaseAux
-		reply.CoinbaseValue = &msgBlock.Transactions[0].TxOut[0].Value
+		reply.CoinbaseValue = &coinbaseValue
 	} else {
 		// Ensure the template has a valid payment address associated
 		// with it when a full coinbase is requested.
@@ -2708,6 +2987,18 @@ func handleGetBlockTemplateLongPoll(s *rpcServer, longPollID string, useCoinbase
 	longPollChan := state.templateUpdateChan(prevHash, lastGenerated)
 	state.Unlock()
 
+	// Also subscribe to template updates from the background block template
+	// generator, if it is running, so the caller is notified as soon as a
+	// new template is generated due to things such as new votes.  A nil
+	// channel is never selected.  The subscription is removed when the
+	// request returns for any other reason so it does not linger until the
+	// next template.
+	var bgTemplateChan chan *BlockTemplate
+	if bg := s.server.bg; bg != nil {
+		bgTemplateChan = bg.RequestTemplateUpdate()
+		defer bg.UnsubscribeTemplateUpdate(bgTemplateChan)
+	}
+
 	select {
 	// When the client closes before it's time to send a reply, just return
 	// now so the goroutine doesn't hang around.
@@ -2717,6 +3008,10 @@ func handleGetBlockTemplateLongPoll(s *rpcServer, longPollID string, useCoinbase
 	// Wait until signal received to send the reply.
 	case <-longPollChan:
 		// Fallthrough
+
+	// Wait until the background generator has a new template.
+	case <-bgTemplateChan:
+		// Fallthrough
 	}
 
 	// Get the lastest block template
@@ -2782,7 +3077,7 @@ func handleGetBlockTemplateRequest(s *rpcServer, request *dcrjson.TemplateReques
 	// way to relay a found block or receive transactions to work on.
 	// However, allow this state when running in the regression test or
 	// simulation test mode.
-	if !cfg.SimNet && s.server.ConnectedCount() == 0 {
+	if !(cfg.SimNet || cfg.RegNet) && s.server.ConnectedCount() == 0 {
 		return nil, &dcrjson.RPCError{
 			Code:    dcrjson.ErrRPCClientNotConnected,
 			Message: "Decred is not connected",
@@ -2938,10 +3233,12 @@ func handleGetBlockTemplateProposal(s *rpcServer, request *dcrjson.TemplateReque
 	}
 	block := dcrutil.NewBlock(&msgBlock)
 
-	// Ensure the block is building from the expected previous block.
-	expectedPrevHash := &s.server.blockManager.chain.BestSnapshot().Hash
+	// Ensure the block is building from the expected previous block.  Note
+	// that blocks may also build from the parent of the current tip when
+	// the tip does not have enough votes.
+	best := s.server.blockManager.chain.BestSnapshot()
 	prevHash := &block.MsgBlock().Header.PrevBlock
-	if expectedPrevHash == nil || !expectedPrevHash.IsEqual(prevHash) {
+	if !best.Hash.IsEqual(prevHash) && !best.PrevHash.IsEqual(prevHash) {
 		return "bad-prevblk", nil
 	}
 
@@ -3078,6 +3375,9 @@ func handleGetCFilter(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 		return "", rpcInternalError(err.Error(), context)
 	}
 	if len(filterBytes) == 0 {
+		if !s.indexSynced(s.server.cfIndex) {
+			return nil, rpcIndexNotSyncedError(s.server.cfIndex.Name())
+		}
 		return nil, &dcrjson.RPCError{
 			Code:    dcrjson.ErrRPCBlockNotFound,
 			Message: fmt.Sprintf("Block not found: %v", hash),
@@ -3123,6 +3423,9 @@ func handleGetCFilterHeader(s *rpcServer, cmd interface{}, closeChan <-chan stru
 	if bytes.Equal(headerBytes, zeroHash[:]) && *hash !=
 		*s.server.chainParams.GenesisHash {
 
+		if !s.indexSynced(s.server.cfIndex) {
+			return nil, rpcIndexNotSyncedError(s.server.cfIndex.Name())
+		}
 		return nil, &dcrjson.RPCError{
 			Code:    dcrjson.ErrRPCBlockNotFound,
 			Message: fmt.Sprintf("Block not found: %v", hash),
@@ -3178,6 +3481,27 @@ func handleGetHeaders(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 	return &dcrjson.GetHeadersResult{Headers: hexBlockHeaders}, nil
 }
 
+// handleGetIndexInfo implements the getindexinfo command.
+func handleGetIndexInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	indexManager := s.server.indexManager
+	if indexManager == nil {
+		return []dcrjson.GetIndexInfoResult{}, nil
+	}
+
+	status := indexManager.SyncStatus()
+	results := make([]dcrjson.GetIndexInfoResult, 0, len(status))
+	for _, st := range status {
+		results = append(results, dcrjson.GetIndexInfoResult{
+			Name:       st.Name,
+			Height:     st.Height,
+			Hash:       st.Hash.String(),
+			BestHeight: st.BestHeight,
+			Synced:     st.Synced,
+		})
+	}
+	return results, nil
+}
+
 // handleGetInfo implements the getinfo command. We only return the fields
 // that are not related to wallet functionality.
 func handleGetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
@@ -3200,16 +3524,22 @@ func handleGetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (in
 
 // handleGetMempoolInfo implements the getmempoolinfo command.
 func handleGetMempoolInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
-	mempoolTxns := s.server.txMemPool.TxDescs()
+	info := s.server.txMemPool.SizeInfo()
 
-	var numBytes int64
-	for _, txD := range mempoolTxns {
-		numBytes += int64(txD.Tx.MsgTx().SerializeSize())
+	// The minimum fee is the higher of the minimum relay fee and the dynamic
+	// minimum fee rate which rises when transactions are evicted.
+	minFee := cfg.minRelayTxFee
+	if info.MinFeeRate > minFee {
+		minFee = info.MinFeeRate
 	}
 
 	ret := &dcrjson.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:          int64(info.Count),
+		Bytes:         info.Bytes,
+		MaxMempool:    info.MaxBytes,
+		MempoolMinFee: minFee.ToCoin(),
+		Evicted:       info.EvictedTxns,
+		EvictedBytes:  info.EvictedBytes,
 	}
 
 	return ret, nil
@@ -3259,10 +3589,33 @@ func handleGetMiningInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{
 // handleGetNetTotals implements the getnettotals command.
 func handleGetNetTotals(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	totalBytesRecv, totalBytesSent := s.server.NetTotals()
+	target := s.server.uploadTarget
+	targetState := target.state()
+	var throttled, peersThrottled time.Duration
+	if s.server.uploadLimiter != nil {
+		throttled = s.server.uploadLimiter.Throttled()
+	}
+	for _, p := range s.server.Peers() {
+		peersThrottled += p.UploadThrottled()
+	}
 	reply := &dcrjson.GetNetTotalsResult{
 		TotalBytesRecv: totalBytesRecv,
 		TotalBytesSent: totalBytesSent,
 		TimeMillis:     time.Now().UTC().UnixNano() / int64(time.Millisecond),
+		UploadTarget: &dcrjson.GetNetTotalsResultUploadTarget{
+			TimeFrame:             int64(uploadTargetTimeframe / time.Second),
+			Target:                target.target,
+			TargetReached:         targetState.reached,
+			ServeHistoricalBlocks: targetState.serveHistorical,
+			BytesLeftInCycle:      targetState.bytesLeft,
+			TimeLeftInCycle:       int64(targetState.timeLeft / time.Second),
+		},
+		UploadRate: &dcrjson.GetNetTotalsResultUploadRate{
+			MaxUploadRate:        cfg.MaxUploadRate * 1024,
+			MaxPeerUploadRate:    cfg.MaxPeerUploadRate * 1024,
+			ThrottledMillis:      int64(throttled / time.Millisecond),
+			PeersThrottledMillis: int64(peersThrottled / time.Millisecond),
+		},
 	}
 	return reply, nil
 }
@@ -3386,16 +3739,29 @@ func handleGetPeerInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 			Version:        statsSnap.Version,
 			SubVer:         statsSnap.UserAgent,
 			Inbound:        statsSnap.Inbound,
+			BlockRelayOnly: p.blockRelayOnly,
 			StartingHeight: statsSnap.StartingHeight,
 			CurrentHeight:  statsSnap.LastBlock,
 			BanScore:       int32(p.banScore.Int()),
 			SyncNode:       p == syncPeer,
+			Encrypted:      statsSnap.Encrypted,
+			MappedAS:       s.server.addrManager.ASN(p.NA()),
 		}
 		if p.LastPingNonce() != 0 {
 			wait := float64(time.Since(statsSnap.LastPingTime).Nanoseconds())
 			// We actually want microseconds.
 			info.PingWait = wait / 1000
 		}
+		if stats := p.TxReconStats(); stats != nil {
+			info.TxReconciliation = &dcrjson.TxReconciliationResult{
+				Flooding:  stats.Flooding,
+				SetSize:   stats.SetSize,
+				Rounds:    stats.Rounds,
+				Failures:  stats.Failures,
+				Announced: stats.Announced,
+				Requested: stats.Requested,
+			}
+		}
 		infos = append(infos, info)
 	}
 	return infos, nil
@@ -3514,6 +3880,9 @@ func handleGetRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan str
 			return nil, rpcInternalError(err.Error(), context)
 		}
 		if idxEntry == nil {
+			if !s.indexSynced(txIndex) {
+				return nil, rpcIndexNotSyncedError(txIndex.Name())
+			}
 			return nil, rpcNoTxInfoError(txHash)
 		}
 		blockRegion := &idxEntry.BlockRegion
@@ -3911,26 +4280,56 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 		return nil, rpcDecodeHexError(c.Txid)
 	}
 
-	// If requested and the tx is available in the mempool try to fetch it
-	// from there, otherwise attempt to fetch from the block database.
-	var bestBlockHash string
-	var confirmations int64
-	var txVersion uint16
-	var value int64
-	var scriptVersion uint16
-	var pkScript []byte
-	var isCoinbase bool
 	includeMempool := true
 	if c.IncludeMempool != nil {
 		includeMempool = *c.IncludeMempool
 	}
+	txOut, err := s.fetchTxOut(txHash, c.Vout, includeMempool)
+	if err != nil {
+		return nil, err
+	}
+
+	// To match the behavior of the reference client, return nil (JSON
+	// null) if the transaction output is spent by another transaction
+	// already in the main chain.
+	if txOut == nil {
+		return nil, nil
+	}
+	return s.txOutResult(txOut), nil
+}
+
+// txOutInfo houses the details of an unspent transaction output returned by
+// fetchTxOut.
+type txOutInfo struct {
+	bestBlockHash string
+	bestHeight    int64
+	confirmations int64
+	txVersion     uint16
+	value         int64
+	scriptVersion uint16
+	pkScript      []byte
+	isCoinbase    bool
+}
+
+// fetchTxOut returns the details of the passed transaction output.  When
+// includeMempool is set and the transaction is available in the mempool, the
+// output is fetched from there, otherwise it is fetched from the utxo set of
+// the main chain.  It returns nil when the output is spent by another
+// transaction already in the main chain.  Mined transactions that are spent by
+// a mempool transaction are not affected by this.
+func (s *rpcServer) fetchTxOut(txHash *chainhash.Hash, vout uint32, includeMempool bool) (*txOutInfo, error) {
 	var txFromMempool *dcrutil.Tx
 	if includeMempool {
 		txFromMempool, _ = s.server.txMemPool.FetchTransaction(txHash)
 	}
+	best := s.chain.BestSnapshot()
+	info := &txOutInfo{
+		bestBlockHash: best.Hash.String(),
+		bestHeight:    best.Height,
+	}
 	if txFromMempool != nil {
 		mtx := txFromMempool.MsgTx()
-		if c.Vout > uint32(len(mtx.TxOut)-1) {
+		if vout > uint32(len(mtx.TxOut)-1) {
 			return nil, &dcrjson.RPCError{
 				Code: dcrjson.ErrRPCInvalidTxVout,
 				Message: "Output index number (vout) does not " +
@@ -3938,77 +4337,90 @@ func handleGetTxOut(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (i
 			}
 		}
 
-		txOut := mtx.TxOut[c.Vout]
+		txOut := mtx.TxOut[vout]
 		if txOut == nil {
 			errStr := fmt.Sprintf("Output index: %d for txid: %s "+
-				"does not exist", c.Vout, txHash)
+				"does not exist", vout, txHash)
 			return nil, rpcInternalError(errStr, "")
 		}
 
-		best := s.chain.BestSnapshot()
-		bestBlockHash = best.Hash.String()
-		confirmations = 0
-		txVersion = mtx.Version
-		value = txOut.Value
-		scriptVersion = txOut.Version
-		pkScript = txOut.PkScript
-		isCoinbase = blockchain.IsCoinBaseTx(mtx)
-	} else {
-		entry, err := s.chain.FetchUtxoEntry(txHash)
-		if err != nil {
-			return nil, rpcNoTxInfoError(txHash)
-		}
-
-		// To match the behavior of the reference client, return nil
-		// (JSON null) if the transaction output is spent by another
-		// transaction already in the main chain.  Mined transactions
-		// that are spent by a mempool transaction are not affected by
-		// this.
-		if entry == nil || entry.IsOutputSpent(c.Vout) {
-			return nil, nil
-		}
+		info.confirmations = 0
+		info.txVersion = mtx.Version
+		info.value = txOut.Value
+		info.scriptVersion = txOut.Version
+		info.pkScript = txOut.PkScript
+		info.isCoinbase = blockchain.IsCoinBaseTx(mtx)
+		return info, nil
+	}
 
-		best := s.chain.BestSnapshot()
-		bestBlockHash = best.Hash.String()
-		confirmations = 1 + best.Height - entry.BlockHeight()
-		txVersion = entry.TxVersion()
-		value = entry.AmountByIndex(c.Vout)
-		scriptVersion = entry.ScriptVersionByIndex(c.Vout)
-		pkScript = entry.PkScriptByIndex(c.Vout)
-		isCoinbase = entry.IsCoinBase()
+	entry, err := s.chain.FetchUtxoEntry(txHash)
+	if err != nil {
+		return nil, rpcNoTxInfoError(txHash)
 	}
+	if entry == nil || entry.IsOutputSpent(vout) {
+		return nil, nil
+	}
+
+	info.confirmations = 1 + best.Height - entry.BlockHeight()
+	info.txVersion = entry.TxVersion()
+	info.value = entry.AmountByIndex(vout)
+	info.scriptVersion = entry.ScriptVersionByIndex(vout)
+	info.pkScript = entry.PkScriptByIndex(vout)
+	info.isCoinbase = entry.IsCoinBase()
+	return info, nil
+}
 
+// txOutResult returns the gettxout result for the passed transaction output.
+func (s *rpcServer) txOutResult(txOut *txOutInfo) *dcrjson.GetTxOutResult {
 	// Disassemble script into single line printable format.  The
 	// disassembled string will contain [error] inline if the script
 	// doesn't fully parse, so ignore the error here.
-	script := pkScript
+	script := txOut.pkScript
 	disbuf, _ := txscript.DisasmString(script)
 
 	// Get further info about the script.  Ignore the error here since an
 	// error means the script couldn't parse and there is no additional
 	// information about it anyways.
-	scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(scriptVersion,
-		script, s.server.chainParams)
+	scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(
+		txOut.scriptVersion, script, s.server.chainParams)
 	addresses := make([]string, len(addrs))
 	for i, addr := range addrs {
 		addresses[i] = addr.EncodeAddress()
 	}
 
-	txOutReply := &dcrjson.GetTxOutResult{
-		BestBlock:     bestBlockHash,
-		Confirmations: confirmations,
-		Value:         dcrutil.Amount(value).ToUnit(dcrutil.AmountCoin),
-		Version:       int32(txVersion),
+	return &dcrjson.GetTxOutResult{
+		BestBlock:     txOut.bestBlockHash,
+		Confirmations: txOut.confirmations,
+		Value:         dcrutil.Amount(txOut.value).ToUnit(dcrutil.AmountCoin),
+		Version:       int32(txOut.txVersion),
 		ScriptPubKey: dcrjson.ScriptPubKeyResult{
 			Asm:       disbuf,
-			Hex:       hex.EncodeToString(pkScript),
+			Hex:       hex.EncodeToString(script),
 			ReqSigs:   int32(reqSigs),
 			Type:      scriptClass.String(),
 			Addresses: addresses,
 		},
-		Coinbase: isCoinbase,
+		Coinbase: txOut.isCoinbase,
 	}
-	return txOutReply, nil
+}
+
+// handleGetTxOutSetInfo handles gettxoutsetinfo commands.
+func handleGetTxOutSetInfo(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	stats, err := s.chain.FetchUtxoStats()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not fetch UTXO set statistics")
+	}
+
+	return &dcrjson.GetTxOutSetInfoResult{
+		Height:         stats.BestHeight,
+		BestBlock:      stats.BestHash.String(),
+		Transactions:   stats.Transactions,
+		TxOuts:         stats.Utxos,
+		SerializedHash: stats.SerializedHash.String(),
+		DiskSize:       stats.Size,
+		TotalAmount:    dcrutil.Amount(stats.Total).ToCoin(),
+	}, nil
 }
 
 // pruneOldBlockTemplates prunes all old block templates from the templatePool
@@ -4417,6 +4829,20 @@ func handleLiveTickets(s *rpcServer, cmd interface{}, closeChan <-chan struct{})
 	return dcrjson.LiveTicketsResult{Tickets: ltString}, nil
 }
 
+// handleLoadMempool implements the loadmempool command.
+func handleLoadMempool(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	accepted, rejected, err := s.server.loadMempool()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not load mempool")
+	}
+
+	return &dcrjson.LoadMempoolResult{
+		Accepted: accepted,
+		Rejected: rejected,
+		Path:     mempoolDataPath(),
+	}, nil
+}
+
 // handleMissedTickets implements the missedtickets command.
 func handleMissedTickets(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
 	mt, err := s.server.blockManager.chain.MissedTickets()
@@ -4503,6 +4929,19 @@ func handleRebroadcastWinners(s *rpcServer, cmd interface{}, closeChan <-chan st
 	return nil, nil
 }
 
+// handleSaveMempool implements the savemempool command.
+func handleSaveMempool(s *rpcServer, cmd interface{}, closeChan <-chan struct{}) (interface{}, error) {
+	numTxns, err := s.server.saveMempool()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not save mempool")
+	}
+
+	return &dcrjson.SaveMempoolResult{
+		NumTxns: numTxns,
+		Path:    mempoolDataPath(),
+	}, nil
+}
+
 // retrievedTx represents a transaction that was either loaded from the
 // transaction memory pool or from the database.  When a transaction is loaded
 // from the database, it is loaded with the raw serialized bytes while the
@@ -4554,6 +4993,9 @@ func fetchInputTxos(s *rpcServer, tx *wire.MsgTx) (map[wire.OutPoint]wire.TxOut,
 			return nil, rpcInternalError(err.Error(), context)
 		}
 		if idxEntry == nil {
+			if !s.indexSynced(s.server.txIndex) {
+				return nil, rpcIndexNotSyncedError(s.server.txIndex.Name())
+			}
 			return nil, rpcNoTxInfoError(&origin.Hash)
 		}
 		blockRegion := &idxEntry.BlockRegion
@@ -4760,6 +5202,12 @@ func handleSearchRawTransactions(s *rpcServer, cmd interface{}, closeChan <-chan
 			"enabled (--addrindex)", "Configuration")
 	}
 
+	// The results would silently omit transactions from blocks the address
+	// index has not processed yet, so refuse to answer until it catches up.
+	if !s.indexSynced(addrIndex) {
+		return nil, rpcIndexNotSyncedError(addrIndex.Name())
+	}
+
 	// Override the flag for including extra previous output information in
 	// each input if needed.
 	c := cmd.(*dcrjson.SearchRawTransactionsCmd)
@@ -5042,6 +5490,22 @@ func handleSendRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan st
 	}
 
 	tx := dcrutil.NewTx(msgtx)
+
+	// Relay regular transactions in the stem phase of the dandelion relay
+	// when it is enabled.  They are relayed like any other transaction when
+	// that is not possible, such as when the stem pool is full.  They are
+	// only added to the rebroadcast logic once they are fluffed so that
+	// rebroadcasting them does not reveal they originated here.
+	txType := stake.DetermineTxType(msgtx)
+	if cfg.Dandelion && txType == stake.TxTypeRegular {
+		err := s.server.StemTransaction(tx, nil, allowHighFees)
+		if err == nil {
+			return tx.Hash().String(), nil
+		}
+		rpcsLog.Debugf("Unable to relay transaction %v in the stem "+
+			"phase: %v", tx.Hash(), err)
+	}
+
 	acceptedTxs, err := s.server.blockManager.ProcessTransaction(tx, false,
 		false, allowHighFees)
 	if err != nil {
@@ -5078,7 +5542,7 @@ func handleSendRawTransaction(s *rpcServer, cmd interface{}, closeChan <-chan st
 	//
 	// Note that votes are only valid for a specific block and are time
 	// sensitive, so they should not be added to the rebroadcast logic.
-	if txType := stake.DetermineTxType(msgtx); txType != stake.TxTypeSSGen {
+	if txType != stake.TxTypeSSGen {
 		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 		s.server.AddRebroadcastInventory(iv, tx)
 	}
@@ -5792,6 +6256,9 @@ type rpcServer struct {
 	chain                  *blockchain.BlockChain
 	authsha                [sha256.Size]byte
 	limitauthsha           [sha256.Size]byte
+	aclUsers               *rpcACLUsers
+	tlsConfig              *tls.Config
+	restLimiter            *restRateLimiter
 	ntfnMgr                *wsNotificationManager
 	numClients             int32
 	statusLines            map[int]string
@@ -5878,6 +6345,12 @@ func (s *rpcServer) Stop() error {
 	s.ntfnMgr.Shutdown()
 	s.ntfnMgr.WaitForShutdown()
 	s.wg.Wait()
+	if cfg.rpcCookieAuth {
+		if err := removeRPCCookie(cfg.RPCCookieFile); err != nil {
+			rpcsLog.Errorf("Unable to remove the RPC cookie file: %v",
+				err)
+		}
+	}
 	rpcsLog.Infof("RPC server shutdown complete")
 	return nil
 }
@@ -5923,46 +6396,85 @@ func (s *rpcServer) decrementClients() {
 	atomic.AddInt32(&s.numClients, -1)
 }
 
-// checkAuth checks the HTTP Basic authentication supplied by a wallet or RPC
-// client in the HTTP request r.  If the supplied authentication does not match
-// the username and password expected, a non-nil error is returned.
+// checkLogin returns the ACL of the user with the passed username and password
+// or nil when they do not match any user.
 //
-// This check is time-constant.
-//
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false). The second is
-// always false if the first is.
-func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, bool, error) {
-	authhdr := r.Header["Authorization"]
-	if len(authhdr) <= 0 {
-		if require {
-			rpcsLog.Warnf("RPC authentication failure from %s",
-				r.RemoteAddr)
-			return false, false, errors.New("auth failure")
-		}
-
-		return false, false, nil
-	}
-
-	authsha := sha256.Sum256([]byte(authhdr[0]))
+// This check is time-constant with respect to the password.
+func (s *rpcServer) checkLogin(username, password string) *rpcACL {
+	login := username + ":" + password
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	authsha := sha256.Sum256([]byte(auth))
 
 	// Check for limited auth first as in environments with limited users,
 	// those are probably expected to have a higher volume of calls
 	limitcmp := subtle.ConstantTimeCompare(authsha[:], s.limitauthsha[:])
 	if limitcmp == 1 {
-		return true, false, nil
+		return rpcLimitedACL
 	}
 
 	// Check for admin-level auth
 	cmp := subtle.ConstantTimeCompare(authsha[:], s.authsha[:])
 	if cmp == 1 {
-		return true, true, nil
+		return rpcAdminACL
+	}
+
+	// Check for the users with an ACL.
+	user, ok := s.aclUsers.byName[username]
+	if !ok || !user.checkPassword(password) {
+		return nil
+	}
+	return &user.acl
+}
+
+// checkAuth checks the HTTP Basic authentication or the TLS client certificate
+// supplied by a wallet or RPC client in the HTTP request r.  If the supplied
+// authentication does not match any user, a non-nil error is returned.
+//
+// This check is time-constant.
+//
+// The bool return value signifies auth success (true if successful) and the
+// ACL describes which methods and notifications the user is authorized for.
+// The ACL is nil if the auth did not succeed.
+func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, *rpcACL, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		// Users with an ACL may authenticate with a client certificate
+		// that was verified during the TLS handshake instead.
+		if acl := s.certACL(r.TLS); acl != nil {
+			return true, acl, nil
+		}
+
+		if require {
+			rpcsLog.Warnf("RPC authentication failure from %s",
+				r.RemoteAddr)
+			return false, nil, errors.New("auth failure")
+		}
+
+		return false, nil, nil
 	}
 
-	// Request's auth doesn't match either user
-	rpcsLog.Warnf("RPC authentication failure from %s", r.RemoteAddr)
-	return false, false, errors.New("auth failure")
+	acl := s.checkLogin(username, password)
+	if acl == nil {
+		// Request's auth doesn't match any user
+		rpcsLog.Warnf("RPC authentication failure from %s", r.RemoteAddr)
+		return false, nil, errors.New("auth failure")
+	}
+	return true, acl, nil
+}
+
+// certACL returns the ACL of the RPC user that authenticated with the client
+// certificate verified during the TLS handshake with the passed state, or nil
+// when the certificate does not belong to any user.
+func (s *rpcServer) certACL(state *tls.ConnectionState) *rpcACL {
+	if state == nil || len(state.VerifiedChains) == 0 {
+		return nil
+	}
+	cert := state.VerifiedChains[0][0]
+	user, ok := s.aclUsers.byCommonName[cert.Subject.CommonName]
+	if !ok {
+		return nil
+	}
+	return &user.acl
 }
 
 // parsedRPCCmd represents a JSON-RPC request object that has been parsed into
@@ -5976,6 +6488,16 @@ type parsedRPCCmd struct {
 	err     *dcrjson.RPCError
 }
 
+// bestBlock returns the hash and height of the best block, which is the tip of
+// the header chain of the light client when the node runs in spv mode.
+func (s *rpcServer) bestBlock() (*chainhash.Hash, int64, error) {
+	if s.server.spvSyncer != nil {
+		return s.server.spvSyncer.BestBlock()
+	}
+	best := s.chain.BestSnapshot()
+	return &best.Hash, best.Height, nil
+}
+
 // standardCmdResult checks that a parsed command is a standard Bitcoin
 // JSON-RPC command and runs the appropriate handler to reply to the command.
 // Any commands which are not recognized or not implemented will return an
@@ -5983,6 +6505,9 @@ type parsedRPCCmd struct {
 func (s *rpcServer) standardCmdResult(cmd *parsedRPCCmd, closeChan <-chan struct{}) (interface{}, error) {
 	handler, ok := rpcHandlers[cmd.method]
 	if ok {
+		if _, ok := rpcSPV[cmd.method]; !ok && s.server.spvSyncer != nil {
+			return nil, ErrRPCNoChainData
+		}
 		goto handled
 	}
 	_, ok = rpcAskWallet[cmd.method]
@@ -5997,6 +6522,9 @@ func (s *rpcServer) standardCmdResult(cmd *parsedRPCCmd, closeChan <-chan struct
 	}
 	return nil, dcrjson.ErrRPCMethodNotFound
 handled:
+	if m := s.server.metricsServer; m != nil {
+		defer m.observeRPC(cmd.method, time.Now())
+	}
 	return handler(s, cmd.cmd, closeChan)
 }
 
@@ -6049,15 +6577,13 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 
 // processRequest determines the incoming request type (single or batched),
 // parses it and returns a marshalled response.
-func (s *rpcServer) processRequest(request *dcrjson.Request, isAdmin bool, closeChan <-chan struct{}) []byte {
+func (s *rpcServer) processRequest(request *dcrjson.Request, acl *rpcACL, closeChan <-chan struct{}) []byte {
 	var result interface{}
 	var jsonErr error
 
-	if !isAdmin {
-		if _, ok := rpcLimited[request.Method]; !ok {
-			jsonErr = rpcInvalidError("limited user not " +
-				"authorized for this method")
-		}
+	if !acl.permitsMethod(request.Method) {
+		jsonErr = rpcInvalidError("user not authorized for this " +
+			"method")
 	}
 
 	if jsonErr == nil {
@@ -6101,7 +6627,7 @@ func (s *rpcServer) processRequest(request *dcrjson.Request, isAdmin bool, close
 }
 
 // jsonRPCRead handles reading and responding to RPC messages.
-func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin bool) {
+func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, acl *rpcACL) {
 	if atomic.LoadInt32(&s.shutdown) != 0 {
 		return
 	}
@@ -6182,7 +6708,7 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 		}
 
 		if err == nil {
-			resp = s.processRequest(&req, isAdmin, closeChan)
+			resp = s.processRequest(&req, acl, closeChan)
 		}
 
 		if resp != nil {
@@ -6271,7 +6797,7 @@ func (s *rpcServer) jsonRPCRead(w http.ResponseWriter, r *http.Request, isAdmin
 						continue
 					}
 
-					resp = s.processRequest(&req, isAdmin, closeChan)
+					resp = s.processRequest(&req, acl, closeChan)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -6356,19 +6882,19 @@ func (s *rpcServer) Start() {
 		// Keep track of the number of connected clients.
 		s.incrementClients()
 		defer s.decrementClients()
-		_, isAdmin, err := s.checkAuth(r, true)
+		_, acl, err := s.checkAuth(r, true)
 		if err != nil {
 			jsonAuthFail(w)
 			return
 		}
 
 		// Read and respond to the request.
-		s.jsonRPCRead(w, r, isAdmin)
+		s.jsonRPCRead(w, r, acl)
 	})
 
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		authenticated, isAdmin, err := s.checkAuth(r, false)
+		authenticated, acl, err := s.checkAuth(r, false)
 		if err != nil {
 			jsonAuthFail(w)
 			return
@@ -6385,9 +6911,14 @@ func (s *rpcServer) Start() {
 			http.Error(w, "400 Bad Request.", http.StatusBadRequest)
 			return
 		}
-		s.WebsocketHandler(ws, r.RemoteAddr, authenticated, isAdmin)
+		s.WebsocketHandler(ws, r.RemoteAddr, authenticated, acl)
 	})
 
+	// Unauthenticated REST endpoint.
+	if cfg.REST {
+		rpcServeMux.HandleFunc(restPathPrefix, s.handleREST)
+	}
+
 	for _, listener := range s.listeners {
 		s.wg.Add(1)
 		go func(listener net.Listener) {
@@ -6440,9 +6971,18 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 		gbtWorkState:           newGbtWorkState(s.timeSource),
 		helpCacher:             newHelpCacher(),
 		requestProcessShutdown: make(chan struct{}),
+		aclUsers:               cfg.rpcACLUsers,
+	}
+	rpcUser, rpcPass := cfg.RPCUser, cfg.RPCPass
+	if cfg.rpcCookieAuth {
+		var err error
+		rpcUser, rpcPass, err = genRPCCookie()
+		if err != nil {
+			return nil, err
+		}
 	}
-	if cfg.RPCUser != "" && cfg.RPCPass != "" {
-		login := cfg.RPCUser + ":" + cfg.RPCPass
+	if rpcUser != "" && rpcPass != "" {
+		login := rpcUser + ":" + rpcPass
 		auth := "Basic " +
 			base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.authsha = sha256.Sum256([]byte(auth))
@@ -6453,6 +6993,9 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 			base64.StdEncoding.EncodeToString([]byte(login))
 		rpc.limitauthsha = sha256.Sum256([]byte(auth))
 	}
+	if cfg.REST {
+		rpc.restLimiter = newRESTRateLimiter(cfg.RESTRateLimit)
+	}
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
 
 	// Setup TLS if not disabled.
@@ -6476,7 +7019,25 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 			MinVersion:   tls.VersionTLS12,
 		}
 
+		// Verify the client certificates RPC users with an ACL may
+		// authenticate with when the issuing certificate authorities are
+		// configured.
+		if cfg.RPCUsers.ClientCAFile != "" {
+			pem, err := ioutil.ReadFile(cfg.RPCUsers.ClientCAFile)
+			if err != nil {
+				return nil, err
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificates found in %s",
+					cfg.RPCUsers.ClientCAFile)
+			}
+			tlsConfig.ClientCAs = pool
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+
 		// Change the standard net.Listen function to the tls one.
+		rpc.tlsConfig = &tlsConfig
 		listenFunc = func(net string, laddr string) (net.Listener, error) {
 			return tls.Listen(net, laddr, &tlsConfig)
 		}
@@ -6511,6 +7072,19 @@ func newRPCServer(listenAddrs []string, generator *BlkTmplGenerator, s *server)
 		return nil, errors.New("RPCS: No valid listen address")
 	}
 
+	// Write the cookie file local clients authenticate with once the
+	// server is able to accept connections.
+	if cfg.rpcCookieAuth {
+		err := writeRPCCookie(cfg.RPCCookieFile, rpcUser, rpcPass)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return nil, err
+		}
+		rpcsLog.Infof("Wrote the RPC cookie file %s", cfg.RPCCookieFile)
+	}
+
 	rpc.listeners = listeners
 
 	return &rpc, nil
@@ -35,6 +35,25 @@ var helpDescsEnUS = map[string]string{
 	"node-target":        "Either the IP address and port of the peer to operate on, or a valid peer ID.",
 	"node-connectsubcmd": "'perm' to make the connected peer a permanent one, 'temp' to try a single connect to a peer",
 
+	// SetBanCmd help.
+	"setban--synopsis": "Bans or unbans an IP address or subnet.\n" +
+		"Peers in a newly banned subnet are disconnected and connections to and from banned addresses are rejected.\n" +
+		"Bans are saved to the banlist.json file in the data directory so they survive restarts.",
+	"setban-subnet":  "The IP address or subnet in CIDR notation to operate on",
+	"setban-subcmd":  "'add' to ban the address or subnet, or 'remove' to remove its ban",
+	"setban-bantime": "The number of seconds the ban lasts, or 0 to use the --banduration option",
+
+	// ListBannedCmd help.
+	"listbanned--synopsis": "Returns the banned IP addresses and subnets.",
+
+	// ListBannedResult help.
+	"listbannedresult-address":     "The banned IP address or subnet in CIDR notation",
+	"listbannedresult-bancreated":  "The time the ban was created in seconds since 1 Jan 1970 GMT",
+	"listbannedresult-banneduntil": "The time the ban expires in seconds since 1 Jan 1970 GMT",
+
+	// ClearBannedCmd help.
+	"clearbanned--synopsis": "Removes all bans.",
+
 	// TransactionInput help.
 	"transactioninput-amount": "The previous output amount",
 	"transactioninput-txid":   "The hash of the input transaction",
@@ -152,6 +171,20 @@ var helpDescsEnUS = map[string]string{
 	"decodescript--synopsis": "Returns a JSON object with information about the provided hex-encoded script.",
 	"decodescript-hexscript": "Hex-encoded script",
 
+	// DumpUtxoSnapshotCmd help.
+	"dumputxosnapshot--synopsis": "Writes a snapshot of the UTXO set, the ticket database, and the block index as of a main chain block to a file.\n" +
+		"The snapshot can be used to bootstrap new nodes with the --loadsnapshot option when the network parameters commit to its hash.\n" +
+		"The history prior to the snapshot block is only validated by nodes that are bootstrapped this way when they run with the --backvalidate option.",
+	"dumputxosnapshot-path":   "Path of the file to write the snapshot to, which must not already exist",
+	"dumputxosnapshot-height": "Height of the main chain block to take the snapshot at (default: the current best block)",
+
+	// DumpUtxoSnapshotResult help.
+	"dumputxosnapshotresult-height":       "The height of the block the snapshot was taken at",
+	"dumputxosnapshotresult-hash":         "The hash of the block the snapshot was taken at",
+	"dumputxosnapshotresult-snapshothash": "The hash of the snapshot contents",
+	"dumputxosnapshotresult-numutxos":     "The number of unspent transaction output set entries in the snapshot",
+	"dumputxosnapshotresult-path":         "The path of the written snapshot file",
+
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
 	"existsaddress-address":   "The address to check",
@@ -417,6 +450,12 @@ var helpDescsEnUS = map[string]string{
 	"getblocktemplateresult-reject-reason":     "Reason the proposal was invalid as-is (only applies to proposal responses)",
 	"getblocktemplateresult-stransactions":     "Stake transactions",
 	"getblocktemplateresult-header":            "Block header",
+	"getblocktemplateresult-voters":            "Number of votes included in the block",
+	"getblocktemplateresult-freshstake":        "Number of ticket purchases included in the block",
+	"getblocktemplateresult-revocations":       "Number of revocations included in the block",
+	"getblocktemplateresult-poolsize":          "Number of live tickets in the ticket pool as of the block",
+	"getblocktemplateresult-sbits":             "The stake difficulty (ticket price) for the block in DCR",
+	"getblocktemplateresult-stakeversion":      "The stake version of the block",
 
 	// GetBlockTemplateCmd help.
 	"getblocktemplate--synopsis": "Returns a JSON object with information necessary to construct a block to mine or accepts a proposal to validate.\n" +
@@ -569,6 +608,16 @@ var helpDescsEnUS = map[string]string{
 	"getheaders-hashstop":      "Optional block hash to stop including block headers for",
 	"getheadersresult-headers": "Serialized block headers of all located blocks, limited to some arbitrary maximum number of hashes (currently 2000, which matches the wire protocol headers message, but this is not guaranteed)",
 
+	// GetIndexInfoCmd help.
+	"getindexinfo--synopsis": "Returns the sync status of each of the enabled optional indexes.",
+
+	// GetIndexInfoResult help.
+	"getindexinforesult-name":       "The name of the index",
+	"getindexinforesult-height":     "The height of the most recent block the index has processed",
+	"getindexinforesult-hash":       "The hash of the most recent block the index has processed",
+	"getindexinforesult-bestheight": "The height of the current best chain tip",
+	"getindexinforesult-synced":     "Whether or not the index has caught up to the best chain tip",
+
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
@@ -576,8 +625,12 @@ var helpDescsEnUS = map[string]string{
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":         "Size in bytes of the mempool",
+	"getmempoolinforesult-size":          "Number of transactions in the mempool",
+	"getmempoolinforesult-maxmempool":    "Maximum size in bytes of the mempool (0 when unlimited)",
+	"getmempoolinforesult-mempoolminfee": "Minimum fee rate in DCR/kB for transactions to be accepted, including the dynamic minimum that rises when transactions are evicted",
+	"getmempoolinforesult-evicted":       "Number of transactions evicted to limit the size of the mempool since startup",
+	"getmempoolinforesult-evictedbytes":  "Total size in bytes of the transactions evicted to limit the size of the mempool since startup",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":           "Height of the latest best block",
@@ -609,28 +662,56 @@ var helpDescsEnUS = map[string]string{
 	"getnettotalsresult-totalbytesrecv": "Total bytes received",
 	"getnettotalsresult-totalbytessent": "Total bytes sent",
 	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
+	"getnettotalsresult-uploadtarget":   "The state of the upload target set with the maxuploadtarget option",
+	"getnettotalsresult-uploadrate":     "The upload rate limits set with the maxuploadrate and maxpeeruploadrate options",
+
+	// GetNetTotalsResultUploadTarget help.
+	"getnettotalsresultuploadtarget-timeframe":             "Length of the cycles the upload target applies to in seconds",
+	"getnettotalsresultuploadtarget-target":                "Maximum number of bytes to upload per cycle (0 for unlimited)",
+	"getnettotalsresultuploadtarget-targetreached":         "Whether or not the target has been reached in the current cycle",
+	"getnettotalsresultuploadtarget-servehistoricalblocks": "Whether or not historical blocks are served to peers that are not whitelisted",
+	"getnettotalsresultuploadtarget-bytesleftincycle":      "Number of bytes left to upload in the current cycle",
+	"getnettotalsresultuploadtarget-timeleftincycle":       "Number of seconds left in the current cycle",
+
+	// GetNetTotalsResultUploadRate help.
+	"getnettotalsresultuploadrate-maxuploadrate":        "Maximum combined number of bytes per second uploaded to peers that are not whitelisted (0 for unlimited)",
+	"getnettotalsresultuploadrate-maxpeeruploadrate":    "Maximum number of bytes per second uploaded to each peer that is not whitelisted (0 for unlimited)",
+	"getnettotalsresultuploadrate-throttledmillis":      "Total number of milliseconds uploads have been delayed by the combined limit",
+	"getnettotalsresultuploadrate-peersthrottledmillis": "Total number of milliseconds uploads to the connected peers have been delayed by the per-peer limit",
 
 	// GetPeerInfoResult help.
-	"getpeerinforesult-id":             "A unique node ID",
-	"getpeerinforesult-addr":           "The ip address and port of the peer",
-	"getpeerinforesult-addrlocal":      "Local address",
-	"getpeerinforesult-services":       "Services bitmask which represents the services supported by the peer",
-	"getpeerinforesult-relaytxes":      "Peer has requested transactions be relayed to it",
-	"getpeerinforesult-lastsend":       "Time the last message was received in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-lastrecv":       "Time the last message was sent in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-bytessent":      "Total bytes sent",
-	"getpeerinforesult-bytesrecv":      "Total bytes received",
-	"getpeerinforesult-conntime":       "Time the connection was made in seconds since 1 Jan 1970 GMT",
-	"getpeerinforesult-timeoffset":     "The time offset of the peer",
-	"getpeerinforesult-pingtime":       "Number of microseconds the last ping took",
-	"getpeerinforesult-pingwait":       "Number of microseconds a queued ping has been waiting for a response",
-	"getpeerinforesult-version":        "The protocol version of the peer",
-	"getpeerinforesult-subver":         "The user agent of the peer",
-	"getpeerinforesult-inbound":        "Whether or not the peer is an inbound connection",
-	"getpeerinforesult-startingheight": "The latest block height the peer knew about when the connection was established",
-	"getpeerinforesult-currentheight":  "The current height of the peer",
-	"getpeerinforesult-banscore":       "The ban score",
-	"getpeerinforesult-syncnode":       "Whether or not the peer is the sync peer",
+	"getpeerinforesult-id":               "A unique node ID",
+	"getpeerinforesult-addr":             "The ip address and port of the peer",
+	"getpeerinforesult-addrlocal":        "Local address",
+	"getpeerinforesult-services":         "Services bitmask which represents the services supported by the peer",
+	"getpeerinforesult-relaytxes":        "Peer has requested transactions be relayed to it",
+	"getpeerinforesult-lastsend":         "Time the last message was received in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-lastrecv":         "Time the last message was sent in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-bytessent":        "Total bytes sent",
+	"getpeerinforesult-bytesrecv":        "Total bytes received",
+	"getpeerinforesult-conntime":         "Time the connection was made in seconds since 1 Jan 1970 GMT",
+	"getpeerinforesult-timeoffset":       "The time offset of the peer",
+	"getpeerinforesult-pingtime":         "Number of microseconds the last ping took",
+	"getpeerinforesult-pingwait":         "Number of microseconds a queued ping has been waiting for a response",
+	"getpeerinforesult-version":          "The protocol version of the peer",
+	"getpeerinforesult-subver":           "The user agent of the peer",
+	"getpeerinforesult-inbound":          "Whether or not the peer is an inbound connection",
+	"getpeerinforesult-blockrelayonly":   "Whether or not the peer is an outbound connection that is only used to relay blocks and headers",
+	"getpeerinforesult-startingheight":   "The latest block height the peer knew about when the connection was established",
+	"getpeerinforesult-currentheight":    "The current height of the peer",
+	"getpeerinforesult-banscore":         "The ban score",
+	"getpeerinforesult-syncnode":         "Whether or not the peer is the sync peer",
+	"getpeerinforesult-encrypted":        "Whether or not the communication with the peer uses the encrypted transport",
+	"getpeerinforesult-mappedas":         "The number of the autonomous system the peer is mapped to by the AS map (only when an AS map is in use and the peer is mapped)",
+	"getpeerinforesult-txreconciliation": "Statistics about the reconciliation of transaction announcements with the peer (only when negotiated)",
+
+	// TxReconciliationResult help.
+	"txreconciliationresult-flooding":  "Whether or not transactions are still announced to the peer individually",
+	"txreconciliationresult-setsize":   "The number of transactions waiting for the next reconciliation round",
+	"txreconciliationresult-rounds":    "The number of completed reconciliation rounds",
+	"txreconciliationresult-failures":  "The number of reconciliation rounds in which the set difference could not be decoded",
+	"txreconciliationresult-announced": "The number of transactions announced to the peer as a result of reconciliation",
+	"txreconciliationresult-requested": "The number of transactions the peer was asked to announce as a result of reconciliation",
 
 	// GetPeerInfoCmd help.
 	"getpeerinfo--synopsis": "Returns data about each connected network peer as an array of json objects.",
@@ -678,6 +759,18 @@ var helpDescsEnUS = map[string]string{
 	"gettxout-vout":           "The index of the output",
 	"gettxout-includemempool": "Include the mempool when true",
 
+	// GetTxOutSetInfoCmd help.
+	"gettxoutsetinfo--synopsis": "Returns statistics about the unspent transaction output set as of the current best block.",
+
+	// GetTxOutSetInfoResult help.
+	"gettxoutsetinforesult-height":         "The height of the current best block",
+	"gettxoutsetinforesult-bestblock":      "The hash of the current best block",
+	"gettxoutsetinforesult-transactions":   "The number of transactions with unspent outputs",
+	"gettxoutsetinforesult-txouts":         "The number of unspent transaction outputs",
+	"gettxoutsetinforesult-serializedhash": "The hash of all of the unspent transaction outputs, which only depends on the outputs in the set",
+	"gettxoutsetinforesult-disksize":       "The serialized size of the unspent transaction output set",
+	"gettxoutsetinforesult-totalamount":    "The total amount of all unspent transaction outputs in DCR",
+
 	// GetWorkResult help.
 	"getworkresult-data":     "Hex-encoded block data",
 	"getworkresult-hash1":    "(DEPRECATED) Hex-encoded formatted hash buffer",
@@ -709,6 +802,14 @@ var helpDescsEnUS = map[string]string{
 	// RebroadcastWinnerCmd help.
 	"rebroadcastwinners--synopsis": "Asks the daemon to rebroadcast the winners of the voting lottery.\n",
 
+	// SaveMempoolCmd help.
+	"savemempool--synopsis": "Writes the regular transactions and ticket purchases in the memory pool to the mempool.dat file in the data directory, replacing any existing one.\n" +
+		"The file is also written when the server shuts down and loaded when it starts.",
+
+	// SaveMempoolResult help.
+	"savemempoolresult-numtxns": "The number of transactions written",
+	"savemempoolresult-path":    "The path of the written file",
+
 	// SearchRawTransactionsCmd help.
 	"searchrawtransactions--synopsis": "Returns raw data for transactions involving the passed address.\n" +
 		"Returned transactions are pulled from both the database, and transactions currently in the mempool.\n" +
@@ -852,6 +953,15 @@ var helpDescsEnUS = map[string]string{
 	"livetickets--synopsis":     "Request tickets the live ticket hashes from the ticket database",
 	"liveticketsresult-tickets": "List of live tickets",
 
+	// LoadMempoolCmd help.
+	"loadmempool--synopsis": "Adds the transactions in the mempool.dat file in the data directory to the memory pool.\n" +
+		"The transactions are subject to the same rules as transactions received from the network, so expired, invalid, and insufficient fee transactions are rejected.",
+
+	// LoadMempoolResult help.
+	"loadmempoolresult-accepted": "The number of transactions added to the memory pool, including any orphans they allowed to be accepted",
+	"loadmempoolresult-rejected": "The number of transactions that were rejected",
+	"loadmempoolresult-path":     "The path of the loaded file",
+
 	// MissedTickets help.
 	"missedtickets--synopsis":     "Request tickets the client missed",
 	"missedticketsresult-tickets": "List of missed tickets",
@@ -939,12 +1049,14 @@ var helpDescsEnUS = map[string]string{
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[string][]interface{}{
 	"addnode":               nil,
+	"clearbanned":           nil,
 	"createrawsstx":         {(*string)(nil)},
 	"createrawssrtx":        {(*string)(nil)},
 	"createrawtransaction":  {(*string)(nil)},
 	"debuglevel":            {(*string)(nil), (*string)(nil)},
 	"decoderawtransaction":  {(*dcrjson.TxRawDecodeResult)(nil)},
 	"decodescript":          {(*dcrjson.DecodeScriptResult)(nil)},
+	"dumputxosnapshot":      {(*dcrjson.DumpUtxoSnapshotResult)(nil)},
 	"estimatefee":           {(*float64)(nil)},
 	"estimatesmartfee":      {(*float64)(nil)},
 	"estimatestakediff":     {(*dcrjson.EstimateStakeDiffResult)(nil)},
@@ -978,6 +1090,7 @@ var rpcResultTypes = map[string][]interface{}{
 	"getgenerate":           {(*bool)(nil)},
 	"gethashespersec":       {(*float64)(nil)},
 	"getheaders":            {(*dcrjson.GetHeadersResult)(nil)},
+	"getindexinfo":          {(*[]dcrjson.GetIndexInfoResult)(nil)},
 	"getinfo":               {(*dcrjson.InfoChainResult)(nil)},
 	"getmempoolinfo":        {(*dcrjson.GetMempoolInfoResult)(nil)},
 	"getmininginfo":         {(*dcrjson.GetMiningInfoResult)(nil)},
@@ -988,18 +1101,23 @@ var rpcResultTypes = map[string][]interface{}{
 	"getrawtransaction":     {(*string)(nil), (*dcrjson.TxRawResult)(nil)},
 	"getticketpoolvalue":    {(*float64)(nil)},
 	"gettxout":              {(*dcrjson.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":       {(*dcrjson.GetTxOutSetInfoResult)(nil)},
 	"getvoteinfo":           {(*dcrjson.GetVoteInfoResult)(nil)},
 	"getwork":               {(*dcrjson.GetWorkResult)(nil), (*bool)(nil)},
 	"getcoinsupply":         {(*int64)(nil)},
 	"help":                  {(*string)(nil), (*string)(nil)},
+	"listbanned":            {(*[]dcrjson.ListBannedResult)(nil)},
 	"livetickets":           {(*dcrjson.LiveTicketsResult)(nil)},
+	"loadmempool":           {(*dcrjson.LoadMempoolResult)(nil)},
 	"missedtickets":         {(*dcrjson.MissedTicketsResult)(nil)},
 	"node":                  nil,
 	"ping":                  nil,
 	"rebroadcastmissed":     nil,
 	"rebroadcastwinners":    nil,
+	"savemempool":           {(*dcrjson.SaveMempoolResult)(nil)},
 	"searchrawtransactions": {(*string)(nil), (*[]dcrjson.SearchRawTransactionsResult)(nil)},
 	"sendrawtransaction":    {(*string)(nil)},
+	"setban":                nil,
 	"setgenerate":           nil,
 	"stop":                  {(*string)(nil)},
 	"submitblock":           {nil, (*string)(nil)},
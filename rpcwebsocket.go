@@ -3,9 +3,6 @@ package main
 import (
 	"bytes"
 	"container/list"
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -23,6 +20,7 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/dcrjson/v2"
 	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/spv"
 	"github.com/decred/dcrd/txscript"
 	"github.com/decred/dcrd/wire"
 )
@@ -77,7 +75,7 @@ var wsHandlersBeforeInit = map[string]wsCommandHandler{
 // must be run in a separate goroutine.  It should be invoked from the websocket
 // server handler which runs each new connection in a new goroutine thereby
 // satisfying the requirement.
-func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, authenticated bool, isAdmin bool) {
+func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, authenticated bool, acl *rpcACL) {
 	// Clear the read deadline that was set before the websocket hijacked
 	// the connection.
 	conn.SetReadDeadline(timeZeroVal)
@@ -95,7 +93,7 @@ func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, au
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shutdown.  Once it has shutdown (and hence
 	// disconnected), remove it and any notifications it registered for.
-	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, isAdmin)
+	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, acl)
 	if err != nil {
 		rpcsLog.Errorf("Failed to serve client %s: %v", remoteAddr, err)
 		conn.Close()
@@ -308,6 +306,26 @@ func (m *wsNotificationManager) NotifyMempoolTx(tx *dcrutil.Tx, isNew bool) {
 	}
 }
 
+// NotifyMempoolTxReplaced passes a transaction removed from the mempool
+// because it, or a transaction it depends on, was replaced by the passed
+// replacement to the notification manager for transaction notification
+// processing.
+func (m *wsNotificationManager) NotifyMempoolTxReplaced(replaced, replacement *dcrutil.Tx) {
+	n := &notificationTxReplacedInMempool{
+		replaced:    replaced,
+		replacement: replacement,
+	}
+
+	// As NotifyMempoolTxReplaced will be called by mempool and the RPC
+	// server may no longer be running, use a select statement to unblock
+	// enqueuing the notification once the RPC server has begun shutting
+	// down.
+	select {
+	case m.queueNotification <- n:
+	case <-m.quit:
+	}
+}
+
 // WinningTicketsNtfnData is the data that is used to generate
 // winning ticket notifications (which indicate a block and
 // the tickets eligible to vote on it).
@@ -456,6 +474,10 @@ type notificationTxAcceptedByMempool struct {
 	isNew bool
 	tx    *dcrutil.Tx
 }
+type notificationTxReplacedInMempool struct {
+	replaced    *dcrutil.Tx
+	replacement *dcrutil.Tx
+}
 
 // Notification control requests
 type notificationRegisterClient wsClient
@@ -472,6 +494,27 @@ type notificationRegisterStakeDifficulty wsClient
 type notificationUnregisterStakeDifficulty wsClient
 type notificationRegisterNewMempoolTxs wsClient
 type notificationUnregisterNewMempoolTxs wsClient
+type notificationRegisterSubscription ntfnSubscription
+type notificationUnregisterSubscription ntfnSubscription
+
+// ntfnSubscription is a subscription to notifications for consumers other than
+// websocket clients, such as the streams of the gRPC server.  The notifications
+// the filter accepts are sent to the channel without blocking.  The channel is
+// closed, and the subscription is removed, when the consumer does not keep up
+// with the notifications.
+type ntfnSubscription struct {
+	filter func(n interface{}) bool
+	c      chan interface{}
+}
+
+// newNtfnSubscription returns a new subscription to the notifications accepted
+// by the passed filter, which buffers up to the passed number of notifications.
+func newNtfnSubscription(filter func(n interface{}) bool, bufSize int) *ntfnSubscription {
+	return &ntfnSubscription{
+		filter: filter,
+		c:      make(chan interface{}, bufSize),
+	}
+}
 
 // notificationHandler reads notifications and control messages from the queue
 // handler and processes one at a time.
@@ -492,6 +535,31 @@ func (m *wsNotificationManager) notificationHandler() {
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
 	stakeDifficultyNotifications := make(map[chan struct{}]*wsClient)
 	txNotifications := make(map[chan struct{}]*wsClient)
+	subscriptions := make(map[*ntfnSubscription]struct{})
+
+	// permitted returns the clients of the passed map whose users are
+	// authorized to receive the notification with the passed method.  The
+	// map itself is returned when all of them are.
+	permitted := func(clients map[chan struct{}]*wsClient, method string) map[chan struct{}]*wsClient {
+		var filtered map[chan struct{}]*wsClient
+		for quit, wsc := range clients {
+			if wsc.permitsNotification(method) {
+				continue
+			}
+			if filtered == nil {
+				filtered = make(map[chan struct{}]*wsClient,
+					len(clients))
+				for q, c := range clients {
+					filtered[q] = c
+				}
+			}
+			delete(filtered, quit)
+		}
+		if filtered == nil {
+			return clients
+		}
+		return filtered
+	}
 
 out:
 	for {
@@ -501,6 +569,9 @@ out:
 				// queueHandler quit.
 				break out
 			}
+			if len(subscriptions) != 0 {
+				m.notifySubscriptions(subscriptions, n)
+			}
 			switch n := n.(type) {
 			case *notificationBlockConnected:
 				block := (*dcrutil.Block)(n)
@@ -511,30 +582,37 @@ out:
 					continue
 				}
 
-				m.notifyBlockConnected(blockNotifications, block)
+				m.notifyBlockConnected(permitted(blockNotifications,
+					dcrjson.BlockConnectedNtfnMethod), block)
 
 			case *notificationBlockDisconnected:
-				m.notifyBlockDisconnected(blockNotifications,
+				m.notifyBlockDisconnected(permitted(blockNotifications,
+					dcrjson.BlockDisconnectedNtfnMethod),
 					(*dcrutil.Block)(n))
 
 			case *notificationReorganization:
-				m.notifyReorganization(blockNotifications,
+				m.notifyReorganization(permitted(blockNotifications,
+					dcrjson.ReorganizationNtfnMethod),
 					(*blockchain.ReorganizationNtfnsData)(n))
 
 			case *notificationWinningTickets:
-				m.notifyWinningTickets(winningTicketNotifications,
+				m.notifyWinningTickets(permitted(winningTicketNotifications,
+					dcrjson.WinningTicketsNtfnMethod),
 					(*WinningTicketsNtfnData)(n))
 
 			case *notificationSpentAndMissedTickets:
-				m.notifySpentAndMissedTickets(ticketSMNotifications,
+				m.notifySpentAndMissedTickets(permitted(ticketSMNotifications,
+					dcrjson.SpentAndMissedTicketsNtfnMethod),
 					(*blockchain.TicketNotificationsData)(n))
 
 			case *notificationNewTickets:
-				m.notifyNewTickets(ticketNewNotifications,
+				m.notifyNewTickets(permitted(ticketNewNotifications,
+					dcrjson.NewTicketsNtfnMethod),
 					(*blockchain.TicketNotificationsData)(n))
 
 			case *notificationStakeDifficulty:
-				m.notifyStakeDifficulty(stakeDifficultyNotifications,
+				m.notifyStakeDifficulty(permitted(stakeDifficultyNotifications,
+					dcrjson.StakeDifficultyNtfnMethod),
 					(*StakeDifficultyNtfnData)(n))
 
 			case *notificationTxAcceptedByMempool:
@@ -543,6 +621,13 @@ out:
 				}
 				m.notifyRelevantTxAccepted(n.tx, clients)
 
+			case *notificationTxReplacedInMempool:
+				if len(txNotifications) != 0 {
+					m.notifyForReplacedTx(permitted(txNotifications,
+						dcrjson.TxRemovedNtfnMethod),
+						n.replaced, n.replacement)
+				}
+
 			case *notificationRegisterBlocks:
 				wsc := (*wsClient)(n)
 				blockNotifications[wsc.quit] = wsc
@@ -603,6 +688,12 @@ out:
 				wsc := (*wsClient)(n)
 				delete(txNotifications, wsc.quit)
 
+			case *notificationRegisterSubscription:
+				subscriptions[(*ntfnSubscription)(n)] = struct{}{}
+
+			case *notificationUnregisterSubscription:
+				delete(subscriptions, (*ntfnSubscription)(n))
+
 			default:
 				rpcsLog.Warn("Unhandled notification type")
 			}
@@ -642,6 +733,41 @@ func (m *wsNotificationManager) UnregisterBlockUpdates(wsc *wsClient) {
 	m.queueNotification <- (*notificationUnregisterBlocks)(wsc)
 }
 
+// RegisterSubscription adds the passed subscription to the notification
+// manager.
+func (m *wsNotificationManager) RegisterSubscription(sub *ntfnSubscription) {
+	select {
+	case m.queueNotification <- (*notificationRegisterSubscription)(sub):
+	case <-m.quit:
+	}
+}
+
+// UnregisterSubscription removes the passed subscription from the notification
+// manager.
+func (m *wsNotificationManager) UnregisterSubscription(sub *ntfnSubscription) {
+	select {
+	case m.queueNotification <- (*notificationUnregisterSubscription)(sub):
+	case <-m.quit:
+	}
+}
+
+// notifySubscriptions sends the passed notification to the subscriptions that
+// accept it.  Subscriptions whose consumers do not keep up are closed and
+// removed.
+func (m *wsNotificationManager) notifySubscriptions(subs map[*ntfnSubscription]struct{}, n interface{}) {
+	for sub := range subs {
+		if !sub.filter(n) {
+			continue
+		}
+		select {
+		case sub.c <- n:
+		default:
+			delete(subs, sub)
+			close(sub.c)
+		}
+	}
+}
+
 // subscribedClients returns the set of all websocket client quit channels that
 // are registered to receive notifications regarding tx, either due to tx
 // spending a watched output or outputting to a watched address.  Matching
@@ -990,6 +1116,14 @@ func (m *wsNotificationManager) notifyForNewTx(clients map[chan struct{}]*wsClie
 	var verboseNtfn *dcrjson.TxAcceptedVerboseNtfn
 	var marshalledJSONVerbose []byte
 	for _, wsc := range clients {
+		method := dcrjson.TxAcceptedNtfnMethod
+		if wsc.verboseTxUpdates {
+			method = dcrjson.TxAcceptedVerboseNtfnMethod
+		}
+		if !wsc.permitsNotification(method) {
+			continue
+		}
+
 		if wsc.verboseTxUpdates {
 			if marshalledJSONVerbose != nil {
 				wsc.QueueNotification(marshalledJSONVerbose)
@@ -1018,6 +1152,25 @@ func (m *wsNotificationManager) notifyForNewTx(clients map[chan struct{}]*wsClie
 	}
 }
 
+// notifyForReplacedTx notifies websocket clients that have registered for
+// updates when a new transaction is added to the memory pool that a
+// transaction was removed from the memory pool due to being replaced.  The
+// replacement itself is announced via notifyForNewTx.
+func (m *wsNotificationManager) notifyForReplacedTx(clients map[chan struct{}]*wsClient, replaced, replacement *dcrutil.Tx) {
+	ntfn := dcrjson.NewTxRemovedNtfn(replaced.Hash().String(),
+		replacement.Hash().String())
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		rpcsLog.Errorf("Failed to marshal tx removed notification: %s",
+			err.Error())
+		return
+	}
+
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // txHexString returns the serialized transaction encoded in hexadecimal.
 func txHexString(tx *wire.MsgTx) string {
 	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
@@ -1090,6 +1243,9 @@ func (m *wsNotificationManager) notifyRelevantTxAccepted(tx *dcrutil.Tx,
 			return
 		}
 		for _, c := range clientsToNotify {
+			if !c.permitsNotification(dcrjson.RelevantTxAcceptedNtfnMethod) {
+				continue
+			}
 			c.QueueNotification(marshalled)
 		}
 	}
@@ -1180,9 +1336,11 @@ type wsClient struct {
 	// and therefore is allowed to communicated over the websocket.
 	authenticated bool
 
-	// isAdmin specifies whether a client may change the state of the server;
-	// false means its access is only to the limited set of RPC calls.
-	isAdmin bool
+	// acl describes the RPC calls the client may make and the notifications
+	// it may receive.  It is nil until the client has been authenticated.
+	// It is protected by the embedded mutex since it is set by the input
+	// handler and read by the notification manager.
+	acl *rpcACL
 
 	// sessionID is a random ID generated for each client when connected.
 	// These IDs may be queried by a client using the session RPC.  A change
@@ -1314,17 +1472,14 @@ out:
 				break out
 			case !c.authenticated:
 				// Check credentials.
-				login := authCmd.Username + ":" + authCmd.Passphrase
-				auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-				authSha := sha256.Sum256([]byte(auth))
-				cmp := subtle.ConstantTimeCompare(authSha[:], c.server.authsha[:])
-				limitcmp := subtle.ConstantTimeCompare(authSha[:], c.server.limitauthsha[:])
-				if cmp != 1 && limitcmp != 1 {
+				acl := c.server.checkLogin(authCmd.Username,
+					authCmd.Passphrase)
+				if acl == nil {
 					rpcsLog.Warnf("Auth failure.")
 					break out
 				}
 				c.authenticated = true
-				c.isAdmin = cmp == 1
+				c.setACL(acl)
 
 				// Marshal and send response.
 				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1337,24 +1492,22 @@ out:
 				continue
 			}
 
-			// Check if the client is using limited RPC credentials and
-			// error when not authorized to call the supplied RPC.
-			if !c.isAdmin {
-				if _, ok := rpcLimited[req.Method]; !ok {
-					jsonErr := &dcrjson.RPCError{
-						Code:    dcrjson.ErrRPCInvalidParams.Code,
-						Message: "limited user not authorized for this method",
-					}
-					// Marshal and send response.
-					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
-					if err != nil {
-						rpcsLog.Errorf("Failed to marshal parse failure "+
-							"reply: %v", err)
-						continue
-					}
-					c.SendMessage(reply, nil)
+			// Error when the client is not authorized to call the
+			// supplied RPC.
+			if !c.acl.permitsMethod(req.Method) {
+				jsonErr := &dcrjson.RPCError{
+					Code:    dcrjson.ErrRPCInvalidParams.Code,
+					Message: "user not authorized for this method",
+				}
+				// Marshal and send response.
+				reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
+				if err != nil {
+					rpcsLog.Errorf("Failed to marshal parse failure "+
+						"reply: %v", err)
 					continue
 				}
+				c.SendMessage(reply, nil)
+				continue
 			}
 
 			// Asynchronously handle the request.  A semaphore is used to
@@ -1551,18 +1704,15 @@ out:
 							break out
 						case !c.authenticated:
 							// Check credentials.
-							login := authCmd.Username + ":" + authCmd.Passphrase
-							auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-							authSha := sha256.Sum256([]byte(auth))
-							cmp := subtle.ConstantTimeCompare(authSha[:], c.server.authsha[:])
-							limitcmp := subtle.ConstantTimeCompare(authSha[:], c.server.limitauthsha[:])
-							if cmp != 1 && limitcmp != 1 {
+							acl := c.server.checkLogin(authCmd.Username,
+								authCmd.Passphrase)
+							if acl == nil {
 								rpcsLog.Warnf("Auth failure.")
 								break out
 							}
 
 							c.authenticated = true
-							c.isAdmin = cmp == 1
+							c.setACL(acl)
 
 							// Marshal and send response.
 							reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1578,27 +1728,25 @@ out:
 							continue
 						}
 
-						// Check if the client is using limited RPC credentials and
-						// error when not authorized to call the supplied RPC.
-						if !c.isAdmin {
-							if _, ok := rpcLimited[req.Method]; !ok {
-								jsonErr := &dcrjson.RPCError{
-									Code:    dcrjson.ErrRPCInvalidParams.Code,
-									Message: "limited user not authorized for this method",
-								}
-								// Marshal and send response.
-								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
-								if err != nil {
-									rpcsLog.Errorf("Failed to marshal parse failure "+
-										"reply: %v", err)
-									continue
-								}
-
-								if reply != nil {
-									results = append(results, reply)
-								}
+						// Error when the client is not authorized to call the
+						// supplied RPC.
+						if !c.acl.permitsMethod(req.Method) {
+							jsonErr := &dcrjson.RPCError{
+								Code:    dcrjson.ErrRPCInvalidParams.Code,
+								Message: "user not authorized for this method",
+							}
+							// Marshal and send response.
+							reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
+							if err != nil {
+								rpcsLog.Errorf("Failed to marshal parse failure "+
+									"reply: %v", err)
 								continue
 							}
+
+							if reply != nil {
+								results = append(results, reply)
+							}
+							continue
 						}
 
 						// Lookup the websocket extension for the command, if it doesn't
@@ -1606,7 +1754,7 @@ out:
 						var resp interface{}
 						wsHandler, ok := wsHandlers[cmd.method]
 						if ok {
-							resp, err = wsHandler(c, cmd.cmd)
+							resp, err = c.serviceWSHandler(wsHandler, cmd)
 						} else {
 							resp, err = c.server.standardCmdResult(cmd, nil)
 						}
@@ -1677,7 +1825,7 @@ func (c *wsClient) serviceRequest(r *parsedRPCCmd) {
 	// exist fallback to handling the command as a standard command.
 	wsHandler, ok := wsHandlers[r.method]
 	if ok {
-		result, err = wsHandler(c, r.cmd)
+		result, err = c.serviceWSHandler(wsHandler, r)
 	} else {
 		result, err = c.server.standardCmdResult(r, nil)
 	}
@@ -1691,6 +1839,15 @@ func (c *wsClient) serviceRequest(r *parsedRPCCmd) {
 	c.SendMessage(reply, nil)
 }
 
+// serviceWSHandler runs the passed websocket extension handler for the passed
+// command and records the time spent on it when metrics are enabled.
+func (c *wsClient) serviceWSHandler(handler wsCommandHandler, r *parsedRPCCmd) (interface{}, error) {
+	if m := c.server.server.metricsServer; m != nil {
+		defer m.observeRPC(r.method, time.Now())
+	}
+	return handler(c, r.cmd)
+}
+
 // notificationQueueHandler handles the queuing of outgoing notifications for
 // the websocket client.  This runs as a muxer for various sources of input to
 // ensure that queuing up notifications to be sent will not block.  Otherwise,
@@ -1847,6 +2004,28 @@ func (c *wsClient) QueueNotification(marshalledJSON []byte) error {
 	return nil
 }
 
+// setACL sets the ACL of the user the websocket client authenticated as.
+//
+// This function is safe for concurrent access.
+func (c *wsClient) setACL(acl *rpcACL) {
+	c.Lock()
+	c.acl = acl
+	c.Unlock()
+}
+
+// permitsNotification returns whether or not the user the websocket client
+// authenticated as is authorized to receive the notification with the passed
+// method.
+//
+// This function is safe for concurrent access.
+func (c *wsClient) permitsNotification(method string) bool {
+	c.Lock()
+	acl := c.acl
+	c.Unlock()
+
+	return acl.permitsNotification(method)
+}
+
 // Disconnected returns whether or not the websocket client is disconnected.
 func (c *wsClient) Disconnected() bool {
 	c.Lock()
@@ -1891,12 +2070,13 @@ func (c *wsClient) WaitForShutdown() {
 
 // newWebsocketClient returns a new websocket client given the notification
 // manager, websocket connection, remote address, and whether or not the client
-// has already been authenticated (via HTTP Basic access authentication).  The
+// has already been authenticated (via HTTP Basic access authentication or a TLS
+// client certificate) along with the ACL of the authenticated user.  The
 // returned client is ready to start.  Once started, the client will process
 // incoming and outgoing messages in separate goroutines complete with queuing
 // and asynchrous handling for long-running operations.
 func newWebsocketClient(server *rpcServer, conn *websocket.Conn,
-	remoteAddr string, authenticated bool, isAdmin bool) (*wsClient, error) {
+	remoteAddr string, authenticated bool, acl *rpcACL) (*wsClient, error) {
 
 	sessionID, err := wire.RandomUint64()
 	if err != nil {
@@ -1907,7 +2087,7 @@ func newWebsocketClient(server *rpcServer, conn *websocket.Conn,
 		conn:              conn,
 		addr:              remoteAddr,
 		authenticated:     authenticated,
-		isAdmin:           isAdmin,
+		acl:               acl,
 		sessionID:         sessionID,
 		server:            server,
 		serviceRequestSem: makeSemaphore(cfg.RPCMaxConcurrentReqs),
@@ -2004,6 +2184,25 @@ func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 		filter.mu.Unlock()
 	}
 
+	// Light clients only download the blocks whose committed filters match
+	// the watched data, so the filter must be watched by the syncer as well.
+	if syncer := wsc.server.server.spvSyncer; syncer != nil {
+		addrs := make([]dcrutil.Address, 0, len(cmd.Addresses))
+		for _, addr := range cmd.Addresses {
+			a, err := dcrutil.DecodeAddress(addr)
+			if err != nil {
+				continue
+			}
+			addrs = append(addrs, a)
+		}
+		if err := syncer.WatchAddresses(addrs, outPoints); err != nil {
+			return nil, &dcrjson.RPCError{
+				Code:    dcrjson.ErrRPCInvalidParameter,
+				Message: err.Error(),
+			}
+		}
+	}
+
 	return nil, nil
 }
 
@@ -2182,6 +2381,10 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 		return nil, err
 	}
 
+	if syncer := wsc.server.server.spvSyncer; syncer != nil {
+		return rescanSPV(syncer, filter, blockHashes)
+	}
+
 	discoveredData := make([]dcrjson.RescannedBlock, 0, len(blockHashes))
 
 	// Iterate over each block in the request and rescan.  When a block
@@ -2217,6 +2420,45 @@ func handleRescan(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return &dcrjson.RescanResult{DiscoveredData: discoveredData}, nil
 }
 
+// rescanSPV implements the rescan command extension for websocket connections
+// when running in spv mode.  Only the blocks whose committed filters match the
+// data watched by the light client syncer are downloaded and rescanned.
+func rescanSPV(syncer *spv.Syncer, filter *wsClientFilter, blockHashes []chainhash.Hash) (interface{}, error) {
+	blocks, err := syncer.Rescan(blockHashes)
+	switch err {
+	case nil:
+	case spv.ErrUnknownBlock:
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: "Failed to fetch block: " + err.Error(),
+		}
+	case spv.ErrNotConsecutive:
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCInvalidParameter,
+			Message: err.Error(),
+		}
+	default:
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCMisc,
+			Message: err.Error(),
+		}
+	}
+
+	discoveredData := make([]dcrjson.RescannedBlock, 0, len(blocks))
+	for _, msgBlock := range blocks {
+		block := dcrutil.NewBlock(msgBlock)
+		transactions := rescanBlock(filter, block)
+		if len(transactions) != 0 {
+			discoveredData = append(discoveredData, dcrjson.RescannedBlock{
+				Hash:         block.Hash().String(),
+				Transactions: transactions,
+			})
+		}
+	}
+
+	return &dcrjson.RescanResult{DiscoveredData: discoveredData}, nil
+}
+
 func init() {
 	wsHandlers = wsHandlersBeforeInit
 }
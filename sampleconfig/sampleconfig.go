@@ -29,6 +29,9 @@ const FileContents = `[Application Options]
 ; Use simnet.
 ; simnet=1
 
+; Use regnet.
+; regnet=1
+
 ; Connect via a SOCKS5 proxy.  NOTE: Specifying a proxy will disable listening
 ; for incoming connections unless listen addresses are provided via the 'listen'
 ; option.
@@ -111,6 +114,13 @@ const FileContents = `[Application Options]
 ; Maximum number of inbound and outbound peers.
 ; maxpeers=8
 
+; Number of additional outbound peers that are only used to relay blocks and
+; headers.  Transactions and addresses are never relayed to or accepted from
+; them, which makes it harder to infer the network topology.  The block relay
+; only peers connected at shutdown are saved to anchors.json in the data
+; directory and reconnected first on the next startup.
+; blockrelayonlypeers=2
+
 ; Disable banning of misbehaving peers.
 ; nobanning=1
 
@@ -118,7 +128,8 @@ const FileContents = `[Application Options]
 ; banthreshold=100
 
 ; How long to ban misbehaving peers. Valid time units are {s, m, h}.
-; Minimum 1s.
+; Minimum 1s.  Bans are saved to banlist.json in the data directory and can be
+; managed with the setban, listbanned, and clearbanned RPCs.
 ; banduration=24h
 ; banduration=11h30m15s
 
@@ -129,6 +140,40 @@ const FileContents = `[Application Options]
 ; whitelist=192.168.0.0/24
 ; whitelist=fd00::/16
 
+; Path to a file that maps IP addresses to the numbers of the autonomous systems
+; (ASNs) announcing them in the compact asmap format.  When set, addresses are
+; grouped by autonomous system instead of by network prefix when storing them
+; and choosing outbound peers, which makes it harder for an attacker controlling
+; many prefixes within a single autonomous system to monopolize the connections.
+; asmap=~/.dcrd/asmap.dat
+
+; Maximum number of bytes to upload to peers per 24 hours.  Once the remaining
+; upload budget is only enough to serve the new blocks expected for the rest
+; of the 24 hour cycle, blocks older than a week are no longer served to peers
+; that are not whitelisted.  The state of the target is shown by the
+; getnettotals RPC.  The default of 0 is unlimited.  The example below is a
+; target of 5 GB.
+; maxuploadtarget=5000000000
+
+; Maximum rate in KiB/s at which data is uploaded to all peers combined and to
+; each individual peer.  Whitelisted peers are exempt from both limits.  The
+; default of 0 is unlimited.
+; maxuploadrate=1024
+; maxpeeruploadrate=256
+
+; Encrypt the communication with peers that also support the encrypted
+; transport.  Communication with peers that do not support it is unaffected.
+; The node authenticates to peers with the identity key stored in
+; p2pidentity.key in the data directory, which is generated as needed and
+; whose public key is logged on startup.
+; p2pencryption=1
+
+; Require the peer at the given IP address to authenticate with the given
+; identity public key over the encrypted transport.  The peer is disconnected
+; when it does not support the encrypted transport or authenticates with a
+; different key.  Requires p2pencryption.  One peer per line.
+; p2ppeerkey=<pubkey>@192.168.1.1
+
 ; Disable DNS seeding for peers.  By default, when dcrd starts, it will use
 ; DNS to query for available peers to connect with.
 ; nodnsseed=1
@@ -170,15 +215,21 @@ const FileContents = `[Application Options]
 ; RPC server options - The following options control the built-in RPC server
 ; which is used to control and query information from a running dcrd process.
 ;
-; NOTE: The RPC server is disabled by default if no rpcuser or rpcpass is
-; specified.
+; NOTE: When no rpcuser or rpcpass is specified, random credentials are
+; generated on startup and written to a cookie file, which is deleted again on
+; shutdown.  Local clients such as dcrctl read the cookie file automatically.
 ; ------------------------------------------------------------------------------
 
 ; Secure the RPC API by specifying the username and password.  You must specify
-; both or the RPC server will be disabled.
+; both or cookie authentication will be used instead.
 ; rpcuser=whatever_username_you_want
 ; rpcpass=
 
+; Specify the file the random credentials are written to when no rpcuser or
+; rpcpass is specified.  The file is only readable by the user running dcrd.
+; The default is a file named .cookie in the network specific data directory.
+; rpccookiefile=~/.dcrd/data/mainnet/.cookie
+
 ; Specify the interfaces for the RPC server listen on.  One listen address per
 ; line.  NOTE: The default port is modified by some options such as 'testnet',
 ; so it is recommended to not specify a port and allow a proper default to be
@@ -215,12 +266,55 @@ const FileContents = `[Application Options]
 ; Specify the maximum number of concurrent RPC websocket clients.
 ; rpcmaxwebsockets=25
 
-; Use the following setting to disable the RPC server even if the rpcuser and
-; rpcpass are specified above.  This allows one to quickly disable the RPC
-; server without having to remove credentials from the config file.
+; Serve the unauthenticated and read-only REST interface under /rest/ on the RPC
+; listeners.  It provides blocks, transactions, header ranges, chain info,
+; mempool contents and unspent output lookups in the binary, hex or JSON format,
+; for example:
+;   /rest/block/<hash>.json
+;   /rest/tx/<txid>.hex  (requires txindex for transactions not in the mempool)
+;   /rest/headers/<count>/<hash>.bin
+;   /rest/chaininfo.json
+;   /rest/mempool/info.json
+;   /rest/mempool/contents.json
+;   /rest/getutxos/checkmempool/<txid>-<n>/<txid>-<n>.json
+; rest=1
+
+; Specify the maximum number of REST requests per second each client IP address
+; may make.
+; restratelimit=10
+
+; Specify the interfaces to serve the gRPC API on.  The API is defined in
+; rpc/dcrdrpc/api.proto and provides chain, mempool, mining and network queries
+; along with streaming notifications about blocks, reorganizations, winning
+; tickets and new mempool transactions.  It uses the same TLS certificate and the
+; same RPC users as the RPC server.  Clients authenticate with basic access
+; authentication in the authorization metadata or with a client certificate.
+; Unary calls in progress count towards rpcmaxclients and streaming calls are
+; limited to rpcmaxwebsockets.  The gRPC API is disabled if this option is not
+; specified.  The default port is 9113 on mainnet and 19113 on testnet.
+; grpclisten=127.0.0.1
+; grpclisten=127.0.0.1:9113
+
+; Use the following setting to disable the RPC server.  This allows one to
+; quickly disable the RPC server without having to remove credentials from the
+; config file.
 ; norpc=1
 
 
+; ------------------------------------------------------------------------------
+; Metrics
+; ------------------------------------------------------------------------------
+
+; Specify the interfaces to serve Prometheus metrics on over HTTP.  The metrics
+; are available at http://ipaddr:<metricsport>/metrics and cover the chain, the
+; memory pool, the signature cache, the database cache, the connected peers and
+; the latency of RPC calls.  The endpoint is not authenticated, so it should
+; only be exposed to trusted networks.  The server is disabled if this option is
+; not specified.  The default port is 9108.
+; metricslisten=127.0.0.1
+; metricslisten=127.0.0.1:9108
+
+
 
 ; ------------------------------------------------------------------------------
 ; Mempool Settings - The following options
@@ -239,9 +333,31 @@ const FileContents = `[Application Options]
 ; Limit orphan transaction pool to 1000 transactions.
 ; maxorphantx=1000
 
+; Limit the total size of the transactions in the memory pool to 300 MiB.  When
+; the limit is exceeded, the transactions with the lowest fee rates are evicted
+; along with the transactions that depend on them and the minimum fee rate for
+; accepting new transactions is raised temporarily.  Votes on the current best
+; block are never evicted.  Set to 0 to disable the limit.
+; maxmempool=300
+
 ; Do not accept transactions from remote peers.
 ; blocksonly=1
 
+; Relay new transactions, including the ones submitted with sendrawtransaction,
+; to a single randomly chosen outbound peer, which passes them along the same
+; way until one of the nodes on the path announces them to the network.  This
+; hides the node a transaction originated from.  Transactions that have not
+; been seen on the network after a random timeout are announced by every node
+; on the path.  May not be used with blocksonly.
+; dandelion=1
+
+; Announce transactions to peers that support it by periodically reconciling
+; the sets of transactions both sides would announce to each other with compact
+; sketches instead of announcing every transaction individually, which reduces
+; the bandwidth used for transaction announcements.  Transactions are still
+; announced individually to a small number of outbound peers.
+; txreconciliation=1
+
 ; Accept and relay non-standard transactions to the network regardless of the
 ; default network settings.
 ; acceptnonstd=1
@@ -271,6 +387,47 @@ const FileContents = `[Application Options]
 ; addrindex=1
 
 
+; ------------------------------------------------------------------------------
+; Block Pruning
+; ------------------------------------------------------------------------------
+
+; Delete the data of old blocks so the stored blocks stay under the given target
+; size in MiB.  The most recent blocks needed for reorganizations are always
+; kept, so the minimum target is 1024.  Pruned nodes do not serve historical
+; blocks to other peers and can't be used with txindex or addrindex.
+; prune=2048
+
+; Initialize a new blockchain database from a UTXO snapshot created with the
+; dumputxosnapshot RPC instead of syncing all of the blocks prior to it.  The
+; snapshot must be committed to by the network parameters.  It is ignored once
+; the database has been initialized.  The history prior to the snapshot is only
+; validated when backvalidate is set and is not served to other peers.  The
+; indexes that require every block are not available, so nocfilters and
+; noexistsaddrindex must be set and txindex and addrindex can't be used.
+; NOTE: The network parameters only commit to the genesis block so far, so this
+; is not yet usable for bootstrapping.
+; loadsnapshot=~/utxo.dat
+
+; Download and validate the blocks prior to the UTXO snapshot the database was
+; initialized from in the background using a separate database that is removed
+; afterwards.  The node is shut down when the validated history does not result
+; in the same UTXO set as the snapshot.
+; backvalidate=1
+
+
+; ------------------------------------------------------------------------------
+; Light Client
+; ------------------------------------------------------------------------------
+
+; Run as a light client that only syncs block headers.  The committed filter
+; headers are verified by comparing the ones served by several peers and the
+; committed filters are used to find and download the blocks relevant to the
+; addresses and outpoints loaded by websocket clients with loadtxfilter.  The
+; matches are delivered through the usual rescan and transaction notifications.
+; Implies blocksonly and can't be used with generate, txindex or addrindex.
+; spv=1
+
+
 ; ------------------------------------------------------------------------------
 ; Signature Verification Cache
 ; ------------------------------------------------------------------------------
@@ -298,6 +455,25 @@ const FileContents = `[Application Options]
 ; miningaddr=youraddress2
 ; miningaddr=youraddress3
 
+; Specify the interfaces to listen on for connections from stratum miners.
+; Miners are sent new jobs whenever the background block template generator
+; creates a new template, and the blocks they solve are submitted the same way
+; as blocks submitted via the submitblock RPC.  At least one mining address is
+; required.  The default port is 3333.  Stratum may only be bound to localhost
+; addresses unless at least one worker is specified below.
+; stratumlisten=127.0.0.1
+; stratumlisten=0.0.0.0:3333
+
+; Specify the worker names and passwords, separated by a colon, that stratum
+; miners may authorize as.  Miners may authorize as any worker when none are
+; specified.
+; stratumworker=worker1:password1
+; stratumworker=worker2:password2
+
+; Specify the minimum difficulty of the shares accepted from stratum miners as a
+; multiple of the minimum network difficulty.
+; stratumsharediff=1
+
 ; Specify the minimum block size in bytes to create.  By default, only
 ; transactions which have enough fees or a high enough priority will be included
 ; in generated block templates.  Specifying a minimum block size will instead
@@ -345,4 +521,41 @@ const FileContents = `[Application Options]
 ;   profile=192.168.1.123:6061
 ; Listen on ipv6 loopback interface:
 ;   profile=[::1]:6061
+
+
+[RPC Users]
+
+; ------------------------------------------------------------------------------
+; RPC users - The following options define any number of additional RPC users
+; that are only authorized for the RPC methods and websocket notifications
+; granted to them.  They must be placed in this section, after all of the
+; application options above.
+; ------------------------------------------------------------------------------
+
+; Add an RPC user that authenticates with a password.  The password is given as
+; a random salt and the hex-encoded HMAC-SHA256 of the password keyed by the
+; salt, which can be computed with, for example:
+;   salt=$(openssl rand -hex 16)
+;   printf '%s' 'password' | openssl dgst -sha256 -hmac "$salt"
+; rpcacluser=explorer:c0e54e4c2a32a4d9a9b1f1e1c1d0c8f2$e33e2e79db0a62080e7cb2d92c6f4a108a6123a37bde87857213a8b07da8fb45
+
+; Add an RPC user that authenticates with a TLS client certificate with the
+; given common name instead.  The certificate must be issued by one of the
+; certificate authorities in the rpcclientcafile.
+; rpcaclcertuser=monitor:monitor.example.com
+; rpcclientcafile=~/.dcrd/rpc-client-ca.cert
+
+; Authorize the users for RPC methods and websocket notifications, such as
+; blockconnected, txaccepted or relevanttxaccepted.  The lists are
+; comma-separated, may be repeated, and * matches everything.  Denied entries
+; take precedence over allowed ones.  Users are not authorized for anything by
+; default.  Note that the notify* and loadtxfilter methods that register for
+; notifications must be allowed as well.
+; rpcaclallow=explorer:getblock,getblockhash,getbestblock,getrawtransaction
+; rpcaclallow=explorer:notifyblocks
+; rpcaclallowntfn=explorer:blockconnected,blockdisconnected
+; rpcaclallow=monitor:getinfo,getmempoolinfo,getnettotals,getpeerinfo
+; rpcaclallow=wallet:*
+; rpcacldeny=wallet:stop,addnode,node,setban,clearbanned
+; rpcaclallowntfn=wallet:*
 `
@@ -24,6 +24,7 @@ import (
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/connmgr"
 	"github.com/decred/dcrd/database"
+	"github.com/decred/dcrd/dcrec/secp256k1"
 	"github.com/decred/dcrd/dcrutil"
 	"github.com/decred/dcrd/fees"
 	"github.com/decred/dcrd/gcs"
@@ -33,6 +34,7 @@ import (
 	"github.com/decred/dcrd/mempool/v2"
 	"github.com/decred/dcrd/mining"
 	"github.com/decred/dcrd/peer"
+	"github.com/decred/dcrd/spv"
 	"github.com/decred/dcrd/txscript"
 	"github.com/decred/dcrd/wire"
 )
@@ -56,11 +58,25 @@ const (
 	connectionRetryInterval = time.Second * 5
 
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.NodeCFVersion
+	maxProtocolVersion = wire.TxReconciliationVersion
 
 	// maxKnownAddrsPerPeer is the maximum number of items to keep in the
 	// per-peer known address cache.
 	maxKnownAddrsPerPeer = 10000
+
+	// maxTxFloodPeers is the maximum number of outbound peers transactions
+	// are still announced to individually when the reconciliation of
+	// transaction announcements is enabled.
+	maxTxFloodPeers = 4
+
+	// defaultSPVFilterHeaderPeers is the number of peers the committed
+	// filter headers are compared across when running in spv mode.
+	defaultSPVFilterHeaderPeers = 3
+
+	// defaultSPVMaxInFlight is the maximum number of committed filters and
+	// blocks that are requested from a single peer at the same time when
+	// running in spv mode.
+	defaultSPVMaxInFlight = 16
 )
 
 var (
@@ -116,13 +132,17 @@ type updatePeerHeightsMsg struct {
 }
 
 // peerState maintains state of inbound, persistent, outbound peers as well
-// as banned peers and outbound groups.
+// as outbound groups.
 type peerState struct {
 	inboundPeers    map[int32]*serverPeer
 	outboundPeers   map[int32]*serverPeer
 	persistentPeers map[int32]*serverPeer
-	banned          map[string]time.Time
 	outboundGroups  map[string]int
+
+	// stemPeer is the outbound peer transactions in the stem phase of the
+	// dandelion relay are relayed to until the stem peer expiry.
+	stemPeer       *serverPeer
+	stemPeerExpiry time.Time
 }
 
 // ConnectionsWithIP returns the number of connections with the given IP.
@@ -181,23 +201,33 @@ type server struct {
 	bytesSent     uint64 // Total bytes sent by all peers since start.
 	started       int32
 	shutdown      int32
+	mempoolLoaded int32
 
 	chainParams          *chaincfg.Params
 	addrManager          *addrmgr.AddrManager
 	connManager          *connmgr.ConnManager
 	sigCache             *txscript.SigCache
 	rpcServer            *rpcServer
+	grpcServer           *grpcServer
 	blockManager         *blockManager
+	spvSyncer            *spv.Syncer
+	historyValidator     *historyValidator
 	bg                   *BgBlkTmplGenerator
 	txMemPool            *mempool.TxPool
 	feeEstimator         *fees.Estimator
 	cpuMiner             *CPUMiner
+	stratumServer        *stratumServer
+	metricsServer        *metricsServer
+	banList              *banList
 	modifyRebroadcastInv chan interface{}
 	newPeers             chan *serverPeer
 	donePeers            chan *serverPeer
 	banPeers             chan *serverPeer
 	query                chan interface{}
 	relayInv             chan relayMsg
+	relayStem            chan relayStemMsg
+	localStemTxnsMtx     sync.Mutex
+	localStemTxns        map[chainhash.Hash]struct{}
 	broadcast            chan broadcastMsg
 	peerHeightsUpdate    chan updatePeerHeightsMsg
 	wg                   sync.WaitGroup
@@ -206,9 +236,16 @@ type server struct {
 	db                   database.DB
 	timeSource           blockchain.MedianTimeSource
 	services             wire.ServiceFlag
+	p2pIdentity          *secp256k1.PrivateKey
+	uploadTarget         *uploadTarget
+	uploadLimiter        *peer.RateLimiter
 	context              context.Context
 	cancel               context.CancelFunc
 
+	// mempoolPersistMtx serializes saving and loading the persisted memory
+	// pool contents.
+	mempoolPersistMtx sync.Mutex
+
 	// The following fields are used for optional indexes.  They will be nil
 	// if the associated index is not enabled.  These fields are set during
 	// initial creation of the server and never changed afterwards, so they
@@ -217,6 +254,11 @@ type server struct {
 	addrIndex       *indexers.AddrIndex
 	existsAddrIndex *indexers.ExistsAddrIndex
 	cfIndex         *indexers.CFIndex
+
+	// indexManager keeps the optional indexes up to date with the main
+	// chain in the background.  It will be nil if none of the optional
+	// indexes are enabled.
+	indexManager *indexers.Manager
 }
 
 // serverPeer extends the peer to maintain state shared by the server and
@@ -227,6 +269,7 @@ type serverPeer struct {
 	connReq         *connmgr.ConnReq
 	server          *server
 	persistent      bool
+	blockRelayOnly  bool
 	continueHash    *chainhash.Hash
 	relayMtx        sync.Mutex
 	disableRelayTx  bool
@@ -243,6 +286,12 @@ type serverPeer struct {
 	addrsSent          bool
 	getMiningStateSent bool
 
+	// pendingCmpctBlock is the block announced by the peer with a compact
+	// block message while the transactions that were not found in the
+	// memory pool are requested from it.  It is only accessed from the
+	// message handlers of the peer, which are invoked sequentially.
+	pendingCmpctBlock *partialBlock
+
 	// The following chans are used to sync blockmanager and server.
 	txProcessed    chan struct{}
 	blockProcessed chan struct{}
@@ -266,6 +315,9 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 // newestBlock returns the current best block hash and height using the format
 // required by the configuration for the peer package.
 func (sp *serverPeer) newestBlock() (*chainhash.Hash, int64, error) {
+	if sp.server.spvSyncer != nil {
+		return sp.server.spvSyncer.BestBlock()
+	}
 	best := sp.server.blockManager.chain.BestSnapshot()
 	return &best.Hash, best.Height, nil
 }
@@ -387,14 +439,27 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		addrManager.SetServices(remoteAddr, msg.Services)
 	}
 
+	// Reject peers from banned subnets.  This normally happens when the
+	// connection is established, but the ban might have been added since.
+	if banEnd, banned := sp.server.banList.BannedUntil(remoteAddr.IP); banned {
+		srvrLog.Debugf("Rejecting peer %s which is banned for another %v",
+			sp.Peer, time.Until(banEnd))
+		return wire.NewMsgReject(msg.Command(), wire.RejectInvalid,
+			"banned")
+	}
+
 	// Ignore peers that have a protcol version that is too old.  The peer
 	// negotiation logic will disconnect it after this callback returns.
 	if msg.ProtocolVersion < int32(wire.InitialProcotolVersion) {
 		return nil
 	}
 
-	// Reject outbound peers that are not full nodes.
+	// Reject outbound peers that are not full nodes.  Light clients also
+	// require the peers to serve committed filters.
 	wantServices := wire.SFNodeNetwork
+	if cfg.SPV {
+		wantServices |= wire.SFNodeCF
+	}
 	if !isInbound && !hasServices(msg.Services, wantServices) {
 		missingServices := wantServices & ^msg.Services
 		srvrLog.Debugf("Rejecting peer %s with services %v due to not "+
@@ -410,11 +475,16 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 	// on the simulation test network since it is only intended to connect
 	// to specified peers and actively avoids advertising and connecting to
 	// discovered peers.
+	//
+	// Addresses are neither sent to nor requested from block relay only
+	// peers.
 	if !cfg.SimNet && !isInbound {
 		// Advertise the local address when the server accepts incoming
 		// connections and it believes itself to be close to the best
 		// known tip.
-		if !cfg.DisableListen && sp.server.blockManager.IsCurrent() {
+		if !cfg.DisableListen && !sp.blockRelayOnly &&
+			sp.server.blockManager.IsCurrent() {
+
 			// Get address that best matches.
 			lna := addrManager.GetBestLocalAddress(remoteAddr)
 			if addrmgr.IsRoutable(lna) {
@@ -426,7 +496,7 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 
 		// Request known addresses if the server address manager needs
 		// more.
-		if addrManager.NeedMoreAddresses() {
+		if !sp.blockRelayOnly && addrManager.NeedMoreAddresses() {
 			p.QueueMessage(wire.NewMsgGetAddr(), nil)
 		}
 
@@ -434,15 +504,32 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		addrManager.Good(remoteAddr)
 	}
 
-	// Choose whether or not to relay transactions.
-	sp.setDisableRelayTx(msg.DisableRelayTx)
+	// Choose whether or not to relay transactions.  They are never relayed
+	// to block relay only peers.
+	sp.setDisableRelayTx(msg.DisableRelayTx || sp.blockRelayOnly)
+
+	// Request new blocks to be announced with compact blocks when the peer
+	// supports them.  Compact blocks are not useful without a memory pool
+	// to reconstruct them from.
+	if p.ProtocolVersion() >= wire.CompactBlocksVersion && !cfg.BlocksOnly {
+		p.QueueMessage(wire.NewMsgSendCmpct(true, wire.CmpctBlockVersion),
+			nil)
+	}
 
 	// Add the remote peer time as a sample for creating an offset against
 	// the local clock to keep the network time in sync.
 	sp.server.timeSource.AddTimeSample(p.Addr(), msg.Timestamp)
 
-	// Signal the block manager this peer is a new sync candidate.
-	sp.server.blockManager.NewPeer(sp)
+	// Signal the block manager, or the light client syncer when running in
+	// spv mode, this peer is a new sync candidate.
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.NewPeer(sp.Peer)
+	} else {
+		sp.server.blockManager.NewPeer(sp)
+	}
+	if sp.server.historyValidator != nil {
+		sp.server.historyValidator.NewPeer(sp)
+	}
 
 	// Add valid peer to the server.
 	sp.server.AddPeer(sp)
@@ -453,6 +540,14 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 // and sends an inventory message with the contents of the memory pool up to the
 // maximum inventory allowed per message.
 func (sp *serverPeer) OnMemPool(p *peer.Peer, msg *wire.MsgMemPool) {
+	// Don't reveal the contents of the memory pool to block relay only
+	// peers.
+	if sp.blockRelayOnly {
+		peerLog.Tracef("Ignoring mempool from %v - block relay only peer",
+			sp.Peer)
+		return
+	}
+
 	// A decaying ban score increase is applied to prevent flooding.
 	// The ban score accumulates and passes the ban threshold if a burst of
 	// mempool messages comes from a peer. The score decays each minute to
@@ -518,6 +613,13 @@ func (sp *serverPeer) pushMiningStateMsg(height uint32, blockHashes []chainhash.
 // It constructs a list of the current best blocks and votes that should be
 // mined on and pushes a miningstate wire message back to the requesting peer.
 func (sp *serverPeer) OnGetMiningState(p *peer.Peer, msg *wire.MsgGetMiningState) {
+	// Don't reveal the votes in the memory pool to block relay only peers.
+	if sp.blockRelayOnly {
+		peerLog.Tracef("Ignoring getminingstate from %v - block relay "+
+			"only peer", sp.Peer)
+		return
+	}
+
 	if sp.getMiningStateSent {
 		peerLog.Tracef("Ignoring getminingstate from %v - already sent", sp.Peer)
 		return
@@ -606,6 +708,11 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 			msg.TxHash(), p)
 		return
 	}
+	if sp.blockRelayOnly {
+		peerLog.Tracef("Ignoring tx %v from %v - block relay only peer",
+			msg.TxHash(), p)
+		return
+	}
 
 	// Add the transaction to the known inventory for the peer.
 	// Convert the raw MsgTx to a dcrutil.Tx which provides some convenience
@@ -623,6 +730,27 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 	<-sp.txProcessed
 }
 
+// OnStemTx is invoked when a peer receives a stemtx wire message.  The
+// transaction is relayed further in the stem phase of the dandelion relay when
+// it is enabled, unless the stem phase is randomly ended here.  Otherwise it is
+// handled like a transaction received in a tx message.
+func (sp *serverPeer) OnStemTx(p *peer.Peer, msg *wire.MsgStemTx) {
+	if !cfg.Dandelion || cfg.BlocksOnly || sp.blockRelayOnly ||
+		shouldFluff() {
+
+		sp.OnTx(p, msg.Tx)
+		return
+	}
+
+	tx := dcrutil.NewTx(msg.Tx)
+	err := sp.server.StemTransaction(tx, sp, false)
+	if err != nil {
+		peerLog.Debugf("Unable to relay stem transaction %v from %v: "+
+			"%v -- fluffing it", tx.Hash(), p, err)
+		sp.OnTx(p, msg.Tx)
+	}
+}
+
 // OnBlock is invoked when a peer receives a block wire message.  It blocks
 // until the network block has been fully processed.
 func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
@@ -634,6 +762,20 @@ func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	iv := wire.NewInvVect(wire.InvTypeBlock, block.Hash())
 	p.AddKnownInventory(iv)
 
+	// Light clients only check the block against the headers and committed
+	// filters, which does not need to hold up further receives.
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueBlock(msg, p)
+		return
+	}
+
+	// Blocks prior to the utxo snapshot the block database was initialized
+	// from are validated separately by the history validator.
+	hv := sp.server.historyValidator
+	if hv != nil && hv.HandleBlock(sp, block) {
+		return
+	}
+
 	// Queue the block up to be handled by the block manager and
 	// intentionally block further receives until the network block is fully
 	// processed and known good or bad.  This helps prevent a malicious peer
@@ -647,12 +789,214 @@ func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	<-sp.blockProcessed
 }
 
+// requestFullBlock requests the block with the passed hash from the peer with
+// a getdata message.  It is used when a block announced with a compact block
+// message can't be reconstructed.
+func (sp *serverPeer) requestFullBlock(hash *chainhash.Hash) {
+	err := sp.server.blockManager.RequestFromPeer(sp,
+		[]*chainhash.Hash{hash}, nil)
+	if err != nil {
+		peerLog.Errorf("Failed to request block %v from %v: %v", hash,
+			sp.Peer, err)
+	}
+}
+
+// processCmpctBlock reconstructs the passed partial block, which must not be
+// missing any transactions, and queues it up to be handled by the block
+// manager.  It blocks until the block has been fully processed.  The full block
+// is requested when the reconstructed block is not valid.
+func (sp *serverPeer) processCmpctBlock(pb *partialBlock) {
+	block, err := pb.block()
+	if err != nil {
+		peerLog.Debugf("Unable to reconstruct compact block from %v: "+
+			"%v -- requesting full block", sp.Peer, err)
+		sp.requestFullBlock(&pb.hash)
+		return
+	}
+
+	sp.server.blockManager.QueueCmpctBlock(block, sp)
+	<-sp.blockProcessed
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock wire message.  The
+// block is reconstructed from the transactions in the memory pool and the ones
+// that are missing are requested from the peer with a getblocktxn message.  It
+// blocks until the block has been fully processed when no transactions are
+// missing.
+func (sp *serverPeer) OnCmpctBlock(p *peer.Peer, msg *wire.MsgCmpctBlock) {
+	blockHash := msg.Header.BlockHash()
+	iv := wire.NewInvVect(wire.InvTypeBlock, &blockHash)
+	p.AddKnownInventory(iv)
+	sp.UpdateLastAnnouncedBlock(&blockHash)
+
+	// Handle the announcement like an inventory vector when the chain is
+	// not current since the memory pool is unlikely to have the
+	// transactions of the block in that case and there is no memory pool
+	// in blocks only mode.
+	bm := sp.server.blockManager
+	if cfg.BlocksOnly || !bm.IsCurrent() {
+		invMsg := wire.NewMsgInvSizeHint(1)
+		invMsg.AddInvVect(iv)
+		bm.QueueInv(invMsg, sp)
+		return
+	}
+
+	// Ignore blocks that are already known.
+	haveBlock, err := bm.chain.HaveBlock(&blockHash)
+	if err != nil {
+		peerLog.Errorf("Failed to look up block %v: %v", blockHash, err)
+		return
+	}
+	if haveBlock {
+		return
+	}
+
+	// Ensure the header has valid proof of work before spending any effort
+	// on reconstructing the block so that it is not possible to make the
+	// memory pool be scanned and transactions be requested for bogus
+	// blocks.
+	header := &msg.Header
+	err = blockchain.CheckProofOfWork(header, sp.server.chainParams.PowLimit)
+	if err != nil {
+		peerLog.Infof("Compact block %v from %v has invalid proof of "+
+			"work: %v -- disconnecting", blockHash, sp.Peer, err)
+		sp.addBanScore(100, 0, "cmpctblock")
+		sp.Disconnect()
+		return
+	}
+
+	// Only reconstruct blocks that extend the current best chain with the
+	// required difficulty.  Any others are handled like an inventory
+	// vector so the full block is requested and validated by the block
+	// manager.
+	best := bm.chain.BestSnapshot()
+	extendsBest := header.PrevBlock == best.Hash &&
+		int64(header.Height) == best.Height+1
+	if extendsBest {
+		bits, err := bm.chain.CalcNextRequiredDifficulty(header.Timestamp)
+		extendsBest = err == nil && bits == header.Bits
+	}
+	if !extendsBest {
+		peerLog.Debugf("Compact block %v from %v does not extend the "+
+			"best chain -- handling as inventory", blockHash, sp.Peer)
+		invMsg := wire.NewMsgInvSizeHint(1)
+		invMsg.AddInvVect(iv)
+		bm.QueueInv(invMsg, sp)
+		return
+	}
+
+	pb, err := newPartialBlock(msg, sp.server.txMemPool.TxDescs())
+	if err != nil {
+		peerLog.Debugf("Unable to use compact block %v from %v: %v -- "+
+			"requesting full block", blockHash, sp.Peer, err)
+		sp.requestFullBlock(&blockHash)
+		return
+	}
+
+	indexes, stakeIndexes := pb.missing()
+	if len(indexes) == 0 && len(stakeIndexes) == 0 {
+		sp.processCmpctBlock(pb)
+		return
+	}
+
+	// Request the transactions that were not found in the memory pool.
+	peerLog.Debugf("Requesting %d transactions and %d stake transactions "+
+		"of compact block %v from %v", len(indexes), len(stakeIndexes),
+		blockHash, sp.Peer)
+	sp.pendingCmpctBlock = pb
+	getBlockTxn := wire.NewMsgGetBlockTxn(&blockHash)
+	getBlockTxn.Indexes = indexes
+	getBlockTxn.StakeIndexes = stakeIndexes
+	p.QueueMessage(getBlockTxn, nil)
+}
+
+// OnBlockTxn is invoked when a peer receives a blocktxn wire message.  The
+// transactions complete the block of the compact block message they were
+// requested for.  It blocks until the block has been fully processed.
+func (sp *serverPeer) OnBlockTxn(p *peer.Peer, msg *wire.MsgBlockTxn) {
+	pb := sp.pendingCmpctBlock
+	if pb == nil || pb.hash != msg.BlockHash {
+		peerLog.Debugf("Ignoring unrequested blocktxn for block %v from "+
+			"%v", msg.BlockHash, sp.Peer)
+		return
+	}
+	sp.pendingCmpctBlock = nil
+
+	if err := pb.fill(msg); err != nil {
+		peerLog.Debugf("Invalid blocktxn for block %v from %v: %v -- "+
+			"requesting full block", msg.BlockHash, sp.Peer, err)
+		sp.requestFullBlock(&pb.hash)
+		return
+	}
+	sp.processCmpctBlock(pb)
+}
+
+// OnGetBlockTxn is invoked when a peer receives a getblocktxn wire message.  It
+// responds with a blocktxn message with the requested transactions of the
+// block when it is one of the most recent blocks of the main chain.  Requests
+// for any other blocks are handled like a getdata message for the full block so
+// that the upload target applies and unknown blocks are answered with a
+// notfound message.
+func (sp *serverPeer) OnGetBlockTxn(p *peer.Peer, msg *wire.MsgGetBlockTxn) {
+	chain := sp.server.blockManager.chain
+	height, err := chain.BlockHeightByHash(&msg.BlockHash)
+	if err != nil || chain.BestSnapshot().Height-height > maxBlockTxnDepth {
+		getData := wire.NewMsgGetDataSizeHint(1)
+		getData.AddInvVect(wire.NewInvVect(wire.InvTypeBlock,
+			&msg.BlockHash))
+		sp.OnGetData(p, getData)
+		return
+	}
+
+	block, err := chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		peerLog.Debugf("Unable to fetch block %v requested by %v: %v",
+			msg.BlockHash, sp.Peer, err)
+		notFound := wire.NewMsgNotFound()
+		notFound.AddInvVect(wire.NewInvVect(wire.InvTypeBlock,
+			&msg.BlockHash))
+		sp.QueueMessage(notFound, nil)
+		return
+	}
+
+	selectTxns := func(txns []*wire.MsgTx, indexes []uint32) ([]*wire.MsgTx, bool) {
+		selected := make([]*wire.MsgTx, 0, len(indexes))
+		for _, index := range indexes {
+			if int(index) >= len(txns) {
+				return nil, false
+			}
+			selected = append(selected, txns[index])
+		}
+		return selected, true
+	}
+	msgBlock := block.MsgBlock()
+	blockTxn := wire.NewMsgBlockTxn(&msg.BlockHash)
+	var ok bool
+	blockTxn.Transactions, ok = selectTxns(msgBlock.Transactions,
+		msg.Indexes)
+	if ok {
+		blockTxn.STransactions, ok = selectTxns(msgBlock.STransactions,
+			msg.StakeIndexes)
+	}
+	if !ok {
+		peerLog.Infof("Peer %v requested transactions out of range of "+
+			"block %v -- disconnecting", sp.Peer, msg.BlockHash)
+		sp.Disconnect()
+		return
+	}
+	sp.QueueMessage(blockTxn, nil)
+}
+
 // OnInv is invoked when a peer receives an inv wire message and is used to
 // examine the inventory being advertised by the remote peer and react
 // accordingly.  We pass the message down to blockmanager which will call
 // QueueMessage with any appropriate responses.
 func (sp *serverPeer) OnInv(p *peer.Peer, msg *wire.MsgInv) {
-	if !cfg.BlocksOnly {
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueInv(msg, p)
+		return
+	}
+	if !cfg.BlocksOnly && !sp.blockRelayOnly {
 		if len(msg.InvList) > 0 {
 			sp.server.blockManager.QueueInv(msg, sp)
 		}
@@ -682,9 +1026,40 @@ func (sp *serverPeer) OnInv(p *peer.Peer, msg *wire.MsgInv) {
 // OnHeaders is invoked when a peer receives a headers wire message.  The
 // message is passed down to the block manager.
 func (sp *serverPeer) OnHeaders(p *peer.Peer, msg *wire.MsgHeaders) {
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueHeaders(msg, p)
+		return
+	}
 	sp.server.blockManager.QueueHeaders(msg, sp)
 }
 
+// OnCFilter is invoked when a peer receives a cfilter wire message.  The
+// message is passed down to the light client syncer when running in spv mode
+// and ignored otherwise.
+func (sp *serverPeer) OnCFilter(p *peer.Peer, msg *wire.MsgCFilter) {
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueCFilter(msg, p)
+	}
+}
+
+// OnCFHeaders is invoked when a peer receives a cfheaders wire message.  The
+// message is passed down to the light client syncer when running in spv mode
+// and ignored otherwise.
+func (sp *serverPeer) OnCFHeaders(p *peer.Peer, msg *wire.MsgCFHeaders) {
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueCFHeaders(msg, p)
+	}
+}
+
+// OnNotFound is invoked when a peer receives a notfound wire message.  The
+// message is passed down to the light client syncer when running in spv mode
+// so the data is requested from another peer.
+func (sp *serverPeer) OnNotFound(p *peer.Peer, msg *wire.MsgNotFound) {
+	if sp.server.spvSyncer != nil {
+		sp.server.spvSyncer.QueueNotFound(msg, p)
+	}
+}
+
 // handleGetData is invoked when a peer receives a getdata wire message and is
 // used to deliver block and transaction information.
 func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
@@ -713,6 +1088,11 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 	var waitChan chan struct{}
 	doneChan := make(chan struct{}, 1)
 
+	// Historical blocks are not served to peers that are not whitelisted
+	// once the upload target would no longer allow serving new blocks.
+	limitHistorical := !sp.isWhitelisted &&
+		!sp.server.uploadTarget.state().serveHistorical
+
 	for i, iv := range msg.InvList {
 		var c chan struct{}
 		// If this will be the last message we send.
@@ -725,8 +1105,27 @@ func (sp *serverPeer) OnGetData(p *peer.Peer, msg *wire.MsgGetData) {
 		var err error
 		switch iv.Type {
 		case wire.InvTypeTx:
+			// Transactions are not relayed over block-relay-only
+			// connections, so requests for them are answered with
+			// notfound to avoid revealing the contents of the memory
+			// pool to those peers.
+			if sp.blockRelayOnly {
+				if c != nil {
+					c <- struct{}{}
+				}
+				err = fmt.Errorf("transaction %v requested by "+
+					"block-relay-only peer", iv.Hash)
+				break
+			}
 			err = sp.server.pushTxMsg(sp, &iv.Hash, c, waitChan)
 		case wire.InvTypeBlock:
+			if limitHistorical && sp.server.isHistoricalBlock(&iv.Hash) {
+				peerLog.Infof("Upload target reached -- "+
+					"disconnecting peer %v requesting "+
+					"historical block %v", sp, iv.Hash)
+				sp.Disconnect()
+				return
+			}
 			err = sp.server.pushBlockMsg(sp, &iv.Hash, c, waitChan)
 		default:
 			peerLog.Warnf("Unknown type in inventory request %d",
@@ -776,6 +1175,17 @@ func (sp *serverPeer) OnGetBlocks(p *peer.Peer, msg *wire.MsgGetBlocks) {
 	hashList := chain.LocateBlocks(msg.BlockLocatorHashes, &msg.HashStop,
 		wire.MaxBlocksPerMsg)
 
+	// Don't announce historical blocks to peers that are not whitelisted
+	// once the upload target no longer allows serving them.
+	if len(hashList) > 0 && !sp.isWhitelisted &&
+		!sp.server.uploadTarget.state().serveHistorical &&
+		sp.server.isHistoricalBlock(&hashList[0]) {
+
+		peerLog.Debugf("Upload target reached -- ignoring getblocks "+
+			"for historical blocks from %v", sp)
+		return
+	}
+
 	// Generate inventory message.
 	invMsg := wire.NewMsgInv()
 	for i := range hashList {
@@ -963,8 +1373,33 @@ func (sp *serverPeer) OnGetCFHeaders(p *peer.Peer, msg *wire.MsgGetCFHeaders) {
 		return
 	}
 
-	// Generate cfheaders message and send it.
+	// The committed filter index is updated in the background, so only serve
+	// the headers of the blocks it has been updated through.  Peers receive
+	// fewer headers than requested, or none at all, while the index is
+	// catching up rather than no response.
 	cfIndex := sp.server.cfIndex
+	indexHeight, ok := sp.server.indexManager.TipHeight(cfIndex)
+	if !ok {
+		return
+	}
+	for i := range hashList {
+		height, err := chain.BlockHeightByHash(&hashList[i])
+		if err != nil || height > indexHeight {
+			hashList = hashList[:i]
+			break
+		}
+	}
+	if len(hashList) == 0 {
+		peerLog.Debugf("Committed filter index has not caught up with "+
+			"the blocks requested by %v", sp)
+		headersMsg := wire.NewMsgCFHeaders()
+		headersMsg.FilterType = msg.FilterType
+		headersMsg.StopHash = msg.HashStop
+		sp.QueueMessage(headersMsg, nil)
+		return
+	}
+
+	// Generate cfheaders message and send it.
 	headersMsg := wire.NewMsgCFHeaders()
 	for i := range hashList {
 		// Fetch the raw committed filter header bytes from the database.
@@ -1052,6 +1487,14 @@ func (sp *serverPeer) OnAddr(p *peer.Peer, msg *wire.MsgAddr) {
 		return
 	}
 
+	// Ignore addresses from block relay only peers since they are never
+	// requested from them.
+	if sp.blockRelayOnly {
+		peerLog.Tracef("Ignoring addr from %v - block relay only peer",
+			sp.Peer)
+		return
+	}
+
 	// A message that has no addresses is invalid.
 	if len(msg.AddrList) == 0 {
 		peerLog.Errorf("Command [%s] from %s does not contain any addresses",
@@ -1096,6 +1539,7 @@ func (sp *serverPeer) OnRead(p *peer.Peer, bytesRead int, msg wire.Message, err
 // the bytes sent by the server.
 func (sp *serverPeer) OnWrite(p *peer.Peer, bytesWritten int, msg wire.Message, err error) {
 	sp.server.AddBytesSent(uint64(bytesWritten))
+	sp.server.uploadTarget.addBytes(uint64(bytesWritten))
 }
 
 // randomUint16Number returns a random uint16 in a specified input range.  Note
@@ -1162,6 +1606,13 @@ func (s *server) AnnounceNewTransactions(newTxs []*dcrutil.Tx) {
 		iv := wire.NewInvVect(wire.InvTypeTx, tx.Hash())
 		s.RelayInventory(iv, tx, false)
 
+		// Transactions that originated from this node and were relayed
+		// in the stem phase of the dandelion relay are rebroadcast once
+		// they are fluffed.
+		if s.takeLocalStemTx(tx.Hash()) {
+			s.AddRebroadcastInventory(iv, tx)
+		}
+
 		if s.rpcServer != nil {
 			// Notify websocket clients about mempool transactions.
 			s.rpcServer.ntfnMgr.NotifyMempoolTx(tx, true)
@@ -1298,16 +1749,11 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		sp.Disconnect()
 		return false
 	}
-	if banEnd, ok := state.banned[host]; ok {
-		if time.Now().Before(banEnd) {
-			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
-			sp.Disconnect()
-			return false
-		}
-
-		srvrLog.Infof("Peer %s is no longer banned", host)
-		delete(state.banned, host)
+	if banEnd, banned := s.banList.BannedUntil(sp.NA().IP); banned {
+		srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
+			host, time.Until(banEnd))
+		sp.Disconnect()
+		return false
 	}
 
 	// Limit max number of connections from a single IP.  However, allow
@@ -1338,17 +1784,47 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 	if sp.Inbound() {
 		state.inboundPeers[sp.ID()] = sp
 	} else {
-		state.outboundGroups[addrmgr.GroupKey(sp.NA())]++
+		state.outboundGroups[s.addrManager.GroupKey(sp.NA())]++
 		if sp.persistent {
 			state.persistentPeers[sp.ID()] = sp
 		} else {
 			state.outboundPeers[sp.ID()] = sp
 		}
 	}
+	s.updateTxFloodPeers(state)
 
 	return true
 }
 
+// updateTxFloodPeers marks outbound peers as flooding peers, to which
+// transactions are still announced individually when the reconciliation of
+// transaction announcements is enabled, until there are maxTxFloodPeers of
+// them.  It is invoked from the peerHandler goroutine.
+func (s *server) updateTxFloodPeers(state *peerState) {
+	if !cfg.TxReconciliation {
+		return
+	}
+
+	var numFlooding int
+	var candidates []*serverPeer
+	state.forAllOutboundPeers(func(sp *serverPeer) {
+		switch {
+		case sp.TxFlooding():
+			numFlooding++
+		case !sp.blockRelayOnly:
+			candidates = append(candidates, sp)
+		}
+	})
+	for _, sp := range candidates {
+		if numFlooding >= maxTxFloodPeers {
+			break
+		}
+		srvrLog.Debugf("Announcing transactions individually to %v", sp)
+		sp.SetTxFlooding(true)
+		numFlooding++
+	}
+}
+
 // handleDonePeerMsg deals with peers that have signalled they are done.  It is
 // invoked from the peerHandler goroutine.
 func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
@@ -1362,13 +1838,16 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 	}
 	if _, ok := list[sp.ID()]; ok {
 		if !sp.Inbound() && sp.VersionKnown() {
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			state.outboundGroups[s.addrManager.GroupKey(sp.NA())]--
 		}
 		if !sp.Inbound() && sp.connReq != nil {
 			s.connManager.Disconnect(sp.connReq.ID())
 		}
 		delete(list, sp.ID())
 		srvrLog.Debugf("Removed peer %s", sp)
+		if sp.TxFlooding() {
+			s.updateTxFloodPeers(state)
+		}
 		return
 	}
 
@@ -1394,30 +1873,76 @@ func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
 		srvrLog.Debugf("can't split ban peer %s %v", sp.Addr(), err)
 		return
 	}
+	subnet, err := parseBanSubnet(host)
+	if err != nil {
+		srvrLog.Debugf("can't ban peer %s: %v", sp.Addr(), err)
+		return
+	}
 	direction := directionString(sp.Inbound())
 	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
 		cfg.BanDuration)
-	state.banned[host] = time.Now().Add(cfg.BanDuration)
+	err = s.banList.Ban(subnet, time.Now().Add(cfg.BanDuration))
+	if err != nil {
+		srvrLog.Errorf("Unable to save ban list: %v", err)
+	}
 }
 
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	// The compact block message is created on demand once for all peers that
+	// prefer compact block announcements.  Those peers are sent a headers or
+	// inventory message instead when it can't be created.
+	var cmpctBlock *wire.MsgCmpctBlock
+	var cmpctBlockCreated bool
+	getCmpctBlock := func() *wire.MsgCmpctBlock {
+		if cmpctBlockCreated {
+			return cmpctBlock
+		}
+		cmpctBlockCreated = true
+		block, ok := msg.data.(*dcrutil.Block)
+		if !ok {
+			peerLog.Warnf("Underlying data for compact block is not " +
+				"a block")
+			return nil
+		}
+		var err error
+		cmpctBlock, err = newCmpctBlock(block)
+		if err != nil {
+			peerLog.Errorf("Failed to create compact block: %v", err)
+		}
+		return cmpctBlock
+	}
+
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
 		}
 
+		// If the inventory is a block and the peer prefers compact
+		// blocks, generate and send a compact block message instead of
+		// an inventory message unless the peer already has the block.
+		if msg.invVect.Type == wire.InvTypeBlock &&
+			sp.WantsCompactBlocks() && !sp.IsKnownInventory(msg.invVect) {
+
+			if cmpctBlock := getCmpctBlock(); cmpctBlock != nil {
+				sp.AddKnownInventory(msg.invVect)
+				sp.QueueMessage(cmpctBlock, nil)
+				return
+			}
+		}
+
 		// If the inventory is a block and the peer prefers headers,
 		// generate and send a headers message instead of an inventory
 		// message.
 		if msg.invVect.Type == wire.InvTypeBlock && sp.WantsHeaders() {
-			blockHeader, ok := msg.data.(wire.BlockHeader)
+			block, ok := msg.data.(*dcrutil.Block)
 			if !ok {
 				peerLog.Warnf("Underlying data for headers" +
-					" is not a block header")
+					" is not a block")
 				return
 			}
+			blockHeader := block.MsgBlock().Header
 			msgHeaders := wire.NewMsgHeaders()
 			if err := msgHeaders.AddBlockHeader(&blockHeader); err != nil {
 				peerLog.Errorf("Failed to add block"+
@@ -1557,7 +2082,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		found := disconnectPeer(state.persistentPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			state.outboundGroups[s.addrManager.GroupKey(sp.NA())]--
 
 			peerLog.Debugf("Removing persistent peer %s:%d (reqid %d)",
 				sp.NA().IP, sp.NA().Port, sp.connReq.ID())
@@ -1602,7 +2127,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			state.outboundGroups[s.addrManager.GroupKey(sp.NA())]--
 		})
 		if found {
 			// If there are multiple outbound connections to the same
@@ -1610,7 +2135,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			// peers are found.
 			for found {
 				found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
-					state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+					state.outboundGroups[s.addrManager.GroupKey(sp.NA())]--
 				})
 			}
 			msg.reply <- nil
@@ -1652,16 +2177,23 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		userAgentComments = append(userAgentComments, version.PreRelease)
 	}
 
-	return &peer.Config{
+	peerCfg := &peer.Config{
 		Listeners: peer.MessageListeners{
 			OnVersion:        sp.OnVersion,
 			OnMemPool:        sp.OnMemPool,
 			OnGetMiningState: sp.OnGetMiningState,
 			OnMiningState:    sp.OnMiningState,
 			OnTx:             sp.OnTx,
+			OnStemTx:         sp.OnStemTx,
 			OnBlock:          sp.OnBlock,
+			OnCmpctBlock:     sp.OnCmpctBlock,
+			OnBlockTxn:       sp.OnBlockTxn,
+			OnGetBlockTxn:    sp.OnGetBlockTxn,
 			OnInv:            sp.OnInv,
 			OnHeaders:        sp.OnHeaders,
+			OnCFilter:        sp.OnCFilter,
+			OnCFHeaders:      sp.OnCFHeaders,
+			OnNotFound:       sp.OnNotFound,
 			OnGetData:        sp.OnGetData,
 			OnGetBlocks:      sp.OnGetBlocks,
 			OnGetHeaders:     sp.OnGetHeaders,
@@ -1681,9 +2213,29 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		UserAgentComments: userAgentComments,
 		Net:               sp.server.chainParams.Net,
 		Services:          sp.server.services,
-		DisableRelayTx:    cfg.BlocksOnly,
+		DisableRelayTx:    cfg.BlocksOnly || sp.blockRelayOnly,
 		ProtocolVersion:   maxProtocolVersion,
+		IdentityKey:       sp.server.p2pIdentity,
+		PinnedIdentity:    pinnedP2PIdentity,
+		TxReconciliation:  cfg.TxReconciliation,
 	}
+
+	// Whitelisted peers are exempt from the upload rate limits.
+	if !sp.isWhitelisted {
+		peerCfg.MaxUploadRate = cfg.MaxPeerUploadRate * 1024
+		peerCfg.UploadLimiter = sp.server.uploadLimiter
+	}
+	return peerCfg
+}
+
+// pinnedP2PIdentity returns the identity key the passed peer is required to
+// authenticate with over the encrypted transport as configured by the
+// p2ppeerkey option, or nil when its identity is not pinned.
+func pinnedP2PIdentity(p *peer.Peer) *secp256k1.PublicKey {
+	if len(cfg.p2pPeerKeys) == 0 || p.NA() == nil {
+		return nil
+	}
+	return cfg.p2pPeerKeys[p.NA().IP.String()]
 }
 
 // inboundPeerConnected is invoked by the connection manager when a new inbound
@@ -1691,6 +2243,17 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 // instance, associates it with the connection, and starts a goroutine to wait
 // for disconnection.
 func (s *server) inboundPeerConnected(conn net.Conn) {
+	// Reject connections from banned addresses before starting the
+	// handshake.
+	if ip := connIP(conn); ip != nil {
+		if banEnd, banned := s.banList.BannedUntil(ip); banned {
+			srvrLog.Debugf("Rejecting inbound connection from %s which is "+
+				"banned for another %v", ip, time.Until(banEnd))
+			conn.Close()
+			return
+		}
+	}
+
 	sp := newServerPeer(s, false)
 	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
 	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
@@ -1704,7 +2267,19 @@ func (s *server) inboundPeerConnected(conn net.Conn) {
 // request instance and the connection itself, and finally notifies the address
 // manager of the attempt.
 func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
+	// Don't connect to banned addresses.
+	if ip := connIP(conn); ip != nil {
+		if banEnd, banned := s.banList.BannedUntil(ip); banned {
+			srvrLog.Debugf("Dropping outbound connection to %s which is "+
+				"banned for another %v", ip, time.Until(banEnd))
+			s.connManager.Disconnect(c.ID())
+			return
+		}
+	}
+
 	sp := newServerPeer(s, c.Permanent)
+	sp.blockRelayOnly = c.BlockRelayOnly
+	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
 	p, err := peer.NewOutboundPeer(newPeerConfig(sp), c.Addr.String())
 	if err != nil {
 		srvrLog.Debugf("Cannot create outbound peer %s: %v", c.Addr, err)
@@ -1713,7 +2288,6 @@ func (s *server) outboundPeerConnected(c *connmgr.ConnReq, conn net.Conn) {
 	}
 	sp.Peer = p
 	sp.connReq = c
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
 	s.addrManager.Attempt(sp.NA())
@@ -1727,7 +2301,14 @@ func (s *server) peerDoneHandler(sp *serverPeer) {
 
 	// Only tell block manager we are gone if we ever told it we existed.
 	if sp.VersionKnown() {
-		s.blockManager.DonePeer(sp)
+		if s.spvSyncer != nil {
+			s.spvSyncer.DonePeer(sp.Peer)
+		} else {
+			s.blockManager.DonePeer(sp)
+		}
+		if s.historyValidator != nil {
+			s.historyValidator.DonePeer(sp)
+		}
 	}
 	close(sp.quit)
 }
@@ -1743,6 +2324,12 @@ func (s *server) peerHandler() {
 	// in this handler.
 	s.addrManager.Start()
 	s.blockManager.Start()
+	if s.spvSyncer != nil {
+		s.spvSyncer.Start()
+	}
+	if s.historyValidator != nil {
+		s.historyValidator.Start()
+	}
 
 	srvrLog.Tracef("Starting peer handler")
 
@@ -1750,7 +2337,6 @@ func (s *server) peerHandler() {
 		inboundPeers:    make(map[int32]*serverPeer),
 		persistentPeers: make(map[int32]*serverPeer),
 		outboundPeers:   make(map[int32]*serverPeer),
-		banned:          make(map[string]time.Time),
 		outboundGroups:  make(map[string]int),
 	}
 
@@ -1790,6 +2376,11 @@ out:
 		case invMsg := <-s.relayInv:
 			s.handleRelayInvMsg(state, invMsg)
 
+		// New transaction in the stem phase to be relayed to the stem
+		// peer.
+		case stemMsg := <-s.relayStem:
+			s.handleRelayStemMsg(state, stemMsg)
+
 		// Message to broadcast to all connected peers except those
 		// which are excluded by the message.
 		case bmsg := <-s.broadcast:
@@ -1799,6 +2390,12 @@ out:
 			s.handleQuery(state, qmsg)
 
 		case <-s.quit:
+			// Save the block relay only peers so they are reconnected
+			// first on the next startup.
+			if cfg.BlockRelayOnlyPeers > 0 {
+				s.saveAnchors(state)
+			}
+
 			// Disconnect all peers on server shutdown.
 			state.forAllPeers(func(sp *serverPeer) {
 				srvrLog.Tracef("Shutdown peer %s", sp)
@@ -1809,6 +2406,15 @@ out:
 	}
 
 	s.connManager.Stop()
+	if s.historyValidator != nil {
+		s.historyValidator.Stop()
+	}
+	if s.spvSyncer != nil {
+		if err := s.spvSyncer.Stop(); err != nil {
+			srvrLog.Errorf("Failed to stop the light client syncer: %v",
+				err)
+		}
+	}
 	s.blockManager.Stop()
 	s.addrManager.Stop()
 
@@ -1821,6 +2427,7 @@ cleanup:
 		case <-s.donePeers:
 		case <-s.peerHeightsUpdate:
 		case <-s.relayInv:
+		case <-s.relayStem:
 		case <-s.broadcast:
 		case <-s.query:
 		default:
@@ -1842,9 +2449,14 @@ func (s *server) BanPeer(sp *serverPeer) {
 }
 
 // RelayInventory relays the passed inventory vector to all connected peers
-// that are not already known to have it.
+// that are not already known to have it.  Nothing is relayed once the server is
+// shutting down, so callers such as the memory pool load do not block on the
+// peer handler after it exited.
 func (s *server) RelayInventory(invVect *wire.InvVect, data interface{}, immediate bool) {
-	s.relayInv <- relayMsg{invVect: invVect, data: data, immediate: immediate}
+	select {
+	case s.relayInv <- relayMsg{invVect: invVect, data: data, immediate: immediate}:
+	case <-s.quit:
+	}
 }
 
 // BroadcastMessage sends msg to all peers currently connected to the server
@@ -2111,11 +2723,29 @@ func (s *server) Start() {
 	s.wg.Add(1)
 	go s.peerHandler()
 
+	// Load the memory pool contents saved on the previous shutdown in the
+	// background since the transactions need to be validated again.
+	s.wg.Add(1)
+	go s.loadMempoolOnStart()
+
+	// Start the index manager which catches the optional indexes up to the
+	// main chain in the background and then keeps them up to date.
+	if s.indexManager != nil {
+		s.indexManager.Start()
+	}
+
 	if s.nat != nil {
 		s.wg.Add(1)
 		go s.upnpUpdateThread()
 	}
 
+	// Start the stem handler, which ensures transactions relayed in the
+	// stem phase of the dandelion relay are fluffed eventually.
+	if cfg.Dandelion {
+		s.wg.Add(1)
+		go s.stemHandler()
+	}
+
 	if !cfg.DisableRPC {
 		s.wg.Add(1)
 
@@ -2124,6 +2754,11 @@ func (s *server) Start() {
 		go s.rebroadcastHandler()
 
 		s.rpcServer.Start()
+
+		// Start the gRPC server if it is enabled.
+		if s.grpcServer != nil {
+			s.grpcServer.Start()
+		}
 	}
 
 	// Start the background block template generator if the config provides
@@ -2140,6 +2775,16 @@ func (s *server) Start() {
 	if cfg.Generate {
 		s.cpuMiner.Start()
 	}
+
+	// Start the stratum server if it is enabled.
+	if s.stratumServer != nil {
+		s.stratumServer.Start()
+	}
+
+	// Start the metrics server if it is enabled.
+	if s.metricsServer != nil {
+		s.metricsServer.Start()
+	}
 }
 
 // Stop gracefully shuts down the server by stopping and disconnecting all
@@ -2164,11 +2809,44 @@ func (s *server) Stop() error {
 		s.cpuMiner.Stop()
 	}
 
+	// Stop the stratum server if needed.
+	if s.stratumServer != nil {
+		s.stratumServer.Stop()
+	}
+
+	// Stop the metrics server if needed.
+	if s.metricsServer != nil {
+		s.metricsServer.Stop()
+	}
+
+	// Stop the gRPC server if needed.  This is done before stopping the RPC
+	// server since it relies on the RPC server for its handlers and
+	// notifications.
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+
 	// Shutdown the RPC server if it's not disabled.
 	if !cfg.DisableRPC && s.rpcServer != nil {
 		s.rpcServer.Stop()
 	}
 
+	// Stop the index manager and wait for any index updates in progress to
+	// be interrupted.
+	if s.indexManager != nil {
+		s.indexManager.Stop()
+	}
+
+	// Save the memory pool contents so they can be loaded on the next
+	// start.  Nothing is saved when the contents saved on the previous
+	// shutdown have not been completely loaded since that would lose the
+	// remaining transactions.
+	if atomic.LoadInt32(&s.mempoolLoaded) != 0 {
+		if _, err := s.saveMempool(); err != nil {
+			srvrLog.Errorf("Unable to save the memory pool: %v", err)
+		}
+	}
+
 	s.feeEstimator.Close()
 
 	// Signal the remaining goroutines to quit.
@@ -2315,10 +2993,57 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	if cfg.NoCFilters {         // false
 		services &^= wire.SFNodeCF
 	}
+	if cfg.Prune != 0 {
+		// Pruned nodes are not able to serve historical blocks.
+		services &^= wire.SFNodeNetwork
+	}
+	if cfg.SPV {
+		// Light clients are not able to serve blocks or filters.
+		services &^= wire.SFNodeNetwork | wire.SFNodeCF
+	}
+
+	// Nodes that were bootstrapped from a utxo snapshot do not have the blocks
+	// prior to it, even once they have been validated in the background, so
+	// they are not able to serve historical blocks either.
+	snapshotBase, err := blockchain.FetchUtxoSnapshotBase(db)
+	if err != nil {
+		return nil, err
+	}
+	if snapshotBase != nil {
+		services &^= wire.SFNodeNetwork
+	}
+
+	// Load the identity used to authenticate with peers over the encrypted
+	// transport and advertise support for it when enabled.
+	var p2pIdentity *secp256k1.PrivateKey
+	if cfg.P2PEncryption {
+		var err error
+		p2pIdentity, err = loadP2PIdentity(path.Join(dataDir,
+			p2pIdentityFilename))
+		if err != nil {
+			return nil, err
+		}
+		services |= wire.SFNodeEncryption
+		srvrLog.Infof("Peer identity key: %x",
+			p2pIdentity.PubKey().SerializeCompressed())
+	}
 
 	// 新建一个地址管理者
 	amgr := addrmgr.New(cfg.DataDir, dcrdLookup) // ~/.dcrd/data/mainnet, net.LookupIP()
 
+	// Group addresses by the autonomous system announcing them when an AS
+	// map is provided.
+	if cfg.ASMap != "" {
+		asmap, err := addrmgr.LoadASMap(cfg.ASMap)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AS map %s: %v",
+				cfg.ASMap, err)
+		}
+		amgr.SetASMap(asmap)
+		srvrLog.Infof("Using AS map %s (checksum %s)", cfg.ASMap,
+			asmap.Checksum())
+	}
+
 	var listeners []net.Listener
 	var nat NAT
 	if !cfg.DisableListen { // !false
@@ -2466,7 +3191,24 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize), // 100000
 		context:              ctx,
 		cancel:               cancel,
+		p2pIdentity:          p2pIdentity,
+		relayStem:            make(chan relayStemMsg, cfg.MaxPeers),
+		localStemTxns:        make(map[chainhash.Hash]struct{}),
+		uploadTarget:         newUploadTarget(cfg.MaxUploadTarget, chainParams),
+	}
+	if cfg.MaxUploadRate != 0 {
+		s.uploadLimiter = peer.NewRateLimiter(cfg.MaxUploadRate * 1024)
+	}
+
+	// Load the banned subnets saved by previous runs.  A corrupt ban list
+	// is not fatal.  The bans that could be loaded are used and a copy of
+	// the corrupt file is saved since it is overwritten as soon as the
+	// bans change.
+	banList, err := newBanList(path.Join(dataDir, banListFilename))
+	if err != nil {
+		srvrLog.Errorf("Unable to load ban list: %v", err)
 	}
+	s.banList = banList
 
 	// Create the transaction and address indexes if needed.
 	//
@@ -2528,14 +3270,77 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	// Create an index manager if any of the optional indexes are enabled.
 	var indexManager blockchain.IndexManager
 	if len(indexes) > 0 {
-		indexManager = indexers.NewManager(db, indexes, chainParams)
+		s.indexManager = indexers.NewManager(db, indexes, chainParams)
+		indexManager = s.indexManager
+	}
+	// Create the metrics server if it is enabled.  This is done before
+	// creating the block manager so the chain reports the block timings to
+	// it.
+	if len(cfg.MetricsListeners) > 0 {
+		s.metricsServer, err = newMetricsServer(cfg.MetricsListeners, &s)
+		if err != nil {
+			return nil, err
+		}
 	}
+
 	bm, err := newBlockManager(&s, indexManager, interrupt)
 	if err != nil {
 		return nil, err
 	}
 	s.blockManager = bm
 
+	// Validate the history prior to the utxo snapshot the block database was
+	// initialized from in the background when requested.
+	if cfg.BackValidate {
+		base := snapshotBase
+		switch {
+		case base == nil:
+			srvrLog.Infof("Not validating the history in the " +
+				"background since the block database was not " +
+				"initialized from a UTXO snapshot")
+
+		case base.Validated:
+			srvrLog.Infof("The history prior to the UTXO snapshot "+
+				"for block %v (height %d) has already been "+
+				"validated", base.Hash, base.Height)
+
+		default:
+			s.historyValidator, err = newHistoryValidator(&s, base,
+				interrupt)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Sync the headers and committed filters with the light client syncer
+	// instead of the block manager when running in spv mode.  The blocks it
+	// connects are passed along to the websocket clients so the matches of
+	// their transaction filters are delivered as usual.
+	if cfg.SPV {
+		s.spvSyncer, err = spv.New(&spv.Config{
+			ChainParams:        chainParams,
+			DisableCheckpoints: cfg.DisableCheckpoints,
+			FilterHeaderPeers:  defaultSPVFilterHeaderPeers,
+			MaxInFlight:        defaultSPVMaxInFlight,
+			BlockConnected: func(block *wire.MsgBlock) {
+				if s.rpcServer != nil {
+					s.rpcServer.ntfnMgr.NotifyBlockConnected(
+						dcrutil.NewBlock(block))
+				}
+			},
+			BlockDisconnected: func(block *wire.MsgBlock) {
+				if s.rpcServer != nil {
+					s.rpcServer.ntfnMgr.NotifyBlockDisconnected(
+						dcrutil.NewBlock(block))
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	txC := mempool.Config{
 		Policy: mempool.Policy{
 			MaxTxVersion:         2,
@@ -2546,6 +3351,7 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 			MaxOrphanTxSize:      defaultMaxOrphanTxSize,
 			MaxSigOpsPerTx:       blockchain.MaxSigOpsPerBlock / 5,
 			MinRelayTxFee:        cfg.minRelayTxFee,
+			MaxPoolSize:          cfg.MaxMempool * 1024 * 1024,
 			AllowOldVotes:        cfg.AllowOldVotes,
 			StandardVerifyFlags: func() (txscript.ScriptFlags, error) {
 				return standardScriptVerifyFlags(bm.chain)
@@ -2575,6 +3381,17 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 				s.bg.OnVoteReceived(voteTx)
 			}
 		},
+		OnTxReplaced: func(replaced, replacement *dcrutil.Tx) {
+			// Notify websocket clients about the removal and stop
+			// rebroadcasting the replaced transaction in case it was
+			// submitted via the RPC server.
+			if s.rpcServer != nil {
+				s.rpcServer.ntfnMgr.NotifyMempoolTxReplaced(replaced,
+					replacement)
+				iv := wire.NewInvVect(wire.InvTypeTx, replaced.Hash())
+				s.RemoveRebroadcastInventory(iv)
+			}
+		},
 	}
 	s.txMemPool = mempool.New(&txC)
 
@@ -2608,6 +3425,26 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 		IsCurrent:                  bm.IsCurrent,
 	})
 
+	// Create the stratum server if it is enabled.  The configuration
+	// ensures there is a mining address, and thus a background block
+	// template generator, in that case.
+	if len(cfg.StratumListeners) > 0 {
+		s.stratumServer, err = newStratumServer(&stratumConfig{
+			ListenAddrs:                cfg.StratumListeners,
+			ChainParams:                s.chainParams,
+			Workers:                    cfg.StratumWorkers,
+			ShareDifficulty:            cfg.StratumShareDiff,
+			BgBlkTmplGenerator:         s.bg,
+			ProcessBlock:               bm.ProcessBlock,
+			PermitConnectionlessMining: cfg.SimNet || cfg.RegNet,
+			ConnectedCount:             s.ConnectedCount,
+			IsCurrent:                  bm.IsCurrent,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Only setup a function to return new addresses to connect to when
 	// not running in connect-only mode.  The simulation network is always
 	// in connect-only mode since it is only intended to connect to
@@ -2629,7 +3466,7 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 				// in the same group so that we are not connecting
 				// to the same network segment at the expense of
 				// others.
-				key := addrmgr.GroupKey(addr.NetAddress())
+				key := s.addrManager.GroupKey(addr.NetAddress())
 				if s.OutboundGroupCount(key) != 0 {
 					continue
 				}
@@ -2659,14 +3496,22 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 	if cfg.MaxPeers < targetOutbound {
 		targetOutbound = cfg.MaxPeers
 	}
+	var targetBlockRelayOnly int
+	if newAddressFunc != nil {
+		targetBlockRelayOnly = cfg.BlockRelayOnlyPeers
+		if cfg.MaxPeers-targetOutbound < targetBlockRelayOnly {
+			targetBlockRelayOnly = cfg.MaxPeers - targetOutbound
+		}
+	}
 	cmgr, err := connmgr.New(&connmgr.Config{
-		Listeners:      listeners,
-		OnAccept:       s.inboundPeerConnected,
-		RetryDuration:  connectionRetryInterval,
-		TargetOutbound: uint32(targetOutbound),
-		Dial:           dcrdDial,
-		OnConnection:   s.outboundPeerConnected,
-		GetNewAddress:  newAddressFunc,
+		Listeners:            listeners,
+		OnAccept:             s.inboundPeerConnected,
+		RetryDuration:        connectionRetryInterval,
+		TargetOutbound:       uint32(targetOutbound),
+		TargetBlockRelayOnly: uint32(targetBlockRelayOnly),
+		Dial:                 dcrdDial,
+		OnConnection:         s.outboundPeerConnected,
+		GetNewAddress:        newAddressFunc,
 	})
 	if err != nil {
 		return nil, err
@@ -2690,12 +3535,44 @@ func newServer(listenAddrs []string, db database.DB, chainParams *chaincfg.Param
 		})
 	}
 
+	// Reconnect to the block relay only peers that were connected at the
+	// last shutdown.  They count toward the target number of block relay
+	// only peers and are replaced by other peers when they fail.
+	if targetBlockRelayOnly > 0 {
+		anchors := loadAnchors()
+		if len(anchors) > targetBlockRelayOnly {
+			anchors = anchors[:targetBlockRelayOnly]
+		}
+		for _, addr := range anchors {
+			tcpAddr, err := addrStringToNetAddr(addr)
+			if err != nil {
+				srvrLog.Warnf("Ignoring invalid anchor peer %s: %v",
+					addr, err)
+				continue
+			}
+
+			srvrLog.Debugf("Reconnecting to anchor peer %s", addr)
+			go s.connManager.Connect(&connmgr.ConnReq{
+				Addr:           tcpAddr,
+				BlockRelayOnly: true,
+			})
+		}
+	}
+
 	if !cfg.DisableRPC {
 		s.rpcServer, err = newRPCServer(cfg.RPCListeners, tg, &s)
 		if err != nil {
 			return nil, err
 		}
 
+		if len(cfg.GRPCListeners) > 0 {
+			s.grpcServer, err = newGRPCServer(cfg.GRPCListeners,
+				s.rpcServer)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		// Signal process shutdown when the RPC server requests it.
 		go func() {
 			<-s.rpcServer.RequestedProcessShutdown()
@@ -2737,6 +3614,16 @@ func addrStringToNetAddr(addr string) (net.Addr, error) {
 	}, nil
 }
 
+// connIP returns the IP address of the remote end of the passed connection or
+// nil when it can't be determined.
+func connIP(conn net.Conn) net.IP {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
 // isWhitelisted returns whether the IP address is included in the whitelisted
 // networks and IPs.
 func isWhitelisted(addr net.Addr) bool {
@@ -0,0 +1,268 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package spv
+
+import (
+	"sort"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/gcs"
+	"github.com/decred/dcrd/gcs/blockcf"
+	"github.com/decred/dcrd/wire"
+)
+
+// cfHeadersRound houses the state of a round of verifying the committed filter
+// headers of a range of blocks of the best chain by requesting them from
+// multiple peers.
+type cfHeadersRound struct {
+	// start is the last block whose committed filter header is verified
+	// and stop is the last block of the range.
+	start *headerNode
+	stop  *headerNode
+	sent  time.Time
+
+	// responses houses the committed filter headers of the range of blocks
+	// each peer the round was sent to responded with.  The entries of the
+	// peers that have not responded yet are nil.
+	responses map[*peerState][]chainhash.Hash
+
+	// checkIndex is the index of the first header the responses disagree
+	// on and checkNode is the block of that header, which is downloaded to
+	// find out which peers are wrong.  The check node is nil when there is
+	// no disagreement.
+	checkIndex int
+	checkNode  *headerNode
+}
+
+// removePeer removes the passed peer from the round.
+func (r *cfHeadersRound) removePeer(ps *peerState) {
+	delete(r.responses, ps)
+}
+
+// filterTip returns the last block of the best chain whose committed filter
+// header is verified.  Since the headers are only verified in order, every
+// block of the best chain up to this one has a verified header.
+func (s *Syncer) filterTip() *headerNode {
+	bestChain := s.chain.bestChain
+	i := sort.Search(len(bestChain), func(i int) bool {
+		return bestChain[i].filterHeader == nil
+	})
+	return bestChain[i-1]
+}
+
+// maybeStartCFHeadersRound starts a new round of verifying committed filter
+// headers when none is in progress, the best chain contains blocks whose
+// headers are not verified yet and enough capable peers are available to
+// request them from.
+func (s *Syncer) maybeStartCFHeadersRound() {
+	if s.cfRound != nil {
+		return
+	}
+
+	start := s.filterTip()
+	tip := s.chain.tip()
+	if start == tip {
+		return
+	}
+	stopHeight := start.height + wire.MaxCFHeadersPerMsg
+	if stopHeight > tip.height {
+		stopHeight = tip.height
+	}
+	stop := s.chain.nodeByHeight(stopHeight)
+
+	peers := make([]*peerState, 0, s.cfg.FilterHeaderPeers)
+	for _, ps := range s.peers {
+		if len(peers) == s.cfg.FilterHeaderPeers {
+			break
+		}
+		if !ps.filterCapable() || ps.peer.LastBlock() < stop.height {
+			continue
+		}
+		peers = append(peers, ps)
+	}
+	if len(peers) < s.cfg.FilterHeaderPeers {
+		return
+	}
+
+	r := &cfHeadersRound{
+		start:     start,
+		stop:      stop,
+		sent:      time.Now(),
+		responses: make(map[*peerState][]chainhash.Hash, len(peers)),
+	}
+	msg := wire.NewMsgGetCFHeaders()
+	msg.AddBlockLocatorHash(&start.hash)
+	msg.HashStop = stop.hash
+	msg.FilterType = wire.GCSFilterRegular
+	for _, ps := range peers {
+		r.responses[ps] = nil
+		ps.peer.QueueMessage(msg, nil)
+	}
+
+	log.Debugf("Requesting committed filter headers for blocks %d to %d "+
+		"from %d peers", start.height+1, stop.height, len(r.responses))
+	s.cfRound = r
+}
+
+// handleCFHeadersMsg records the committed filter headers a peer responded with
+// in the round that is in progress.
+func (s *Syncer) handleCFHeadersMsg(cmsg *cfHeadersMsg) {
+	r := s.cfRound
+	ps, exists := s.peers[cmsg.peer]
+	if r == nil || !exists {
+		return
+	}
+	if resp, exists := r.responses[ps]; !exists || resp != nil {
+		return
+	}
+
+	// Responses that do not cover the requested range, which is the case
+	// when the peer does not consider the blocks part of the best chain,
+	// are not usable.
+	msg := cmsg.cfheaders
+	count := int(r.stop.height - r.start.height)
+	if msg.FilterType != wire.GCSFilterRegular || msg.StopHash != r.stop.hash ||
+		len(msg.HeaderHashes) != count {
+
+		log.Debugf("Ignoring committed filter headers from peer %s that "+
+			"do not match the requested range", ps.peer)
+		r.removePeer(ps)
+		s.evaluateCFHeadersRound()
+		return
+	}
+
+	headers := make([]chainhash.Hash, count)
+	for i, hash := range msg.HeaderHashes {
+		headers[i] = *hash
+	}
+	r.responses[ps] = headers
+	s.evaluateCFHeadersRound()
+}
+
+// evaluateCFHeadersRound compares the responses of the round that is in
+// progress once all peers responded or the round timed out.  The committed
+// filter headers are accepted when all responses agree and there are at least
+// as many as configured.  The round is abandoned when there are fewer, so it
+// is started again with other peers.  Otherwise, the block of the first header
+// the responses disagree on is requested so the header can be derived from the
+// block.
+func (s *Syncer) evaluateCFHeadersRound() {
+	r := s.cfRound
+	if r == nil {
+		return
+	}
+
+	// Resolve the disagreement once the block of the header in question is
+	// available.
+	if r.checkNode != nil {
+		if block, exists := s.blocks[r.checkNode.hash]; exists {
+			s.resolveCFHeadersCheck(block)
+			return
+		}
+		s.requestBlock(r.checkNode)
+		return
+	}
+
+	// Wait for the pending responses until the round times out.
+	timedOut := time.Since(r.sent) >= requestTimeout
+	for ps, resp := range r.responses {
+		if resp != nil {
+			continue
+		}
+		if !timedOut {
+			return
+		}
+		log.Debugf("Peer %s did not respond with committed filter "+
+			"headers in time", ps.peer)
+		r.removePeer(ps)
+	}
+	if len(r.responses) < s.cfg.FilterHeaderPeers {
+		log.Debugf("Only %d of %d peers responded with usable committed "+
+			"filter headers for blocks %d to %d -- retrying",
+			len(r.responses), s.cfg.FilterHeaderPeers,
+			r.start.height+1, r.stop.height)
+		s.cfRound = nil
+		return
+	}
+
+	// Find the first header the responses disagree on.
+	var reference []chainhash.Hash
+	checkIndex := -1
+	for _, resp := range r.responses {
+		if reference == nil {
+			reference = resp
+			continue
+		}
+		for i := range resp {
+			if checkIndex != -1 && i >= checkIndex {
+				break
+			}
+			if resp[i] != reference[i] {
+				checkIndex = i
+				break
+			}
+		}
+	}
+	if checkIndex != -1 {
+		r.checkIndex = checkIndex
+		r.checkNode = s.chain.nodeByHeight(r.start.height + 1 +
+			int64(checkIndex))
+		log.Infof("Peers disagree on the committed filter header of "+
+			"block %v (height %d) -- downloading the block",
+			&r.checkNode.hash, r.checkNode.height)
+		s.evaluateCFHeadersRound()
+		return
+	}
+
+	// All responses agree, so accept the headers.
+	node := r.stop
+	for i := len(reference) - 1; i >= 0; i-- {
+		header := reference[i]
+		node.filterHeader = &header
+		node = node.parent
+	}
+	log.Debugf("Verified committed filter headers for blocks %d to %d "+
+		"with %d peers", r.start.height+1, r.stop.height,
+		len(r.responses))
+	s.cfRound = nil
+	s.maybeStartCFHeadersRound()
+}
+
+// resolveCFHeadersCheck derives the committed filter header the peers of the
+// round that is in progress disagree on from the passed block and disconnects
+// the peers that responded with a different header.
+func (s *Syncer) resolveCFHeadersCheck(block *wire.MsgBlock) {
+	r := s.cfRound
+	f, err := blockcf.Regular(block)
+	if err != nil {
+		log.Errorf("Failed to build the committed filter for block %v: "+
+			"%v", &r.checkNode.hash, err)
+		s.cfRound = nil
+		return
+	}
+
+	// The responses agree on all headers before the checked one.
+	prevHeader := r.start.filterHeader
+	if r.checkIndex > 0 {
+		for _, resp := range r.responses {
+			prevHeader = &resp[r.checkIndex-1]
+			break
+		}
+	}
+	header := gcs.MakeHeaderForFilter(f, prevHeader)
+	for ps, resp := range r.responses {
+		if resp[r.checkIndex] == header {
+			continue
+		}
+		log.Warnf("Peer %s responded with an invalid committed filter "+
+			"header for block %v -- disconnecting", ps.peer,
+			&r.checkNode.hash)
+		ps.peer.Disconnect()
+		r.removePeer(ps)
+	}
+	r.checkNode = nil
+	s.evaluateCFHeadersRound()
+}
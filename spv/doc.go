@@ -0,0 +1,51 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package spv implements a light client that syncs block headers and committed
+filters from peers instead of full blocks.
+
+Overview
+
+The syncer downloads the block headers from the peer that claims the most
+blocks and keeps track of the chain with the most cumulative proof of work.
+Headers are checked to connect to known headers, to have the proof of work and
+stake difficulties required by the retarget rules, to have a valid proof of work
+for their difficulty target and to match the checkpoints of the network.  Note
+that, unlike a full node, the syncer does not verify any of the other stake
+rules.  Since the vote on the stake difficulty algorithm defined in DCP0001 can
+not be tallied from headers, the stake difficulty of headers of networks that
+vote on it may match either the original or the new algorithm.
+
+Committed Filter Headers
+
+The regular committed filter headers of the blocks of the best chain are
+requested from several peers in ranges of up to wire.MaxCFHeadersPerMsg blocks.
+A range is only requested when enough capable peers are connected, and it is
+accepted once the configured number of peers responded with the same headers.
+When they disagree, the block of the first header in question is downloaded and
+its filter is built locally to find out which peers are wrong, and those peers
+are disconnected.  The range is requested again when too few peers are left.
+
+Committed Filters
+
+Once something is watched, the committed filters of the blocks are downloaded
+from all capable peers and verified against the filter headers.  Blocks are
+only downloaded when their filter matches the watched data, in which case the
+outpoints of the outputs that pay to watched output scripts are watched as well
+so spending transactions match too.  The blocks are connected in order through
+the BlockConnected callback of the configuration.
+
+Rescans
+
+Rescan matches the filters of a range of blocks against the watched data and
+returns the full blocks that match, which allows callers to find the
+transactions of blocks that were connected before the data was watched.
+
+Peers
+
+The syncer does not manage connections.  The caller provides the peers with
+NewPeer and DonePeer and passes the messages they receive to the Queue methods.
+*/
+package spv
@@ -0,0 +1,444 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package spv
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/gcs"
+	"github.com/decred/dcrd/gcs/blockcf"
+	"github.com/decred/dcrd/wire"
+)
+
+// filterWindow is the maximum number of blocks past the last connected block,
+// or the next block to scan of a rescan, whose committed filters are requested.
+const filterWindow = 500
+
+// request houses information about an outstanding request for a committed
+// filter or a block.
+type request struct {
+	peer *peerState
+	sent time.Time
+}
+
+// rescanJob houses the state of a rescan that is in progress.
+type rescanJob struct {
+	nodes  []*headerNode
+	next   int
+	blocks []*wire.MsgBlock
+	reply  chan rescanResponse
+}
+
+// fetchPeer returns the least busy peer that is below the limit of in-flight
+// requests and is able to provide the committed filter, when filter is set, or
+// the block at the passed height.  The passed peer is skipped.  It returns nil
+// when there is no such peer.
+func (s *Syncer) fetchPeer(height int64, filter bool, skip *peerState) *peerState {
+	var bestPeer *peerState
+	for _, ps := range s.peers {
+		if ps == skip || ps.inFlight >= s.cfg.MaxInFlight ||
+			ps.peer.LastBlock() < height {
+			continue
+		}
+		if filter && !ps.filterCapable() {
+			continue
+		}
+		if !filter && ps.peer.Services()&wire.SFNodeNetwork == 0 {
+			continue
+		}
+		if bestPeer == nil || ps.inFlight < bestPeer.inFlight {
+			bestPeer = ps
+		}
+	}
+	return bestPeer
+}
+
+// sendFilterRequest requests the committed filter of the block with the passed
+// hash from the passed peer.
+func (s *Syncer) sendFilterRequest(ps *peerState, hash *chainhash.Hash) {
+	ps.inFlight++
+	s.filterRequests[*hash] = &request{peer: ps, sent: time.Now()}
+	ps.peer.QueueMessage(wire.NewMsgGetCFilter(hash, wire.GCSFilterRegular),
+		nil)
+}
+
+// sendBlockRequest requests the block with the passed hash from the passed
+// peer.
+func (s *Syncer) sendBlockRequest(ps *peerState, hash *chainhash.Hash) {
+	ps.inFlight++
+	s.blockRequests[*hash] = &request{peer: ps, sent: time.Now()}
+	gdmsg := wire.NewMsgGetData()
+	gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, hash))
+	ps.peer.QueueMessage(gdmsg, nil)
+}
+
+// requestFilter requests the committed filter of the passed block unless it is
+// already requested.  It returns false when there is no peer to request it
+// from.
+func (s *Syncer) requestFilter(node *headerNode) bool {
+	if _, exists := s.filterRequests[node.hash]; exists {
+		return true
+	}
+
+	// The filter of the genesis block is built locally since its header is
+	// not committed to by a previous header.
+	if node.parent == nil {
+		f, err := blockcf.Regular(s.cfg.ChainParams.GenesisBlock)
+		if err != nil {
+			return false
+		}
+		s.filters[node.hash] = f
+		return true
+	}
+
+	ps := s.fetchPeer(node.height, true, nil)
+	if ps == nil {
+		return false
+	}
+	s.sendFilterRequest(ps, &node.hash)
+	return true
+}
+
+// requestBlock requests the passed block unless it is already requested.
+func (s *Syncer) requestBlock(node *headerNode) {
+	if _, exists := s.blockRequests[node.hash]; exists {
+		return
+	}
+	if node.parent == nil {
+		s.blocks[node.hash] = s.cfg.ChainParams.GenesisBlock
+		return
+	}
+	ps := s.fetchPeer(node.height, false, nil)
+	if ps == nil {
+		return
+	}
+	s.sendBlockRequest(ps, &node.hash)
+}
+
+// fetchFilters requests the committed filters within the filter window starting
+// with the passed block that are neither requested nor received yet.  Only the
+// filters of blocks whose committed filter headers are verified are requested.
+func (s *Syncer) fetchFilters(node *headerNode, next func(*headerNode) *headerNode) {
+	for i := 0; node != nil && i < filterWindow; i++ {
+		if node.filterHeader == nil {
+			return
+		}
+		if _, exists := s.filters[node.hash]; !exists {
+			if !s.requestFilter(node) {
+				return
+			}
+		}
+		node = next(node)
+	}
+}
+
+// fetchData requests the committed filters and blocks needed to connect the
+// next blocks and to make progress on the rescans that are in progress.
+func (s *Syncer) fetchData() {
+	s.connectBlocks()
+	s.processRescans()
+
+	if len(s.watchList) == 0 {
+		return
+	}
+	s.fetchFilters(s.chain.next(s.notified), s.chain.next)
+	for _, job := range s.rescans {
+		nodes := job.nodes[job.next:]
+		s.fetchFilters(nodes[0], func(node *headerNode) *headerNode {
+			i := node.height - nodes[0].height + 1
+			if i >= int64(len(nodes)) {
+				return nil
+			}
+			return nodes[i]
+		})
+	}
+}
+
+// match returns whether or not the committed filter of the passed block, which
+// must have been received, matches the watched data.  It returns the full block
+// when it matches and has been received as well.  The block is requested when
+// it matches and has not been received yet.
+func (s *Syncer) match(node *headerNode) (matched bool, block *wire.MsgBlock) {
+	f := s.filters[node.hash]
+	if !f.MatchAny(blockcf.Key(&node.hash), s.watchList) {
+		return false, nil
+	}
+	block, exists := s.blocks[node.hash]
+	if !exists {
+		s.requestBlock(node)
+	}
+	return true, block
+}
+
+// isPending returns whether or not the passed block is yet to be connected.
+func (s *Syncer) isPending(node *headerNode) bool {
+	return node.height > s.notified.height && s.chain.contains(node)
+}
+
+// connectBlocks connects the blocks after the last connected block in order
+// as long as their committed filters, and the blocks themselves when the
+// filters match the watched data, have been received.  The filters are not
+// needed when nothing is watched.
+func (s *Syncer) connectBlocks() {
+	for node := s.chain.next(s.notified); node != nil; node = s.chain.next(node) {
+		block := &wire.MsgBlock{Header: node.header}
+		if len(s.watchList) > 0 {
+			if _, exists := s.filters[node.hash]; !exists {
+				return
+			}
+			matched, fullBlock := s.match(node)
+			if matched {
+				if fullBlock == nil {
+					return
+				}
+				block = fullBlock
+				s.watchOutputs(block)
+			}
+		}
+
+		delete(s.filters, node.hash)
+		delete(s.blocks, node.hash)
+		s.notified = node
+		if s.cfg.BlockConnected != nil {
+			s.cfg.BlockConnected(block)
+		}
+	}
+}
+
+// processRescans scans the blocks of the rescans that are in progress in order
+// as long as their committed filters, and the blocks themselves when the
+// filters match the watched data, have been received.  The rescans are
+// completed once all of their blocks have been scanned.
+func (s *Syncer) processRescans() {
+	jobs := s.rescans[:0]
+	for _, job := range s.rescans {
+		for ; job.next < len(job.nodes) && len(s.watchList) > 0; job.next++ {
+			node := job.nodes[job.next]
+			if _, exists := s.filters[node.hash]; !exists {
+				break
+			}
+			matched, block := s.match(node)
+			if matched {
+				if block == nil {
+					break
+				}
+				job.blocks = append(job.blocks, block)
+				s.watchOutputs(block)
+			}
+
+			// Keep the data of blocks that are yet to be connected.
+			if !s.isPending(node) {
+				delete(s.filters, node.hash)
+				delete(s.blocks, node.hash)
+			}
+		}
+		if job.next < len(job.nodes) && len(s.watchList) > 0 {
+			jobs = append(jobs, job)
+			continue
+		}
+		job.reply <- rescanResponse{blocks: job.blocks}
+	}
+	s.rescans = jobs
+}
+
+// watchOutputs adds the outpoints of the outputs of the passed block that pay to
+// watched output scripts to the watched data so the blocks that spend them
+// match as well.
+func (s *Syncer) watchOutputs(block *wire.MsgBlock) {
+	var data blockcf.Entries
+	for _, tx := range block.Transactions {
+		txHash := tx.TxHash()
+		for i, out := range tx.TxOut {
+			if _, exists := s.watched[string(out.PkScript)]; exists {
+				data.AddOutPoint(wire.NewOutPoint(&txHash, uint32(i),
+					wire.TxTreeRegular))
+			}
+		}
+	}
+	for _, tx := range block.STransactions {
+		txHash := tx.TxHash()
+		for i, out := range tx.TxOut {
+			if len(out.PkScript) == 0 {
+				continue
+			}
+			if _, exists := s.watched[string(out.PkScript[1:])]; exists {
+				data.AddOutPoint(wire.NewOutPoint(&txHash, uint32(i),
+					wire.TxTreeStake))
+			}
+		}
+	}
+	for _, d := range data {
+		s.watch(d)
+	}
+}
+
+// handleCFilterMsg verifies a received committed filter against the verified
+// committed filter headers and makes it available for matching.  Peers that
+// send invalid filters are disconnected.
+func (s *Syncer) handleCFilterMsg(cmsg *cfilterMsg) {
+	msg := cmsg.cfilter
+	req, exists := s.filterRequests[msg.BlockHash]
+	if !exists || msg.FilterType != wire.GCSFilterRegular {
+		return
+	}
+	node := s.chain.lookup(&msg.BlockHash)
+	if node == nil || node.parent == nil || node.filterHeader == nil {
+		return
+	}
+	delete(s.filterRequests, msg.BlockHash)
+	req.peer.inFlight--
+
+	f, err := gcs.FromNBytes(blockcf.P, msg.Data)
+	if err == nil {
+		header := gcs.MakeHeaderForFilter(f, node.parent.filterHeader)
+		if header != *node.filterHeader {
+			err = fmt.Errorf("filter does not match the committed " +
+				"filter header")
+		}
+	}
+	if err != nil {
+		log.Warnf("Received invalid committed filter for block %v from "+
+			"peer %s: %v -- disconnecting", &msg.BlockHash, cmsg.peer,
+			err)
+		cmsg.peer.Disconnect()
+		return
+	}
+
+	s.filters[msg.BlockHash] = f
+	s.fetchData()
+}
+
+// checkMerkleRoots returns an error when the transactions of the passed block
+// do not match the merkle roots committed to by its header.
+func checkMerkleRoots(msgBlock *wire.MsgBlock) error {
+	block := dcrutil.NewBlock(msgBlock)
+	header := &msgBlock.Header
+	merkles := blockchain.BuildMerkleTreeStore(block.Transactions())
+	if !header.MerkleRoot.IsEqual(merkles[len(merkles)-1]) {
+		return fmt.Errorf("block merkle root is invalid")
+	}
+	merkles = blockchain.BuildMerkleTreeStore(block.STransactions())
+	if !header.StakeRoot.IsEqual(merkles[len(merkles)-1]) {
+		return fmt.Errorf("block stake merkle root is invalid")
+	}
+	return nil
+}
+
+// handleBlockMsg verifies a received block against its header and makes it
+// available for connecting, rescans and committed filter header checks.  Peers
+// that send invalid blocks are disconnected.
+func (s *Syncer) handleBlockMsg(bmsg *blockMsg) {
+	hash := bmsg.block.BlockHash()
+	req, exists := s.blockRequests[hash]
+	if !exists {
+		return
+	}
+	node := s.chain.lookup(&hash)
+	if node == nil {
+		return
+	}
+	delete(s.blockRequests, hash)
+	req.peer.inFlight--
+
+	if err := checkMerkleRoots(bmsg.block); err != nil {
+		log.Warnf("Received invalid block %v from peer %s: %v -- "+
+			"disconnecting", &hash, bmsg.peer, err)
+		bmsg.peer.Disconnect()
+		return
+	}
+
+	if r := s.cfRound; r != nil && r.checkNode == node {
+		s.resolveCFHeadersCheck(bmsg.block)
+	}
+	s.blocks[hash] = bmsg.block
+	s.fetchData()
+
+	// Drop the block when it was only needed for the check of the committed
+	// filter headers.
+	if _, exists := s.blocks[hash]; exists && !s.isPending(node) {
+		delete(s.blocks, hash)
+	}
+}
+
+// handleNotFoundMsg marks the requests for blocks the peer does not have as
+// timed out so they are sent to other peers.
+func (s *Syncer) handleNotFoundMsg(nmsg *notFoundMsg) {
+	for _, iv := range nmsg.notFound.InvList {
+		if iv.Type != wire.InvTypeBlock {
+			continue
+		}
+		req, exists := s.blockRequests[iv.Hash]
+		if exists && req.peer.peer == nmsg.peer {
+			req.sent = time.Time{}
+		}
+	}
+}
+
+// resendRequests sends the requests in the passed map that have timed out to
+// other peers.
+func (s *Syncer) resendRequests(requests map[chainhash.Hash]*request, filter bool) {
+	now := time.Now()
+	for hash, req := range requests {
+		if now.Sub(req.sent) < requestTimeout {
+			continue
+		}
+		node := s.chain.lookup(&hash)
+		ps := s.fetchPeer(node.height, filter, req.peer)
+		if ps == nil {
+			continue
+		}
+		log.Debugf("Request for block %v to peer %s timed out -- "+
+			"requesting from %s", &hash, req.peer.peer, ps.peer)
+		req.peer.inFlight--
+		delete(requests, hash)
+		hash := hash
+		if filter {
+			s.sendFilterRequest(ps, &hash)
+		} else {
+			s.sendBlockRequest(ps, &hash)
+		}
+	}
+}
+
+// handleRequestTimeouts sends the requests that have timed out to other peers
+// and retries rounds of verifying committed filter headers that failed.
+func (s *Syncer) handleRequestTimeouts() {
+	s.evaluateCFHeadersRound()
+	s.resendRequests(s.filterRequests, true)
+	s.resendRequests(s.blockRequests, false)
+	s.maybeStartCFHeadersRound()
+	s.fetchData()
+}
+
+// handleRescanMsg starts a rescan of the requested blocks.
+func (s *Syncer) handleRescanMsg(msg rescanMsg) {
+	job := &rescanJob{
+		nodes: make([]*headerNode, 0, len(msg.hashes)),
+		reply: msg.reply,
+	}
+	for i := range msg.hashes {
+		node := s.chain.lookup(&msg.hashes[i])
+		if node == nil {
+			msg.reply <- rescanResponse{err: ErrUnknownBlock}
+			return
+		}
+		if len(job.nodes) > 0 && node.parent != job.nodes[len(job.nodes)-1] {
+			msg.reply <- rescanResponse{err: ErrNotConsecutive}
+			return
+		}
+		if node.filterHeader == nil {
+			msg.reply <- rescanResponse{err: ErrFilterHeadersNotSynced}
+			return
+		}
+		job.nodes = append(job.nodes, node)
+	}
+
+	s.rescans = append(s.rescans, job)
+	s.fetchData()
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package spv
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/gcs"
+	"github.com/decred/dcrd/gcs/blockcf"
+	"github.com/decred/dcrd/wire"
+)
+
+// headerNode represents a block header in the header chain.
+type headerNode struct {
+	parent  *headerNode
+	hash    chainhash.Hash
+	header  wire.BlockHeader
+	height  int64
+	workSum *big.Int
+
+	// filterHeader is the regular committed filter header of the block.
+	// It is nil until it has been verified.
+	filterHeader *chainhash.Hash
+}
+
+// The following methods implement the blockchain.HeaderCtx interface so the
+// difficulty retarget rules can be applied to the header chain.
+
+func (n *headerNode) Height() int64     { return n.height }
+func (n *headerNode) Bits() uint32      { return n.header.Bits }
+func (n *headerNode) Timestamp() int64  { return n.header.Timestamp.Unix() }
+func (n *headerNode) SBits() int64      { return n.header.SBits }
+func (n *headerNode) PoolSize() uint32  { return n.header.PoolSize }
+func (n *headerNode) FreshStake() uint8 { return n.header.FreshStake }
+
+func (n *headerNode) Parent() blockchain.HeaderCtx {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+// headerChain houses the block headers known to the syncer and tracks the
+// chain with the most cumulative proof of work.
+//
+// It is not safe for concurrent access.
+type headerChain struct {
+	params      *chaincfg.Params
+	checkpoints map[int64]*chainhash.Hash
+	index       map[chainhash.Hash]*headerNode
+	bestChain   []*headerNode
+
+	// stakeDiffV1 is set when the network votes on the activation of the
+	// stake difficulty algorithm defined in DCP0001.  The vote can not be
+	// tallied from headers, so the stake difficulty of each header must
+	// then match either the original or the new algorithm.
+	stakeDiffV1 bool
+}
+
+// newHeaderChain returns a header chain that only contains the genesis block
+// of the passed network.  Checkpoints are not enforced when disableCheckpoints
+// is set.
+func newHeaderChain(params *chaincfg.Params, disableCheckpoints bool) (*headerChain, error) {
+	genesis := params.GenesisBlock
+	f, err := blockcf.Regular(genesis)
+	if err != nil {
+		return nil, err
+	}
+	var zeroHash chainhash.Hash
+	filterHeader := gcs.MakeHeaderForFilter(f, &zeroHash)
+	node := &headerNode{
+		hash:         genesis.BlockHash(),
+		header:       genesis.Header,
+		workSum:      blockchain.CalcWork(genesis.Header.Bits),
+		filterHeader: &filterHeader,
+	}
+
+	c := &headerChain{
+		params:      params,
+		checkpoints: make(map[int64]*chainhash.Hash),
+		index:       map[chainhash.Hash]*headerNode{node.hash: node},
+		bestChain:   []*headerNode{node},
+	}
+	for _, deployments := range params.Deployments {
+		for _, deployment := range deployments {
+			if deployment.Vote.Id == chaincfg.VoteIDSDiffAlgorithm {
+				c.stakeDiffV1 = true
+			}
+		}
+	}
+	if !disableCheckpoints {
+		for i := range params.Checkpoints {
+			checkpoint := &params.Checkpoints[i]
+			c.checkpoints[checkpoint.Height] = checkpoint.Hash
+		}
+	}
+	return c, nil
+}
+
+// lookup returns the node for the block with the passed hash or nil when the
+// block is unknown.
+func (c *headerChain) lookup(hash *chainhash.Hash) *headerNode {
+	return c.index[*hash]
+}
+
+// tip returns the tip of the best chain.
+func (c *headerChain) tip() *headerNode {
+	return c.bestChain[len(c.bestChain)-1]
+}
+
+// nodeByHeight returns the node at the passed height of the best chain or nil
+// when the best chain is not that long.
+func (c *headerChain) nodeByHeight(height int64) *headerNode {
+	if height < 0 || height >= int64(len(c.bestChain)) {
+		return nil
+	}
+	return c.bestChain[height]
+}
+
+// contains returns whether or not the passed node is part of the best chain.
+func (c *headerChain) contains(node *headerNode) bool {
+	return c.nodeByHeight(node.height) == node
+}
+
+// next returns the child of the passed node on the best chain or nil when the
+// node is the tip or not part of the best chain.
+func (c *headerChain) next(node *headerNode) *headerNode {
+	if !c.contains(node) {
+		return nil
+	}
+	return c.nodeByHeight(node.height + 1)
+}
+
+// locator returns a block locator for the passed node.  The locator starts
+// with the node and steps back exponentially after the first ten entries down
+// to the genesis block.
+func (c *headerChain) locator(node *headerNode) blockchain.BlockLocator {
+	var locator blockchain.BlockLocator
+	step := int64(1)
+	for node != nil {
+		locator = append(locator, &node.hash)
+		if node.height == 0 {
+			break
+		}
+		height := node.height - step
+		if height < 0 {
+			height = 0
+		}
+		for node.height > height {
+			node = node.parent
+		}
+		if len(locator) > 10 {
+			step *= 2
+		}
+	}
+	return locator
+}
+
+// addHeader checks that the passed header connects to a known block, has the
+// proof of work and stake difficulties required by the retarget rules, has a
+// valid proof of work and matches the checkpoint at its height, if any, and
+// adds it to the chain.  It returns the node for the header.
+func (c *headerChain) addHeader(header *wire.BlockHeader) (*headerNode, error) {
+	hash := header.BlockHash()
+	if node := c.lookup(&hash); node != nil {
+		return node, nil
+	}
+
+	parent := c.lookup(&header.PrevBlock)
+	if parent == nil {
+		return nil, fmt.Errorf("header %v does not connect to a "+
+			"known block", hash)
+	}
+	height := parent.height + 1
+	if int64(header.Height) != height {
+		return nil, fmt.Errorf("header %v claims height %d "+
+			"instead of %d", hash, header.Height, height)
+	}
+	bits := blockchain.CalcNextRequiredDiffFromHeader(c.params, parent,
+		header.Timestamp)
+	if header.Bits != bits {
+		return nil, fmt.Errorf("header %v has difficulty %08x instead "+
+			"of the required %08x", hash, header.Bits, bits)
+	}
+	sbits := blockchain.CalcNextRequiredStakeDiffFromHeaderV2(c.params,
+		parent)
+	if header.SBits != sbits && c.stakeDiffV1 {
+		sbits = blockchain.CalcNextRequiredStakeDiffFromHeaderV1(c.params,
+			parent)
+	}
+	if header.SBits != sbits {
+		return nil, fmt.Errorf("header %v has stake difficulty %d "+
+			"instead of the required %d", hash, header.SBits, sbits)
+	}
+	err := blockchain.CheckProofOfWork(header, c.params.PowLimit)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint, ok := c.checkpoints[height]; ok && *checkpoint != hash {
+		return nil, fmt.Errorf("header %v does not match the "+
+			"checkpoint %v at height %d", hash, checkpoint, height)
+	}
+
+	node := &headerNode{
+		parent: parent,
+		hash:   hash,
+		header: *header,
+		height: height,
+		workSum: new(big.Int).Add(parent.workSum,
+			blockchain.CalcWork(header.Bits)),
+	}
+	c.index[hash] = node
+	return node, nil
+}
+
+// setTip makes the passed node the tip of the best chain.  It returns the nodes
+// that were removed from the best chain, starting with the old tip, and the
+// nodes that were added to it, starting with the lowest one.
+func (c *headerChain) setTip(node *headerNode) (detached, attached []*headerNode) {
+	for n := node; n != nil && !c.contains(n); n = n.parent {
+		attached = append(attached, n)
+	}
+	for i, j := 0, len(attached)-1; i < j; i, j = i+1, j-1 {
+		attached[i], attached[j] = attached[j], attached[i]
+	}
+
+	forkHeight := node.height - int64(len(attached))
+	for i := int64(len(c.bestChain)) - 1; i > forkHeight; i-- {
+		detached = append(detached, c.bestChain[i])
+	}
+	c.bestChain = append(c.bestChain[:forkHeight+1], attached...)
+	return detached, attached
+}
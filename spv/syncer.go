@@ -0,0 +1,629 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package spv
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/gcs"
+	"github.com/decred/dcrd/gcs/blockcf"
+	"github.com/decred/dcrd/peer"
+	"github.com/decred/dcrd/txscript"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// requestTimeout is the amount of time after which a request for
+	// committed filter headers, a committed filter or a block that has not
+	// been answered is sent to another peer.
+	requestTimeout = 20 * time.Second
+
+	// requestCheckInterval is the interval at which requests are checked
+	// for timeouts.
+	requestCheckInterval = 5 * time.Second
+)
+
+var (
+	// ErrShutdown signifies that a request could not be completed because
+	// the syncer was stopped.
+	ErrShutdown = errors.New("syncer is shutting down")
+
+	// ErrUnknownBlock signifies that a rescan was requested for a block
+	// that is not part of the header chain.
+	ErrUnknownBlock = errors.New("unknown block")
+
+	// ErrNotConsecutive signifies that a rescan was requested for blocks
+	// that are not consecutive blocks of a chain.
+	ErrNotConsecutive = errors.New("blocks are not consecutive")
+
+	// ErrFilterHeadersNotSynced signifies that a rescan was requested for
+	// blocks whose committed filter headers have not been verified yet.
+	ErrFilterHeadersNotSynced = errors.New("committed filter headers " +
+		"are not synced")
+)
+
+// Config is a descriptor containing the syncer configuration.
+type Config struct {
+	// ChainParams identifies which chain parameters the syncer is
+	// associated with.
+	ChainParams *chaincfg.Params
+
+	// DisableCheckpoints disables the enforcement of the checkpoints of the
+	// chain parameters on the downloaded headers.
+	DisableCheckpoints bool
+
+	// FilterHeaderPeers is the number of peers the committed filter headers
+	// are requested from.  The headers are only accepted once this many
+	// peers responded with the same headers.
+	FilterHeaderPeers int
+
+	// MaxInFlight is the maximum number of committed filters and blocks
+	// that are requested from a single peer at the same time.
+	MaxInFlight int
+
+	// BlockConnected is invoked when a block is connected to the best chain
+	// once it has been checked for watched data.  The block only consists
+	// of the header unless its committed filter matched the watched data,
+	// in which case it is the full block.
+	BlockConnected func(block *wire.MsgBlock)
+
+	// BlockDisconnected is invoked when a block that was previously
+	// connected is disconnected from the best chain due to a reorganize.
+	// The block only consists of the header.
+	BlockDisconnected func(block *wire.MsgBlock)
+}
+
+// peerState houses the state of a peer the syncer uses.
+type peerState struct {
+	peer     *peer.Peer
+	inFlight int
+}
+
+// filterCapable returns whether or not committed filters and their headers can
+// be requested from the peer.
+func (ps *peerState) filterCapable() bool {
+	return ps.peer.ProtocolVersion() >= wire.NodeCFVersion &&
+		ps.peer.Services()&wire.SFNodeCF == wire.SFNodeCF
+}
+
+// Syncer provides a concurrency safe light client that syncs block headers and
+// regular committed filters instead of full blocks.  Only the blocks whose
+// filters match the watched data are downloaded.
+type Syncer struct {
+	started  int32
+	shutdown int32
+	cfg      Config
+	msgChan  chan interface{}
+	wg       sync.WaitGroup
+	quit     chan struct{}
+
+	// The following fields are only accessed by the sync handler.
+	chain     *headerChain
+	peers     map[*peer.Peer]*peerState
+	syncPeer  *peerState
+	watched   map[string]struct{}
+	watchList [][]byte
+
+	// notified is the last block that was connected to the best chain by
+	// invoking the BlockConnected callback.
+	notified *headerNode
+
+	// The following fields track the committed filters and blocks that
+	// are requested or received and not used yet.
+	filterRequests map[chainhash.Hash]*request
+	blockRequests  map[chainhash.Hash]*request
+	filters        map[chainhash.Hash]*gcs.Filter
+	blocks         map[chainhash.Hash]*wire.MsgBlock
+
+	// cfRound is the committed filter headers verification round that is
+	// in progress, if any.
+	cfRound *cfHeadersRound
+
+	// rescans are the rescans that are in progress.
+	rescans []*rescanJob
+}
+
+// Message types handled by the sync handler.
+type (
+	newPeerMsg struct {
+		peer *peer.Peer
+	}
+	donePeerMsg struct {
+		peer *peer.Peer
+	}
+	invMsg struct {
+		inv  *wire.MsgInv
+		peer *peer.Peer
+	}
+	headersMsg struct {
+		headers *wire.MsgHeaders
+		peer    *peer.Peer
+	}
+	cfHeadersMsg struct {
+		cfheaders *wire.MsgCFHeaders
+		peer      *peer.Peer
+	}
+	cfilterMsg struct {
+		cfilter *wire.MsgCFilter
+		peer    *peer.Peer
+	}
+	blockMsg struct {
+		block *wire.MsgBlock
+		peer  *peer.Peer
+	}
+	notFoundMsg struct {
+		notFound *wire.MsgNotFound
+		peer     *peer.Peer
+	}
+	watchMsg struct {
+		data [][]byte
+	}
+	rescanMsg struct {
+		hashes []chainhash.Hash
+		reply  chan rescanResponse
+	}
+	bestBlockMsg struct {
+		reply chan bestBlockResponse
+	}
+)
+
+// rescanResponse is a response sent to the reply channel of a rescanMsg.
+type rescanResponse struct {
+	blocks []*wire.MsgBlock
+	err    error
+}
+
+// bestBlockResponse is a response sent to the reply channel of a bestBlockMsg.
+type bestBlockResponse struct {
+	hash   chainhash.Hash
+	height int64
+}
+
+// New returns a new syncer with the passed configuration.  Use Start to begin
+// syncing once peers are provided with NewPeer.
+func New(cfg *Config) (*Syncer, error) {
+	chain, err := newHeaderChain(cfg.ChainParams, cfg.DisableCheckpoints)
+	if err != nil {
+		return nil, err
+	}
+	s := &Syncer{
+		cfg:            *cfg,
+		msgChan:        make(chan interface{}, 100),
+		quit:           make(chan struct{}),
+		chain:          chain,
+		peers:          make(map[*peer.Peer]*peerState),
+		watched:        make(map[string]struct{}),
+		notified:       chain.tip(),
+		filterRequests: make(map[chainhash.Hash]*request),
+		blockRequests:  make(map[chainhash.Hash]*request),
+		filters:        make(map[chainhash.Hash]*gcs.Filter),
+		blocks:         make(map[chainhash.Hash]*wire.MsgBlock),
+	}
+	if s.cfg.FilterHeaderPeers <= 0 {
+		s.cfg.FilterHeaderPeers = 1
+	}
+	if s.cfg.MaxInFlight <= 0 {
+		s.cfg.MaxInFlight = 1
+	}
+	return s, nil
+}
+
+// Start begins the core sync handler which syncs headers and committed filters
+// from the peers.
+func (s *Syncer) Start() {
+	// Already started?
+	if atomic.AddInt32(&s.started, 1) != 1 {
+		return
+	}
+
+	log.Trace("Starting SPV syncer")
+	s.wg.Add(1)
+	go s.syncHandler()
+}
+
+// Stop gracefully shuts down the syncer by stopping all asynchronous handlers
+// and waiting for them to finish.
+func (s *Syncer) Stop() error {
+	if atomic.AddInt32(&s.shutdown, 1) != 1 {
+		log.Warnf("SPV syncer is already in the process of shutting " +
+			"down")
+		return nil
+	}
+
+	log.Infof("SPV syncer shutting down")
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+// queue sends the passed message to the sync handler unless the syncer is
+// shutting down.
+func (s *Syncer) queue(msg interface{}) {
+	select {
+	case s.msgChan <- msg:
+	case <-s.quit:
+	}
+}
+
+// NewPeer informs the syncer of a newly active peer.
+func (s *Syncer) NewPeer(p *peer.Peer) {
+	s.queue(&newPeerMsg{peer: p})
+}
+
+// DonePeer informs the syncer that a peer has disconnected.
+func (s *Syncer) DonePeer(p *peer.Peer) {
+	s.queue(&donePeerMsg{peer: p})
+}
+
+// QueueInv adds the passed inv message and peer to the sync handling queue.
+// Only block announcements are used.
+func (s *Syncer) QueueInv(inv *wire.MsgInv, p *peer.Peer) {
+	s.queue(&invMsg{inv: inv, peer: p})
+}
+
+// QueueHeaders adds the passed headers message and peer to the sync handling
+// queue.
+func (s *Syncer) QueueHeaders(headers *wire.MsgHeaders, p *peer.Peer) {
+	s.queue(&headersMsg{headers: headers, peer: p})
+}
+
+// QueueCFHeaders adds the passed cfheaders message and peer to the sync
+// handling queue.
+func (s *Syncer) QueueCFHeaders(cfheaders *wire.MsgCFHeaders, p *peer.Peer) {
+	s.queue(&cfHeadersMsg{cfheaders: cfheaders, peer: p})
+}
+
+// QueueCFilter adds the passed cfilter message and peer to the sync handling
+// queue.
+func (s *Syncer) QueueCFilter(cfilter *wire.MsgCFilter, p *peer.Peer) {
+	s.queue(&cfilterMsg{cfilter: cfilter, peer: p})
+}
+
+// QueueBlock adds the passed block message and peer to the sync handling
+// queue.
+func (s *Syncer) QueueBlock(block *wire.MsgBlock, p *peer.Peer) {
+	s.queue(&blockMsg{block: block, peer: p})
+}
+
+// QueueNotFound adds the passed notfound message and peer to the sync handling
+// queue.
+func (s *Syncer) QueueNotFound(notFound *wire.MsgNotFound, p *peer.Peer) {
+	s.queue(&notFoundMsg{notFound: notFound, peer: p})
+}
+
+// Watch adds the passed data to the data the committed filters are matched
+// against.  The data consists of output scripts, with the stake opcode tag
+// removed for stake outputs, and serialized outpoints as described by package
+// blockcf.  Watched data is never removed.
+func (s *Syncer) Watch(data [][]byte) {
+	s.queue(&watchMsg{data: data})
+}
+
+// WatchAddresses adds the output scripts paying to the passed addresses and the
+// passed outpoints to the watched data.
+func (s *Syncer) WatchAddresses(addrs []dcrutil.Address, outPoints []*wire.OutPoint) error {
+	var data blockcf.Entries
+	for _, addr := range addrs {
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return err
+		}
+		data.AddRegularPkScript(script)
+	}
+	for _, op := range outPoints {
+		data.AddOutPoint(op)
+	}
+	s.Watch(data)
+	return nil
+}
+
+// BestBlock returns the hash and height of the tip of the best header chain.
+func (s *Syncer) BestBlock() (*chainhash.Hash, int64, error) {
+	reply := make(chan bestBlockResponse, 1)
+	s.queue(bestBlockMsg{reply: reply})
+	select {
+	case resp := <-reply:
+		return &resp.hash, resp.height, nil
+	case <-s.quit:
+		return nil, 0, ErrShutdown
+	}
+}
+
+// Rescan returns the full blocks among the blocks with the passed hashes whose
+// committed filters match the watched data.  The hashes must identify
+// consecutive blocks of the header chain whose committed filter headers have
+// been verified.  It blocks until the filters and matching blocks have been
+// downloaded.
+func (s *Syncer) Rescan(hashes []chainhash.Hash) ([]*wire.MsgBlock, error) {
+	reply := make(chan rescanResponse, 1)
+	s.queue(rescanMsg{hashes: hashes, reply: reply})
+	select {
+	case resp := <-reply:
+		return resp.blocks, resp.err
+	case <-s.quit:
+		return nil, ErrShutdown
+	}
+}
+
+// syncHandler is the main handler for the syncer.  It must be run as a
+// goroutine.  It processes the messages from the peers and the requests of the
+// caller in a single goroutine so the state of the syncer does not need to be
+// protected by locks.
+func (s *Syncer) syncHandler() {
+	requestTicker := time.NewTicker(requestCheckInterval)
+	defer requestTicker.Stop()
+out:
+	for {
+		select {
+		case m := <-s.msgChan:
+			switch msg := m.(type) {
+			case *newPeerMsg:
+				s.handleNewPeerMsg(msg.peer)
+
+			case *donePeerMsg:
+				s.handleDonePeerMsg(msg.peer)
+
+			case *invMsg:
+				s.handleInvMsg(msg)
+
+			case *headersMsg:
+				s.handleHeadersMsg(msg)
+
+			case *cfHeadersMsg:
+				s.handleCFHeadersMsg(msg)
+
+			case *cfilterMsg:
+				s.handleCFilterMsg(msg)
+
+			case *blockMsg:
+				s.handleBlockMsg(msg)
+
+			case *notFoundMsg:
+				s.handleNotFoundMsg(msg)
+
+			case *watchMsg:
+				s.handleWatchMsg(msg)
+
+			case rescanMsg:
+				s.handleRescanMsg(msg)
+
+			case bestBlockMsg:
+				tip := s.chain.tip()
+				msg.reply <- bestBlockResponse{
+					hash:   tip.hash,
+					height: tip.height,
+				}
+
+			default:
+				log.Warnf("Invalid message type in sync handler: %T",
+					msg)
+			}
+
+		case <-requestTicker.C:
+			s.handleRequestTimeouts()
+
+		case <-s.quit:
+			break out
+		}
+	}
+
+	for _, job := range s.rescans {
+		job.reply <- rescanResponse{err: ErrShutdown}
+	}
+	s.wg.Done()
+	log.Trace("SPV sync handler done")
+}
+
+// handleNewPeerMsg adds the passed peer to the peers the syncer uses and
+// starts syncing headers from it if there is no sync peer yet.
+func (s *Syncer) handleNewPeerMsg(p *peer.Peer) {
+	if _, exists := s.peers[p]; exists {
+		return
+	}
+	ps := &peerState{peer: p}
+	s.peers[p] = ps
+	log.Debugf("New SPV peer %s (services %v)", p, p.Services())
+
+	s.startHeaderSync()
+	s.maybeStartCFHeadersRound()
+	s.fetchData()
+}
+
+// handleDonePeerMsg removes the passed peer from the peers the syncer uses and
+// requests the data that was outstanding from it from other peers.
+func (s *Syncer) handleDonePeerMsg(p *peer.Peer) {
+	ps, exists := s.peers[p]
+	if !exists {
+		return
+	}
+	delete(s.peers, p)
+	log.Debugf("Lost SPV peer %s", p)
+
+	for hash, req := range s.filterRequests {
+		if req.peer == ps {
+			delete(s.filterRequests, hash)
+		}
+	}
+	for hash, req := range s.blockRequests {
+		if req.peer == ps {
+			delete(s.blockRequests, hash)
+		}
+	}
+	if s.cfRound != nil {
+		s.cfRound.removePeer(ps)
+		s.evaluateCFHeadersRound()
+	}
+	if s.syncPeer == ps {
+		s.syncPeer = nil
+		s.startHeaderSync()
+	}
+	s.fetchData()
+}
+
+// startHeaderSync chooses the peer with the most blocks that has more blocks
+// than the best chain as the sync peer, if there is no sync peer yet, and
+// requests headers from it.
+func (s *Syncer) startHeaderSync() {
+	if s.syncPeer != nil {
+		return
+	}
+
+	tip := s.chain.tip()
+	var bestPeer *peerState
+	for _, ps := range s.peers {
+		if ps.peer.LastBlock() <= tip.height {
+			continue
+		}
+		if bestPeer == nil || ps.peer.LastBlock() > bestPeer.peer.LastBlock() {
+			bestPeer = ps
+		}
+	}
+	if bestPeer == nil {
+		return
+	}
+
+	s.syncPeer = bestPeer
+	log.Infof("Syncing headers to height %d from peer %s",
+		bestPeer.peer.LastBlock(), bestPeer.peer)
+	s.requestHeaders(bestPeer, tip)
+}
+
+// requestHeaders requests the headers after the passed node from the passed
+// peer.
+func (s *Syncer) requestHeaders(ps *peerState, node *headerNode) {
+	var zeroHash chainhash.Hash
+	err := ps.peer.PushGetHeadersMsg(s.chain.locator(node), &zeroHash)
+	if err != nil {
+		log.Warnf("Failed to send getheaders message to peer %s: %v",
+			ps.peer, err)
+	}
+}
+
+// handleInvMsg requests the headers of announced blocks that are unknown.
+func (s *Syncer) handleInvMsg(imsg *invMsg) {
+	ps, exists := s.peers[imsg.peer]
+	if !exists {
+		return
+	}
+	for _, iv := range imsg.inv.InvList {
+		if iv.Type != wire.InvTypeBlock {
+			continue
+		}
+		if s.chain.lookup(&iv.Hash) == nil {
+			s.requestHeaders(ps, s.chain.tip())
+			return
+		}
+	}
+}
+
+// handleHeadersMsg adds the received headers to the header chain and requests
+// more headers when the message was full.  Peers that send headers which do
+// not pass the checks of the header chain are disconnected.
+func (s *Syncer) handleHeadersMsg(hmsg *headersMsg) {
+	ps, exists := s.peers[hmsg.peer]
+	if !exists {
+		return
+	}
+	headers := hmsg.headers.Headers
+	if len(headers) == 0 {
+		if ps == s.syncPeer {
+			s.syncPeer = nil
+		}
+		return
+	}
+
+	// Headers that do not connect are most likely announcements of blocks
+	// whose parents are unknown, so request the missing headers instead.
+	if s.chain.lookup(&headers[0].PrevBlock) == nil {
+		s.requestHeaders(ps, s.chain.tip())
+		return
+	}
+
+	var lastNode *headerNode
+	for _, header := range headers {
+		node, err := s.chain.addHeader(header)
+		if err != nil {
+			log.Warnf("Received invalid header from peer %s: %v -- "+
+				"disconnecting", ps.peer, err)
+			ps.peer.Disconnect()
+			return
+		}
+		lastNode = node
+	}
+	if lastNode.workSum.Cmp(s.chain.tip().workSum) > 0 {
+		s.setTip(lastNode)
+	}
+	if lastNode.height > ps.peer.LastBlock() {
+		ps.peer.UpdateLastBlockHeight(lastNode.height)
+	}
+
+	// Request more headers when the peer may have more.
+	if len(headers) == wire.MaxBlockHeadersPerMsg {
+		s.requestHeaders(ps, lastNode)
+		return
+	}
+	if ps == s.syncPeer {
+		log.Infof("Synced headers to height %d from peer %s",
+			s.chain.tip().height, ps.peer)
+		s.syncPeer = nil
+	}
+
+	s.maybeStartCFHeadersRound()
+	s.fetchData()
+}
+
+// setTip makes the passed node the tip of the best chain and disconnects the
+// blocks that were connected and are no longer part of the best chain.
+func (s *Syncer) setTip(node *headerNode) {
+	detached, attached := s.chain.setTip(node)
+	if len(detached) > 0 {
+		log.Infof("Reorganized the header chain from %v (height %d) to "+
+			"%v (height %d)", &detached[0].hash, detached[0].height,
+			&node.hash, node.height)
+	}
+
+	for _, n := range detached {
+		if n.height > s.notified.height {
+			continue
+		}
+		s.notified = n.parent
+		if s.cfg.BlockDisconnected != nil {
+			s.cfg.BlockDisconnected(&wire.MsgBlock{Header: n.header})
+		}
+	}
+	if len(attached) > 0 {
+		log.Debugf("New header chain tip %v (height %d)", &node.hash,
+			node.height)
+	}
+
+	// Abandon the committed filter headers round when its blocks are no
+	// longer part of the best chain.
+	if s.cfRound != nil && !s.chain.contains(s.cfRound.stop) {
+		s.cfRound = nil
+	}
+}
+
+// handleWatchMsg adds the passed data to the watched data.
+func (s *Syncer) handleWatchMsg(msg *watchMsg) {
+	for _, d := range msg.data {
+		s.watch(d)
+	}
+	s.fetchData()
+}
+
+// watch adds the passed data to the watched data.
+func (s *Syncer) watch(data []byte) {
+	if _, exists := s.watched[string(data)]; exists {
+		return
+	}
+	s.watched[string(data)] = struct{}{}
+	s.watchList = append(s.watchList, data)
+}
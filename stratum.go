@@ -0,0 +1,748 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// stratumExtraNonce1Size is the size in bytes of the extra nonce that
+	// is assigned to each stratum connection.
+	stratumExtraNonce1Size = 4
+
+	// stratumExtraNonce2Size is the size in bytes of the extra nonce that
+	// stratum miners are free to vary.
+	stratumExtraNonce2Size = 4
+
+	// stratumMaxMessageSize is the maximum size of a single message read
+	// from a stratum connection.
+	stratumMaxMessageSize = 4096
+
+	// stratumMaxJobs is the maximum number of the most recent jobs per
+	// connection that shares are accepted for.
+	stratumMaxJobs = 8
+
+	// stratumIdleTimeout is the amount of time a stratum connection may go
+	// without sending any messages before it is disconnected.
+	stratumIdleTimeout = time.Minute * 10
+
+	// stratumWriteTimeout is the amount of time allowed for writing a
+	// message to a stratum connection.
+	stratumWriteTimeout = time.Second * 30
+)
+
+// The following are the offsets of the fields of a serialized block header
+// that are used to split it into the parts sent in stratum jobs.
+const (
+	stratumPrevBlockOffset    = 4
+	stratumMerkleRootOffset   = 36
+	stratumBitsOffset         = 116
+	stratumTimestampOffset    = 136
+	stratumExtraDataOffset    = 144
+	stratumStakeVersionOffset = 176
+)
+
+// These are the error codes returned to stratum miners.
+const (
+	stratumErrOther          = 20
+	stratumErrJobNotFound    = 21
+	stratumErrDuplicateShare = 22
+	stratumErrLowDifficulty  = 23
+	stratumErrUnauthorized   = 24
+	stratumErrNotSubscribed  = 25
+)
+
+// stratumError describes an error that is returned to a stratum miner in
+// response to a request.
+type stratumError struct {
+	Code    int
+	Message string
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *stratumError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+// MarshalJSON encodes the error in the [code, message, traceback] form
+// expected by stratum miners.
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Code, e.Message, nil})
+}
+
+// stratumRequest is a request or notification received from a stratum miner.
+type stratumRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// stratumResponse is a response to a stratum request.
+type stratumResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+// stratumNotification is a notification sent to stratum miners.
+type stratumNotification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stratumConfig is a descriptor containing the stratum server configuration.
+type stratumConfig struct {
+	// ListenAddrs are the addresses the stratum server listens on for
+	// connections from miners.
+	ListenAddrs []string
+
+	// ChainParams identifies which chain parameters the stratum server is
+	// associated with.
+	ChainParams *chaincfg.Params
+
+	// Workers are the worker names and passwords, separated by a colon,
+	// that miners may authorize as.  Miners may authorize as any worker
+	// when there are none, so the server must only be reachable by trusted
+	// miners in that case.
+	Workers []string
+
+	// ShareDifficulty is the minimum difficulty, as a multiple of the
+	// proof-of-work limit, of shares accepted from miners.
+	ShareDifficulty float64
+
+	// BgBlkTmplGenerator identifies the background block template
+	// generator that provides the templates miners work on.
+	BgBlkTmplGenerator *BgBlkTmplGenerator
+
+	// ProcessBlock defines the function to call with any solved blocks.
+	// It typically must run the provided block through the same set of
+	// rules and handling as any other block coming from the network.
+	ProcessBlock func(*dcrutil.Block, blockchain.BehaviorFlags) (bool, error)
+
+	// PermitConnectionlessMining allows single node mining on simnet.
+	PermitConnectionlessMining bool
+
+	// ConnectedCount defines the function to use to obtain how many other
+	// peers the server is connected to.  No work is sent to miners while
+	// there are no peers since there would be nobody to send found blocks
+	// to.
+	ConnectedCount func() int32
+
+	// IsCurrent defines the function to use to obtain whether or not the
+	// block chain is current.  No work is sent to miners while the chain
+	// is not current since any solved blocks would end up orphaned.
+	IsCurrent func() bool
+}
+
+// stratumJob houses the block a stratum miner was asked to solve along with
+// the shares submitted for it.
+type stratumJob struct {
+	id     string
+	block  *wire.MsgBlock
+	shares map[[12]byte]struct{}
+}
+
+// stratumClient houses the state of a connection from a stratum miner.
+type stratumClient struct {
+	server      *stratumServer
+	conn        net.Conn
+	addr        string
+	extraNonce1 uint32
+
+	writeMtx sync.Mutex
+
+	mtx         sync.Mutex
+	subscribed  bool
+	initialized bool
+	workers     map[string]struct{}
+	jobs        []*stratumJob
+}
+
+// stratumServer provides a stratum mining server that pushes work from the
+// background block template generator to miners and submits the blocks they
+// solve.
+type stratumServer struct {
+	started         int32
+	shutdown        int32
+	nextExtraNonce1 uint32
+	nextJobID       uint64
+
+	cfg         *stratumConfig
+	listeners   []net.Listener
+	shareTarget *big.Int
+	workers     map[string][sha256.Size]byte
+
+	mtx           sync.Mutex
+	clients       map[*stratumClient]struct{}
+	lastPrevBlock chainhash.Hash
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// decodeStratumHex decodes the passed hex string and ensures it decodes to the
+// given number of bytes.
+func decodeStratumHex(s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	return b, nil
+}
+
+// writeMessage writes the passed message to the connection as a single line
+// of JSON.  The connection is closed on failure.
+//
+// This function is safe for concurrent access.
+func (c *stratumClient) writeMessage(msg interface{}) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		minrLog.Errorf("Unable to marshal stratum message: %v", err)
+		return
+	}
+	b = append(b, '\n')
+
+	c.writeMtx.Lock()
+	c.conn.SetWriteDeadline(time.Now().Add(stratumWriteTimeout))
+	_, err = c.conn.Write(b)
+	c.writeMtx.Unlock()
+	if err != nil {
+		minrLog.Debugf("Unable to write to stratum client %s: %v", c.addr,
+			err)
+		c.conn.Close()
+	}
+}
+
+// notify sends a notification with the passed method and parameters.
+func (c *stratumClient) notify(method string, params ...interface{}) {
+	c.writeMessage(&stratumNotification{
+		Method: method,
+		Params: params,
+	})
+}
+
+// sendJob creates a new job from the passed block template with the extra
+// nonce of the client and sends it to the miner.  All previous jobs are
+// discarded when clean is set.
+func (c *stratumClient) sendJob(template *BlockTemplate, clean bool) {
+	// Give every connection its own extra nonce space by updating the
+	// coinbase with the extra nonce assigned to it.  The header extra data
+	// starts with the same extra nonce followed by the extra nonce the
+	// miner varies, and the remainder of it is zero.
+	block := deepCopyBlockTemplate(template).Block
+	err := UpdateExtraNonce(block, template.Height, uint64(c.extraNonce1))
+	if err != nil {
+		minrLog.Errorf("Unable to update extra nonce for stratum job: %v",
+			err)
+		return
+	}
+	block.Header.ExtraData = [32]byte{}
+	littleEndian.PutUint32(block.Header.ExtraData[:], c.extraNonce1)
+
+	header, err := block.Header.Bytes()
+	if err != nil {
+		minrLog.Errorf("Unable to serialize stratum job header: %v", err)
+		return
+	}
+
+	job := &stratumJob{
+		id:     strconv.FormatUint(atomic.AddUint64(&c.server.nextJobID, 1), 16),
+		block:  block,
+		shares: make(map[[12]byte]struct{}),
+	}
+	c.mtx.Lock()
+	if clean {
+		c.jobs = nil
+	}
+	c.jobs = append(c.jobs, job)
+	if len(c.jobs) > stratumMaxJobs {
+		c.jobs = c.jobs[len(c.jobs)-stratumMaxJobs:]
+	}
+	c.mtx.Unlock()
+
+	// The job consists of the parts of the serialized header before and
+	// after the extra nonces so miners can reassemble it.
+	c.notify("mining.notify", job.id,
+		hex.EncodeToString(header[stratumPrevBlockOffset:stratumMerkleRootOffset]),
+		hex.EncodeToString(header[stratumMerkleRootOffset:stratumExtraDataOffset]),
+		hex.EncodeToString(header[stratumStakeVersionOffset:]),
+		[]string{},
+		hex.EncodeToString(header[:stratumPrevBlockOffset]),
+		hex.EncodeToString(header[stratumBitsOffset:stratumBitsOffset+4]),
+		hex.EncodeToString(header[stratumTimestampOffset:stratumTimestampOffset+4]),
+		clean)
+}
+
+// handleSubscribe handles the mining.subscribe request.
+func (c *stratumClient) handleSubscribe() (interface{}, error) {
+	c.mtx.Lock()
+	c.subscribed = true
+	c.mtx.Unlock()
+
+	var extraNonce1 [stratumExtraNonce1Size]byte
+	littleEndian.PutUint32(extraNonce1[:], c.extraNonce1)
+	subscriptionID := hex.EncodeToString(extraNonce1[:])
+	subscriptions := [][]string{
+		{"mining.set_difficulty", subscriptionID},
+		{"mining.notify", subscriptionID},
+	}
+	return []interface{}{subscriptions, subscriptionID,
+		stratumExtraNonce2Size}, nil
+}
+
+// handleAuthorize handles the mining.authorize request.  The worker name and
+// password must match one of the configured workers when there are any, and
+// any worker name is accepted otherwise.  A connection may authorize several
+// workers, each of which may then submit shares over it.
+func (c *stratumClient) handleAuthorize(params []json.RawMessage) (interface{}, error) {
+	var worker, password string
+	if len(params) < 1 || json.Unmarshal(params[0], &worker) != nil {
+		return nil, &stratumError{stratumErrOther, "invalid parameters"}
+	}
+	if len(params) > 1 && json.Unmarshal(params[1], &password) != nil {
+		return nil, &stratumError{stratumErrOther, "invalid parameters"}
+	}
+	if !c.server.checkWorker(worker, password) {
+		minrLog.Warnf("Stratum miner %s failed to authorize as %q",
+			c.addr, worker)
+		return nil, &stratumError{stratumErrUnauthorized,
+			"unauthorized worker"}
+	}
+
+	c.mtx.Lock()
+	if c.workers == nil {
+		c.workers = make(map[string]struct{})
+	}
+	c.workers[worker] = struct{}{}
+	c.mtx.Unlock()
+
+	minrLog.Infof("Stratum miner %s authorized as %q", c.addr, worker)
+	return true, nil
+}
+
+// handleSubmit handles the mining.submit request by validating the share
+// against the share difficulty and submitting the block when it also satisfies
+// the proof-of-work requirement.  The share must be submitted by one of the
+// workers the connection authorized.
+func (c *stratumClient) handleSubmit(params []json.RawMessage) (interface{}, error) {
+	var args [5]string
+	if len(params) < len(args) {
+		return nil, &stratumError{stratumErrOther, "invalid parameters"}
+	}
+	for i := range args {
+		if err := json.Unmarshal(params[i], &args[i]); err != nil {
+			return nil, &stratumError{stratumErrOther, "invalid parameters"}
+		}
+	}
+	worker, jobID := args[0], args[1]
+	extraNonce2, err := decodeStratumHex(args[2], stratumExtraNonce2Size)
+	if err != nil {
+		return nil, &stratumError{stratumErrOther, "invalid extranonce2"}
+	}
+	timestamp, err := decodeStratumHex(args[3], 4)
+	if err != nil {
+		return nil, &stratumError{stratumErrOther, "invalid ntime"}
+	}
+	nonce, err := decodeStratumHex(args[4], 4)
+	if err != nil {
+		return nil, &stratumError{stratumErrOther, "invalid nonce"}
+	}
+
+	c.mtx.Lock()
+	if !c.subscribed {
+		c.mtx.Unlock()
+		return nil, &stratumError{stratumErrNotSubscribed, "not subscribed"}
+	}
+	if _, ok := c.workers[worker]; !ok {
+		c.mtx.Unlock()
+		return nil, &stratumError{stratumErrUnauthorized, "unauthorized worker"}
+	}
+	var job *stratumJob
+	for _, j := range c.jobs {
+		if j.id == jobID {
+			job = j
+			break
+		}
+	}
+	if job == nil {
+		c.mtx.Unlock()
+		return nil, &stratumError{stratumErrJobNotFound, "job not found"}
+	}
+	var shareKey [12]byte
+	copy(shareKey[0:4], extraNonce2)
+	copy(shareKey[4:8], timestamp)
+	copy(shareKey[8:12], nonce)
+	if _, ok := job.shares[shareKey]; ok {
+		c.mtx.Unlock()
+		return nil, &stratumError{stratumErrDuplicateShare, "duplicate share"}
+	}
+	job.shares[shareKey] = struct{}{}
+	c.mtx.Unlock()
+
+	// Reconstruct the header from the job and submitted values.
+	header := job.block.Header
+	copy(header.ExtraData[stratumExtraNonce1Size:], extraNonce2)
+	header.Timestamp = time.Unix(int64(littleEndian.Uint32(timestamp)), 0)
+	header.Nonce = littleEndian.Uint32(nonce)
+	maxTime := time.Now().Add(time.Second * blockchain.MaxTimeOffsetSeconds)
+	if header.Timestamp.Before(job.block.Header.Timestamp) ||
+		header.Timestamp.After(maxTime) {
+
+		return nil, &stratumError{stratumErrOther, "ntime out of range"}
+	}
+
+	// Ensure the share meets the share difficulty.
+	hash := header.BlockHash()
+	hashNum := blockchain.HashToBig(&hash)
+	if hashNum.Cmp(c.server.shareTarget) > 0 {
+		return nil, &stratumError{stratumErrLowDifficulty,
+			"low difficulty share"}
+	}
+
+	// Submit the block when the share also satisfies the proof-of-work
+	// requirement of the block.
+	if hashNum.Cmp(blockchain.CompactToBig(header.Bits)) <= 0 {
+		c.server.submitBlock(job.block, &header, worker)
+	}
+
+	return true, nil
+}
+
+// handleRequest handles the passed request and returns the result or error to
+// respond with.
+func (c *stratumClient) handleRequest(req *stratumRequest) (interface{}, error) {
+	switch req.Method {
+	case "mining.subscribe":
+		return c.handleSubscribe()
+	case "mining.authorize":
+		return c.handleAuthorize(req.Params)
+	case "mining.submit":
+		return c.handleSubmit(req.Params)
+	}
+	return nil, &stratumError{stratumErrOther, "unknown method"}
+}
+
+// sendInitialWork sends the share difficulty and a job for the current block
+// template once the miner has both subscribed and authorized.
+func (c *stratumClient) sendInitialWork() {
+	c.mtx.Lock()
+	ready := c.subscribed && len(c.workers) > 0 && !c.initialized
+	c.initialized = c.initialized || ready
+	c.mtx.Unlock()
+	if !ready {
+		return
+	}
+
+	c.notify("mining.set_difficulty", c.server.cfg.ShareDifficulty)
+	if !c.server.canMine() {
+		return
+	}
+	template := c.server.cfg.BgBlkTmplGenerator.CurrentTemplate()
+	if template != nil {
+		c.sendJob(template, true)
+	}
+}
+
+// inHandler reads and handles requests from the stratum miner until the
+// connection is closed.  It must be run as a goroutine.
+func (c *stratumClient) inHandler() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, stratumMaxMessageSize),
+		stratumMaxMessageSize)
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(stratumIdleTimeout))
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				minrLog.Debugf("Unable to read from stratum client "+
+					"%s: %v", c.addr, err)
+			}
+			break
+		}
+
+		var req stratumRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			minrLog.Debugf("Malformed message from stratum client %s: "+
+				"%v", c.addr, err)
+			break
+		}
+
+		result, err := c.handleRequest(&req)
+		resp := &stratumResponse{ID: req.ID, Result: result}
+		if err != nil {
+			minrLog.Debugf("Stratum request %s from %s failed: %v",
+				req.Method, c.addr, err)
+			resp.Error = err.(*stratumError)
+		}
+		c.writeMessage(resp)
+		c.sendInitialWork()
+	}
+
+	c.conn.Close()
+	c.server.mtx.Lock()
+	delete(c.server.clients, c)
+	c.server.mtx.Unlock()
+	minrLog.Infof("Stratum miner %s disconnected", c.addr)
+	c.server.wg.Done()
+}
+
+// checkWorker returns whether or not the passed worker name and password match
+// one of the configured workers.  All workers are accepted when there are none.
+//
+// The password is compared in constant time to avoid leaking information
+// about the configured password through timing.
+func (s *stratumServer) checkWorker(worker, password string) bool {
+	if len(s.workers) == 0 {
+		return true
+	}
+	want, ok := s.workers[worker]
+	passwordHash := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(passwordHash[:], want[:]) == 1 && ok
+}
+
+// canMine returns whether or not work should be given to miners.
+func (s *stratumServer) canMine() bool {
+	if !s.cfg.PermitConnectionlessMining && s.cfg.ConnectedCount() == 0 {
+		return false
+	}
+	return s.cfg.IsCurrent()
+}
+
+// submitBlock submits the block solved by a stratum miner through the same
+// path as blocks submitted via the submitblock RPC.
+func (s *stratumServer) submitBlock(jobBlock *wire.MsgBlock, header *wire.BlockHeader, worker string) {
+	msgBlock := &wire.MsgBlock{
+		Header:        *header,
+		Transactions:  jobBlock.Transactions,
+		STransactions: jobBlock.STransactions,
+	}
+	block := dcrutil.NewBlock(msgBlock)
+	isOrphan, err := s.cfg.ProcessBlock(block, blockchain.BFNone)
+	if err != nil {
+		minrLog.Infof("Block submitted via stratum by %q rejected: %v",
+			worker, err)
+		return
+	}
+	if isOrphan {
+		minrLog.Infof("Block submitted via stratum by %q rejected: an "+
+			"orphan building on parent %v", worker, header.PrevBlock)
+		return
+	}
+
+	minrLog.Infof("Block submitted via stratum by %q accepted: %s "+
+		"(height %d)", worker, block.Hash(), header.Height)
+}
+
+// broadcastJobs sends a job for the passed block template to all miners that
+// are ready to receive work.
+func (s *stratumServer) broadcastJobs(template *BlockTemplate) {
+	if !s.canMine() {
+		return
+	}
+
+	// Miners discard their previous jobs when the new template builds on a
+	// different block since any work on them is stale.
+	s.mtx.Lock()
+	prevBlock := template.Block.Header.PrevBlock
+	clean := prevBlock != s.lastPrevBlock
+	s.lastPrevBlock = prevBlock
+	clients := make([]*stratumClient, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mtx.Unlock()
+
+	for _, c := range clients {
+		c.mtx.Lock()
+		initialized := c.initialized
+		c.mtx.Unlock()
+		if initialized {
+			c.sendJob(template, clean)
+		}
+	}
+}
+
+// templateHandler subscribes to template updates from the background block
+// template generator and sends new jobs to the miners whenever it generates a
+// new template.  The subscription is removed on shutdown.  It must be run as a
+// goroutine.
+func (s *stratumServer) templateHandler() {
+out:
+	for {
+		templateChan := s.cfg.BgBlkTmplGenerator.RequestTemplateUpdate()
+		select {
+		case template := <-templateChan:
+			if template != nil {
+				s.broadcastJobs(template)
+			}
+
+		case <-s.quit:
+			s.cfg.BgBlkTmplGenerator.UnsubscribeTemplateUpdate(templateChan)
+			break out
+		}
+	}
+
+	s.wg.Done()
+}
+
+// listenHandler accepts connections from stratum miners on the passed listener
+// until the server is shutdown.  It must be run as a goroutine.
+func (s *stratumServer) listenHandler(listener net.Listener) {
+	minrLog.Infof("Stratum server listening on %s", listener.Addr())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if atomic.LoadInt32(&s.shutdown) != 0 {
+				break
+			}
+			minrLog.Errorf("Unable to accept stratum connection: %v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		c := &stratumClient{
+			server:      s,
+			conn:        conn,
+			addr:        conn.RemoteAddr().String(),
+			extraNonce1: atomic.AddUint32(&s.nextExtraNonce1, 1),
+		}
+		s.mtx.Lock()
+		if atomic.LoadInt32(&s.shutdown) != 0 {
+			s.mtx.Unlock()
+			conn.Close()
+			break
+		}
+		s.clients[c] = struct{}{}
+		s.mtx.Unlock()
+
+		minrLog.Infof("Stratum miner %s connected", c.addr)
+		s.wg.Add(1)
+		go c.inHandler()
+	}
+
+	minrLog.Tracef("Stratum listener %s done", listener.Addr())
+	s.wg.Done()
+}
+
+// Start begins accepting connections from stratum miners.
+func (s *stratumServer) Start() {
+	if atomic.AddInt32(&s.started, 1) != 1 {
+		return
+	}
+
+	minrLog.Trace("Starting stratum server")
+	for _, listener := range s.listeners {
+		s.wg.Add(1)
+		go s.listenHandler(listener)
+	}
+	s.wg.Add(1)
+	go s.templateHandler()
+}
+
+// Stop gracefully shuts down the stratum server by closing its listeners and
+// disconnecting all miners.
+func (s *stratumServer) Stop() {
+	if atomic.AddInt32(&s.shutdown, 1) != 1 {
+		minrLog.Infof("Stratum server is already in the process of " +
+			"shutting down")
+		return
+	}
+
+	minrLog.Warnf("Stratum server shutting down")
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+	close(s.quit)
+	s.mtx.Lock()
+	for c := range s.clients {
+		c.conn.Close()
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+	minrLog.Infof("Stratum server shutdown complete")
+}
+
+// newStratumServer returns a new stratum server listening on the configured
+// addresses.  Use Start to begin accepting connections.
+func newStratumServer(config *stratumConfig) (*stratumServer, error) {
+	if config.ShareDifficulty <= 0 {
+		return nil, errors.New("stratum share difficulty must be positive")
+	}
+
+	// The share target is the proof-of-work limit divided by the share
+	// difficulty.
+	target := new(big.Float).SetInt(config.ChainParams.PowLimit)
+	target.Quo(target, big.NewFloat(config.ShareDifficulty))
+	shareTarget, _ := target.Int(nil)
+
+	workers := make(map[string][sha256.Size]byte, len(config.Workers))
+	for _, worker := range config.Workers {
+		parts := strings.SplitN(worker, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid stratum worker %q", worker)
+		}
+		workers[parts[0]] = sha256.Sum256([]byte(parts[1]))
+	}
+
+	ipv4ListenAddrs, ipv6ListenAddrs, _, err := parseListeners(config.ListenAddrs)
+	if err != nil {
+		return nil, err
+	}
+	listeners := make([]net.Listener, 0,
+		len(ipv6ListenAddrs)+len(ipv4ListenAddrs))
+	for _, addr := range ipv4ListenAddrs {
+		listener, err := net.Listen("tcp4", addr)
+		if err != nil {
+			minrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	for _, addr := range ipv6ListenAddrs {
+		listener, err := net.Listen("tcp6", addr)
+		if err != nil {
+			minrLog.Warnf("Can't listen on %s: %v", addr, err)
+			continue
+		}
+		listeners = append(listeners, listener)
+	}
+	if len(listeners) == 0 {
+		return nil, errors.New("stratum: no valid listen address")
+	}
+
+	return &stratumServer{
+		nextExtraNonce1: rand.Uint32(),
+		cfg:             config,
+		listeners:       listeners,
+		shareTarget:     shareTarget,
+		workers:         workers,
+		clients:         make(map[*stratumClient]struct{}),
+		quit:            make(chan struct{}),
+	}, nil
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain"
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/dcrutil"
+	"github.com/decred/dcrd/wire"
+)
+
+// stratumTestMiner is the miner end of a connection to a stratum client that is
+// handled by a test stratum server.
+type stratumTestMiner struct {
+	t       *testing.T
+	conn    net.Conn
+	scanner *bufio.Scanner
+	nextID  int
+}
+
+// request sends a request with the passed method and parameters and returns
+// the result and error code of the response.  The error code is zero when the
+// request succeeded.
+func (m *stratumTestMiner) request(method string, params ...interface{}) (json.RawMessage, int) {
+	m.t.Helper()
+
+	m.nextID++
+	req, err := json.Marshal(map[string]interface{}{
+		"id":     m.nextID,
+		"method": method,
+		"params": params,
+	})
+	if err != nil {
+		m.t.Fatalf("unable to marshal %s request: %v", method, err)
+	}
+	m.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := m.conn.Write(append(req, '\n')); err != nil {
+		m.t.Fatalf("unable to send %s request: %v", method, err)
+	}
+
+	var resp struct {
+		ID     int
+		Result json.RawMessage
+		Error  []interface{}
+	}
+	m.readLine(&resp)
+	if resp.ID != m.nextID {
+		m.t.Fatalf("%s: response id %d, want %d", method, resp.ID,
+			m.nextID)
+	}
+	if resp.Error == nil {
+		return resp.Result, 0
+	}
+	return nil, int(resp.Error[0].(float64))
+}
+
+// readLine reads the next message from the connection into the passed value.
+func (m *stratumTestMiner) readLine(v interface{}) {
+	m.t.Helper()
+
+	if !m.scanner.Scan() {
+		m.t.Fatalf("unable to read from stratum connection: %v",
+			m.scanner.Err())
+	}
+	if err := json.Unmarshal(m.scanner.Bytes(), v); err != nil {
+		m.t.Fatalf("malformed stratum message %q: %v", m.scanner.Text(),
+			err)
+	}
+}
+
+// TestStratumSubmitFlow ensures shares are only accepted from connections that
+// subscribed and from workers that authorized over the same connection, and
+// that solved blocks are submitted.
+func TestStratumSubmitFlow(t *testing.T) {
+	params := &chaincfg.RegNetParams
+	var submitted []*dcrutil.Block
+	s, err := newStratumServer(&stratumConfig{
+		ListenAddrs:     []string{"127.0.0.1:0"},
+		ChainParams:     params,
+		Workers:         []string{"alice:secret", "bob:hunter2"},
+		ShareDifficulty: 1,
+		ProcessBlock: func(block *dcrutil.Block, flags blockchain.BehaviorFlags) (bool, error) {
+			submitted = append(submitted, block)
+			return false, nil
+		},
+		PermitConnectionlessMining: true,
+		ConnectedCount:             func() int32 { return 0 },
+		IsCurrent:                  func() bool { return false },
+	})
+	if err != nil {
+		t.Fatalf("unable to create stratum server: %v", err)
+	}
+	for _, listener := range s.listeners {
+		listener.Close()
+	}
+
+	serverConn, minerConn := net.Pipe()
+	c := &stratumClient{
+		server:      s,
+		conn:        serverConn,
+		addr:        "pipe",
+		extraNonce1: 0x01020304,
+	}
+	s.wg.Add(1)
+	go c.inHandler()
+	defer func() {
+		minerConn.Close()
+		s.wg.Wait()
+	}()
+	m := &stratumTestMiner{
+		t:       t,
+		conn:    minerConn,
+		scanner: bufio.NewScanner(minerConn),
+	}
+
+	// Give the connection a job to submit shares for.  The block is
+	// solved for any hash below the proof-of-work limit.
+	now := time.Unix(time.Now().Unix(), 0)
+	job := &stratumJob{
+		id: "1",
+		block: &wire.MsgBlock{Header: wire.BlockHeader{
+			Bits:      params.PowLimitBits,
+			Height:    1,
+			Timestamp: now,
+		}},
+		shares: make(map[[12]byte]struct{}),
+	}
+	littleEndian.PutUint32(job.block.Header.ExtraData[:], c.extraNonce1)
+	c.jobs = []*stratumJob{job}
+
+	// Find a nonce that solves the block.
+	extraNonce2 := []byte{0, 0, 0, 1}
+	var timestamp [4]byte
+	littleEndian.PutUint32(timestamp[:], uint32(now.Unix()))
+	header := job.block.Header
+	copy(header.ExtraData[stratumExtraNonce1Size:], extraNonce2)
+	for {
+		hash := header.BlockHash()
+		if blockchain.HashToBig(&hash).Cmp(params.PowLimit) <= 0 {
+			break
+		}
+		header.Nonce++
+	}
+	var nonce [4]byte
+	littleEndian.PutUint32(nonce[:], header.Nonce)
+	submit := func(worker, jobID string) int {
+		_, code := m.request("mining.submit", worker, jobID,
+			hex.EncodeToString(extraNonce2),
+			hex.EncodeToString(timestamp[:]),
+			hex.EncodeToString(nonce[:]))
+		return code
+	}
+
+	// Shares are rejected before the connection subscribed and authorized.
+	if code := submit("alice", "1"); code != stratumErrNotSubscribed {
+		t.Fatalf("submit before subscribe: got error %d, want %d", code,
+			stratumErrNotSubscribed)
+	}
+	if _, code := m.request("mining.subscribe"); code != 0 {
+		t.Fatalf("subscribe: unexpected error %d", code)
+	}
+	if code := submit("alice", "1"); code != stratumErrUnauthorized {
+		t.Fatalf("submit before authorize: got error %d, want %d", code,
+			stratumErrUnauthorized)
+	}
+
+	// Authorization requires the configured password.
+	_, code := m.request("mining.authorize", "alice", "wrong")
+	if code != stratumErrUnauthorized {
+		t.Fatalf("authorize with the wrong password: got error %d, "+
+			"want %d", code, stratumErrUnauthorized)
+	}
+	_, code = m.request("mining.authorize", "mallory", "secret")
+	if code != stratumErrUnauthorized {
+		t.Fatalf("authorize an unknown worker: got error %d, want %d",
+			code, stratumErrUnauthorized)
+	}
+	if _, code := m.request("mining.authorize", "alice", "secret"); code != 0 {
+		t.Fatalf("authorize: unexpected error %d", code)
+	}
+
+	// The share difficulty is sent once the connection has both subscribed
+	// and authorized.
+	var ntfn struct {
+		Method string
+		Params []interface{}
+	}
+	m.readLine(&ntfn)
+	if ntfn.Method != "mining.set_difficulty" {
+		t.Fatalf("got notification %q, want mining.set_difficulty",
+			ntfn.Method)
+	}
+
+	// Shares are rejected for workers that did not authorize over this
+	// connection, even when they are configured, and for unknown jobs.
+	if code := submit("bob", "1"); code != stratumErrUnauthorized {
+		t.Fatalf("submit as another worker: got error %d, want %d", code,
+			stratumErrUnauthorized)
+	}
+	if code := submit("alice", "2"); code != stratumErrJobNotFound {
+		t.Fatalf("submit for an unknown job: got error %d, want %d",
+			code, stratumErrJobNotFound)
+	}
+	if len(submitted) != 0 {
+		t.Fatalf("%d blocks submitted for rejected shares",
+			len(submitted))
+	}
+
+	// A valid share that solves the block is accepted and submits it, and
+	// the same share is not accepted twice.
+	if code := submit("alice", "1"); code != 0 {
+		t.Fatalf("submit: unexpected error %d", code)
+	}
+	if len(submitted) != 1 {
+		t.Fatalf("%d blocks submitted, want 1", len(submitted))
+	}
+	if got := submitted[0].MsgBlock().Header.BlockHash(); got != header.BlockHash() {
+		t.Fatalf("submitted block %v, want %v", got, header.BlockHash())
+	}
+	if code := submit("alice", "1"); code != stratumErrDuplicateShare {
+		t.Fatalf("duplicate submit: got error %d, want %d", code,
+			stratumErrDuplicateShare)
+	}
+
+	// Workers that authorize later over the same connection may submit
+	// shares as well.
+	if _, code := m.request("mining.authorize", "bob", "hunter2"); code != 0 {
+		t.Fatalf("authorize second worker: unexpected error %d", code)
+	}
+	extraNonce2 = []byte{0, 0, 0, 2}
+	if code := submit("bob", "1"); code != stratumErrLowDifficulty && code != 0 {
+		t.Fatalf("submit as second worker: unexpected error %d", code)
+	}
+}
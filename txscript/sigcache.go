@@ -3,6 +3,7 @@ package txscript
 import (
 	"bytes"
 	"sync"
+	"sync/atomic"
 
 	"github.com/decred/dcrd/chaincfg/chainec"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -30,6 +31,11 @@ type sigCacheEntry struct {
 // optimization which speeds up the validation of transactions within a block,
 // if they've already been seen and verified within the mempool.
 type SigCache struct {
+	// The following variables must only be used atomically.  Putting the
+	// uint64s first makes them 64-bit aligned for 32-bit systems.
+	hits   uint64 // Number of lookups that found a matching entry.
+	misses uint64 // Number of lookups that did not find a matching entry.
+
 	sync.RWMutex
 	validSigs  map[chainhash.Hash]sigCacheEntry
 	maxEntries uint
@@ -58,10 +64,25 @@ func (s *SigCache) Exists(sigHash chainhash.Hash, sig chainec.Signature, pubKey
 	entry, ok := s.validSigs[sigHash]
 	s.RUnlock()
 
-	return ok &&
+	exists := ok &&
 		bytes.Equal(entry.pubKey.SerializeCompressed(),
 			pubKey.SerializeCompressed()) &&
 		bytes.Equal(entry.sig.Serialize(), sig.Serialize())
+	if exists {
+		atomic.AddUint64(&s.hits, 1)
+	} else {
+		atomic.AddUint64(&s.misses, 1)
+	}
+	return exists
+}
+
+// Stats returns the number of lookups performed with Exists that found a
+// matching entry and the number of lookups that did not since the SigCache was
+// created.
+//
+// NOTE: This function is safe for concurrent access.
+func (s *SigCache) Stats() (hits, misses uint64) {
+	return atomic.LoadUint64(&s.hits), atomic.LoadUint64(&s.misses)
 }
 
 // Add adds an entry for a signature over 'sigHash' under public key 'pubKey'
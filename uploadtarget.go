@@ -0,0 +1,128 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// uploadTargetTimeframe is the length of the cycles the upload target
+	// applies to.
+	uploadTargetTimeframe = 24 * time.Hour
+
+	// historicalBlockAge is the minimum age of a block for it to be
+	// considered historical.  Historical blocks are no longer served to
+	// peers that are not whitelisted once the upload target is reached.
+	historicalBlockAge = 7 * 24 * time.Hour
+)
+
+// uploadTarget keeps track of the number of bytes sent to peers within the
+// current cycle of uploadTargetTimeframe against the target configured with
+// the maxuploadtarget option.
+type uploadTarget struct {
+	// These fields are set at creation time and never modified, so they
+	// are safe to read from concurrently without a mutex.
+	target        uint64
+	blockInterval time.Duration
+	maxBlockSize  uint64
+
+	mtx        sync.Mutex
+	cycleStart time.Time
+	cycleBytes uint64
+}
+
+// newUploadTarget returns a new upload target of the passed number of bytes
+// per cycle, where a target of zero is unlimited.  The chain parameters
+// determine how many bytes are reserved for serving new blocks.
+func newUploadTarget(target uint64, params *chaincfg.Params) *uploadTarget {
+	var maxBlockSize uint64
+	for _, size := range params.MaximumBlockSizes {
+		if uint64(size) > maxBlockSize {
+			maxBlockSize = uint64(size)
+		}
+	}
+	return &uploadTarget{
+		target:        target,
+		blockInterval: params.TargetTimePerBlock,
+		maxBlockSize:  maxBlockSize,
+		cycleStart:    time.Now(),
+	}
+}
+
+// maybeStartCycle starts a new cycle once the current one has ended.
+//
+// This function MUST be called with the upload target mutex held.
+func (u *uploadTarget) maybeStartCycle(now time.Time) {
+	if now.Sub(u.cycleStart) >= uploadTargetTimeframe {
+		u.cycleStart = now
+		u.cycleBytes = 0
+	}
+}
+
+// addBytes adds the passed number of bytes sent to a peer to the current cycle.
+//
+// This function is safe for concurrent access.
+func (u *uploadTarget) addBytes(n uint64) {
+	if u.target == 0 {
+		return
+	}
+
+	u.mtx.Lock()
+	u.maybeStartCycle(time.Now())
+	u.cycleBytes += n
+	u.mtx.Unlock()
+}
+
+// uploadTargetState houses the state of the upload target at a point in time.
+type uploadTargetState struct {
+	bytesLeft       uint64
+	timeLeft        time.Duration
+	reached         bool
+	serveHistorical bool
+}
+
+// state returns the state of the upload target.
+//
+// This function is safe for concurrent access.
+func (u *uploadTarget) state() uploadTargetState {
+	if u.target == 0 {
+		return uploadTargetState{serveHistorical: true}
+	}
+
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	now := time.Now()
+	u.maybeStartCycle(now)
+	var state uploadTargetState
+	state.timeLeft = uploadTargetTimeframe - now.Sub(u.cycleStart)
+	if u.cycleBytes < u.target {
+		state.bytesLeft = u.target - u.cycleBytes
+	}
+	state.reached = state.bytesLeft == 0
+
+	// Historical blocks are only served while the remaining bytes exceed
+	// what is needed to serve the new blocks expected for the rest of the
+	// cycle.
+	reserve := uint64(state.timeLeft/u.blockInterval) * u.maxBlockSize
+	state.serveHistorical = state.bytesLeft > reserve
+	return state
+}
+
+// isHistoricalBlock returns whether or not the block with the passed hash is
+// old enough to be considered historical for the purposes of the upload target.
+// Unknown blocks are not considered historical.
+func (s *server) isHistoricalBlock(hash *chainhash.Hash) bool {
+	header, err := s.blockManager.chain.HeaderByHash(hash)
+	if err != nil {
+		return false
+	}
+	return time.Since(header.Timestamp) > historicalBlockAge
+}
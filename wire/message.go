@@ -55,6 +55,15 @@ const (
 	CmdCFilter        = "cfilter"
 	CmdCFHeaders      = "cfheaders"
 	CmdCFTypes        = "cftypes"
+	CmdSendCmpct      = "sendcmpct"
+	CmdCmpctBlock     = "cmpctblock"
+	CmdGetBlockTxn    = "getblocktxn"
+	CmdBlockTxn       = "blocktxn"
+	CmdStemTx         = "stemtx"
+	CmdSendTxRcncl    = "sendtxrcncl"
+	CmdReqRecon       = "reqrecon"
+	CmdSketch         = "sketch"
+	CmdReconcilDiff   = "reconcildiff"
 )
 
 // Message is an interface that describes a Decred message.  A type that
@@ -151,6 +160,33 @@ func makeEmptyMessage(command string) (Message, error) {
 	case CmdCFTypes:
 		msg = &MsgCFTypes{}
 
+	case CmdSendCmpct:
+		msg = &MsgSendCmpct{}
+
+	case CmdCmpctBlock:
+		msg = &MsgCmpctBlock{}
+
+	case CmdGetBlockTxn:
+		msg = &MsgGetBlockTxn{}
+
+	case CmdBlockTxn:
+		msg = &MsgBlockTxn{}
+
+	case CmdStemTx:
+		msg = &MsgStemTx{}
+
+	case CmdSendTxRcncl:
+		msg = &MsgSendTxRcncl{}
+
+	case CmdReqRecon:
+		msg = &MsgReqRecon{}
+
+	case CmdSketch:
+		msg = &MsgSketch{}
+
+	case CmdReconcilDiff:
+		msg = &MsgReconcilDiff{}
+
 	default:
 		return nil, fmt.Errorf("unhandled command [%s]", command)
 	}
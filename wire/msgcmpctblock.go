@@ -0,0 +1,305 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// ShortTxIDSize is the number of bytes a short transaction id in a
+	// cmpctblock message is serialized to.
+	ShortTxIDSize = 6
+
+	// maxShortTxID is the largest value a short transaction id may have.
+	maxShortTxID = 1<<(ShortTxIDSize*8) - 1
+)
+
+// PrefilledTx houses a transaction that is sent in full in a cmpctblock message
+// along with its position in its transaction tree of the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a cmpctblock
+// message.  It is used to announce a block with its header and short ids of
+// the transactions in both its regular and stake transaction trees so that a
+// peer can reconstruct the block from the transactions in its memory pool.
+// Transactions the receiving peer is unlikely to have, such as the coinbase,
+// are sent in full as prefilled transactions.
+//
+// The short ids are derived from the full hashes of the transactions with a
+// key that depends on the block header and the nonce.  The regular and stake
+// trees of the block consist of the transactions in the short id and prefilled
+// lists merged by the indexes of the prefilled transactions.
+//
+// This message was not added until protocol versions starting with
+// CompactBlocksVersion.
+type MsgCmpctBlock struct {
+	Header            BlockHeader
+	Nonce             uint64
+	ShortIDs          []uint64
+	PrefilledTxs      []PrefilledTx
+	StakeShortIDs     []uint64
+	StakePrefilledTxs []PrefilledTx
+}
+
+// TxCount returns the number of transactions in the regular transaction tree
+// of the announced block.
+func (msg *MsgCmpctBlock) TxCount() int {
+	return len(msg.ShortIDs) + len(msg.PrefilledTxs)
+}
+
+// STxCount returns the number of transactions in the stake transaction tree of
+// the announced block.
+func (msg *MsgCmpctBlock) STxCount() int {
+	return len(msg.StakeShortIDs) + len(msg.StakePrefilledTxs)
+}
+
+// readShortTxID reads a short transaction id from r.
+func readShortTxID(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:ShortTxIDSize]); err != nil {
+		return 0, err
+	}
+	return littleEndian.Uint64(buf[:]), nil
+}
+
+// writeShortTxID writes the passed short transaction id to w.
+func writeShortTxID(w io.Writer, id uint64) error {
+	var buf [8]byte
+	littleEndian.PutUint64(buf[:], id)
+	_, err := w.Write(buf[:ShortTxIDSize])
+	return err
+}
+
+// readDiffIndex reads a differentially encoded transaction index from r.  The
+// first index is encoded as is and each following one as the difference to
+// the index that precedes it minus one, so that the decoded indexes are
+// always strictly increasing.  The decoded index must not exceed maxIndex.
+func readDiffIndex(r io.Reader, pver uint32, first bool, prev uint32, maxIndex uint64, op string) (uint32, error) {
+	diff, err := ReadVarInt(r, pver)
+	if err != nil {
+		return 0, err
+	}
+	index := diff
+	if !first {
+		index += uint64(prev) + 1
+	}
+	if diff > maxIndex || index > maxIndex {
+		str := fmt.Sprintf("transaction index out of range [max %d]",
+			maxIndex)
+		return 0, messageError(op, str)
+	}
+	return uint32(index), nil
+}
+
+// writeDiffIndex writes the passed transaction index to w using the encoding
+// described by readDiffIndex.  The index must be greater than the previous one
+// unless it is the first.
+func writeDiffIndex(w io.Writer, pver uint32, first bool, prev, index uint32, op string) error {
+	diff := uint64(index)
+	if !first {
+		if index <= prev {
+			str := fmt.Sprintf("transaction indexes are not strictly "+
+				"increasing [index %d, previous %d]", index, prev)
+			return messageError(op, str)
+		}
+		diff -= uint64(prev) + 1
+	}
+	return WriteVarInt(w, pver, diff)
+}
+
+// readCmpctTxTree reads the short transaction ids and prefilled transactions
+// of one transaction tree of a compact block from r.
+func readCmpctTxTree(r io.Reader, pver uint32) ([]uint64, []PrefilledTx, error) {
+	const op = "MsgCmpctBlock.BtcDecode"
+
+	// Prevent more transactions than could possibly fit into a tree of a
+	// block.  It would be possible to cause memory exhaustion and panics
+	// without a sane upper bound on these counts.
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	idCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if idCount > maxTxPerTree {
+		str := fmt.Sprintf("too many short transaction ids to fit "+
+			"into a block [count %d, max %d]", idCount, maxTxPerTree)
+		return nil, nil, messageError(op, str)
+	}
+	shortIDs := make([]uint64, 0, idCount)
+	for i := uint64(0); i < idCount; i++ {
+		id, err := readShortTxID(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		shortIDs = append(shortIDs, id)
+	}
+
+	prefilledCount, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prefilledCount > maxTxPerTree-idCount {
+		str := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", idCount+prefilledCount,
+			maxTxPerTree)
+		return nil, nil, messageError(op, str)
+	}
+
+	// The prefilled transactions must be positioned within the tree made up
+	// of both lists.
+	maxIndex := idCount + prefilledCount - 1
+	prefilled := make([]PrefilledTx, 0, prefilledCount)
+	var prev uint32
+	for i := uint64(0); i < prefilledCount; i++ {
+		index, err := readDiffIndex(r, pver, i == 0, prev, maxIndex, op)
+		if err != nil {
+			return nil, nil, err
+		}
+		var tx MsgTx
+		if err := tx.BtcDecode(r, pver); err != nil {
+			return nil, nil, err
+		}
+		prefilled = append(prefilled, PrefilledTx{Index: index, Tx: &tx})
+		prev = index
+	}
+
+	return shortIDs, prefilled, nil
+}
+
+// writeCmpctTxTree writes the passed short transaction ids and prefilled
+// transactions of one transaction tree of a compact block to w.
+func writeCmpctTxTree(w io.Writer, pver uint32, shortIDs []uint64, prefilled []PrefilledTx) error {
+	const op = "MsgCmpctBlock.BtcEncode"
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(shortIDs) + len(prefilled))
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many transactions to fit into a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, str)
+	}
+
+	err := WriteVarInt(w, pver, uint64(len(shortIDs)))
+	if err != nil {
+		return err
+	}
+	for _, id := range shortIDs {
+		if id > maxShortTxID {
+			str := fmt.Sprintf("short transaction id %x does not "+
+				"fit into %d bytes", id, ShortTxIDSize)
+			return messageError(op, str)
+		}
+		if err := writeShortTxID(w, id); err != nil {
+			return err
+		}
+	}
+
+	err = WriteVarInt(w, pver, uint64(len(prefilled)))
+	if err != nil {
+		return err
+	}
+	for i, ptx := range prefilled {
+		if uint64(ptx.Index) >= count {
+			str := fmt.Sprintf("prefilled transaction index %d out "+
+				"of range [count %d]", ptx.Index, count)
+			return messageError(op, str)
+		}
+		var prev uint32
+		if i > 0 {
+			prev = prefilled[i-1].Index
+		}
+		err := writeDiffIndex(w, pver, i == 0, prev, ptx.Index, op)
+		if err != nil {
+			return err
+		}
+		if err := ptx.Tx.BtcEncode(w, pver); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+
+	err := readBlockHeader(r, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = readElement(r, &msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	msg.ShortIDs, msg.PrefilledTxs, err = readCmpctTxTree(r, pver)
+	if err != nil {
+		return err
+	}
+	msg.StakeShortIDs, msg.StakePrefilledTxs, err = readCmpctTxTree(r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcEncode", str)
+	}
+
+	err := writeBlockHeader(w, pver, &msg.Header)
+	if err != nil {
+		return err
+	}
+	err = writeElement(w, msg.Nonce)
+	if err != nil {
+		return err
+	}
+
+	err = writeCmpctTxTree(w, pver, msg.ShortIDs, msg.PrefilledTxs)
+	if err != nil {
+		return err
+	}
+	return writeCmpctTxTree(w, pver, msg.StakeShortIDs,
+		msg.StakePrefilledTxs)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	// A compact block is never larger than the block it describes by more
+	// than the nonce since short ids and differential indexes are smaller
+	// than the transactions they replace.
+	return MaxBlockPayload + 8
+}
+
+// NewMsgCmpctBlock returns a new cmpctblock message that conforms to the
+// Message interface using the passed parameters and defaults for the
+// remaining fields.
+func NewMsgCmpctBlock(header *BlockHeader, nonce uint64) *MsgCmpctBlock {
+	return &MsgCmpctBlock{
+		Header: *header,
+		Nonce:  nonce,
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// MsgGetBlockTxn implements the Message interface and represents a getblocktxn
+// message.  It is used to request the transactions of a block announced with a
+// cmpctblock message that could not be found in the memory pool, identified by
+// their positions in the regular and stake transaction trees of the block.  The
+// transactions are delivered with a blocktxn message.
+//
+// This message was not added until protocol versions starting with
+// CompactBlocksVersion.
+type MsgGetBlockTxn struct {
+	BlockHash    chainhash.Hash
+	Indexes      []uint32
+	StakeIndexes []uint32
+}
+
+// readIndexes reads a list of differentially encoded transaction indexes from
+// r.
+func readIndexes(r io.Reader, pver uint32) ([]uint32, error) {
+	const op = "MsgGetBlockTxn.BtcDecode"
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many transaction indexes for a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return nil, messageError(op, str)
+	}
+
+	indexes := make([]uint32, 0, count)
+	var prev uint32
+	for i := uint64(0); i < count; i++ {
+		index, err := readDiffIndex(r, pver, i == 0, prev,
+			maxTxPerTree-1, op)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+		prev = index
+	}
+	return indexes, nil
+}
+
+// writeIndexes writes the passed strictly increasing transaction indexes to w
+// using a differential encoding.
+func writeIndexes(w io.Writer, pver uint32, indexes []uint32) error {
+	const op = "MsgGetBlockTxn.BtcEncode"
+
+	maxTxPerTree := MaxTxPerTxTree(pver)
+	count := uint64(len(indexes))
+	if count > maxTxPerTree {
+		str := fmt.Sprintf("too many transaction indexes for a block "+
+			"[count %d, max %d]", count, maxTxPerTree)
+		return messageError(op, str)
+	}
+
+	err := WriteVarInt(w, pver, count)
+	if err != nil {
+		return err
+	}
+	for i, index := range indexes {
+		var prev uint32
+		if i > 0 {
+			prev = indexes[i-1]
+		}
+		err := writeDiffIndex(w, pver, i == 0, prev, index, op)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("getblocktxn message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxn.BtcDecode", str)
+	}
+
+	err := readElement(r, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	msg.Indexes, err = readIndexes(r, pver)
+	if err != nil {
+		return err
+	}
+	msg.StakeIndexes, err = readIndexes(r, pver)
+	return err
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("getblocktxn message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxn.BtcEncode", str)
+	}
+
+	err := writeElement(w, &msg.BlockHash)
+	if err != nil {
+		return err
+	}
+	err = writeIndexes(w, pver, msg.Indexes)
+	if err != nil {
+		return err
+	}
+	return writeIndexes(w, pver, msg.StakeIndexes)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgGetBlockTxn) Command() string {
+	return CmdGetBlockTxn
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) MaxPayloadLength(pver uint32) uint32 {
+	// Block hash + num indexes (varInt) + max indexes (varInt) for each of
+	// the regular and stake trees.
+	maxTxPerTree := uint32(MaxTxPerTxTree(pver))
+	return chainhash.HashSize + 2*(MaxVarIntPayload+
+		maxTxPerTree*MaxVarIntPayload)
+}
+
+// NewMsgGetBlockTxn returns a new getblocktxn message that conforms to the
+// Message interface using the passed parameters and defaults for the remaining
+// fields.
+func NewMsgGetBlockTxn(blockHash *chainhash.Hash) *MsgGetBlockTxn {
+	return &MsgGetBlockTxn{
+		BlockHash: *blockHash,
+	}
+}
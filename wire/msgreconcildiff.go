@@ -0,0 +1,116 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgReconcilDiff implements the Message interface and represents a
+// reconcildiff message.  It is used to conclude a round of transaction
+// reconciliation.  When the sketch could be decoded, Success is set and
+// AskShortIDs holds the short ids of the transactions the sending peer is
+// missing, which the receiving peer announces with inventory vectors.
+// Otherwise both peers announce all transactions of the round.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgReconcilDiff struct {
+	Success     bool
+	AskShortIDs []uint32
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("reconcildiff message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgReconcilDiff.BtcDecode", str)
+	}
+
+	err := readElement(r, &msg.Success)
+	if err != nil {
+		return err
+	}
+
+	// The missing transactions can never exceed the number of differences
+	// that can be recovered from a sketch.
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count > MaxSketchCapacity {
+		str := fmt.Sprintf("too many short ids for message "+
+			"[count %d, max %d]", count, MaxSketchCapacity)
+		return messageError("MsgReconcilDiff.BtcDecode", str)
+	}
+
+	msg.AskShortIDs = make([]uint32, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var id uint32
+		if err := readElement(r, &id); err != nil {
+			return err
+		}
+		msg.AskShortIDs = append(msg.AskShortIDs, id)
+	}
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("reconcildiff message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgReconcilDiff.BtcEncode", str)
+	}
+
+	count := len(msg.AskShortIDs)
+	if count > MaxSketchCapacity {
+		str := fmt.Sprintf("too many short ids for message "+
+			"[count %d, max %d]", count, MaxSketchCapacity)
+		return messageError("MsgReconcilDiff.BtcEncode", str)
+	}
+
+	err := writeElement(w, msg.Success)
+	if err != nil {
+		return err
+	}
+	err = WriteVarInt(w, pver, uint64(count))
+	if err != nil {
+		return err
+	}
+	for _, id := range msg.AskShortIDs {
+		if err := writeElement(w, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReconcilDiff) Command() string {
+	return CmdReconcilDiff
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReconcilDiff) MaxPayloadLength(pver uint32) uint32 {
+	// Success flag 1 byte + num short ids (varInt) + max short ids 4
+	// bytes each.
+	return 1 + MaxVarIntPayload + MaxSketchCapacity*4
+}
+
+// NewMsgReconcilDiff returns a new reconcildiff message that conforms to the
+// Message interface.  See MsgReconcilDiff for details.
+func NewMsgReconcilDiff(success bool, askShortIDs []uint32) *MsgReconcilDiff {
+	return &MsgReconcilDiff{
+		Success:     success,
+		AskShortIDs: askShortIDs,
+	}
+}
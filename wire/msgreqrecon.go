@@ -0,0 +1,71 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgReqRecon implements the Message interface and represents a reqrecon
+// message.  It is used to start a round of transaction reconciliation by
+// requesting a sketch of the transactions the receiving peer would announce to
+// the sending peer.  SetSize is the number of transactions the sending peer
+// would announce itself and Q is the coefficient used to estimate the size of
+// the difference of both sets, scaled by 2^15-1 so it can be transmitted as an
+// integer.  The sketch is delivered with a sketch message.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgReqRecon struct {
+	SetSize uint16
+	Q       uint16
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("reqrecon message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgReqRecon.BtcDecode", str)
+	}
+
+	return readElements(r, &msg.SetSize, &msg.Q)
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgReqRecon) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("reqrecon message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgReqRecon.BtcEncode", str)
+	}
+
+	return writeElements(w, msg.SetSize, msg.Q)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgReqRecon) Command() string {
+	return CmdReqRecon
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgReqRecon) MaxPayloadLength(pver uint32) uint32 {
+	// Set size 2 bytes + q 2 bytes.
+	return 4
+}
+
+// NewMsgReqRecon returns a new reqrecon message that conforms to the Message
+// interface.  See MsgReqRecon for details.
+func NewMsgReqRecon(setSize, q uint16) *MsgReqRecon {
+	return &MsgReqRecon{
+		SetSize: setSize,
+		Q:       q,
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// TxRcnclVersion is the version of the transaction reconciliation protocol
+// described by the sendtxrcncl, reqrecon, sketch and reconcildiff messages.
+const TxRcnclVersion uint32 = 1
+
+// MsgSendTxRcncl implements the Message interface and represents a sendtxrcncl
+// message.  It is used to signal that the sending peer supports the
+// reconciliation of transaction announcements of the given version.  The salt
+// is combined with the salt of the other peer to derive the keys for the short
+// transaction ids used in the sketches.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgSendTxRcncl struct {
+	Version uint32
+	Salt    uint64
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSendTxRcncl) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("sendtxrcncl message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgSendTxRcncl.BtcDecode", str)
+	}
+
+	return readElements(r, &msg.Version, &msg.Salt)
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSendTxRcncl) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("sendtxrcncl message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgSendTxRcncl.BtcEncode", str)
+	}
+
+	return writeElements(w, msg.Version, msg.Salt)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSendTxRcncl) Command() string {
+	return CmdSendTxRcncl
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSendTxRcncl) MaxPayloadLength(pver uint32) uint32 {
+	// Version 4 bytes + salt 8 bytes.
+	return 12
+}
+
+// NewMsgSendTxRcncl returns a new sendtxrcncl message that conforms to the
+// Message interface.  See MsgSendTxRcncl for details.
+func NewMsgSendTxRcncl(version uint32, salt uint64) *MsgSendTxRcncl {
+	return &MsgSendTxRcncl{
+		Version: version,
+		Salt:    salt,
+	}
+}
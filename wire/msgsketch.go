@@ -0,0 +1,95 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	// MaxSketchCapacity is the maximum capacity of a sketch in a sketch
+	// message, which is the maximum number of differences between the
+	// transaction sets of two peers that can be reconciled in one round.
+	MaxSketchCapacity = 128
+
+	// SketchElementSize is the size in bytes every unit of the capacity of
+	// a sketch adds to its serialization.
+	SketchElementSize = 4
+)
+
+// MsgSketch implements the Message interface and represents a sketch message.
+// It is used to reply to a reqrecon message with a sketch of the short ids of
+// the transactions the sending peer would announce to the receiving peer.  An
+// empty sketch signals that the difference of the transaction sets is expected
+// to be too large to be reconciled.
+//
+// This message was not added until protocol versions starting with
+// TxReconciliationVersion.
+type MsgSketch struct {
+	SketchData []byte
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgSketch) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("sketch message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgSketch.BtcDecode", str)
+	}
+
+	var err error
+	msg.SketchData, err = ReadVarBytes(r, pver,
+		MaxSketchCapacity*SketchElementSize, "sketch data")
+	if err != nil {
+		return err
+	}
+	if len(msg.SketchData)%SketchElementSize != 0 {
+		str := fmt.Sprintf("sketch data size %d is not a multiple of "+
+			"the element size %d", len(msg.SketchData),
+			SketchElementSize)
+		return messageError("MsgSketch.BtcDecode", str)
+	}
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgSketch) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < TxReconciliationVersion {
+		str := fmt.Sprintf("sketch message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgSketch.BtcEncode", str)
+	}
+
+	size := len(msg.SketchData)
+	if size > MaxSketchCapacity*SketchElementSize {
+		str := fmt.Sprintf("sketch data too large for message "+
+			"[size %d, max %d]", size,
+			MaxSketchCapacity*SketchElementSize)
+		return messageError("MsgSketch.BtcEncode", str)
+	}
+	return WriteVarBytes(w, pver, msg.SketchData)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgSketch) Command() string {
+	return CmdSketch
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgSketch) MaxPayloadLength(pver uint32) uint32 {
+	// Size of sketch data (varInt) + max sketch data.
+	return MaxVarIntPayload + MaxSketchCapacity*SketchElementSize
+}
+
+// NewMsgSketch returns a new sketch message that conforms to the Message
+// interface for the passed serialized sketch.  See MsgSketch for details.
+func NewMsgSketch(sketchData []byte) *MsgSketch {
+	return &MsgSketch{SketchData: sketchData}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// MsgStemTx implements the Message interface and represents a stemtx message.
+// It is used to relay a transaction in the stem phase of the dandelion
+// transaction relay, in which a new transaction is passed along a path of
+// single peers before it is announced to the network, which hides the node it
+// originated from.  Unlike transactions in tx messages, it is not announced
+// with an inventory vector first.
+//
+// This message was not added until protocol versions starting with
+// DandelionVersion.
+type MsgStemTx struct {
+	Tx *MsgTx
+}
+
+// BtcDecode decodes r using the protocol encoding into the receiver.
+// This is part of the Message interface implementation.
+func (msg *MsgStemTx) BtcDecode(r io.Reader, pver uint32) error {
+	if pver < DandelionVersion {
+		str := fmt.Sprintf("stemtx message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgStemTx.BtcDecode", str)
+	}
+
+	var tx MsgTx
+	if err := tx.BtcDecode(r, pver); err != nil {
+		return err
+	}
+	msg.Tx = &tx
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgStemTx) BtcEncode(w io.Writer, pver uint32) error {
+	if pver < DandelionVersion {
+		str := fmt.Sprintf("stemtx message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgStemTx.BtcEncode", str)
+	}
+
+	return msg.Tx.BtcEncode(w, pver)
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgStemTx) Command() string {
+	return CmdStemTx
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgStemTx) MaxPayloadLength(pver uint32) uint32 {
+	return MaxBlockPayload
+}
+
+// NewMsgStemTx returns a new stemtx message that conforms to the Message
+// interface for the passed transaction.  See MsgStemTx for details.
+func NewMsgStemTx(tx *MsgTx) *MsgStemTx {
+	return &MsgStemTx{Tx: tx}
+}
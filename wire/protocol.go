@@ -12,7 +12,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 6
+	ProtocolVersion uint32 = 9
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -34,6 +34,18 @@ const (
 	// flag and the cfheaders, cfilter, cftypes, getcfheaders, getcfilter and
 	// getcftypes messages.
 	NodeCFVersion uint32 = 6
+
+	// CompactBlocksVersion is the protocol version which adds the
+	// sendcmpct, cmpctblock, getblocktxn and blocktxn messages.
+	CompactBlocksVersion uint32 = 7
+
+	// DandelionVersion is the protocol version which adds the stemtx
+	// message.
+	DandelionVersion uint32 = 8
+
+	// TxReconciliationVersion is the protocol version which adds the
+	// sendtxrcncl, reqrecon, sketch and reconcildiff messages.
+	TxReconciliationVersion uint32 = 9
 )
 
 // ServiceFlag identifies services supported by a Decred peer.
@@ -53,13 +65,18 @@ const (
 	// filters (CFs).
 	// 支持committed过滤
 	SFNodeCF
+
+	// SFNodeEncryption is a flag used to indicate a peer supports the
+	// encrypted and authenticated peer-to-peer transport.
+	SFNodeEncryption
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:    "SFNodeNetwork",
+	SFNodeBloom:      "SFNodeBloom",
+	SFNodeCF:         "SFNodeCF",
+	SFNodeEncryption: "SFNodeEncryption",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -68,6 +85,7 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeNetwork,
 	SFNodeBloom,
 	SFNodeCF,
+	SFNodeEncryption,
 }
 
 // String returns the ServiceFlag in human-readable form.
@@ -105,12 +123,24 @@ type CurrencyNet uint32
 const (
 	// MainNet represents the main Decred network.
 	MainNet CurrencyNet = 0xd9b400f9
+
+	// RegNet represents the regression test network.
+	RegNet CurrencyNet = 0xe6d4e2fa
+
+	// TestNet3 represents the 3rd test network.
+	TestNet3 CurrencyNet = 0xb194aa75
+
+	// SimNet represents the simulation test network.
+	SimNet CurrencyNet = 0x12141c16
 )
 
 // bnStrings is a map of Decred networks back to their constant names for
 // pretty printing.
 var bnStrings = map[CurrencyNet]string{
-	MainNet: "MainNet",
+	MainNet:  "MainNet",
+	TestNet3: "TestNet3",
+	RegNet:   "RegNet",
+	SimNet:   "SimNet",
 }
 
 // String returns the CurrencyNet in human-readable form.